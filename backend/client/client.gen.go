@@ -0,0 +1,2292 @@
+// Code generated by client/gen from internal/openapi/openapi.yaml. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type AccountDeletion struct {
+	ConfirmedAt  *string `json:"ConfirmedAt,omitempty"`
+	CreatedAt    string  `json:"CreatedAt"`
+	FamilyID     string  `json:"FamilyID"`
+	ID           string  `json:"ID"`
+	RequestedBy  string  `json:"RequestedBy"`
+	ScheduledFor *string `json:"ScheduledFor,omitempty"`
+	Status       string  `json:"Status"`
+	UpdatedAt    string  `json:"UpdatedAt"`
+}
+
+type AdminCreateFamilyRequest struct {
+	Email      string  `json:"email"`
+	FamilyName string  `json:"familyName"`
+	ParentName string  `json:"parentName"`
+	Password   string  `json:"password"`
+	Plan       *string `json:"plan,omitempty"`
+}
+
+type AdminStats struct {
+	PremiumFamilies   int `json:"premiumFamilies"`
+	TotalChildren     int `json:"totalChildren"`
+	TotalFamilies     int `json:"totalFamilies"`
+	TotalParents      int `json:"totalParents"`
+	TotalPointsEarned int `json:"totalPointsEarned"`
+	TotalRedemptions  int `json:"totalRedemptions"`
+	TotalTasksToday   int `json:"totalTasksToday"`
+}
+
+type AgeBand string
+
+type Analytics struct {
+	Data    AnalyticsReport `json:"data"`
+	Message string          `json:"message"`
+}
+
+type AnalyticsChild struct {
+	Avatar    string `json:"avatar"`
+	ChildID   string `json:"childId"`
+	ChildName string `json:"childName"`
+}
+
+type AnalyticsReport struct {
+	Children         []AnalyticsChild       `json:"children"`
+	CompletionDaily  []CompletionPoint      `json:"completionDaily"`
+	CompletionWeekly []CompletionPoint      `json:"completionWeekly"`
+	From             string                 `json:"from"`
+	MostCompleted    []TaskInsight          `json:"mostCompleted"`
+	MostSkipped      []TaskInsight          `json:"mostSkipped"`
+	Points           []PointsPoint          `json:"points"`
+	PrayerHeatmap    []HeatmapCell          `json:"prayerHeatmap"`
+	Streaks          []StreakInfo           `json:"streaks"`
+	Summary          AnalyticsReportSummary `json:"summary"`
+	To               string                 `json:"to"`
+}
+
+type Announcement struct {
+	CreatedAt string `json:"CreatedAt"`
+	ID        string `json:"ID"`
+	IsActive  bool   `json:"IsActive"`
+	Message   string `json:"Message"`
+	Title     string `json:"Title"`
+	Type      string `json:"Type"`
+	UpdatedAt string `json:"UpdatedAt"`
+}
+
+type AnnouncementRequest struct {
+	Message string  `json:"message"`
+	Title   string  `json:"title"`
+	Type    *string `json:"type,omitempty"`
+}
+
+type AppliedRewards struct {
+	Message string   `json:"message"`
+	Rewards []Reward `json:"rewards"`
+}
+
+type AppliedRule struct {
+	BonusPoints *int     `json:"bonusPoints,omitempty"`
+	Multiplier  *float64 `json:"multiplier,omitempty"`
+	Name        string   `json:"name"`
+	RuleID      string   `json:"ruleId"`
+	RuleType    string   `json:"ruleType"`
+}
+
+type AppliedTasks struct {
+	Message string `json:"message"`
+	Tasks   []Task `json:"tasks"`
+}
+
+type AuthToken struct {
+	Role  string `json:"role"`
+	Token string `json:"token"`
+}
+
+type Badge struct {
+	CreatedAt   string  `json:"CreatedAt"`
+	Description string  `json:"Description"`
+	FamilyID    string  `json:"FamilyID"`
+	ID          string  `json:"ID"`
+	Icon        string  `json:"Icon"`
+	Metric      string  `json:"Metric"`
+	Name        string  `json:"Name"`
+	TaskID      *string `json:"TaskID,omitempty"`
+	Threshold   int     `json:"Threshold"`
+	UpdatedAt   string  `json:"UpdatedAt"`
+}
+
+type BadgeProgress struct {
+	Badge    Badge                       `json:"badge"`
+	Children []BadgeProgressChildrenItem `json:"children"`
+}
+
+type BadgeRequest struct {
+	Description *string `json:"description,omitempty"`
+	Icon        *string `json:"icon,omitempty"`
+	Metric      *string `json:"metric,omitempty"`
+	Name        string  `json:"name"`
+	TaskID      *string `json:"taskId,omitempty"`
+	Threshold   int     `json:"threshold"`
+}
+
+type CashoutRequest struct {
+	Note   *string `json:"note,omitempty"`
+	Points int     `json:"points"`
+}
+
+type CheckoutRequest struct {
+	Coupon  *string `json:"coupon,omitempty"`
+	Package string  `json:"package"`
+}
+
+type Child struct {
+	AvatarIcon    string  `json:"AvatarIcon"`
+	CreatedAt     string  `json:"CreatedAt"`
+	Email         *string `json:"Email,omitempty"`
+	FamilyID      string  `json:"FamilyID"`
+	ID            string  `json:"ID"`
+	Name          string  `json:"Name"`
+	PointsBalance int     `json:"PointsBalance"`
+	Role          string  `json:"Role"`
+	UpdatedAt     string  `json:"UpdatedAt"`
+	Whatsapp      *string `json:"Whatsapp,omitempty"`
+}
+
+type ChildPinRequest struct {
+	ChildID string `json:"childId"`
+	Pin     string `json:"pin"`
+}
+
+type ChildProfile struct {
+	Avatar string `json:"avatar"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
+type CodeRequest struct {
+	Code string `json:"code"`
+}
+
+type Completion struct {
+	AppliedRules []AppliedRule `json:"appliedRules"`
+	ChildID      *string       `json:"childId,omitempty"`
+	Date         string        `json:"date"`
+	EarnedPoints int           `json:"earnedPoints"`
+	Message      string        `json:"message"`
+	NewBalance   int           `json:"newBalance"`
+}
+
+type CompletionPoint struct {
+	ChildID     string  `json:"childId"`
+	Completions int     `json:"completions"`
+	Date        string  `json:"date"`
+	Rate        float64 `json:"rate"`
+}
+
+type CompletionRequest struct {
+	Date   *string `json:"date,omitempty"`
+	TaskID string  `json:"taskId"`
+}
+
+type ConfirmDeletionRequest struct {
+	Token string `json:"token"`
+}
+
+type Coupon struct {
+	Code        string  `json:"Code"`
+	CreatedAt   string  `json:"CreatedAt"`
+	Description string  `json:"Description"`
+	ID          string  `json:"ID"`
+	IsActive    bool    `json:"IsActive"`
+	Kind        string  `json:"Kind"`
+	MaxUses     *int    `json:"MaxUses,omitempty"`
+	UpdatedAt   string  `json:"UpdatedAt"`
+	ValidFrom   *string `json:"ValidFrom,omitempty"`
+	ValidUntil  *string `json:"ValidUntil,omitempty"`
+	Value       int     `json:"Value"`
+}
+
+type CouponQuote struct {
+	Amount   int    `json:"amount"`
+	Code     string `json:"code"`
+	Discount int    `json:"discount"`
+	Package  string `json:"package"`
+	Price    int    `json:"price"`
+}
+
+type CouponQuoteRequest struct {
+	Code    string `json:"code"`
+	Package string `json:"package"`
+}
+
+type CouponRequest struct {
+	Code        *string `json:"code,omitempty"`
+	Description *string `json:"description,omitempty"`
+	IsActive    *bool   `json:"isActive,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	MaxUses     *int    `json:"maxUses,omitempty"`
+	ValidFrom   *string `json:"validFrom,omitempty"`
+	ValidUntil  *string `json:"validUntil,omitempty"`
+	Value       *int    `json:"value,omitempty"`
+}
+
+type CouponStats struct {
+	Code          string  `json:"Code"`
+	Description   string  `json:"Description"`
+	ID            string  `json:"ID"`
+	IsActive      bool    `json:"IsActive"`
+	Kind          string  `json:"Kind"`
+	MaxUses       *int    `json:"MaxUses,omitempty"`
+	Reserved      int     `json:"Reserved"`
+	Revenue       int     `json:"Revenue"`
+	TotalDiscount int     `json:"TotalDiscount"`
+	Used          int     `json:"Used"`
+	ValidFrom     *string `json:"ValidFrom,omitempty"`
+	ValidUntil    *string `json:"ValidUntil,omitempty"`
+	Value         int     `json:"Value"`
+}
+
+type CouponUse struct {
+	CreatedAt  string  `json:"CreatedAt"`
+	Days       int     `json:"Days"`
+	Discount   int     `json:"Discount"`
+	FamilyID   string  `json:"FamilyID"`
+	FamilyName string  `json:"FamilyName"`
+	OrderID    *string `json:"OrderID,omitempty"`
+	Status     string  `json:"Status"`
+}
+
+type CreateChildRequest struct {
+	Avatar *string `json:"avatar,omitempty"`
+	Name   string  `json:"name"`
+	Pin    string  `json:"pin"`
+}
+
+type CreateRewardRequest struct {
+	Icon           *string `json:"icon,omitempty"`
+	Name           string  `json:"name"`
+	PointsRequired int     `json:"pointsRequired"`
+}
+
+type CreateTaskRequest struct {
+	Category  *string `json:"category,omitempty"`
+	Icon      *string `json:"icon,omitempty"`
+	MaxPerDay *int    `json:"maxPerDay,omitempty"`
+	Name      string  `json:"name"`
+	Points    int     `json:"points"`
+	Quantity  *int    `json:"quantity,omitempty"`
+}
+
+type CreatedFamily struct {
+	Family  Family              `json:"family"`
+	Message string              `json:"message"`
+	Parent  CreatedFamilyParent `json:"parent"`
+}
+
+type DailyLog struct {
+	ChildID       string  `json:"ChildID"`
+	ClientEventID *string `json:"ClientEventID,omitempty"`
+	CompletedDate string  `json:"CompletedDate"`
+	CreatedAt     string  `json:"CreatedAt"`
+	EarnedPoints  int     `json:"EarnedPoints"`
+	ID            string  `json:"ID"`
+	Quantity      int     `json:"Quantity"`
+	Status        string  `json:"Status"`
+	TaskID        string  `json:"TaskID"`
+	UpdatedAt     string  `json:"UpdatedAt"`
+}
+
+type DailyLogEdit struct {
+	Changes    []LogDiff `json:"changes"`
+	ChildID    string    `json:"childId"`
+	Date       string    `json:"date"`
+	NewBalance int       `json:"newBalance"`
+}
+
+type DecisionRequest struct {
+	Status string `json:"status"`
+}
+
+type DeletionStatus struct {
+	Deletion AccountDeletion `json:"deletion"`
+	Message  string          `json:"message"`
+}
+
+type Entitlements struct {
+	ExpiresAt  *string         `json:"expiresAt,omitempty"`
+	FamilyPlan string          `json:"familyPlan"`
+	Features   map[string]bool `json:"features"`
+	Limits     map[string]*int `json:"limits"`
+	Plan       string          `json:"plan"`
+	PlanName   string          `json:"planName"`
+	ReadOnly   *ReadOnlySet    `json:"readOnly,omitempty"`
+	Usage      map[string]int  `json:"usage"`
+}
+
+type Error struct {
+	Code    string       `json:"code"`
+	Details []FieldError `json:"details,omitempty"`
+	Message string       `json:"message"`
+}
+
+type Family struct {
+	CreatedAt         string   `json:"CreatedAt"`
+	EnableLeaderboard bool     `json:"EnableLeaderboard"`
+	ID                string   `json:"ID"`
+	Name              string   `json:"Name"`
+	Plan              string   `json:"Plan"`
+	PlanExpiresAt     *string  `json:"PlanExpiresAt,omitempty"`
+	PlanReminder      string   `json:"PlanReminder"`
+	ReferralCode      *string  `json:"ReferralCode,omitempty"`
+	Rewards           []Reward `json:"Rewards,omitempty"`
+	SeasonEnd         *string  `json:"SeasonEnd,omitempty"`
+	SeasonStart       *string  `json:"SeasonStart,omitempty"`
+	Tasks             []Task   `json:"Tasks,omitempty"`
+	Timezone          string   `json:"Timezone"`
+	UpdatedAt         string   `json:"UpdatedAt"`
+	Users             []Child  `json:"Users,omitempty"`
+}
+
+type FamilyArchive struct {
+	DailyLogs   []map[string]interface{} `json:"daily_logs"`
+	ExportedAt  string                   `json:"exported_at"`
+	Family      map[string]interface{}   `json:"family"`
+	Goals       []map[string]interface{} `json:"goals,omitempty"`
+	PointRules  []map[string]interface{} `json:"point_rules,omitempty"`
+	Redemptions []map[string]interface{} `json:"redemptions"`
+	Rewards     []map[string]interface{} `json:"rewards"`
+	Tasks       []map[string]interface{} `json:"tasks"`
+	Users       []map[string]interface{} `json:"users"`
+	Version     int                      `json:"version"`
+	Wallets     []map[string]interface{} `json:"wallets,omitempty"`
+}
+
+type FamilyChildren struct {
+	Children    []ChildProfile `json:"children"`
+	FamilyTitle string         `json:"familyTitle"`
+}
+
+type FamilyGoal struct {
+	AchievedAt  *string `json:"AchievedAt,omitempty"`
+	CreatedAt   string  `json:"CreatedAt"`
+	DailyTarget int     `json:"DailyTarget"`
+	EndDate     string  `json:"EndDate"`
+	FamilyID    string  `json:"FamilyID"`
+	GoalType    string  `json:"GoalType"`
+	ID          string  `json:"ID"`
+	Icon        string  `json:"Icon"`
+	Metric      string  `json:"Metric"`
+	Name        string  `json:"Name"`
+	RewardIcon  string  `json:"RewardIcon"`
+	RewardName  string  `json:"RewardName"`
+	StartDate   string  `json:"StartDate"`
+	Target      int     `json:"Target"`
+	Tasks       []Task  `json:"Tasks,omitempty"`
+	UpdatedAt   string  `json:"UpdatedAt"`
+}
+
+type FamilySettingsRequest struct {
+	EnableLeaderboard *bool   `json:"enableLeaderboard,omitempty"`
+	SeasonEnd         *string `json:"seasonEnd,omitempty"`
+	SeasonStart       *string `json:"seasonStart,omitempty"`
+	Timezone          *string `json:"timezone,omitempty"`
+	Title             *string `json:"title,omitempty"`
+}
+
+type FieldError struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type GoalProgress struct {
+	Contributions []GoalProgressContributionsItem `json:"contributions"`
+	Current       int                             `json:"current"`
+	Goal          FamilyGoal                      `json:"goal"`
+	Percent       int                             `json:"percent"`
+	Target        int                             `json:"target"`
+	Unlocked      bool                            `json:"unlocked"`
+}
+
+type GoalRequest struct {
+	DailyTarget *int     `json:"dailyTarget,omitempty"`
+	EndDate     string   `json:"endDate"`
+	GoalType    string   `json:"goalType"`
+	Icon        *string  `json:"icon,omitempty"`
+	Metric      string   `json:"metric"`
+	Name        string   `json:"name"`
+	RewardIcon  *string  `json:"rewardIcon,omitempty"`
+	RewardName  string   `json:"rewardName"`
+	StartDate   string   `json:"startDate"`
+	Target      int      `json:"target"`
+	TaskIds     []string `json:"taskIds"`
+}
+
+type HeatmapCell struct {
+	ChildID string `json:"childId"`
+	Date    string `json:"date"`
+	Prayers int    `json:"prayers"`
+}
+
+type ImportArchiveRequest struct {
+	Archive    map[string]interface{} `json:"archive"`
+	Email      string                 `json:"email"`
+	FamilyName *string                `json:"familyName,omitempty"`
+	Password   string                 `json:"password"`
+}
+
+type ImportResult struct {
+	ChildrenWithoutPin  []string       `json:"childrenWithoutPin"`
+	FamilyID            string         `json:"familyId"`
+	Imported            map[string]int `json:"imported"`
+	OwnerID             string         `json:"ownerId"`
+	ParentsWithoutEmail []string       `json:"parentsWithoutEmail"`
+	Skipped             int            `json:"skipped"`
+}
+
+type ImportedFamily struct {
+	Import ImportResult `json:"import"`
+	Role   string       `json:"role"`
+	Token  string       `json:"token"`
+}
+
+type JobRun struct {
+	Attempt    int     `json:"Attempt"`
+	Error      string  `json:"Error"`
+	FinishedAt *string `json:"FinishedAt,omitempty"`
+	ID         string  `json:"ID"`
+	JobName    string  `json:"JobName"`
+	StartedAt  string  `json:"StartedAt"`
+	Status     string  `json:"Status"`
+	Worker     string  `json:"Worker"`
+}
+
+type JobStatus struct {
+	Attempts    int      `json:"Attempts"`
+	IsPaused    bool     `json:"IsPaused"`
+	LastError   string   `json:"LastError"`
+	LastRunAt   *string  `json:"LastRunAt,omitempty"`
+	LockedBy    string   `json:"LockedBy"`
+	LockedUntil *string  `json:"LockedUntil,omitempty"`
+	MaxAttempts int      `json:"MaxAttempts"`
+	Name        string   `json:"Name"`
+	NextRunAt   string   `json:"NextRunAt"`
+	Schedule    string   `json:"Schedule"`
+	RecentRuns  []JobRun `json:"recentRuns"`
+	Registered  bool     `json:"registered"`
+}
+
+type KioskCompletionRequest struct {
+	ChildID string  `json:"childId"`
+	Date    *string `json:"date,omitempty"`
+	TaskID  string  `json:"taskId"`
+}
+
+type Leaderboard struct {
+	Leaderboard []LeaderboardEntry `json:"leaderboard"`
+	Period      string             `json:"period"`
+	PeriodEnd   string             `json:"periodEnd"`
+	PeriodStart string             `json:"periodStart"`
+	RankBy      string             `json:"rankBy"`
+	WeekEnd     string             `json:"weekEnd"`
+	WeekStart   string             `json:"weekStart"`
+}
+
+type LeaderboardEntry struct {
+	Avatar         string  `json:"avatar"`
+	ChildID        string  `json:"childId"`
+	ChildName      string  `json:"childName"`
+	CompletionRate float64 `json:"completionRate"`
+	Completions    int     `json:"completions"`
+	Points         int     `json:"points"`
+	Rank           int     `json:"rank"`
+	WeekPoints     int     `json:"weekPoints"`
+}
+
+type LogDiff struct {
+	Added       int    `json:"added"`
+	After       int    `json:"after"`
+	Before      int    `json:"before"`
+	PointsDelta int    `json:"pointsDelta"`
+	Removed     int    `json:"removed"`
+	TaskID      string `json:"taskId"`
+	TaskName    string `json:"taskName"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type Message struct {
+	Message string `json:"message"`
+}
+
+type NotificationPreference struct {
+	Approvals   bool   `json:"Approvals"`
+	Maghrib     bool   `json:"Maghrib"`
+	QuietEnd    string `json:"QuietEnd"`
+	QuietStart  string `json:"QuietStart"`
+	Redemptions bool   `json:"Redemptions"`
+	Streaks     bool   `json:"Streaks"`
+	UpdatedAt   string `json:"UpdatedAt"`
+	UserID      string `json:"UserID"`
+}
+
+type NotificationPreferencesRequest struct {
+	Approvals   *bool   `json:"approvals,omitempty"`
+	Maghrib     *bool   `json:"maghrib,omitempty"`
+	QuietEnd    *string `json:"quietEnd,omitempty"`
+	QuietStart  *string `json:"quietStart,omitempty"`
+	Redemptions *bool   `json:"redemptions,omitempty"`
+	Streaks     *bool   `json:"streaks,omitempty"`
+}
+
+type Payment struct {
+	Amount        int     `json:"Amount"`
+	CouponCode    *string `json:"CouponCode,omitempty"`
+	CreatedAt     string  `json:"CreatedAt"`
+	Days          int     `json:"Days"`
+	Discount      *int    `json:"Discount,omitempty"`
+	FamilyID      string  `json:"FamilyID"`
+	ID            string  `json:"ID"`
+	OrderID       string  `json:"OrderID"`
+	PackageCode   string  `json:"PackageCode"`
+	PaidAt        *string `json:"PaidAt,omitempty"`
+	PaymentType   *string `json:"PaymentType,omitempty"`
+	RedirectURL   *string `json:"RedirectURL,omitempty"`
+	RefundedAt    *string `json:"RefundedAt,omitempty"`
+	SnapToken     *string `json:"SnapToken,omitempty"`
+	Status        string  `json:"Status"`
+	TransactionID *string `json:"TransactionID,omitempty"`
+	UpdatedAt     string  `json:"UpdatedAt"`
+}
+
+type PayoutRequest struct {
+	Amount int     `json:"amount"`
+	Note   *string `json:"note,omitempty"`
+}
+
+type PinVerification struct {
+	ChildID  string `json:"childId"`
+	Name     string `json:"name"`
+	Verified bool   `json:"verified"`
+}
+
+type Plan struct {
+	Code        string         `json:"Code"`
+	CreatedAt   string         `json:"CreatedAt"`
+	Description string         `json:"Description"`
+	Features    []string       `json:"Features"`
+	Limits      map[string]int `json:"Limits"`
+	Name        string         `json:"Name"`
+	UpdatedAt   string         `json:"UpdatedAt"`
+}
+
+type PlanPackage struct {
+	Code  string `json:"code"`
+	Days  int    `json:"days"`
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+type PlanRequest struct {
+	Code        *string        `json:"code,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	Features    []string       `json:"features,omitempty"`
+	Limits      map[string]int `json:"limits,omitempty"`
+	Name        *string        `json:"name,omitempty"`
+}
+
+type PlanStatus struct {
+	DaysLeft      *int           `json:"daysLeft,omitempty"`
+	ExpiresAt     *string        `json:"expiresAt,omitempty"`
+	GraceUntil    *string        `json:"graceUntil,omitempty"`
+	InGracePeriod bool           `json:"inGracePeriod"`
+	IsPremium     bool           `json:"isPremium"`
+	Limits        map[string]int `json:"limits,omitempty"`
+	Plan          string         `json:"plan"`
+	ReadOnly      *ReadOnlySet   `json:"readOnly,omitempty"`
+	Usage         map[string]int `json:"usage"`
+}
+
+type PlanSummary struct {
+	Code        string         `json:"Code"`
+	Description string         `json:"Description"`
+	Families    int            `json:"Families"`
+	Features    []string       `json:"Features"`
+	Limits      map[string]int `json:"Limits"`
+	Name        string         `json:"Name"`
+}
+
+type PointRule struct {
+	BonusPoints int     `json:"BonusPoints"`
+	ChildID     *string `json:"ChildID,omitempty"`
+	CreatedAt   string  `json:"CreatedAt"`
+	EndDate     *string `json:"EndDate,omitempty"`
+	FamilyID    string  `json:"FamilyID"`
+	ID          string  `json:"ID"`
+	IsActive    bool    `json:"IsActive"`
+	Multiplier  float64 `json:"Multiplier"`
+	Name        string  `json:"Name"`
+	RuleType    string  `json:"RuleType"`
+	StartDate   *string `json:"StartDate,omitempty"`
+	TaskID      *string `json:"TaskID,omitempty"`
+	UpdatedAt   string  `json:"UpdatedAt"`
+	Weekdays    string  `json:"Weekdays"`
+}
+
+type PointRuleRequest struct {
+	BonusPoints *int     `json:"bonusPoints,omitempty"`
+	ChildID     *string  `json:"childId,omitempty"`
+	EndDate     *string  `json:"endDate,omitempty"`
+	IsActive    *bool    `json:"isActive,omitempty"`
+	Multiplier  *float64 `json:"multiplier,omitempty"`
+	Name        string   `json:"name"`
+	RuleType    string   `json:"ruleType"`
+	StartDate   *string  `json:"startDate,omitempty"`
+	TaskID      *string  `json:"taskId,omitempty"`
+	Weekdays    []int    `json:"weekdays,omitempty"`
+}
+
+type PointSummary struct {
+	Balance       int `json:"balance"`
+	CashoutPoints int `json:"cashoutPoints"`
+	PendingPoints int `json:"pendingPoints"`
+	SpentPoints   int `json:"spentPoints"`
+	TotalPoints   int `json:"totalPoints"`
+}
+
+type PointsPoint struct {
+	Date   string `json:"date"`
+	Earned int    `json:"earned"`
+	Spent  int    `json:"spent"`
+}
+
+type PushEndpointRequest struct {
+	Endpoint string `json:"endpoint"`
+}
+
+type PushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	ID       string `json:"id"`
+}
+
+type PushSubscriptionRequest struct {
+	Endpoint string                      `json:"endpoint"`
+	Keys     PushSubscriptionRequestKeys `json:"keys"`
+}
+
+type ReadOnlySet struct {
+	Children []string `json:"children"`
+	Rewards  []string `json:"rewards"`
+	Tasks    []string `json:"tasks"`
+}
+
+type RedeemedCoupon struct {
+	Plan          string  `json:"plan"`
+	PlanExpiresAt *string `json:"planExpiresAt,omitempty"`
+}
+
+type Redemption struct {
+	Child         *Child  `json:"Child,omitempty"`
+	ChildID       string  `json:"ChildID"`
+	ClientEventID *string `json:"ClientEventID,omitempty"`
+	CreatedAt     string  `json:"CreatedAt"`
+	ID            string  `json:"ID"`
+	PointsSpent   int     `json:"PointsSpent"`
+	Reward        *Reward `json:"Reward,omitempty"`
+	RewardID      string  `json:"RewardID"`
+	Status        string  `json:"Status"`
+	UpdatedAt     string  `json:"UpdatedAt"`
+}
+
+type RedemptionRequest struct {
+	ChildID  *string `json:"childId,omitempty"`
+	Quantity *int    `json:"quantity,omitempty"`
+	RewardID string  `json:"rewardId"`
+}
+
+type ReferralInfo struct {
+	BonusDays  int                       `json:"bonusDays"`
+	Code       string                    `json:"code"`
+	Invites    []ReferralInfoInvitesItem `json:"invites"`
+	ReferredBy *string                   `json:"referredBy,omitempty"`
+}
+
+type ReferralStats struct {
+	BonusDays    int                             `json:"BonusDays"`
+	Rewarded     int                             `json:"Rewarded"`
+	TopReferrers []ReferralStatsTopReferrersItem `json:"TopReferrers"`
+	Total        int                             `json:"Total"`
+}
+
+type RegisterRequest struct {
+	Email         string  `json:"email"`
+	FamilyName    *string `json:"familyName,omitempty"`
+	Name          string  `json:"name"`
+	Password      string  `json:"password"`
+	ReferralCode  *string `json:"referralCode,omitempty"`
+	Slug          *string `json:"slug,omitempty"`
+	Whatsapp      *string `json:"whatsapp,omitempty"`
+	WhatsappOptIn *bool   `json:"whatsappOptIn,omitempty"`
+}
+
+type ReportSent struct {
+	Message string `json:"message"`
+	To      string `json:"to"`
+}
+
+type Reward struct {
+	CreatedAt      string `json:"CreatedAt"`
+	FamilyID       string `json:"FamilyID"`
+	ID             string `json:"ID"`
+	Icon           string `json:"Icon"`
+	IsActive       bool   `json:"IsActive"`
+	Name           string `json:"Name"`
+	PointsRequired int    `json:"PointsRequired"`
+	UpdatedAt      string `json:"UpdatedAt"`
+}
+
+type RewardTemplateRequest struct {
+	Preset string `json:"preset"`
+}
+
+type SavedTemplateItem struct {
+	BonusPoints    *int     `json:"BonusPoints,omitempty"`
+	Category       *string  `json:"Category,omitempty"`
+	Description    *string  `json:"Description,omitempty"`
+	Icon           *string  `json:"Icon,omitempty"`
+	Key            string   `json:"Key"`
+	Kind           string   `json:"Kind"`
+	LastDays       *int     `json:"LastDays,omitempty"`
+	MaxPerDay      *int     `json:"MaxPerDay,omitempty"`
+	Metric         *string  `json:"Metric,omitempty"`
+	Multiplier     *float64 `json:"Multiplier,omitempty"`
+	Name           string   `json:"Name"`
+	Points         *int     `json:"Points,omitempty"`
+	PointsRequired *int     `json:"PointsRequired,omitempty"`
+	Quantity       *int     `json:"Quantity,omitempty"`
+	RuleType       *string  `json:"RuleType,omitempty"`
+	Task           *string  `json:"Task,omitempty"`
+	Threshold      *int     `json:"Threshold,omitempty"`
+	Weekdays       []int    `json:"Weekdays,omitempty"`
+}
+
+type SendReportRequest struct {
+	Date   *string  `json:"date,omitempty"`
+	Period *string  `json:"period,omitempty"`
+	To     []string `json:"to,omitempty"`
+}
+
+type SetDailyLogsRequest struct {
+	ChildID string                         `json:"childId"`
+	Date    string                         `json:"date"`
+	Tasks   []SetDailyLogsRequestTasksItem `json:"tasks"`
+}
+
+type SetPlanRequest struct {
+	Days      *int    `json:"days,omitempty"`
+	ExpiresAt *string `json:"expiresAt,omitempty"`
+	Plan      string  `json:"plan"`
+}
+
+type StreakInfo struct {
+	ChildID string `json:"childId"`
+	Current int    `json:"current"`
+	Longest int    `json:"longest"`
+}
+
+type SyncChanges struct {
+	Children    []SyncChild        `json:"children"`
+	Cursor      string             `json:"cursor"`
+	Deleted     SyncChangesDeleted `json:"deleted"`
+	Full        bool               `json:"full"`
+	Logs        []DailyLog         `json:"logs"`
+	Redemptions []Redemption       `json:"redemptions"`
+	Rewards     []Reward           `json:"rewards"`
+	Tasks       []Task             `json:"tasks"`
+}
+
+type SyncChild struct {
+	AvailablePoints int    `json:"availablePoints"`
+	AvatarIcon      string `json:"avatarIcon"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	PointsBalance   int    `json:"pointsBalance"`
+}
+
+type SyncEvent struct {
+	ChildID    *string `json:"childId,omitempty"`
+	Date       *string `json:"date,omitempty"`
+	ID         string  `json:"id"`
+	OccurredAt string  `json:"occurredAt"`
+	Quantity   *int    `json:"quantity,omitempty"`
+	RewardID   *string `json:"rewardId,omitempty"`
+	TaskID     *string `json:"taskId,omitempty"`
+	Type       string  `json:"type"`
+}
+
+type SyncEventResult struct {
+	Code         *string `json:"code,omitempty"`
+	EarnedPoints *int    `json:"earnedPoints,omitempty"`
+	ID           string  `json:"id"`
+	LogID        *string `json:"logId,omitempty"`
+	NewBalance   *int    `json:"newBalance,omitempty"`
+	Reason       *string `json:"reason,omitempty"`
+	RedemptionID *string `json:"redemptionId,omitempty"`
+	Status       string  `json:"status"`
+}
+
+type SyncPushRequest struct {
+	DeviceID *string     `json:"deviceId,omitempty"`
+	Events   []SyncEvent `json:"events"`
+}
+
+type SyncPushResult struct {
+	DeviceID string            `json:"deviceId"`
+	Results  []SyncEventResult `json:"results"`
+}
+
+type Task struct {
+	Category    string `json:"Category"`
+	CreatedAt   string `json:"CreatedAt"`
+	FamilyID    string `json:"FamilyID"`
+	ID          string `json:"ID"`
+	Icon        string `json:"Icon"`
+	IsActive    bool   `json:"IsActive"`
+	MaxPerDay   *int   `json:"MaxPerDay,omitempty"`
+	Name        string `json:"Name"`
+	PointReward int    `json:"PointReward"`
+	Quantity    int    `json:"Quantity"`
+	TaskType    string `json:"TaskType"`
+	UpdatedAt   string `json:"UpdatedAt"`
+}
+
+type TaskInsight struct {
+	Completions int    `json:"completions"`
+	Icon        string `json:"icon"`
+	Name        string `json:"name"`
+	Skipped     int    `json:"skipped"`
+	TaskID      string `json:"taskId"`
+}
+
+type TaskTemplateRequest struct {
+	TemplateType string `json:"templateType"`
+}
+
+type Template struct {
+	AgeBand     AgeBand             `json:"AgeBand"`
+	CreatedAt   string              `json:"CreatedAt"`
+	Description string              `json:"Description"`
+	FamilyID    *string             `json:"FamilyID,omitempty"`
+	ID          string              `json:"ID"`
+	Items       []SavedTemplateItem `json:"Items"`
+	Key         *string             `json:"Key,omitempty"`
+	Name        string              `json:"Name"`
+	ShareCode   *string             `json:"ShareCode,omitempty"`
+	UpdatedAt   string              `json:"UpdatedAt"`
+}
+
+type TemplateFromFamilyRequest struct {
+	AgeBand     AgeBand `json:"ageBand"`
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+type TemplateItem struct {
+	BonusPoints    *int     `json:"bonusPoints,omitempty"`
+	Category       *string  `json:"category,omitempty"`
+	Description    *string  `json:"description,omitempty"`
+	Icon           *string  `json:"icon,omitempty"`
+	Key            string   `json:"key"`
+	Kind           string   `json:"kind"`
+	LastDays       *int     `json:"lastDays,omitempty"`
+	MaxPerDay      *int     `json:"maxPerDay,omitempty"`
+	Metric         *string  `json:"metric,omitempty"`
+	Multiplier     *float64 `json:"multiplier,omitempty"`
+	Name           string   `json:"name"`
+	Points         *int     `json:"points,omitempty"`
+	PointsRequired *int     `json:"pointsRequired,omitempty"`
+	Quantity       *int     `json:"quantity,omitempty"`
+	RuleType       *string  `json:"ruleType,omitempty"`
+	Task           *string  `json:"task,omitempty"`
+	Threshold      *int     `json:"threshold,omitempty"`
+	Weekdays       []int    `json:"weekdays,omitempty"`
+}
+
+type TemplatePlan struct {
+	Applied    bool                    `json:"applied"`
+	Counts     map[string]int          `json:"counts"`
+	Items      []TemplatePlanItemsItem `json:"items"`
+	Name       string                  `json:"name"`
+	TemplateID string                  `json:"templateId"`
+}
+
+type TemplateRequest struct {
+	AgeBand     AgeBand        `json:"ageBand"`
+	Description *string        `json:"description,omitempty"`
+	Items       []TemplateItem `json:"items"`
+	Name        string         `json:"name"`
+}
+
+type TemplateSelection struct {
+	Items []string `json:"items,omitempty"`
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+type TestNotification struct {
+	Devices int `json:"devices"`
+}
+
+type UndoneLog struct {
+	Log     DailyLog `json:"log"`
+	Message string   `json:"message"`
+}
+
+type UpdateChildRequest struct {
+	Avatar *string `json:"avatar,omitempty"`
+	Name   string  `json:"name"`
+	Pin    *string `json:"pin,omitempty"`
+}
+
+type UpdateRewardRequest struct {
+	Icon           *string `json:"icon,omitempty"`
+	Name           string  `json:"name"`
+	PointsRequired int     `json:"pointsRequired"`
+}
+
+type UpdateTaskRequest struct {
+	Category  *string `json:"category,omitempty"`
+	Icon      *string `json:"icon,omitempty"`
+	MaxPerDay *int    `json:"maxPerDay,omitempty"`
+	Name      string  `json:"name"`
+	Points    int     `json:"points"`
+	Quantity  *int    `json:"quantity,omitempty"`
+}
+
+type VapidKey struct {
+	PublicKey string `json:"publicKey"`
+}
+
+type Wallet struct {
+	ChildID        string `json:"ChildID"`
+	CreatedAt      string `json:"CreatedAt"`
+	FamilyID       string `json:"FamilyID"`
+	ID             string `json:"ID"`
+	IsActive       bool   `json:"IsActive"`
+	RupiahPerPoint int    `json:"RupiahPerPoint"`
+	THRPerPoint    int    `json:"THRPerPoint"`
+	UpdatedAt      string `json:"UpdatedAt"`
+}
+
+type WalletSettingsRequest struct {
+	IsActive       *bool `json:"isActive,omitempty"`
+	RupiahPerPoint *int  `json:"rupiahPerPoint,omitempty"`
+	ThrPerPoint    *int  `json:"thrPerPoint,omitempty"`
+}
+
+type WalletSummary struct {
+	ChildID         string              `json:"ChildID"`
+	FamilyID        string              `json:"FamilyID"`
+	ID              string              `json:"ID"`
+	IsActive        bool                `json:"IsActive"`
+	RupiahPerPoint  int                 `json:"RupiahPerPoint"`
+	THRPerPoint     int                 `json:"THRPerPoint"`
+	AvailablePoints int                 `json:"availablePoints"`
+	Balance         int                 `json:"balance"`
+	ChildName       string              `json:"childName"`
+	PendingAmount   int                 `json:"pendingAmount"`
+	Transactions    []WalletTransaction `json:"transactions,omitempty"`
+}
+
+type WalletTransaction struct {
+	Amount     int     `json:"Amount"`
+	ChildID    string  `json:"ChildID"`
+	CreatedAt  string  `json:"CreatedAt"`
+	ID         string  `json:"ID"`
+	Note       string  `json:"Note"`
+	Points     int     `json:"Points"`
+	RecordedBy *string `json:"RecordedBy,omitempty"`
+	Reference  string  `json:"Reference"`
+	Status     string  `json:"Status"`
+	Type       string  `json:"Type"`
+	UpdatedAt  string  `json:"UpdatedAt"`
+	WalletID   string  `json:"WalletID"`
+}
+
+type WhatsappInbound struct {
+	Received int `json:"received"`
+	Updated  int `json:"updated"`
+}
+
+type WhatsappSettings struct {
+	OptedIn    bool    `json:"optedIn"`
+	OptedInAt  *string `json:"optedInAt,omitempty"`
+	OptedOutAt *string `json:"optedOutAt,omitempty"`
+	Provider   string  `json:"provider"`
+	Whatsapp   *string `json:"whatsapp,omitempty"`
+}
+
+type WhatsappSettingsRequest struct {
+	OptIn    *bool   `json:"optIn,omitempty"`
+	Whatsapp *string `json:"whatsapp,omitempty"`
+}
+
+// AdminListAnnouncements — GET /admin/announcements: GET /admin/announcements
+func (c *Client) AdminListAnnouncements(ctx context.Context) ([]Announcement, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Announcement](c.do(ctx, "GET", "/admin/announcements", query, header, nil))
+}
+
+// AdminCreateAnnouncement — POST /admin/announcements: POST /admin/announcements
+func (c *Client) AdminCreateAnnouncement(ctx context.Context, body AnnouncementRequest) (*Announcement, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Announcement](c.do(ctx, "POST", "/admin/announcements", query, header, body))
+}
+
+// AdminDeleteAnnouncement — DELETE /admin/announcements/{id}: DELETE /admin/announcements/{id}
+func (c *Client) AdminDeleteAnnouncement(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/admin/announcements/"+url.PathEscape(id), query, header, nil)
+}
+
+// AdminListCoupons — GET /admin/coupons: GET /admin/coupons
+func (c *Client) AdminListCoupons(ctx context.Context) ([]CouponStats, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]CouponStats](c.do(ctx, "GET", "/admin/coupons", query, header, nil))
+}
+
+// AdminCreateCoupon — POST /admin/coupons: POST /admin/coupons
+func (c *Client) AdminCreateCoupon(ctx context.Context, body CouponRequest) (*Coupon, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Coupon](c.do(ctx, "POST", "/admin/coupons", query, header, body))
+}
+
+// AdminUpdateCoupon — PUT /admin/coupons/{id}: PUT /admin/coupons/{id}
+func (c *Client) AdminUpdateCoupon(ctx context.Context, id string, body CouponRequest) (*Coupon, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Coupon](c.do(ctx, "PUT", "/admin/coupons/"+url.PathEscape(id), query, header, body))
+}
+
+// AdminDeleteCoupon — DELETE /admin/coupons/{id}: DELETE /admin/coupons/{id}
+func (c *Client) AdminDeleteCoupon(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/admin/coupons/"+url.PathEscape(id), query, header, nil)
+}
+
+// AdminListCouponRedemptions — GET /admin/coupons/{id}/redemptions: GET /admin/coupons/{id}/redemptions
+func (c *Client) AdminListCouponRedemptions(ctx context.Context, id string) ([]CouponUse, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]CouponUse](c.do(ctx, "GET", "/admin/coupons/"+url.PathEscape(id)+"/redemptions", query, header, nil))
+}
+
+// AdminListFamilies — GET /admin/families: GET /admin/families
+func (c *Client) AdminListFamilies(ctx context.Context) ([]Family, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Family](c.do(ctx, "GET", "/admin/families", query, header, nil))
+}
+
+// AdminCreateFamily — POST /admin/families: POST /admin/families
+func (c *Client) AdminCreateFamily(ctx context.Context, body AdminCreateFamilyRequest) (*CreatedFamily, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*CreatedFamily](c.do(ctx, "POST", "/admin/families", query, header, body))
+}
+
+// AdminDeleteFamily — DELETE /admin/families/{id}: DELETE /admin/families/{id}
+func (c *Client) AdminDeleteFamily(ctx context.Context, id string) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "DELETE", "/admin/families/"+url.PathEscape(id), query, header, nil))
+}
+
+// AdminSetFamilyPlan — PUT /admin/families/{id}/plan: PUT /admin/families/{id}/plan
+func (c *Client) AdminSetFamilyPlan(ctx context.Context, id string, body SetPlanRequest) (*Family, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Family](c.do(ctx, "PUT", "/admin/families/"+url.PathEscape(id)+"/plan", query, header, body))
+}
+
+// AdminListJobs — GET /admin/jobs: GET /admin/jobs
+func (c *Client) AdminListJobs(ctx context.Context) ([]JobStatus, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]JobStatus](c.do(ctx, "GET", "/admin/jobs", query, header, nil))
+}
+
+// AdminPauseJob — PUT /admin/jobs/{name}/pause: PUT /admin/jobs/{name}/pause
+func (c *Client) AdminPauseJob(ctx context.Context, name string) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "PUT", "/admin/jobs/"+url.PathEscape(name)+"/pause", query, header, nil))
+}
+
+// AdminResumeJob — PUT /admin/jobs/{name}/resume: PUT /admin/jobs/{name}/resume
+func (c *Client) AdminResumeJob(ctx context.Context, name string) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "PUT", "/admin/jobs/"+url.PathEscape(name)+"/resume", query, header, nil))
+}
+
+// AdminListJobRunsParams holds the optional query and header parameters of AdminListJobRuns.
+type AdminListJobRunsParams struct {
+	Limit string // query "limit"
+}
+
+// AdminListJobRuns — GET /admin/jobs/{name}/runs: GET /admin/jobs/{name}/runs
+func (c *Client) AdminListJobRuns(ctx context.Context, name string, params *AdminListJobRunsParams) ([]JobRun, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.Limit != "" {
+			query.Set("limit", params.Limit)
+		}
+	}
+	return decode[[]JobRun](c.do(ctx, "GET", "/admin/jobs/"+url.PathEscape(name)+"/runs", query, header, nil))
+}
+
+// AdminTriggerJob — POST /admin/jobs/{name}/trigger: POST /admin/jobs/{name}/trigger
+func (c *Client) AdminTriggerJob(ctx context.Context, name string) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "POST", "/admin/jobs/"+url.PathEscape(name)+"/trigger", query, header, nil))
+}
+
+// AdminListPlans — GET /admin/plans: GET /admin/plans
+func (c *Client) AdminListPlans(ctx context.Context) ([]PlanSummary, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]PlanSummary](c.do(ctx, "GET", "/admin/plans", query, header, nil))
+}
+
+// AdminCreatePlan — POST /admin/plans: POST /admin/plans
+func (c *Client) AdminCreatePlan(ctx context.Context, body PlanRequest) (*Plan, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Plan](c.do(ctx, "POST", "/admin/plans", query, header, body))
+}
+
+// AdminUpdatePlan — PUT /admin/plans/{code}: PUT /admin/plans/{code}
+func (c *Client) AdminUpdatePlan(ctx context.Context, code string, body PlanRequest) (*Plan, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Plan](c.do(ctx, "PUT", "/admin/plans/"+url.PathEscape(code), query, header, body))
+}
+
+// AdminDeletePlan — DELETE /admin/plans/{code}: DELETE /admin/plans/{code}
+func (c *Client) AdminDeletePlan(ctx context.Context, code string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/admin/plans/"+url.PathEscape(code), query, header, nil)
+}
+
+// AdminReferralStats — GET /admin/referrals: GET /admin/referrals
+func (c *Client) AdminReferralStats(ctx context.Context) (*ReferralStats, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*ReferralStats](c.do(ctx, "GET", "/admin/referrals", query, header, nil))
+}
+
+// AdminStats — GET /admin/stats: GET /admin/stats
+func (c *Client) AdminStats(ctx context.Context) (*AdminStats, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*AdminStats](c.do(ctx, "GET", "/admin/stats", query, header, nil))
+}
+
+// GetAnalyticsParams holds the optional query and header parameters of GetAnalytics.
+type GetAnalyticsParams struct {
+	From    string // query "from"
+	To      string // query "to"
+	ChildID string // query "childId"
+}
+
+// GetAnalytics — GET /analytics: GET /analytics
+func (c *Client) GetAnalytics(ctx context.Context, params *GetAnalyticsParams) (*Analytics, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+		if params.ChildID != "" {
+			query.Set("childId", params.ChildID)
+		}
+	}
+	return decode[*Analytics](c.do(ctx, "GET", "/analytics", query, header, nil))
+}
+
+// ListActiveAnnouncements — GET /announcements: GET /announcements
+func (c *Client) ListActiveAnnouncements(ctx context.Context) ([]Announcement, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Announcement](c.do(ctx, "GET", "/announcements", query, header, nil))
+}
+
+// ChildLogin — POST /auth/child-login: POST /auth/child-login
+func (c *Client) ChildLogin(ctx context.Context, body ChildPinRequest) (*AuthToken, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*AuthToken](c.do(ctx, "POST", "/auth/child-login", query, header, body))
+}
+
+// GoogleLogin — GET /auth/google: Redirect to Google sign-in
+func (c *Client) GoogleLogin(ctx context.Context) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "GET", "/auth/google", query, header, nil)
+}
+
+// GoogleCallbackParams holds the optional query and header parameters of GoogleCallback.
+type GoogleCallbackParams struct {
+	Code  string // query "code"
+	State string // query "state"
+}
+
+// GoogleCallback — GET /auth/google/callback: GET /auth/google/callback
+func (c *Client) GoogleCallback(ctx context.Context, params *GoogleCallbackParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.Code != "" {
+			query.Set("code", params.Code)
+		}
+		if params.State != "" {
+			query.Set("state", params.State)
+		}
+	}
+	return c.do(ctx, "GET", "/auth/google/callback", query, header, nil)
+}
+
+// ImportArchive — POST /auth/import: Create a new family from an exported archive
+func (c *Client) ImportArchive(ctx context.Context, body ImportArchiveRequest) (*ImportedFamily, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*ImportedFamily](c.do(ctx, "POST", "/auth/import", query, header, body))
+}
+
+// Login — POST /auth/login: POST /auth/login
+func (c *Client) Login(ctx context.Context, body LoginRequest) (*AuthToken, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*AuthToken](c.do(ctx, "POST", "/auth/login", query, header, body))
+}
+
+// RegisterParams holds the optional query and header parameters of Register.
+type RegisterParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// Register — POST /auth/register: Create a family and its first parent (idempotent)
+func (c *Client) Register(ctx context.Context, params *RegisterParams, body RegisterRequest) (*AuthToken, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*AuthToken](c.do(ctx, "POST", "/auth/register", query, header, body))
+}
+
+// ListBadges — GET /badges: Badges with every child's progress
+func (c *Client) ListBadges(ctx context.Context) ([]BadgeProgress, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]BadgeProgress](c.do(ctx, "GET", "/badges", query, header, nil))
+}
+
+// CreateBadge — POST /badges: Add a custom badge (parent, custom_badges feature)
+func (c *Client) CreateBadge(ctx context.Context, body BadgeRequest) (*Badge, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Badge](c.do(ctx, "POST", "/badges", query, header, body))
+}
+
+// UpdateBadge — PUT /badges/{id}: PUT /badges/{id}
+func (c *Client) UpdateBadge(ctx context.Context, id string, body BadgeRequest) (*Badge, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Badge](c.do(ctx, "PUT", "/badges/"+url.PathEscape(id), query, header, body))
+}
+
+// DeleteBadge — DELETE /badges/{id}: DELETE /badges/{id}
+func (c *Client) DeleteBadge(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/badges/"+url.PathEscape(id), query, header, nil)
+}
+
+// ListChildren — GET /children: GET /children
+func (c *Client) ListChildren(ctx context.Context) ([]Child, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Child](c.do(ctx, "GET", "/children", query, header, nil))
+}
+
+// CreateChild — POST /children: POST /children
+func (c *Client) CreateChild(ctx context.Context, body CreateChildRequest) (*Child, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Child](c.do(ctx, "POST", "/children", query, header, body))
+}
+
+// VerifyChildPin — POST /children/verify-pin: Check a child's PIN (parent)
+func (c *Client) VerifyChildPin(ctx context.Context, body ChildPinRequest) (*PinVerification, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*PinVerification](c.do(ctx, "POST", "/children/verify-pin", query, header, body))
+}
+
+// GetChildPoints — GET /children/{childId}/points: GET /children/{childId}/points
+func (c *Client) GetChildPoints(ctx context.Context, childID string) (*PointSummary, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*PointSummary](c.do(ctx, "GET", "/children/"+url.PathEscape(childID)+"/points", query, header, nil))
+}
+
+// ListChildRedemptions — GET /children/{childId}/redemptions: GET /children/{childId}/redemptions
+func (c *Client) ListChildRedemptions(ctx context.Context, childID string) ([]Redemption, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Redemption](c.do(ctx, "GET", "/children/"+url.PathEscape(childID)+"/redemptions", query, header, nil))
+}
+
+// UpdateChild — PUT /children/{id}: PUT /children/{id}
+func (c *Client) UpdateChild(ctx context.Context, id string, body UpdateChildRequest) (*Child, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Child](c.do(ctx, "PUT", "/children/"+url.PathEscape(id), query, header, body))
+}
+
+// DeleteChild — DELETE /children/{id}: DELETE /children/{id}
+func (c *Client) DeleteChild(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/children/"+url.PathEscape(id), query, header, nil)
+}
+
+// CompleteTaskParams holds the optional query and header parameters of CompleteTask.
+type CompleteTaskParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// CompleteTask — POST /completions: Complete a task as the signed-in child (idempotent)
+func (c *Client) CompleteTask(ctx context.Context, params *CompleteTaskParams, body CompletionRequest) (*Completion, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*Completion](c.do(ctx, "POST", "/completions", query, header, body))
+}
+
+// RedeemCoupon — POST /coupons/redeem: POST /coupons/redeem
+func (c *Client) RedeemCoupon(ctx context.Context, body CodeRequest) (*RedeemedCoupon, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*RedeemedCoupon](c.do(ctx, "POST", "/coupons/redeem", query, header, body))
+}
+
+// QuoteCoupon — POST /coupons/validate: POST /coupons/validate
+func (c *Client) QuoteCoupon(ctx context.Context, body CouponQuoteRequest) (*CouponQuote, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*CouponQuote](c.do(ctx, "POST", "/coupons/validate", query, header, body))
+}
+
+// StreamEventsParams holds the optional query and header parameters of StreamEvents.
+type StreamEventsParams struct {
+	AccessToken string // query "access_token"
+}
+
+// StreamEvents — GET /events/stream: Server-Sent Events for the caller's family
+func (c *Client) StreamEvents(ctx context.Context, params *StreamEventsParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.AccessToken != "" {
+			query.Set("access_token", params.AccessToken)
+		}
+	}
+	return c.do(ctx, "GET", "/events/stream", query, header, nil)
+}
+
+// ExportLogsParams holds the optional query and header parameters of ExportLogs.
+type ExportLogsParams struct {
+	From    string // query "from"
+	To      string // query "to"
+	ChildID string // query "childId"
+	Format  string // query "format"
+}
+
+// ExportLogs — GET /exports/logs: GET /exports/logs
+func (c *Client) ExportLogs(ctx context.Context, params *ExportLogsParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+		if params.ChildID != "" {
+			query.Set("childId", params.ChildID)
+		}
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+	}
+	return c.do(ctx, "GET", "/exports/logs", query, header, nil)
+}
+
+// ExportPointsParams holds the optional query and header parameters of ExportPoints.
+type ExportPointsParams struct {
+	From    string // query "from"
+	To      string // query "to"
+	ChildID string // query "childId"
+	Format  string // query "format"
+}
+
+// ExportPoints — GET /exports/points: GET /exports/points
+func (c *Client) ExportPoints(ctx context.Context, params *ExportPointsParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+		if params.ChildID != "" {
+			query.Set("childId", params.ChildID)
+		}
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+	}
+	return c.do(ctx, "GET", "/exports/points", query, header, nil)
+}
+
+// ExportRedemptionsParams holds the optional query and header parameters of ExportRedemptions.
+type ExportRedemptionsParams struct {
+	From    string // query "from"
+	To      string // query "to"
+	ChildID string // query "childId"
+	Format  string // query "format"
+}
+
+// ExportRedemptions — GET /exports/redemptions: GET /exports/redemptions
+func (c *Client) ExportRedemptions(ctx context.Context, params *ExportRedemptionsParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+		if params.ChildID != "" {
+			query.Set("childId", params.ChildID)
+		}
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+	}
+	return c.do(ctx, "GET", "/exports/redemptions", query, header, nil)
+}
+
+// GetFamilyChildren — GET /families/{slug}/children: Children shown on the child gate
+func (c *Client) GetFamilyChildren(ctx context.Context, slug string) (*FamilyChildren, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*FamilyChildren](c.do(ctx, "GET", "/families/"+url.PathEscape(slug)+"/children", query, header, nil))
+}
+
+// ExportArchive — GET /family/archive: Download the family archive (parent)
+func (c *Client) ExportArchive(ctx context.Context) (*FamilyArchive, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*FamilyArchive](c.do(ctx, "GET", "/family/archive", query, header, nil))
+}
+
+// GetDeletion — GET /family/deletion: GET /family/deletion
+func (c *Client) GetDeletion(ctx context.Context) (*AccountDeletion, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*AccountDeletion](c.do(ctx, "GET", "/family/deletion", query, header, nil))
+}
+
+// RequestDeletion — POST /family/deletion: Request account deletion; a confirmation code is e-mailed
+func (c *Client) RequestDeletion(ctx context.Context) (*DeletionStatus, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*DeletionStatus](c.do(ctx, "POST", "/family/deletion", query, header, nil))
+}
+
+// CancelDeletion — DELETE /family/deletion: DELETE /family/deletion
+func (c *Client) CancelDeletion(ctx context.Context) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "DELETE", "/family/deletion", query, header, nil))
+}
+
+// ConfirmDeletion — POST /family/deletion/confirm: POST /family/deletion/confirm
+func (c *Client) ConfirmDeletion(ctx context.Context, body ConfirmDeletionRequest) (*DeletionStatus, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*DeletionStatus](c.do(ctx, "POST", "/family/deletion/confirm", query, header, body))
+}
+
+// GetEntitlements — GET /family/entitlements: GET /family/entitlements
+func (c *Client) GetEntitlements(ctx context.Context) (*Entitlements, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Entitlements](c.do(ctx, "GET", "/family/entitlements", query, header, nil))
+}
+
+// GetFamilyPlan — GET /family/plan: GET /family/plan
+func (c *Client) GetFamilyPlan(ctx context.Context) (*PlanStatus, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*PlanStatus](c.do(ctx, "GET", "/family/plan", query, header, nil))
+}
+
+// GetFamilySettings — GET /family/settings: GET /family/settings
+func (c *Client) GetFamilySettings(ctx context.Context) (*Family, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Family](c.do(ctx, "GET", "/family/settings", query, header, nil))
+}
+
+// UpdateFamilySettings — PUT /family/settings: PUT /family/settings
+func (c *Client) UpdateFamilySettings(ctx context.Context, body FamilySettingsRequest) (*Family, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Family](c.do(ctx, "PUT", "/family/settings", query, header, body))
+}
+
+// ListGoals — GET /goals: GET /goals
+func (c *Client) ListGoals(ctx context.Context) ([]GoalProgress, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]GoalProgress](c.do(ctx, "GET", "/goals", query, header, nil))
+}
+
+// CreateGoal — POST /goals: POST /goals
+func (c *Client) CreateGoal(ctx context.Context, body GoalRequest) (*FamilyGoal, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*FamilyGoal](c.do(ctx, "POST", "/goals", query, header, body))
+}
+
+// UpdateGoal — PUT /goals/{id}: PUT /goals/{id}
+func (c *Client) UpdateGoal(ctx context.Context, id string, body GoalRequest) (*FamilyGoal, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*FamilyGoal](c.do(ctx, "PUT", "/goals/"+url.PathEscape(id), query, header, body))
+}
+
+// DeleteGoal — DELETE /goals/{id}: DELETE /goals/{id}
+func (c *Client) DeleteGoal(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/goals/"+url.PathEscape(id), query, header, nil)
+}
+
+// GetGoalProgress — GET /goals/{id}/progress: GET /goals/{id}/progress
+func (c *Client) GetGoalProgress(ctx context.Context, id string) (*GoalProgress, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*GoalProgress](c.do(ctx, "GET", "/goals/"+url.PathEscape(id)+"/progress", query, header, nil))
+}
+
+// KioskCompleteTaskParams holds the optional query and header parameters of KioskCompleteTask.
+type KioskCompleteTaskParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// KioskCompleteTask — POST /kiosk/completions: Complete a task for a child from the parent kiosk (idempotent)
+func (c *Client) KioskCompleteTask(ctx context.Context, params *KioskCompleteTaskParams, body KioskCompletionRequest) (*Completion, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*Completion](c.do(ctx, "POST", "/kiosk/completions", query, header, body))
+}
+
+// GetLeaderboardParams holds the optional query and header parameters of GetLeaderboard.
+type GetLeaderboardParams struct {
+	Period string // query "period"
+	Date   string // query "date"
+	RankBy string // query "rankBy"
+}
+
+// GetLeaderboard — GET /leaderboard: GET /leaderboard
+func (c *Client) GetLeaderboard(ctx context.Context, params *GetLeaderboardParams) (*Leaderboard, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.Period != "" {
+			query.Set("period", params.Period)
+		}
+		if params.Date != "" {
+			query.Set("date", params.Date)
+		}
+		if params.RankBy != "" {
+			query.Set("rankBy", params.RankBy)
+		}
+	}
+	return decode[*Leaderboard](c.do(ctx, "GET", "/leaderboard", query, header, nil))
+}
+
+// ListLogsParams holds the optional query and header parameters of ListLogs.
+type ListLogsParams struct {
+	ChildID string // query "childId"
+	Date    string // query "date"
+}
+
+// ListLogs — GET /logs: GET /logs
+func (c *Client) ListLogs(ctx context.Context, params *ListLogsParams) ([]DailyLog, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.ChildID != "" {
+			query.Set("childId", params.ChildID)
+		}
+		if params.Date != "" {
+			query.Set("date", params.Date)
+		}
+	}
+	return decode[[]DailyLog](c.do(ctx, "GET", "/logs", query, header, nil))
+}
+
+// SetDailyLogs — PUT /logs: Set completion counts per task for a child's day (parent)
+func (c *Client) SetDailyLogs(ctx context.Context, body SetDailyLogsRequest) (*DailyLogEdit, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*DailyLogEdit](c.do(ctx, "PUT", "/logs", query, header, body))
+}
+
+// UndoLog — POST /logs/{logId}/undo: Undo a completion and take its points back (parent)
+func (c *Client) UndoLog(ctx context.Context, logID string) (*UndoneLog, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*UndoneLog](c.do(ctx, "POST", "/logs/"+url.PathEscape(logID)+"/undo", query, header, nil))
+}
+
+// GetNotificationPreferences — GET /notifications/preferences: GET /notifications/preferences
+func (c *Client) GetNotificationPreferences(ctx context.Context) (*NotificationPreference, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*NotificationPreference](c.do(ctx, "GET", "/notifications/preferences", query, header, nil))
+}
+
+// UpdateNotificationPreferences — PUT /notifications/preferences: PUT /notifications/preferences
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, body NotificationPreferencesRequest) (*NotificationPreference, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*NotificationPreference](c.do(ctx, "PUT", "/notifications/preferences", query, header, body))
+}
+
+// SubscribePush — POST /notifications/subscriptions: POST /notifications/subscriptions
+func (c *Client) SubscribePush(ctx context.Context, body PushSubscriptionRequest) (*PushSubscription, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*PushSubscription](c.do(ctx, "POST", "/notifications/subscriptions", query, header, body))
+}
+
+// UnsubscribePush — DELETE /notifications/subscriptions: DELETE /notifications/subscriptions
+func (c *Client) UnsubscribePush(ctx context.Context, body PushEndpointRequest) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/notifications/subscriptions", query, header, body)
+}
+
+// SendTestNotification — POST /notifications/test: POST /notifications/test
+func (c *Client) SendTestNotification(ctx context.Context) (*TestNotification, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*TestNotification](c.do(ctx, "POST", "/notifications/test", query, header, nil))
+}
+
+// GetVapidKey — GET /notifications/vapid-key: GET /notifications/vapid-key
+func (c *Client) GetVapidKey(ctx context.Context) (*VapidKey, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*VapidKey](c.do(ctx, "GET", "/notifications/vapid-key", query, header, nil))
+}
+
+// GetWhatsappSettings — GET /notifications/whatsapp: GET /notifications/whatsapp
+func (c *Client) GetWhatsappSettings(ctx context.Context) (*WhatsappSettings, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WhatsappSettings](c.do(ctx, "GET", "/notifications/whatsapp", query, header, nil))
+}
+
+// UpdateWhatsappSettings — PUT /notifications/whatsapp: PUT /notifications/whatsapp
+func (c *Client) UpdateWhatsappSettings(ctx context.Context, body WhatsappSettingsRequest) (*WhatsappSettings, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WhatsappSettings](c.do(ctx, "PUT", "/notifications/whatsapp", query, header, body))
+}
+
+// GetOpenAPI — GET /openapi.json: This document as JSON
+func (c *Client) GetOpenAPI(ctx context.Context) (map[string]interface{}, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[map[string]interface{}](c.do(ctx, "GET", "/openapi.json", query, header, nil))
+}
+
+// GetOpenAPIYAML — GET /openapi.yaml: This document as YAML
+func (c *Client) GetOpenAPIYAML(ctx context.Context) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "GET", "/openapi.yaml", query, header, nil)
+}
+
+// ListPayments — GET /payments: GET /payments
+func (c *Client) ListPayments(ctx context.Context) ([]Payment, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Payment](c.do(ctx, "GET", "/payments", query, header, nil))
+}
+
+// Checkout — POST /payments/checkout: POST /payments/checkout
+func (c *Client) Checkout(ctx context.Context, body CheckoutRequest) (*Payment, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Payment](c.do(ctx, "POST", "/payments/checkout", query, header, body))
+}
+
+// MidtransNotification — POST /payments/midtrans/notification: Midtrans HTTP notification (verified by signature_key)
+func (c *Client) MidtransNotification(ctx context.Context) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "POST", "/payments/midtrans/notification", query, header, nil))
+}
+
+// ListPackages — GET /payments/packages: GET /payments/packages
+func (c *Client) ListPackages(ctx context.Context) ([]PlanPackage, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]PlanPackage](c.do(ctx, "GET", "/payments/packages", query, header, nil))
+}
+
+// GetPayment — GET /payments/{orderId}: GET /payments/{orderId}
+func (c *Client) GetPayment(ctx context.Context, orderID string) (*Payment, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Payment](c.do(ctx, "GET", "/payments/"+url.PathEscape(orderID), query, header, nil))
+}
+
+// ListPointRules — GET /point-rules: GET /point-rules
+func (c *Client) ListPointRules(ctx context.Context) ([]PointRule, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]PointRule](c.do(ctx, "GET", "/point-rules", query, header, nil))
+}
+
+// CreatePointRule — POST /point-rules: POST /point-rules
+func (c *Client) CreatePointRule(ctx context.Context, body PointRuleRequest) (*PointRule, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*PointRule](c.do(ctx, "POST", "/point-rules", query, header, body))
+}
+
+// UpdatePointRule — PUT /point-rules/{id}: PUT /point-rules/{id}
+func (c *Client) UpdatePointRule(ctx context.Context, id string, body PointRuleRequest) (*PointRule, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*PointRule](c.do(ctx, "PUT", "/point-rules/"+url.PathEscape(id), query, header, body))
+}
+
+// DeletePointRule — DELETE /point-rules/{id}: DELETE /point-rules/{id}
+func (c *Client) DeletePointRule(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/point-rules/"+url.PathEscape(id), query, header, nil)
+}
+
+// ListRedemptions — GET /redemptions: GET /redemptions
+func (c *Client) ListRedemptions(ctx context.Context) ([]Redemption, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Redemption](c.do(ctx, "GET", "/redemptions", query, header, nil))
+}
+
+// CreateRedemptionParams holds the optional query and header parameters of CreateRedemption.
+type CreateRedemptionParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// CreateRedemption — POST /redemptions: Request a reward (idempotent)
+func (c *Client) CreateRedemption(ctx context.Context, params *CreateRedemptionParams, body RedemptionRequest) (*Redemption, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*Redemption](c.do(ctx, "POST", "/redemptions", query, header, body))
+}
+
+// UpdateRedemptionStatusParams holds the optional query and header parameters of UpdateRedemptionStatus.
+type UpdateRedemptionStatusParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// UpdateRedemptionStatus — PUT /redemptions/{id}/status: Approve or reject a redemption (idempotent)
+func (c *Client) UpdateRedemptionStatus(ctx context.Context, id string, params *UpdateRedemptionStatusParams, body DecisionRequest) (*Redemption, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*Redemption](c.do(ctx, "PUT", "/redemptions/"+url.PathEscape(id)+"/status", query, header, body))
+}
+
+// GetReferral — GET /referral: GET /referral
+func (c *Client) GetReferral(ctx context.Context) (*ReferralInfo, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*ReferralInfo](c.do(ctx, "GET", "/referral", query, header, nil))
+}
+
+// ClaimReferral — POST /referral/claim: POST /referral/claim
+func (c *Client) ClaimReferral(ctx context.Context, body CodeRequest) (*Message, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Message](c.do(ctx, "POST", "/referral/claim", query, header, body))
+}
+
+// GetReportParams holds the optional query and header parameters of GetReport.
+type GetReportParams struct {
+	Period string // query "period"
+	Date   string // query "date"
+	Format string // query "format"
+}
+
+// GetReport — GET /reports/{childId}: Rapor Ramadhan as HTML or PDF (parent)
+func (c *Client) GetReport(ctx context.Context, childID string, params *GetReportParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.Period != "" {
+			query.Set("period", params.Period)
+		}
+		if params.Date != "" {
+			query.Set("date", params.Date)
+		}
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+	}
+	return c.do(ctx, "GET", "/reports/"+url.PathEscape(childID), query, header, nil)
+}
+
+// SendReport — POST /reports/{childId}/send: POST /reports/{childId}/send
+func (c *Client) SendReport(ctx context.Context, childID string, body *SendReportRequest) (*ReportSent, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*ReportSent](c.do(ctx, "POST", "/reports/"+url.PathEscape(childID)+"/send", query, header, body))
+}
+
+// ListRewards — GET /rewards: GET /rewards
+func (c *Client) ListRewards(ctx context.Context) ([]Reward, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Reward](c.do(ctx, "GET", "/rewards", query, header, nil))
+}
+
+// CreateReward — POST /rewards: POST /rewards
+func (c *Client) CreateReward(ctx context.Context, body CreateRewardRequest) (*Reward, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Reward](c.do(ctx, "POST", "/rewards", query, header, body))
+}
+
+// ApplyRewardTemplateParams holds the optional query and header parameters of ApplyRewardTemplate.
+type ApplyRewardTemplateParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// ApplyRewardTemplate — POST /rewards/templates/apply: Add a preset reward list (parent, idempotent)
+func (c *Client) ApplyRewardTemplate(ctx context.Context, params *ApplyRewardTemplateParams, body RewardTemplateRequest) (*AppliedRewards, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*AppliedRewards](c.do(ctx, "POST", "/rewards/templates/apply", query, header, body))
+}
+
+// UpdateReward — PUT /rewards/{id}: PUT /rewards/{id}
+func (c *Client) UpdateReward(ctx context.Context, id string, body UpdateRewardRequest) (*Reward, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Reward](c.do(ctx, "PUT", "/rewards/"+url.PathEscape(id), query, header, body))
+}
+
+// DeleteReward — DELETE /rewards/{id}: DELETE /rewards/{id}
+func (c *Client) DeleteReward(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/rewards/"+url.PathEscape(id), query, header, nil)
+}
+
+// PushSyncEvents — POST /sync: Apply events recorded offline
+func (c *Client) PushSyncEvents(ctx context.Context, body SyncPushRequest) (*SyncPushResult, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*SyncPushResult](c.do(ctx, "POST", "/sync", query, header, body))
+}
+
+// GetSyncChangesParams holds the optional query and header parameters of GetSyncChanges.
+type GetSyncChangesParams struct {
+	Since string // query "since"
+}
+
+// GetSyncChanges — GET /sync/changes: GET /sync/changes
+func (c *Client) GetSyncChanges(ctx context.Context, params *GetSyncChangesParams) (*SyncChanges, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.Since != "" {
+			query.Set("since", params.Since)
+		}
+	}
+	return decode[*SyncChanges](c.do(ctx, "GET", "/sync/changes", query, header, nil))
+}
+
+// ListTasks — GET /tasks: GET /tasks
+func (c *Client) ListTasks(ctx context.Context) ([]Task, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]Task](c.do(ctx, "GET", "/tasks", query, header, nil))
+}
+
+// CreateTask — POST /tasks: POST /tasks
+func (c *Client) CreateTask(ctx context.Context, body CreateTaskRequest) (*Task, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Task](c.do(ctx, "POST", "/tasks", query, header, body))
+}
+
+// ApplyTaskTemplateParams holds the optional query and header parameters of ApplyTaskTemplate.
+type ApplyTaskTemplateParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// ApplyTaskTemplate — POST /tasks/templates/apply: Add a preset task list (parent, idempotent)
+func (c *Client) ApplyTaskTemplate(ctx context.Context, params *ApplyTaskTemplateParams, body TaskTemplateRequest) (*AppliedTasks, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*AppliedTasks](c.do(ctx, "POST", "/tasks/templates/apply", query, header, body))
+}
+
+// UpdateTask — PUT /tasks/{id}: PUT /tasks/{id}
+func (c *Client) UpdateTask(ctx context.Context, id string, body UpdateTaskRequest) (*Task, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Task](c.do(ctx, "PUT", "/tasks/"+url.PathEscape(id), query, header, body))
+}
+
+// DeleteTask — DELETE /tasks/{id}: DELETE /tasks/{id}
+func (c *Client) DeleteTask(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/tasks/"+url.PathEscape(id), query, header, nil)
+}
+
+// ListTemplatesParams holds the optional query and header parameters of ListTemplates.
+type ListTemplatesParams struct {
+	AgeBand string // query "ageBand"
+}
+
+// ListTemplates — GET /templates: Built-in catalog and the family's own templates
+func (c *Client) ListTemplates(ctx context.Context, params *ListTemplatesParams) ([]Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.AgeBand != "" {
+			query.Set("ageBand", params.AgeBand)
+		}
+	}
+	return decode[[]Template](c.do(ctx, "GET", "/templates", query, header, nil))
+}
+
+// CreateTemplate — POST /templates: POST /templates
+func (c *Client) CreateTemplate(ctx context.Context, body TemplateRequest) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "POST", "/templates", query, header, body))
+}
+
+// CreateTemplateFromFamily — POST /templates/from-family: Save the family's tasks, rewards, point rules and badges as a template
+func (c *Client) CreateTemplateFromFamily(ctx context.Context, body TemplateFromFamilyRequest) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "POST", "/templates/from-family", query, header, body))
+}
+
+// GetSharedTemplate — GET /templates/shared/{code}: Look up a template another family shared
+func (c *Client) GetSharedTemplate(ctx context.Context, code string) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "GET", "/templates/shared/"+url.PathEscape(code), query, header, nil))
+}
+
+// GetTemplate — GET /templates/{id}: GET /templates/{id}
+func (c *Client) GetTemplate(ctx context.Context, id string) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "GET", "/templates/"+url.PathEscape(id), query, header, nil))
+}
+
+// UpdateTemplate — PUT /templates/{id}: Change one of the family's templates (built-in ones are read-only)
+func (c *Client) UpdateTemplate(ctx context.Context, id string, body TemplateRequest) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "PUT", "/templates/"+url.PathEscape(id), query, header, body))
+}
+
+// DeleteTemplate — DELETE /templates/{id}: DELETE /templates/{id}
+func (c *Client) DeleteTemplate(ctx context.Context, id string) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return c.do(ctx, "DELETE", "/templates/"+url.PathEscape(id), query, header, nil)
+}
+
+// ApplyTemplateParams holds the optional query and header parameters of ApplyTemplate.
+type ApplyTemplateParams struct {
+	IdempotencyKey string // header "Idempotency-Key"
+}
+
+// ApplyTemplate — POST /templates/{id}/apply: Apply the template; items applied before are not duplicated (idempotent)
+func (c *Client) ApplyTemplate(ctx context.Context, id string, params *ApplyTemplateParams, body *TemplateSelection) (*TemplatePlan, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.IdempotencyKey != "" {
+			header.Set("Idempotency-Key", params.IdempotencyKey)
+		}
+	}
+	return decode[*TemplatePlan](c.do(ctx, "POST", "/templates/"+url.PathEscape(id)+"/apply", query, header, body))
+}
+
+// PreviewTemplate — POST /templates/{id}/preview: What applying the template would create, link or skip
+func (c *Client) PreviewTemplate(ctx context.Context, id string, body *TemplateSelection) (*TemplatePlan, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*TemplatePlan](c.do(ctx, "POST", "/templates/"+url.PathEscape(id)+"/preview", query, header, body))
+}
+
+// ShareTemplate — POST /templates/{id}/share: Give the template a share code
+func (c *Client) ShareTemplate(ctx context.Context, id string) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "POST", "/templates/"+url.PathEscape(id)+"/share", query, header, nil))
+}
+
+// UnshareTemplate — DELETE /templates/{id}/share: DELETE /templates/{id}/share
+func (c *Client) UnshareTemplate(ctx context.Context, id string) (*Template, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Template](c.do(ctx, "DELETE", "/templates/"+url.PathEscape(id)+"/share", query, header, nil))
+}
+
+// ListWallets — GET /wallets: GET /wallets
+func (c *Client) ListWallets(ctx context.Context) ([]WalletSummary, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[[]WalletSummary](c.do(ctx, "GET", "/wallets", query, header, nil))
+}
+
+// UpdateCashoutStatus — PUT /wallets/cashouts/{id}/status: PUT /wallets/cashouts/{id}/status
+func (c *Client) UpdateCashoutStatus(ctx context.Context, id string, body DecisionRequest) (*WalletTransaction, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WalletTransaction](c.do(ctx, "PUT", "/wallets/cashouts/"+url.PathEscape(id)+"/status", query, header, body))
+}
+
+// GetWallet — GET /wallets/{childId}: GET /wallets/{childId}
+func (c *Client) GetWallet(ctx context.Context, childID string) (*WalletSummary, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WalletSummary](c.do(ctx, "GET", "/wallets/"+url.PathEscape(childID), query, header, nil))
+}
+
+// ConfigureWallet — PUT /wallets/{childId}: PUT /wallets/{childId}
+func (c *Client) ConfigureWallet(ctx context.Context, childID string, body WalletSettingsRequest) (*Wallet, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*Wallet](c.do(ctx, "PUT", "/wallets/"+url.PathEscape(childID), query, header, body))
+}
+
+// RequestCashout — POST /wallets/{childId}/cashouts: POST /wallets/{childId}/cashouts
+func (c *Client) RequestCashout(ctx context.Context, childID string, body CashoutRequest) (*WalletTransaction, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WalletTransaction](c.do(ctx, "POST", "/wallets/"+url.PathEscape(childID)+"/cashouts", query, header, body))
+}
+
+// RecordPayout — POST /wallets/{childId}/payouts: POST /wallets/{childId}/payouts
+func (c *Client) RecordPayout(ctx context.Context, childID string, body PayoutRequest) (*WalletTransaction, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WalletTransaction](c.do(ctx, "POST", "/wallets/"+url.PathEscape(childID)+"/payouts", query, header, body))
+}
+
+// GrantTHR — POST /wallets/{childId}/thr: Grant the Lebaran bonus for the family's season (once per season)
+func (c *Client) GrantTHR(ctx context.Context, childID string) (*WalletTransaction, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	return decode[*WalletTransaction](c.do(ctx, "POST", "/wallets/"+url.PathEscape(childID)+"/thr", query, header, nil))
+}
+
+// VerifyWhatsappWebhookParams holds the optional query and header parameters of VerifyWhatsappWebhook.
+type VerifyWhatsappWebhookParams struct {
+	HubMode        string // query "hub.mode"
+	HubVerifyToken string // query "hub.verify_token"
+	HubChallenge   string // query "hub.challenge"
+}
+
+// VerifyWhatsappWebhook — GET /whatsapp/webhook: GET /whatsapp/webhook
+func (c *Client) VerifyWhatsappWebhook(ctx context.Context, params *VerifyWhatsappWebhookParams) (*Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.HubMode != "" {
+			query.Set("hub.mode", params.HubMode)
+		}
+		if params.HubVerifyToken != "" {
+			query.Set("hub.verify_token", params.HubVerifyToken)
+		}
+		if params.HubChallenge != "" {
+			query.Set("hub.challenge", params.HubChallenge)
+		}
+	}
+	return c.do(ctx, "GET", "/whatsapp/webhook", query, header, nil)
+}
+
+// WhatsappInboundParams holds the optional query and header parameters of WhatsappInbound.
+type WhatsappInboundParams struct {
+	Token string // query "token"
+}
+
+// WhatsappInbound — POST /whatsapp/webhook: Inbound WhatsApp message (STOP/BERHENTI opt out, MULAI/START opt in)
+func (c *Client) WhatsappInbound(ctx context.Context, params *WhatsappInboundParams) (*WhatsappInbound, *Response, error) {
+	query := url.Values{}
+	header := http.Header{}
+	if params != nil {
+		if params.Token != "" {
+			query.Set("token", params.Token)
+		}
+	}
+	return decode[*WhatsappInbound](c.do(ctx, "POST", "/whatsapp/webhook", query, header, nil))
+}
+
+type AnalyticsReportSummary struct {
+	Completions   int `json:"completions"`
+	PointsEarned  int `json:"pointsEarned"`
+	PointsSpent   int `json:"pointsSpent"`
+	TotalChildren int `json:"totalChildren"`
+	TotalRewards  int `json:"totalRewards"`
+	TotalTasks    int `json:"totalTasks"`
+}
+
+type BadgeProgressChildrenItem struct {
+	Avatar    string `json:"avatar"`
+	ChildID   string `json:"childId"`
+	ChildName string `json:"childName"`
+	Earned    bool   `json:"earned"`
+	Percent   int    `json:"percent"`
+	Value     int    `json:"value"`
+}
+
+type CreatedFamilyParent struct {
+	Email string `json:"email"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+}
+
+type GoalProgressContributionsItem struct {
+	Avatar    string `json:"avatar"`
+	ChildID   string `json:"childId"`
+	ChildName string `json:"childName"`
+	Value     int    `json:"value"`
+}
+
+type PushSubscriptionRequestKeys struct {
+	Auth   string `json:"auth"`
+	P256dh string `json:"p256dh"`
+}
+
+type ReferralInfoInvitesItem struct {
+	CreatedAt  string  `json:"CreatedAt"`
+	FamilyName string  `json:"FamilyName"`
+	RewardedAt *string `json:"RewardedAt,omitempty"`
+	Status     string  `json:"Status"`
+}
+
+type ReferralStatsTopReferrersItem struct {
+	FamilyID   string `json:"FamilyID"`
+	FamilyName string `json:"FamilyName"`
+	Invites    int    `json:"Invites"`
+	Rewarded   int    `json:"Rewarded"`
+}
+
+type SetDailyLogsRequestTasksItem struct {
+	Count  int    `json:"count"`
+	TaskID string `json:"taskId"`
+}
+
+type SyncChangesDeleted struct {
+	Logs        []string `json:"logs"`
+	Redemptions []string `json:"redemptions"`
+	Rewards     []string `json:"rewards"`
+	Tasks       []string `json:"tasks"`
+}
+
+type TemplatePlanItemsItem struct {
+	Code     *string `json:"code,omitempty"`
+	Key      string  `json:"key"`
+	Kind     string  `json:"kind"`
+	Name     string  `json:"name"`
+	Reason   *string `json:"reason,omitempty"`
+	Status   string  `json:"status"`
+	TargetID *string `json:"targetId,omitempty"`
+}
@@ -0,0 +1,138 @@
+// Package client is a Go client for /api/v1, generated from
+// internal/openapi/openapi.yaml. Request types and one method per operation
+// live in client.gen.go; regenerate it with `go generate ./client` after
+// changing the spec. Operations with a JSON response return it decoded
+// along with the raw Response.
+//
+//	c := client.New("https://api.example.com/api/v1", nil)
+//	login, _, err := c.Login(ctx, client.LoginRequest{Email: email, Password: password})
+//	c.Token = login.Token
+package client
+
+//go:generate go run ./gen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Client calls one API server. Token, when set, is sent as a bearer token;
+// Language ("id" or "en") picks the language of error messages.
+type Client struct {
+	BaseURL    string
+	Token      string
+	Language   string
+	HTTPClient *http.Client
+}
+
+// New returns a client for baseURL, the /api/v1 root. A nil httpClient means
+// http.DefaultClient.
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: httpClient}
+}
+
+// Response is a successful (2xx or 3xx) response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode unmarshals the JSON body into v.
+func (r *Response) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// decode unmarshals the body of a successful call into a T.
+func decode[T any](res *Response, err error) (T, *Response, error) {
+	var v T
+	if err != nil {
+		return v, res, err
+	}
+	if err := res.Decode(&v); err != nil {
+		return v, res, err
+	}
+	return v, res, nil
+}
+
+// APIError is a 4xx or 5xx response. Code is the stable error code
+// ("task_not_found"); Details is set for validation failures.
+type APIError struct {
+	StatusCode int
+	Code       string       `json:"code"`
+	Message    string       `json:"message"`
+	Details    []FieldError `json:"details"`
+}
+
+func (e *APIError) Error() string {
+	if len(e.Details) == 0 {
+		return fmt.Sprintf("api: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	parts := make([]string, len(e.Details))
+	for i, d := range e.Details {
+		parts[i] = d.Message
+	}
+	return fmt.Sprintf("api: %d %s: %s", e.StatusCode, e.Code, strings.Join(parts, "; "))
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body interface{}) (*Response, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if v := reflect.ValueOf(body); body != nil && !(v.Kind() == reflect.Ptr && v.IsNil()) {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Language != "" {
+		req.Header.Set("Accept-Language", c.Language)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: res.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(res.StatusCode)
+		}
+		return nil, apiErr
+	}
+	return &Response{StatusCode: res.StatusCode, Header: res.Header, Body: data}, nil
+}
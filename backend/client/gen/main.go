@@ -0,0 +1,318 @@
+// Command gen writes client.gen.go from the embedded OpenAPI document.
+// Run it through go generate in the client package.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/username/ramadhan-ceria-backend/internal/openapi"
+)
+
+var methodOrder = map[string]int{"GET": 0, "POST": 1, "PUT": 2, "PATCH": 3, "DELETE": 4}
+
+func main() {
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{spec: spec, types: map[string]string{}}
+	g.printf("// Code generated by client/gen from internal/openapi/openapi.yaml. DO NOT EDIT.\n\n")
+	g.printf("package client\n\n")
+	g.printf("import (\n\t\"context\"\n\t\"net/http\"\n\t\"net/url\"\n)\n\n")
+
+	names := make([]string, 0, len(spec.Components.Schemas))
+	for name := range spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.namedType(name, spec.Components.Schemas[name])
+	}
+
+	var ops []*openapi.Operation
+	for _, item := range spec.Paths {
+		for _, op := range item {
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return methodOrder[ops[i].Method] < methodOrder[ops[j].Method]
+	})
+	for _, op := range ops {
+		g.operation(op)
+	}
+	g.buf.WriteString(g.extra.String())
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v\n%s", err, g.buf.String())
+	}
+	if err := os.WriteFile("client.gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	spec  *openapi.Spec
+	buf   bytes.Buffer
+	extra bytes.Buffer // types named after inline schemas
+	types map[string]string
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) namedType(name string, schema *openapi.Schema) {
+	g.printf("type %s %s\n\n", name, g.goType(name, schema, &g.buf))
+}
+
+// goType returns the Go type for schema. Inline objects with properties
+// become named types (prefix + field name) written to out.
+func (g *generator) goType(prefix string, schema *openapi.Schema, out *bytes.Buffer) string {
+	if schema == nil {
+		return "interface{}"
+	}
+	if schema.Ref != "" {
+		return strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+	}
+	switch schema.Type {
+	case "object":
+		if len(schema.Properties) == 0 {
+			var additional openapi.Schema
+			if json.Unmarshal(schema.AdditionalProperties, &additional) == nil && additional.Type != "" {
+				value := g.goType(prefix+"Value", &additional, out)
+				if additional.Nullable {
+					value = "*" + value
+				}
+				return "map[string]" + value
+			}
+			return "map[string]interface{}"
+		}
+		return g.structType(prefix, schema)
+	case "array":
+		return "[]" + g.goType(prefix+"Item", schema.Items, out)
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return "interface{}"
+}
+
+func (g *generator) structType(prefix string, schema *openapi.Schema) string {
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, name := range names {
+		prop := schema.Properties[name]
+		field := exported(name)
+		typ := g.inlineType(prefix+field, prop)
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+			if !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") {
+				typ = "*" + typ
+			}
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// inlineType is goType, but an inline object with properties gets its own
+// named type instead of an anonymous struct.
+func (g *generator) inlineType(name string, schema *openapi.Schema) string {
+	if schema != nil && schema.Ref == "" && schema.Type == "object" && len(schema.Properties) > 0 {
+		if _, ok := g.types[name]; !ok {
+			g.types[name] = name
+			fmt.Fprintf(&g.extra, "type %s %s\n\n", name, g.structType(name, schema))
+		}
+		return name
+	}
+	if schema != nil && schema.Ref == "" && schema.Type == "array" {
+		return "[]" + g.inlineType(name+"Item", schema.Items)
+	}
+	return g.goType(name, schema, &g.extra)
+}
+
+func (g *generator) operation(op *openapi.Operation) {
+	name := exported(op.OperationID)
+
+	var pathParams, queryParams, headerParams []*openapi.Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		case "header":
+			headerParams = append(headerParams, p)
+		}
+	}
+
+	paramsType := ""
+	if len(queryParams) > 0 || len(headerParams) > 0 {
+		paramsType = name + "Params"
+		g.printf("// %s holds the optional query and header parameters of %s.\n", paramsType, name)
+		g.printf("type %s struct {\n", paramsType)
+		for _, p := range append(append([]*openapi.Parameter{}, queryParams...), headerParams...) {
+			g.printf("\t%s string // %s %q\n", exported(p.Name), p.In, p.Name)
+		}
+		g.printf("}\n\n")
+	}
+
+	var bodyType string
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			bodyType = g.goType(name+"Body", media.Schema, &g.extra)
+		}
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, lowerFirst(exported(p.Name))+" string")
+	}
+	if paramsType != "" {
+		args = append(args, "params *"+paramsType)
+	}
+	if bodyType != "" {
+		if op.RequestBody.Required {
+			args = append(args, "body "+bodyType)
+		} else {
+			args = append(args, "body *"+bodyType)
+		}
+	}
+
+	resultType := g.resultType(name, op)
+
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	g.printf("// %s — %s %s: %s\n", name, op.Method, op.Path, summary)
+	if resultType != "" {
+		g.printf("func (c *Client) %s(%s) (%s, *Response, error) {\n", name, strings.Join(args, ", "), resultType)
+	} else {
+		g.printf("func (c *Client) %s(%s) (*Response, error) {\n", name, strings.Join(args, ", "))
+	}
+
+	path := fmt.Sprintf("%q", op.Path)
+	for _, p := range pathParams {
+		path = strings.Replace(path, "{"+p.Name+"}", `" + url.PathEscape(`+lowerFirst(exported(p.Name))+`) + "`, 1)
+	}
+	path = strings.TrimSuffix(strings.TrimPrefix(path, `"" + `), ` + ""`)
+
+	g.printf("\tquery := url.Values{}\n")
+	g.printf("\theader := http.Header{}\n")
+	if paramsType != "" {
+		g.printf("\tif params != nil {\n")
+		for _, p := range queryParams {
+			g.printf("\t\tif params.%s != \"\" {\n\t\t\tquery.Set(%q, params.%s)\n\t\t}\n", exported(p.Name), p.Name, exported(p.Name))
+		}
+		for _, p := range headerParams {
+			g.printf("\t\tif params.%s != \"\" {\n\t\t\theader.Set(%q, params.%s)\n\t\t}\n", exported(p.Name), p.Name, exported(p.Name))
+		}
+		g.printf("\t}\n")
+	}
+	body := "nil"
+	if bodyType != "" {
+		body = "body"
+	}
+	if resultType != "" {
+		g.printf("\treturn decode[%s](c.do(ctx, %q, %s, query, header, %s))\n", resultType, op.Method, path, body)
+	} else {
+		g.printf("\treturn c.do(ctx, %q, %s, query, header, %s)\n", op.Method, path, body)
+	}
+	g.printf("}\n\n")
+}
+
+// resultType is the Go type of the first 2xx JSON response of op, or "" when
+// it has none (204, redirects, files). Objects are returned by pointer.
+func (g *generator) resultType(name string, op *openapi.Operation) string {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		media, ok := op.Responses[code].Content["application/json"]
+		if !ok || media.Schema == nil {
+			continue
+		}
+		typ := g.inlineType(name+"Result", media.Schema)
+		if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "interface{}" {
+			return typ
+		}
+		return "*" + typ
+	}
+	return ""
+}
+
+// exported turns "child-login", "hub.verify_token" or "Idempotency-Key" into
+// a Go identifier: ChildLogin, HubVerifyToken, IdempotencyKey.
+func exported(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	for _, initialism := range []string{"Id", "Url", "Pdf", "Thr", "Vapid", "Json", "Yaml", "Api"} {
+		if strings.HasSuffix(s, initialism) {
+			s = strings.TrimSuffix(s, initialism) + strings.ToUpper(initialism)
+		}
+	}
+	return s
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	for i, r := range s {
+		if !unicode.IsUpper(r) {
+			if i > 1 {
+				i-- // keep the last capital of an initialism: IDValue → idValue
+			}
+			return strings.ToLower(s[:i]) + s[i:]
+		}
+	}
+	return strings.ToLower(s)
+}
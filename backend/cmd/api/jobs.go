@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/jobs"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+// registerJobs declares every periodic job. Schedules are in WIB.
+func registerJobs(s *jobs.Scheduler, deletionService *services.AccountDeletionService, reportService *services.ReportService, planService *services.PlanService, notificationService *services.NotificationService, whatsappService *services.WhatsappService, idempotencyService *services.IdempotencyService) error {
+	all := []jobs.Job{
+		{
+			// Executes confirmed account deletions once their cooling-off period is over.
+			Name:     "account-deletions",
+			Schedule: "0 * * * *",
+			Run: func(ctx context.Context) error {
+				_, err := deletionService.ProcessDueDeletions()
+				return err
+			},
+		},
+		{
+			// Weekly rapor e-mail to parents, Sunday evening after Maghrib.
+			Name:     "weekly-digest",
+			Schedule: "0 19 * * 0",
+			Timeout:  30 * time.Minute,
+			Run: func(ctx context.Context) error {
+				return reportService.SendAllDigests(ctx, "week")
+			},
+		},
+		{
+			// Season rapor on the last day of each family's Ramadhan, 19:00 local time.
+			Name:     "season-end-digest",
+			Schedule: "10 * * * *",
+			Timeout:  30 * time.Minute,
+			Run:      reportService.SendSeasonEndDigests,
+		},
+		{
+			// Expiry reminders 7 days and 1 day before, and when the grace period starts.
+			Name:     "plan-expiry-reminders",
+			Schedule: "0 8 * * *",
+			Run:      planService.SendExpiryReminders,
+		},
+		{
+			// Back to FREE once the grace period is over; excess items become read-only.
+			Name:     "plan-downgrades",
+			Schedule: "15 * * * *",
+			Run: func(ctx context.Context) error {
+				_, err := planService.DowngradeExpired(ctx)
+				return err
+			},
+		},
+		{
+			// Safety net for the push outbox: retries and anything the in-process kick missed.
+			Name:     "push-outbox",
+			Schedule: "* * * * *",
+			Timeout:  55 * time.Second,
+			Run:      notificationService.DeliverOutbox,
+		},
+		{
+			// Checks every 5 minutes around sunset (WIB hours cover WITA and WIT); each family gets it 10 minutes before Maghrib.
+			Name:     "maghrib-reminders",
+			Schedule: "*/5 15-19 * * *",
+			Run:      notificationService.SendMaghribReminders,
+		},
+		{
+			// Hourly so every timezone gets its warning at 19:00 local time.
+			Name:     "streak-warnings",
+			Schedule: "0 * * * *",
+			Run:      notificationService.SendStreakWarnings,
+		},
+		{
+			// Hourly so every timezone gets its summary at 20:00 local time.
+			Name:     "whatsapp-daily-summary",
+			Schedule: "5 * * * *",
+			Run:      whatsappService.SendDailySummaries,
+		},
+		{
+			Name:     "push-outbox-cleanup",
+			Schedule: "45 3 * * *",
+			Run:      notificationService.PruneOutbox(14 * 24 * time.Hour),
+		},
+		{
+			Name:     "idempotency-keys-cleanup",
+			Schedule: "50 3 * * *",
+			Run:      idempotencyService.Prune,
+		},
+		{
+			Name:     "job-history-cleanup",
+			Schedule: "30 3 * * *",
+			Run:      s.PruneHistory(30 * 24 * time.Hour),
+		},
+	}
+
+	for _, job := range all {
+		if err := s.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,125 +1,380 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/joho/godotenv"
+	"github.com/username/ramadhan-ceria-backend/internal/config"
 	"github.com/username/ramadhan-ceria-backend/internal/controllers"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/events"
 	"github.com/username/ramadhan-ceria-backend/internal/handlers"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/i18n"
+	"github.com/username/ramadhan-ceria-backend/internal/jobs"
+	"github.com/username/ramadhan-ceria-backend/internal/mailer"
 	"github.com/username/ramadhan-ceria-backend/internal/middleware"
+	"github.com/username/ramadhan-ceria-backend/internal/openapi"
+	"github.com/username/ramadhan-ceria-backend/internal/payments"
 	"github.com/username/ramadhan-ceria-backend/internal/services"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/webpush"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "optional YAML config file; .env and the environment override it")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted, then exit")
+	flag.Parse()
+
+	if *printConfig {
+		os.Exit(printEffectiveConfig(*configFile))
+	}
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	database.ConnectDB(cfg.DB)
+	if err := database.RequireMigrated(); err != nil {
+		log.Fatal("Database schema is not up to date: ", err)
+	}
+
+	if missing := i18n.Missing(); len(missing) > 0 {
+		log.Fatalf("Untranslated messages: %v", missing)
 	}
 
-	database.ConnectDB()
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatal("Invalid OpenAPI spec:", err)
+	}
 
-	app := fiber.New()
-	app.Use(cors.New())
+	app := fiber.New(fiber.Config{
+		BodyLimit:    20 * 1024 * 1024, // family archives can be several MB
+		ErrorHandler: httperr.Handler,
+	})
+	app.Use(cors.New(cors.Config{AllowOrigins: strings.Join(cfg.CORSOrigins, ",")}))
 	app.Use(logger.New())
 
+	// Real-time family events, fanned out across instances through Postgres LISTEN/NOTIFY.
+	eventBus := events.New()
+	eventBus.Listen(context.Background(), database.DB, cfg.DB.DSN())
+	handlers.UseEvents(eventBus)
+
 	// Init Services
-	authService := services.NewAuthService()
-	taskService := services.NewTaskService()
-	logService := services.NewLogService()
+	tokens := utils.NewJWT(cfg.JWT.Secret)
+	handlers.UseAuth(tokens, utils.GoogleOAuthConfig(cfg.Google), cfg.FrontendURL)
+	authService := services.NewAuthService(tokens)
+	taskService := services.NewTaskService(eventBus)
+	logService := services.NewLogService(eventBus)
+	pushSender, err := webpush.SenderFromConfig(cfg.VAPID)
+	if err != nil {
+		log.Fatal("Invalid VAPID configuration:", err)
+	}
+	if pushSender == nil {
+		log.Println("VAPID_PRIVATE_KEY not set, push notifications are disabled")
+	}
+	notificationService := services.NewNotificationService(pushSender)
+	notificationService.Start(context.Background())
+	whatsappProvider, err := whatsapp.FromConfig(cfg.WhatsApp)
+	if err != nil {
+		log.Fatal("Invalid WhatsApp configuration:", err)
+	}
+	whatsappService := services.NewWhatsappService(whatsappProvider, cfg.WhatsApp.WebhookToken)
+	redemptionService := services.NewRedemptionService(notificationService, whatsappService, eventBus)
+	handlers.UseRedemptions(redemptionService)
+	syncService := services.NewSyncService(taskService, redemptionService)
+
+	walletService := services.NewWalletService(notificationService)
+	pointRuleService := services.NewPointRuleService()
+	goalService := services.NewGoalService()
+	leaderboardService := services.NewLeaderboardService()
+	analyticsService := services.NewAnalyticsService()
+	reportService := services.NewReportService(mailer.FromConfig(cfg.SMTP))
+	exportService := services.NewExportService()
+	familyDataService := services.NewFamilyDataService()
+	deletionService := services.NewAccountDeletionService(mailer.FromConfig(cfg.SMTP))
+	planService := services.NewPlanService(mailer.FromConfig(cfg.SMTP), whatsappService)
+	paymentService := services.NewPaymentService(payments.NewClient(cfg.Midtrans))
+	entitlementService := services.NewEntitlementService()
+	promoService := services.NewPromoService()
+	idempotencyService := services.NewIdempotencyService()
+	templateService := services.NewTemplateService(entitlementService)
+	badgeService := services.NewBadgeService()
+
+	if err := templateService.SeedCatalog(); err != nil {
+		log.Fatal("Failed to seed template catalog:", err)
+	}
+
+	// Background jobs. Every replica may poll; SKIP LOCKED leases keep runs unique.
+	// RUN_JOBS=false turns polling off on this instance (admin endpoints still work).
+	scheduler := jobs.New(database.DB, utils.LoadLocation("Asia/Jakarta"))
+	if err := registerJobs(scheduler, deletionService, reportService, planService, notificationService, whatsappService, idempotencyService); err != nil {
+		log.Fatal("Failed to register jobs:", err)
+	}
+	if cfg.RunJobs {
+		if err := scheduler.Start(context.Background()); err != nil {
+			log.Fatal("Failed to start job scheduler:", err)
+		}
+	}
 
 	// Init Controllers
 	authController := controllers.NewAuthController(authService)
 	taskController := controllers.NewTaskController(taskService)
 	logController := controllers.NewLogController(logService)
+	walletController := controllers.NewWalletController(walletService)
+	pointRuleController := controllers.NewPointRuleController(pointRuleService)
+	goalController := controllers.NewGoalController(goalService)
+	templateController := controllers.NewTemplateController(templateService)
+	badgeController := controllers.NewBadgeController(badgeService)
+	leaderboardController := controllers.NewLeaderboardController(leaderboardService)
+	analyticsController := controllers.NewAnalyticsController(analyticsService)
+	reportController := controllers.NewReportController(reportService)
+	exportController := controllers.NewExportController(exportService)
+	familyDataController := controllers.NewFamilyDataController(familyDataService, deletionService, tokens)
+	jobController := controllers.NewJobController(scheduler)
+	planController := controllers.NewPlanController(planService)
+	paymentController := controllers.NewPaymentController(paymentService)
+	entitlementController := controllers.NewEntitlementController(entitlementService)
+	promoController := controllers.NewPromoController(promoService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	whatsappController := controllers.NewWhatsappController(whatsappService)
+	eventController := controllers.NewEventController(eventBus)
+	syncController := controllers.NewSyncController(syncService)
+	openAPIController := controllers.NewOpenAPIController(spec)
+
+	// Retries with the same Idempotency-Key replay the first response
+	idempotent := middleware.Idempotency(idempotencyService)
+	parent := middleware.ParentGuard()
+	entitled := func(name string) fiber.Handler { return middleware.RequireEntitlement(entitlementService, name) }
+
+	// Every endpoint lives under /api/v1 and is validated against openapi.yaml;
+	// the second path is the old unversioned route, kept as a deprecated alias.
+	routes := newRouter(app, spec)
+	routes.handle("GET", "/openapi.json", "", openAPIController.GetJSON)
+	routes.handle("GET", "/openapi.yaml", "", openAPIController.GetYAML)
 
 	// Public routes (Auth)
-	auth := app.Group("/api/auth")
-	auth.Post("/register", handlers.Register)
-	auth.Post("/login", handlers.Login)
-	auth.Get("/google", handlers.GoogleLogin)
-	auth.Get("/google/callback", handlers.GoogleCallback)
-	auth.Post("/child/login", authController.LoginChild) // Changed
-	auth.Get("/family/:slug/children", handlers.GetFamilyChildren)
+	routes.handle("POST", "/auth/register", "/api/auth/register", idempotent, handlers.Register)
+	routes.handle("POST", "/auth/login", "/api/auth/login", handlers.Login)
+	routes.handle("GET", "/auth/google", "/api/auth/google", handlers.GoogleLogin)
+	routes.handle("GET", "/auth/google/callback", "/api/auth/google/callback", handlers.GoogleCallback)
+	routes.handle("POST", "/auth/child-login", "/api/auth/child/login", authController.LoginChild)
+	routes.handle("GET", "/families/:slug/children", "/api/auth/family/:slug/children", handlers.GetFamilyChildren)
+	routes.handleRaw("POST", "/auth/import", "/api/auth/import", familyDataController.ImportArchive)
+
+	// Public: Midtrans HTTP notification (verified by signature_key)
+	routes.handleRaw("POST", "/payments/midtrans/notification", "/api/payments/midtrans/notification", paymentController.Notification)
+
+	// Public: WhatsApp inbound messages (opt-out replies), checked against WHATSAPP_WEBHOOK_TOKEN
+	routes.handleRaw("GET", "/whatsapp/webhook", "/api/whatsapp/webhook", whatsappController.VerifyWebhook)
+	routes.handleRaw("POST", "/whatsapp/webhook", "/api/whatsapp/webhook", whatsappController.Inbound)
+
+	// Real-time stream (SSE); the JWT may be passed as ?access_token= for EventSource
+	routes.handleRaw("GET", "/events/stream", "/api/events/stream", middleware.StreamAuthMiddleware(tokens), eventController.Stream)
 
 	// Protected Routes
-	api := app.Group("/api", middleware.AuthMiddleware())
+	api := routes.with(middleware.AuthMiddleware(tokens))
 
 	// Family Settings
-	family := api.Group("/family")
-	family.Get("/settings", handlers.GetFamilySettings)
-	family.Put("/settings", handlers.UpdateFamilySettings)
+	api.handle("GET", "/family/settings", "/api/family/settings", handlers.GetFamilySettings)
+	api.handle("PUT", "/family/settings", "/api/family/settings", handlers.UpdateFamilySettings)
+	api.handle("GET", "/family/plan", "/api/family/plan", planController.GetPlan)
+	api.handle("GET", "/family/entitlements", "/api/family/entitlements", entitlementController.GetEntitlements)
+	api.handle("GET", "/family/archive", "/api/family/archive", parent, familyDataController.ExportArchive)
+	api.handle("GET", "/family/deletion", "/api/family/deletion", parent, familyDataController.GetDeletion)
+	api.handle("POST", "/family/deletion", "/api/family/deletion", parent, familyDataController.RequestDeletion)
+	api.handle("POST", "/family/deletion/confirm", "/api/family/deletion/confirm", parent, familyDataController.ConfirmDeletion)
+	api.handle("DELETE", "/family/deletion", "/api/family/deletion", parent, familyDataController.CancelDeletion)
 
 	// Children Management (Parent role typically)
-	children := api.Group("/children")
-	children.Get("/", handlers.GetChildren)
-	children.Post("/", handlers.CreateChild)
-	children.Put("/:id", handlers.UpdateChild)
-	children.Delete("/:id", handlers.DeleteChild)
+	api.handle("GET", "/children", "/api/children", handlers.GetChildren)
+	api.handle("POST", "/children", "/api/children", entitled(services.LimitChildren), handlers.CreateChild)
+	api.handle("POST", "/children/verify-pin", "/api/parent/verify-pin", parent, handlers.VerifyChildPIN)
+	api.handle("PUT", "/children/:id", "/api/children/:id", handlers.UpdateChild)
+	api.handle("DELETE", "/children/:id", "/api/children/:id", handlers.DeleteChild)
 
 	// Task Management
-	tasks := api.Group("/tasks")
-	tasks.Get("/", handlers.GetTasks)
-	tasks.Post("/", handlers.CreateTask)
-	tasks.Put("/:id", handlers.UpdateTask)
-	tasks.Delete("/:id", handlers.DeleteTask)
-
-	// New Endpoints
-	app.Post("/api/child/tasks/complete", middleware.AuthMiddleware(), middleware.ChildGuard(), taskController.CompleteTask)
-	app.Post("/api/parent/kiosk/complete", middleware.AuthMiddleware(), middleware.ParentGuard(), taskController.KioskCompleteTask)
-	app.Post("/api/parent/verify-pin", middleware.AuthMiddleware(), middleware.ParentGuard(), handlers.VerifyChildPIN)
-	app.Post("/api/parent/tasks/magic", middleware.AuthMiddleware(), middleware.ParentGuard(), taskController.ApplyMagicTemplate)
-	app.Post("/api/parent/rewards/magic", middleware.AuthMiddleware(), middleware.ParentGuard(), handlers.ApplyRewardMagicTemplate)
+	api.handle("GET", "/tasks", "/api/tasks", handlers.GetTasks)
+	api.handle("POST", "/tasks", "/api/tasks", entitled(services.LimitTasks), handlers.CreateTask)
+	api.handle("POST", "/tasks/templates/apply", "/api/parent/tasks/magic", parent, idempotent, templateController.ApplyTaskPreset)
+	api.handle("PUT", "/tasks/:id", "/api/tasks/:id", handlers.UpdateTask)
+	api.handle("DELETE", "/tasks/:id", "/api/tasks/:id", handlers.DeleteTask)
+
+	// Completions (child app and parent kiosk)
+	api.handle("POST", "/completions", "/api/child/tasks/complete", middleware.ChildGuard(), idempotent, taskController.CompleteTask)
+	api.handle("POST", "/kiosk/completions", "/api/parent/kiosk/complete", parent, idempotent, taskController.KioskCompleteTask)
 
 	// Reward Management
-	rewards := api.Group("/rewards")
-	rewards.Get("/", handlers.GetRewards)
-	rewards.Post("/", handlers.CreateReward)
-	rewards.Put("/:id", handlers.UpdateReward)
-	rewards.Delete("/:id", handlers.DeleteReward)
+	api.handle("GET", "/rewards", "/api/rewards", handlers.GetRewards)
+	api.handle("POST", "/rewards", "/api/rewards", entitled(services.LimitRewards), handlers.CreateReward)
+	api.handle("POST", "/rewards/templates/apply", "/api/parent/rewards/magic", parent, idempotent, templateController.ApplyRewardPreset)
+	api.handle("PUT", "/rewards/:id", "/api/rewards/:id", handlers.UpdateReward)
+	api.handle("DELETE", "/rewards/:id", "/api/rewards/:id", handlers.DeleteReward)
 
 	// Daily Logs Management
-	logs := api.Group("/logs")
-	logs.Get("/", handlers.GetLogs)
-	logs.Post("/", handlers.SaveLogs)
-	app.Post("/api/parent/logs/:log_id/undo", middleware.AuthMiddleware(), middleware.ParentGuard(), logController.UndoTask)
+	api.handle("GET", "/logs", "/api/logs", handlers.GetLogs)
+	api.handle("PUT", "/logs", "", parent, logController.SaveLogs)
+	api.handle("POST", "/logs/:logId/undo", "/api/parent/logs/:logId/undo", parent, logController.UndoTask)
+	// The old body was { childId, date, logs: [{ taskId, quantity }] }.
+	app.Post("/api/logs", middleware.Deprecated(openapi.Prefix+"/logs"), middleware.LegacyNames("logs", "tasks", "quantity", "count"), middleware.AuthMiddleware(tokens), parent, logController.SaveLogs)
 
 	// Analytics Management
-	analytics := api.Group("/analytics")
-	analytics.Get("/", handlers.GetAnalytics)
+	api.handle("GET", "/analytics", "/api/analytics", entitled(services.FeatureAnalytics), analyticsController.GetAnalytics)
+
+	// Rapor Ramadhan (HTML / PDF digest)
+	reports := api.with(parent, entitled(services.FeatureReports))
+	reports.handle("GET", "/reports/:childId", "/api/reports/:childId", reportController.GetReport)
+	reports.handle("POST", "/reports/:childId/send", "/api/reports/:childId/send", reportController.SendReport)
+
+	// Exports (CSV / XLSX)
+	exportRoutes := api.with(parent, entitled(services.FeatureExports))
+	exportRoutes.handle("GET", "/exports/logs", "/api/exports/logs", exportController.ExportLogs)
+	exportRoutes.handle("GET", "/exports/redemptions", "/api/exports/redemptions", exportController.ExportRedemptions)
+	exportRoutes.handle("GET", "/exports/points", "/api/exports/points", exportController.ExportPoints)
+
+	// Payments (Midtrans Snap)
+	paymentRoutes := api.with(parent)
+	paymentRoutes.handle("GET", "/payments/packages", "/api/payments/packages", paymentController.GetPackages)
+	paymentRoutes.handle("POST", "/payments/checkout", "/api/payments/checkout", paymentController.Checkout)
+	paymentRoutes.handle("GET", "/payments", "/api/payments", paymentController.GetPayments)
+	paymentRoutes.handle("GET", "/payments/:orderId", "/api/payments/:orderId", paymentController.GetPayment)
+
+	// Promo codes & referrals
+	promoRoutes := api.with(parent)
+	promoRoutes.handle("POST", "/coupons/validate", "/api/coupons/validate", promoController.QuoteCoupon)
+	promoRoutes.handle("POST", "/coupons/redeem", "/api/coupons/redeem", promoController.RedeemCoupon)
+	promoRoutes.handle("GET", "/referral", "/api/referral", promoController.GetReferral)
+	promoRoutes.handle("POST", "/referral/claim", "/api/referral/claim", promoController.ClaimReferral)
+
+	// Web Push (parents and children)
+	api.handle("GET", "/notifications/vapid-key", "/api/notifications/vapid-key", notificationController.GetVAPIDKey)
+	api.handle("POST", "/notifications/subscriptions", "/api/notifications/subscriptions", notificationController.Subscribe)
+	api.handle("DELETE", "/notifications/subscriptions", "/api/notifications/subscriptions", notificationController.Unsubscribe)
+	api.handle("GET", "/notifications/preferences", "/api/notifications/preferences", notificationController.GetPreferences)
+	api.handle("PUT", "/notifications/preferences", "/api/notifications/preferences", notificationController.UpdatePreferences)
+	api.handle("POST", "/notifications/test", "/api/notifications/test", notificationController.SendTest)
+	api.handle("GET", "/notifications/whatsapp", "/api/notifications/whatsapp", parent, whatsappController.GetSettings)
+	api.handle("PUT", "/notifications/whatsapp", "/api/notifications/whatsapp", parent, whatsappController.UpdateSettings)
 
 	// Points & Redemptions
-	api.Get("/points/:childId", handlers.GetBalance)
+	api.handle("GET", "/children/:childId/points", "/api/points/:childId", handlers.GetBalance)
+	api.handle("GET", "/children/:childId/redemptions", "/api/redemptions/child/:childId", handlers.GetRedemptionsByChild)
+	api.handle("GET", "/redemptions", "/api/redemptions", handlers.GetRedemptions)
+	api.handle("POST", "/redemptions", "/api/redemptions", idempotent, handlers.CreateRedemption)
+	api.handle("PUT", "/redemptions/:id/status", "/api/redemptions/:id/status", idempotent, handlers.UpdateRedemptionStatus)
+
+	// Offline sync (batched client events + changes feed)
+	api.handle("POST", "/sync", "/api/sync", syncController.Push)
+	api.handle("GET", "/sync/changes", "/api/sync/changes", syncController.Changes)
+
+	// Point Rules (multipliers & bonuses)
+	pointRules := api.with(parent)
+	pointRules.handle("GET", "/point-rules", "/api/point-rules", pointRuleController.GetRules)
+	pointRules.handle("POST", "/point-rules", "/api/point-rules", pointRuleController.CreateRule)
+	pointRules.handle("PUT", "/point-rules/:id", "/api/point-rules/:id", pointRuleController.UpdateRule)
+	pointRules.handle("DELETE", "/point-rules/:id", "/api/point-rules/:id", pointRuleController.DeleteRule)
 
-	redemptions := api.Group("/redemptions")
-	redemptions.Get("/", handlers.GetRedemptions)
-	redemptions.Get("/child/:childId", handlers.GetRedemptionsByChild)
-	redemptions.Post("/", handlers.CreateRedemption)
-	redemptions.Put("/:id/status", handlers.UpdateRedemptionStatus)
+	// Wallets (points → rupiah allowance)
+	api.handle("GET", "/wallets", "/api/wallets", parent, walletController.GetWallets)
+	api.handle("PUT", "/wallets/cashouts/:id/status", "/api/wallets/cashouts/:id/status", parent, walletController.UpdateCashoutStatus)
+	api.handle("GET", "/wallets/:childId", "/api/wallets/:childId", walletController.GetWallet)
+	api.handle("PUT", "/wallets/:childId", "/api/wallets/:childId", parent, walletController.ConfigureWallet)
+	api.handle("POST", "/wallets/:childId/cashouts", "/api/wallets/:childId/cashout", walletController.RequestCashout)
+	api.handle("POST", "/wallets/:childId/payouts", "/api/wallets/:childId/payouts", parent, walletController.RecordPayout)
+	api.handle("POST", "/wallets/:childId/thr", "/api/wallets/:childId/thr", parent, walletController.GrantTHR)
+
+	// Family Goals (cooperative, shared progress)
+	api.handle("GET", "/goals", "/api/goals", goalController.GetGoals)
+	api.handle("GET", "/goals/:id/progress", "/api/goals/:id/progress", goalController.GetProgress)
+	api.handle("POST", "/goals", "/api/goals", parent, goalController.CreateGoal)
+	api.handle("PUT", "/goals/:id", "/api/goals/:id", parent, goalController.UpdateGoal)
+	api.handle("DELETE", "/goals/:id", "/api/goals/:id", parent, goalController.DeleteGoal)
+
+	// Templates (built-in catalog and family-authored, shareable by code)
+	templates := api.with(parent)
+	templates.handle("GET", "/templates", "", templateController.GetTemplates)
+	templates.handle("POST", "/templates", "", templateController.CreateTemplate)
+	templates.handle("POST", "/templates/from-family", "", templateController.CreateFromFamily)
+	templates.handle("GET", "/templates/shared/:code", "", templateController.GetSharedTemplate)
+	templates.handle("GET", "/templates/:id", "", templateController.GetTemplate)
+	templates.handle("PUT", "/templates/:id", "", templateController.UpdateTemplate)
+	templates.handle("DELETE", "/templates/:id", "", templateController.DeleteTemplate)
+	templates.handle("POST", "/templates/:id/share", "", templateController.ShareTemplate)
+	templates.handle("DELETE", "/templates/:id/share", "", templateController.UnshareTemplate)
+	templates.handle("POST", "/templates/:id/preview", "", templateController.Preview)
+	templates.handle("POST", "/templates/:id/apply", "", idempotent, templateController.Apply)
+
+	// Badges
+	api.handle("GET", "/badges", "", badgeController.GetBadges)
+	api.handle("POST", "/badges", "", parent, entitled(services.FeatureCustomBadges), badgeController.CreateBadge)
+	api.handle("PUT", "/badges/:id", "", parent, badgeController.UpdateBadge)
+	api.handle("DELETE", "/badges/:id", "", parent, badgeController.DeleteBadge)
 
 	// Leaderboard
-	api.Get("/leaderboard", handlers.GetLeaderboard)
+	api.handle("GET", "/leaderboard", "/api/leaderboard", entitled(services.FeatureLeaderboard), leaderboardController.GetLeaderboard)
 
 	// Super Admin Routes
-	admin := app.Group("/api/admin", middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
-	admin.Get("/families", handlers.GetAllFamilies)
-	admin.Post("/families", handlers.AdminCreateFamily)
-	admin.Delete("/family/:id", handlers.AdminDeleteFamily)
-	admin.Put("/family/:id/plan", handlers.UpdateFamilyPlan)
-	admin.Get("/stats", handlers.GetAdminStats)
-	admin.Get("/announcements", handlers.GetAnnouncements)
-	admin.Post("/announcements", handlers.CreateAnnouncement)
-	admin.Delete("/announcements/:id", handlers.DeleteAnnouncement)
+	admin := api.with(middleware.SuperAdminMiddleware())
+	admin.handle("GET", "/admin/families", "/api/admin/families", handlers.GetAllFamilies)
+	admin.handle("POST", "/admin/families", "/api/admin/families", handlers.AdminCreateFamily)
+	admin.handle("DELETE", "/admin/families/:id", "/api/admin/family/:id", handlers.AdminDeleteFamily)
+	admin.handle("PUT", "/admin/families/:id/plan", "/api/admin/family/:id/plan", planController.SetPlan)
+	admin.handle("GET", "/admin/plans", "/api/admin/plans", entitlementController.GetPlans)
+	admin.handle("POST", "/admin/plans", "/api/admin/plans", entitlementController.CreatePlan)
+	admin.handle("PUT", "/admin/plans/:code", "/api/admin/plans/:code", entitlementController.UpdatePlan)
+	admin.handle("DELETE", "/admin/plans/:code", "/api/admin/plans/:code", entitlementController.DeletePlan)
+	admin.handle("GET", "/admin/coupons", "/api/admin/coupons", promoController.GetCoupons)
+	admin.handle("POST", "/admin/coupons", "/api/admin/coupons", promoController.CreateCoupon)
+	admin.handle("PUT", "/admin/coupons/:id", "/api/admin/coupons/:id", promoController.UpdateCoupon)
+	admin.handle("DELETE", "/admin/coupons/:id", "/api/admin/coupons/:id", promoController.DeleteCoupon)
+	admin.handle("GET", "/admin/coupons/:id/redemptions", "/api/admin/coupons/:id/redemptions", promoController.GetCouponUses)
+	admin.handle("GET", "/admin/referrals", "/api/admin/referrals", promoController.GetReferralStats)
+	admin.handle("GET", "/admin/stats", "/api/admin/stats", handlers.GetAdminStats)
+	admin.handle("GET", "/admin/announcements", "/api/admin/announcements", handlers.GetAnnouncements)
+	admin.handle("POST", "/admin/announcements", "/api/admin/announcements", handlers.CreateAnnouncement)
+	admin.handle("DELETE", "/admin/announcements/:id", "/api/admin/announcements/:id", handlers.DeleteAnnouncement)
+	admin.handle("GET", "/admin/jobs", "/api/admin/jobs", jobController.GetJobs)
+	admin.handle("GET", "/admin/jobs/:name/runs", "/api/admin/jobs/:name/runs", jobController.GetRuns)
+	admin.handle("PUT", "/admin/jobs/:name/pause", "/api/admin/jobs/:name/pause", jobController.PauseJob)
+	admin.handle("PUT", "/admin/jobs/:name/resume", "/api/admin/jobs/:name/resume", jobController.ResumeJob)
+	admin.handle("POST", "/admin/jobs/:name/trigger", "/api/admin/jobs/:name/trigger", jobController.TriggerJob)
 
 	// Public: active announcements (for all logged-in users)
-	api.Get("/announcements", handlers.GetActiveAnnouncements)
+	api.handle("GET", "/announcements", "/api/announcements", handlers.GetActiveAnnouncements)
+
+	if err := routes.check(); err != nil {
+		log.Fatal(err)
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3005"
+	log.Fatal(app.Listen(":" + cfg.Port))
+}
+
+// printEffectiveConfig writes the merged configuration as YAML with secrets
+// redacted, then any validation problems, and returns the exit code.
+func printEffectiveConfig(file string) int {
+	cfg, err := config.Read(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	out, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	os.Stdout.Write(out)
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
-	log.Fatal(app.Listen(":" + port))
+	return 0
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/middleware"
+	"github.com/username/ramadhan-ceria-backend/internal/openapi"
+)
+
+// router registers each endpoint under /api/v1, validated against the
+// OpenAPI operation for it, and optionally at its old unversioned path as a
+// deprecated alias. The alias runs the same handlers without validation, so
+// existing clients keep their behavior.
+type router struct {
+	app        *fiber.App
+	spec       *openapi.Spec
+	middleware []fiber.Handler
+	registered map[string]bool // "METHOD /openapi/path", shared between copies
+	errs       *[]string
+}
+
+func newRouter(app *fiber.App, spec *openapi.Spec) *router {
+	return &router{app: app, spec: spec, registered: map[string]bool{}, errs: &[]string{}}
+}
+
+// with returns a router that runs mw before every handler it registers.
+func (r *router) with(mw ...fiber.Handler) *router {
+	next := *r
+	next.middleware = append(append([]fiber.Handler{}, r.middleware...), mw...)
+	return &next
+}
+
+// handle registers method path (relative to /api/v1) and, when legacy is not
+// empty, the deprecated alias legacy (a full path under /api). Legacy
+// requests get their snake_case body and query names renamed first.
+func (r *router) handle(method, path, legacy string, handlers ...fiber.Handler) {
+	r.register(method, path, legacy, true, handlers)
+}
+
+// handleRaw is handle for aliases whose payload names belong to someone else
+// (archives, webhooks) and must not be renamed.
+func (r *router) handleRaw(method, path, legacy string, handlers ...fiber.Handler) {
+	r.register(method, path, legacy, false, handlers)
+}
+
+func (r *router) register(method, path, legacy string, rename bool, handlers []fiber.Handler) {
+	route := openapi.Prefix + path
+	op, ok := r.spec.Operation(method, route)
+	if !ok {
+		*r.errs = append(*r.errs, fmt.Sprintf("%s %s is not in openapi.yaml", method, route))
+		return
+	}
+	r.registered[op.Method+" "+op.Path] = true
+
+	chain := append([]fiber.Handler{}, r.middleware...)
+	chain = append(chain, handlers[:len(handlers)-1]...)
+	chain = append(chain, middleware.ValidateRequest(op), handlers[len(handlers)-1])
+	r.app.Add(method, route, chain...)
+
+	if legacy == "" {
+		return
+	}
+	alias := []fiber.Handler{middleware.Deprecated(route)}
+	if rename {
+		alias = append(alias, middleware.LegacyNames())
+	}
+	alias = append(alias, r.middleware...)
+	alias = append(alias, handlers...)
+	r.app.Add(method, legacy, alias...)
+}
+
+// check reports routes missing from the spec and operations without a route.
+func (r *router) check() error {
+	errs := append([]string{}, *r.errs...)
+	for path, item := range r.spec.Paths {
+		for method := range item {
+			key := strings.ToUpper(method) + " " + path
+			if !r.registered[key] {
+				errs = append(errs, key+" is in openapi.yaml but has no route")
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Strings(errs)
+	return fmt.Errorf("routes do not match the OpenAPI spec:\n  %s", strings.Join(errs, "\n  "))
+}
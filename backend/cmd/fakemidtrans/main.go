@@ -0,0 +1,29 @@
+// Command fakemidtrans runs a local Midtrans stand-in for development and
+// integration testing. Point the API at it with
+//
+//	MIDTRANS_SERVER_KEY=SB-Mid-server-fake
+//	MIDTRANS_SNAP_URL=http://localhost:8089
+//	MIDTRANS_API_URL=http://localhost:8089
+//
+// then settle an order with: curl -X POST localhost:8089/fake/orders/<order_id>/settlement
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/username/ramadhan-ceria-backend/internal/payments/fakemidtrans"
+)
+
+func main() {
+	addr := flag.String("addr", ":8089", "listen address")
+	key := flag.String("key", "SB-Mid-server-fake", "server key the API is configured with")
+	base := flag.String("base", "http://localhost:8089", "public URL of this server, used in redirect_url")
+	notify := flag.String("notify", "http://localhost:3005/api/payments/midtrans/notification", "payment notification URL of the API")
+	flag.Parse()
+
+	server := fakemidtrans.New(*key, *base, *notify)
+	log.Printf("fake Midtrans listening on %s, notifying %s", *addr, *notify)
+	log.Fatal(http.ListenAndServe(*addr, server.Handler()))
+}
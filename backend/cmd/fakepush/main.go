@@ -0,0 +1,26 @@
+// Command fakepush runs a local Web Push service for development and
+// integration testing. Create a device subscription with
+//
+//	curl -X POST localhost:8090/subscriptions
+//
+// register it with POST /api/notifications/subscriptions, then read what the
+// device received with: curl localhost:8090/push/<id>/messages
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/username/ramadhan-ceria-backend/internal/webpush/fakepush"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	base := flag.String("base", "http://localhost:8090", "public URL of this server, used in endpoints and as VAPID audience")
+	flag.Parse()
+
+	server := fakepush.New(*base)
+	log.Printf("fake push service listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server.Handler()))
+}
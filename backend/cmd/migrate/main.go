@@ -0,0 +1,108 @@
+// Command migrate applies the embedded schema migrations. Run it from the
+// backend directory before starting a new API version:
+//
+//	go run ./cmd/migrate up            apply every pending migration
+//	go run ./cmd/migrate down [n]      roll back the newest n (default 1)
+//	go run ./cmd/migrate status        list migrations and when they ran
+//	go run ./cmd/migrate create <name> add an empty up/down pair
+//
+// The database settings come from the DB_* variables, .env or CONFIG_FILE.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/migrations"
+)
+
+const usage = "usage: migrate up | down [n] | status | create <name>"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	if cmd == "create" {
+		if len(args) != 1 {
+			log.Fatal("usage: migrate create <name>")
+		}
+		up, down, err := migrations.Create(migrations.Dir, args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("created %s\ncreated %s\n", up, down)
+		return
+	}
+
+	dbConfig, err := config.LoadDB(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	database.ConnectDB(*dbConfig)
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	switch cmd {
+	case "up":
+		done, err := migrator.Up(ctx)
+		report("applied", done)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+				log.Fatal("down takes a positive number of steps")
+			}
+		}
+		done, err := migrator.Down(ctx, steps)
+		report("rolled back", done)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("nothing to roll back")
+		}
+	case "status":
+		list, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range list {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			if s.Up == "" {
+				state += " (unknown to this binary)"
+			}
+			fmt.Printf("%04d  %-28s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func report(verb string, list []migrations.Migration) {
+	for _, m := range list {
+		fmt.Printf("%s %04d_%s\n", verb, m.Version, m.Name)
+	}
+}
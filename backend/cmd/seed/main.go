@@ -2,20 +2,25 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/google/uuid"
-	"github.com/joho/godotenv"
+	"github.com/username/ramadhan-ceria-backend/internal/config"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
 	"github.com/username/ramadhan-ceria-backend/internal/utils"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	dbConfig, err := config.LoadDB(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	database.ConnectDB()
+	database.ConnectDB(*dbConfig)
+	if err := database.RequireMigrated(); err != nil {
+		log.Fatal("Database schema is not up to date: ", err)
+	}
 
 	// Clean up existing data for a fresh start (optional but good for this seed script)
 	database.DB.Exec("DELETE FROM daily_logs")
@@ -93,7 +98,7 @@ func main() {
 
 	// Insert dummy Tasks & Rewards for this family to play with
 	tasks := []models.Task{
-		{ID: uuid.New().String(), Name: "Sholat Subuh", PointReward: 5, FamilyID: familyID},
+		{ID: uuid.New().String(), Name: "Sholat Subuh", PointReward: 5, Category: models.TaskCategoryPrayer, FamilyID: familyID},
 		{ID: uuid.New().String(), Name: "Bantu Cuci Piring", PointReward: 3, FamilyID: familyID},
 	}
 	database.DB.Create(&tasks)
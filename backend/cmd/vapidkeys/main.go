@@ -0,0 +1,17 @@
+// Command vapidkeys prints a new VAPID key pair for VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/username/ramadhan-ceria-backend/internal/webpush"
+)
+
+func main() {
+	pub, priv, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n", pub, priv)
+}
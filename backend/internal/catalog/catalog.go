@@ -0,0 +1,100 @@
+// Package catalog holds the built-in templates: ready-made tasks, rewards,
+// schedules and badges for each age band. The catalog is embedded in the
+// binary and copied into the templates table on startup, next to the
+// templates families write themselves.
+package catalog
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates.yaml
+var document []byte
+
+// Age bands a template is written for.
+const (
+	AgeTK      = "tk"     // kindergarten
+	AgeSDLower = "sd_1_3" // primary school, grades 1-3
+	AgeSDUpper = "sd_4_6" // primary school, grades 4-6
+	AgeSMP     = "smp"    // junior high school
+)
+
+// AgeBands lists the age bands youngest first.
+var AgeBands = []string{AgeTK, AgeSDLower, AgeSDUpper, AgeSMP}
+
+// Kinds of template items.
+const (
+	KindTask     = "task"
+	KindReward   = "reward"
+	KindSchedule = "schedule" // a point rule
+	KindBadge    = "badge"
+)
+
+// Kinds lists the item kinds in the order they are applied: schedules and
+// badges may point at a task of the same template.
+var Kinds = []string{KindTask, KindReward, KindSchedule, KindBadge}
+
+type Template struct {
+	Key         string `yaml:"key"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	AgeBand     string `yaml:"ageBand"`
+	Items       []Item `yaml:"items"`
+}
+
+// Item is one thing a template creates. Which fields apply depends on Kind.
+type Item struct {
+	Key         string `yaml:"key"` // unique within the template, remembered once applied
+	Kind        string `yaml:"kind"`
+	Name        string `yaml:"name"`
+	Icon        string `yaml:"icon"`
+	Description string `yaml:"description"`
+
+	// task
+	Points    int    `yaml:"points"`
+	MaxPerDay *int   `yaml:"maxPerDay"` // nil = once a day, 0 = unlimited
+	Category  string `yaml:"category"`  // general (default), prayer, quran
+	Quantity  int    `yaml:"quantity"`  // units per completion, e.g. Quran pages; 0 = 1
+
+	// reward
+	PointsRequired int `yaml:"pointsRequired"`
+
+	// schedule
+	RuleType    string  `yaml:"ruleType"` // multiplier, bonus
+	Multiplier  float64 `yaml:"multiplier"`
+	BonusPoints int     `yaml:"bonusPoints"`
+	Weekdays    []int   `yaml:"weekdays"` // 0=Sunday … 6=Saturday, empty = every day
+	LastDays    int     `yaml:"lastDays"` // only the last N days of the family's season, 0 = always
+
+	// schedule and badge
+	Task string `yaml:"task"` // key of a task item, empty = every task
+
+	// badge
+	Metric    string `yaml:"metric"` // completions, points
+	Threshold int    `yaml:"threshold"`
+}
+
+// Load parses the embedded catalog. Unknown fields are an error so a typo
+// doesn't silently drop a setting.
+func Load() ([]Template, error) {
+	var doc struct {
+		Templates []Template `yaml:"templates"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(document))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, t := range doc.Templates {
+		if t.Key == "" || seen[t.Key] {
+			return nil, fmt.Errorf("catalog: missing or duplicate template key %q", t.Key)
+		}
+		seen[t.Key] = true
+	}
+	return doc.Templates, nil
+}
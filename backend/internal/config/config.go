@@ -0,0 +1,393 @@
+// Package config is the API's configuration: defaults, then an optional
+// YAML file, then .env, then the environment, each overriding the one
+// before. Everything is validated once at startup and handed to the
+// packages that need it, so nothing reads the environment after that.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Port        string   `yaml:"port"`
+	RunJobs     bool     `yaml:"runJobs"`     // false turns job polling off on this instance
+	FrontendURL string   `yaml:"frontendUrl"` // where the web app lives, e.g. the OAuth login redirect
+	CORSOrigins []string `yaml:"corsOrigins"` // defaults to the frontend's origin
+
+	DB       DB       `yaml:"db"`
+	JWT      JWT      `yaml:"jwt"`
+	Google   Google   `yaml:"google"`
+	SMTP     SMTP     `yaml:"smtp"`
+	Midtrans Midtrans `yaml:"midtrans"`
+	VAPID    VAPID    `yaml:"vapid"`
+	WhatsApp WhatsApp `yaml:"whatsapp"`
+}
+
+type DB struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslMode"`  // libpq sslmode
+	TimeZone string `yaml:"timeZone"` // session time zone
+}
+
+type JWT struct {
+	Secret string `yaml:"secret"`
+}
+
+// Google is the OAuth client for "Login with Google"; unset disables it.
+type Google struct {
+	ClientID     string `yaml:"clientId"`
+	ClientSecret string `yaml:"clientSecret"`
+	RedirectURL  string `yaml:"redirectUrl"` // e.g. http://localhost:3005/api/v1/auth/google/callback
+}
+
+// SMTP sends e-mail; without a host mail is only logged.
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// Midtrans is the payment gateway. SnapURL and APIURL override the hosts
+// picked by Env, e.g. to point at cmd/fakemidtrans.
+type Midtrans struct {
+	ServerKey string `yaml:"serverKey"`
+	Env       string `yaml:"env"` // sandbox, production
+	SnapURL   string `yaml:"snapUrl"`
+	APIURL    string `yaml:"apiUrl"`
+}
+
+// VAPID signs Web Push messages; without a private key push is disabled.
+type VAPID struct {
+	PublicKey  string `yaml:"publicKey"`
+	PrivateKey string `yaml:"privateKey"`
+	Subject    string `yaml:"subject"`
+}
+
+// WhatsApp picks the message provider; without one messages are only logged.
+type WhatsApp struct {
+	Provider      string `yaml:"provider"` // cloud, fonnte, wablas
+	Token         string `yaml:"token"`
+	PhoneNumberID string `yaml:"phoneNumberId"`
+	APIURL        string `yaml:"apiUrl"`
+	WebhookToken  string `yaml:"webhookToken"` // shared secret of the inbound webhook
+}
+
+// Default is the configuration before any source is read.
+func Default() Config {
+	return Config{
+		Port:        "3005",
+		RunJobs:     true,
+		FrontendURL: "http://localhost:3000",
+		DB:          DB{Port: "5432", SSLMode: "disable", TimeZone: "Asia/Jakarta"},
+		SMTP:        SMTP{Port: "587"},
+		Midtrans:    Midtrans{Env: "sandbox"},
+		VAPID:       VAPID{Subject: "mailto:admin@ramadhanceria.id"},
+	}
+}
+
+// Load reads every source and validates the result, reporting every
+// invalid setting at once.
+func Load(file string) (*Config, error) {
+	cfg, err := Read(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadDB is Load for tools that only talk to the database.
+func LoadDB(file string) (*DB, error) {
+	cfg, err := Read(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(cfg.DB.problems()); err != nil {
+		return nil, err
+	}
+	return &cfg.DB, nil
+}
+
+// Read merges the sources without validating them. file may be empty; .env
+// is optional.
+func Read(file string) (*Config, error) {
+	cfg := Default()
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", file, err)
+		}
+	}
+
+	dotenv, err := godotenv.Read()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("config: .env: %w", err)
+	}
+	lookup := func(name string) (string, bool) {
+		if v, ok := os.LookupEnv(name); ok {
+			return v, true
+		}
+		v, ok := dotenv[name]
+		return v, ok
+	}
+
+	for name, dst := range cfg.variables() {
+		if v, ok := lookup(name); ok {
+			*dst = v
+		}
+	}
+	if v, ok := lookup("RUN_JOBS"); ok {
+		if cfg.RunJobs, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("config: RUN_JOBS must be true or false, got %q", v)
+		}
+	}
+	if v, ok := lookup("CORS_ORIGINS"); ok {
+		cfg.CORSOrigins = nil
+		for _, origin := range strings.Split(v, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.CORSOrigins = append(cfg.CORSOrigins, origin)
+			}
+		}
+	}
+	if len(cfg.CORSOrigins) == 0 {
+		if u, err := url.Parse(cfg.FrontendURL); err == nil && u.Host != "" {
+			cfg.CORSOrigins = []string{u.Scheme + "://" + u.Host}
+		}
+	}
+	return &cfg, nil
+}
+
+// variables maps the string settings to their environment variables.
+func (c *Config) variables() map[string]*string {
+	return map[string]*string{
+		"PORT":         &c.Port,
+		"FRONTEND_URL": &c.FrontendURL,
+
+		"DB_HOST":     &c.DB.Host,
+		"DB_PORT":     &c.DB.Port,
+		"DB_USER":     &c.DB.User,
+		"DB_PASSWORD": &c.DB.Password,
+		"DB_NAME":     &c.DB.Name,
+		"DB_SSLMODE":  &c.DB.SSLMode,
+		"DB_TIMEZONE": &c.DB.TimeZone,
+
+		"JWT_SECRET": &c.JWT.Secret,
+
+		"GOOGLE_CLIENT_ID":     &c.Google.ClientID,
+		"GOOGLE_CLIENT_SECRET": &c.Google.ClientSecret,
+		"GOOGLE_REDIRECT_URL":  &c.Google.RedirectURL,
+
+		"SMTP_HOST":     &c.SMTP.Host,
+		"SMTP_PORT":     &c.SMTP.Port,
+		"SMTP_USER":     &c.SMTP.User,
+		"SMTP_PASSWORD": &c.SMTP.Password,
+		"MAIL_FROM":     &c.SMTP.From,
+
+		"MIDTRANS_SERVER_KEY": &c.Midtrans.ServerKey,
+		"MIDTRANS_ENV":        &c.Midtrans.Env,
+		"MIDTRANS_SNAP_URL":   &c.Midtrans.SnapURL,
+		"MIDTRANS_API_URL":    &c.Midtrans.APIURL,
+
+		"VAPID_PUBLIC_KEY":  &c.VAPID.PublicKey,
+		"VAPID_PRIVATE_KEY": &c.VAPID.PrivateKey,
+		"VAPID_SUBJECT":     &c.VAPID.Subject,
+
+		"WHATSAPP_PROVIDER":        &c.WhatsApp.Provider,
+		"WHATSAPP_TOKEN":           &c.WhatsApp.Token,
+		"WHATSAPP_PHONE_NUMBER_ID": &c.WhatsApp.PhoneNumberID,
+		"WHATSAPP_API_URL":         &c.WhatsApp.APIURL,
+		"WHATSAPP_WEBHOOK_TOKEN":   &c.WhatsApp.WebhookToken,
+	}
+}
+
+// DSN is the libpq connection string. Values are quoted, so passwords may
+// contain spaces and quotes.
+func (d DB) DSN() string {
+	quote := func(v string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=%s",
+		quote(d.Host), quote(d.Port), quote(d.User), quote(d.Password), quote(d.Name), quote(d.SSLMode), quote(d.TimeZone))
+}
+
+// MinSecretLength is the shortest JWT secret accepted, 256 bits of HS256 key.
+const MinSecretLength = 32
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var p []string
+	p = append(p, checkPort("PORT", c.Port)...)
+	p = append(p, checkURL("FRONTEND_URL", c.FrontendURL, true)...)
+	for _, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+			p = append(p, fmt.Sprintf("CORS_ORIGINS: %q is not an origin like https://example.com", origin))
+		}
+	}
+
+	p = append(p, c.DB.problems()...)
+	p = append(p, checkSecret("JWT_SECRET", c.JWT.Secret)...)
+
+	if c.Google != (Google{}) {
+		if c.Google.ClientID == "" || c.Google.ClientSecret == "" {
+			p = append(p, "GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET must be set together")
+		}
+		p = append(p, checkURL("GOOGLE_REDIRECT_URL", c.Google.RedirectURL, true)...)
+	}
+
+	if c.SMTP.Host != "" {
+		p = append(p, checkPort("SMTP_PORT", c.SMTP.Port)...)
+		if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+			p = append(p, fmt.Sprintf("MAIL_FROM: %q is not an e-mail address", c.SMTP.From))
+		}
+		if c.SMTP.User != "" && c.SMTP.Password == "" {
+			p = append(p, "SMTP_PASSWORD is required with SMTP_USER")
+		}
+	}
+
+	switch c.Midtrans.Env {
+	case "sandbox":
+	case "production":
+		if strings.HasPrefix(c.Midtrans.ServerKey, "SB-") {
+			p = append(p, "MIDTRANS_SERVER_KEY is a sandbox key but MIDTRANS_ENV is production")
+		}
+	default:
+		p = append(p, fmt.Sprintf("MIDTRANS_ENV must be sandbox or production, got %q", c.Midtrans.Env))
+	}
+	p = append(p, checkURL("MIDTRANS_SNAP_URL", c.Midtrans.SnapURL, false)...)
+	p = append(p, checkURL("MIDTRANS_API_URL", c.Midtrans.APIURL, false)...)
+
+	if c.VAPID.PublicKey != "" && c.VAPID.PrivateKey == "" {
+		p = append(p, "VAPID_PRIVATE_KEY is required with VAPID_PUBLIC_KEY")
+	}
+	if s := c.VAPID.Subject; !strings.HasPrefix(s, "mailto:") && !strings.HasPrefix(s, "https://") {
+		p = append(p, fmt.Sprintf("VAPID_SUBJECT must be a mailto: or https: URL, got %q", s))
+	}
+
+	switch c.WhatsApp.Provider {
+	case "", "cloud", "fonnte", "wablas":
+	default:
+		p = append(p, fmt.Sprintf("WHATSAPP_PROVIDER must be cloud, fonnte or wablas, got %q", c.WhatsApp.Provider))
+	}
+	p = append(p, checkURL("WHATSAPP_API_URL", c.WhatsApp.APIURL, false)...)
+	if t := c.WhatsApp.WebhookToken; t != "" && len(t) < 16 {
+		p = append(p, "WHATSAPP_WEBHOOK_TOKEN must be at least 16 characters")
+	}
+	return check(p)
+}
+
+func (d DB) problems() []string {
+	var p []string
+	if d.Host == "" {
+		p = append(p, "DB_HOST is required")
+	}
+	if d.User == "" {
+		p = append(p, "DB_USER is required")
+	}
+	if d.Name == "" {
+		p = append(p, "DB_NAME is required")
+	}
+	p = append(p, checkPort("DB_PORT", d.Port)...)
+	if !contains(sslModes, d.SSLMode) {
+		p = append(p, fmt.Sprintf("DB_SSLMODE must be one of %s, got %q", strings.Join(sslModes, ", "), d.SSLMode))
+	}
+	if _, err := time.LoadLocation(d.TimeZone); err != nil || d.TimeZone == "" {
+		p = append(p, fmt.Sprintf("DB_TIMEZONE: unknown time zone %q", d.TimeZone))
+	}
+	return p
+}
+
+func check(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+}
+
+func checkPort(name, v string) []string {
+	if n, err := strconv.Atoi(v); err != nil || n < 1 || n > 65535 {
+		return []string{fmt.Sprintf("%s must be a port number, got %q", name, v)}
+	}
+	return nil
+}
+
+// checkURL wants an absolute http(s) URL; optional ones may be empty.
+func checkURL(name, v string, required bool) []string {
+	if v == "" && !required {
+		return nil
+	}
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []string{fmt.Sprintf("%s must be an absolute http(s) URL, got %q", name, v)}
+	}
+	return nil
+}
+
+// checkSecret rejects secrets that are short or repeat a few characters.
+func checkSecret(name, v string) []string {
+	if v == "" {
+		return []string{name + " is required"}
+	}
+	if len(v) < MinSecretLength {
+		return []string{fmt.Sprintf("%s must be at least %d characters, got %d", name, MinSecretLength, len(v))}
+	}
+	distinct := map[rune]bool{}
+	for _, r := range v {
+		distinct[r] = true
+	}
+	if len(distinct) < 10 {
+		return []string{name + " is too repetitive to be a secret"}
+	}
+	return nil
+}
+
+// Redacted is a copy safe to print: every secret that is set reads "[redacted]".
+func (c Config) Redacted() Config {
+	for _, s := range []*string{
+		&c.DB.Password, &c.JWT.Secret, &c.Google.ClientSecret, &c.SMTP.Password,
+		&c.Midtrans.ServerKey, &c.VAPID.PrivateKey, &c.WhatsApp.Token, &c.WhatsApp.WebhookToken,
+	} {
+		if *s != "" {
+			*s = "[redacted]"
+		}
+	}
+	return c
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
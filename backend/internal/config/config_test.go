@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validConfig is the defaults plus the settings that have none.
+func validConfig() Config {
+	c := Default()
+	c.CORSOrigins = []string{"http://localhost:3000"}
+	c.DB.Host = "localhost"
+	c.DB.User = "postgres"
+	c.DB.Name = "ramadhan_ceria"
+	c.JWT.Secret = "k3y-0f-at-l3ast-32-characters-long!"
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string // a substring of the error; empty means valid
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"everything set", func(c *Config) {
+			c.CORSOrigins = []string{"https://ramadhanceria.id", "http://localhost:3000/", "*"}
+			c.Google = Google{ClientID: "id", ClientSecret: "secret", RedirectURL: "https://api.ramadhanceria.id/api/v1/auth/google/callback"}
+			c.SMTP = SMTP{Host: "smtp.example.com", Port: "465", User: "mailer", Password: "pw", From: "Ramadhan Ceria <noreply@ramadhanceria.id>"}
+			c.Midtrans = Midtrans{Env: "production", ServerKey: "Mid-server-abc", SnapURL: "http://localhost:4010", APIURL: "http://localhost:4010"}
+			c.VAPID = VAPID{PublicKey: "pub", PrivateKey: "priv", Subject: "https://ramadhanceria.id"}
+			c.WhatsApp = WhatsApp{Provider: "fonnte", Token: "t", APIURL: "https://api.fonnte.com", WebhookToken: "0123456789abcdef"}
+		}, ""},
+
+		{"port not a number", func(c *Config) { c.Port = "http" }, `PORT must be a port number, got "http"`},
+		{"port out of range", func(c *Config) { c.Port = "70000" }, "PORT must be a port number"},
+		{"frontend required", func(c *Config) { c.FrontendURL = "" }, "FRONTEND_URL must be an absolute http(s) URL"},
+		{"frontend relative", func(c *Config) { c.FrontendURL = "localhost:3000" }, "FRONTEND_URL must be an absolute http(s) URL"},
+		{"cors origin with path", func(c *Config) { c.CORSOrigins = []string{"https://example.com/app"} }, `CORS_ORIGINS: "https://example.com/app" is not an origin`},
+		{"cors origin without scheme", func(c *Config) { c.CORSOrigins = []string{"example.com"} }, "CORS_ORIGINS"},
+
+		{"db host", func(c *Config) { c.DB.Host = "" }, "DB_HOST is required"},
+		{"db user", func(c *Config) { c.DB.User = "" }, "DB_USER is required"},
+		{"db name", func(c *Config) { c.DB.Name = "" }, "DB_NAME is required"},
+		{"db port", func(c *Config) { c.DB.Port = "" }, "DB_PORT must be a port number"},
+		{"db sslmode", func(c *Config) { c.DB.SSLMode = "on" }, `DB_SSLMODE must be one of disable, allow, prefer, require, verify-ca, verify-full, got "on"`},
+		{"db time zone", func(c *Config) { c.DB.TimeZone = "Asia/Bandung" }, `DB_TIMEZONE: unknown time zone "Asia/Bandung"`},
+		{"db time zone empty", func(c *Config) { c.DB.TimeZone = "" }, "DB_TIMEZONE"},
+
+		{"jwt secret required", func(c *Config) { c.JWT.Secret = "" }, "JWT_SECRET is required"},
+		{"jwt secret short", func(c *Config) { c.JWT.Secret = "secret" }, "JWT_SECRET must be at least 32 characters, got 6"},
+		{"jwt secret repetitive", func(c *Config) { c.JWT.Secret = strings.Repeat("ab", 20) }, "JWT_SECRET is too repetitive"},
+
+		{"google half set", func(c *Config) {
+			c.Google = Google{ClientID: "id", RedirectURL: "https://example.com/callback"}
+		}, "GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET must be set together"},
+		{"google redirect", func(c *Config) { c.Google = Google{ClientID: "id", ClientSecret: "secret"} }, "GOOGLE_REDIRECT_URL"},
+
+		{"smtp port", func(c *Config) { c.SMTP = SMTP{Host: "smtp", Port: "smtp", From: "a@b.id"} }, "SMTP_PORT must be a port number"},
+		{"smtp from", func(c *Config) { c.SMTP = SMTP{Host: "smtp", Port: "587"} }, `MAIL_FROM: "" is not an e-mail address`},
+		{"smtp password", func(c *Config) { c.SMTP = SMTP{Host: "smtp", Port: "587", From: "a@b.id", User: "u"} }, "SMTP_PASSWORD is required with SMTP_USER"},
+		{"smtp ignored without host", func(c *Config) { c.SMTP = SMTP{Port: "x", User: "u"} }, ""},
+
+		{"midtrans env", func(c *Config) { c.Midtrans.Env = "live" }, `MIDTRANS_ENV must be sandbox or production, got "live"`},
+		{"midtrans sandbox key in production", func(c *Config) {
+			c.Midtrans = Midtrans{Env: "production", ServerKey: "SB-Mid-server-abc"}
+		}, "MIDTRANS_SERVER_KEY is a sandbox key"},
+		{"midtrans sandbox key in sandbox", func(c *Config) { c.Midtrans.ServerKey = "SB-Mid-server-abc" }, ""},
+		{"midtrans snap url", func(c *Config) { c.Midtrans.SnapURL = "localhost:4010" }, "MIDTRANS_SNAP_URL"},
+		{"midtrans api url", func(c *Config) { c.Midtrans.APIURL = "ftp://localhost" }, "MIDTRANS_API_URL"},
+
+		{"vapid private key", func(c *Config) { c.VAPID.PublicKey = "pub" }, "VAPID_PRIVATE_KEY is required with VAPID_PUBLIC_KEY"},
+		{"vapid subject", func(c *Config) { c.VAPID.Subject = "admin@ramadhanceria.id" }, "VAPID_SUBJECT must be a mailto: or https: URL"},
+
+		{"whatsapp provider", func(c *Config) { c.WhatsApp.Provider = "twilio" }, `WHATSAPP_PROVIDER must be cloud, fonnte or wablas, got "twilio"`},
+		{"whatsapp api url", func(c *Config) { c.WhatsApp.APIURL = "api.fonnte.com" }, "WHATSAPP_API_URL"},
+		{"whatsapp webhook token", func(c *Config) { c.WhatsApp.WebhookToken = "short" }, "WHATSAPP_WEBHOOK_TOKEN must be at least 16 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(&c)
+			err := c.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("Validate() = nil, want %q", tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	c := validConfig()
+	c.Port = "0"
+	c.DB.Host = ""
+	c.JWT.Secret = ""
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+	for _, want := range []string{"PORT", "DB_HOST", "JWT_SECRET"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, missing %s", err, want)
+		}
+	}
+}
+
+func TestReadOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "port: \"4000\"\nfrontendUrl: https://ramadhanceria.id/app\ndb:\n  host: db\n  name: from_file\n"
+	if err := os.WriteFile(file, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Clear the environment so only the variables below apply; t.Setenv restores it.
+	var none Config
+	for name := range none.variables() {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	t.Setenv("CORS_ORIGINS", "")
+	os.Unsetenv("CORS_ORIGINS")
+	t.Setenv("DB_NAME", "from_env")
+	t.Setenv("RUN_JOBS", "false")
+
+	c, err := Read(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Port != "4000" || c.DB.Host != "db" || c.DB.Name != "from_env" || c.RunJobs || c.DB.SSLMode != "disable" {
+		t.Errorf("port %q, db host %q, db name %q, runJobs %v, sslmode %q; want 4000, db, from_env, false, disable",
+			c.Port, c.DB.Host, c.DB.Name, c.RunJobs, c.DB.SSLMode)
+	}
+	if len(c.CORSOrigins) != 1 || c.CORSOrigins[0] != "https://ramadhanceria.id" {
+		t.Errorf("CORS origins = %q, want the frontend's origin", c.CORSOrigins)
+	}
+
+	if err := os.WriteFile(file, []byte("dbHost: db\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(file); err == nil {
+		t.Error("unknown YAML key accepted")
+	}
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type AnalyticsController struct {
+	analyticsService *services.AnalyticsService
+}
+
+func NewAnalyticsController(analyticsService *services.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{analyticsService: analyticsService}
+}
+
+// GetAnalytics — ?from=YYYY-MM-DD&to=YYYY-MM-DD&child_id=a,b (gated by the analytics entitlement)
+func (c *AnalyticsController) GetAnalytics(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	report, err := c.analyticsService.GetAnalytics(familyID, services.AnalyticsQuery{
+		From:     ctx.Query("from"),
+		To:       ctx.Query("to"),
+		ChildIDs: services.ParseChildIDs(ctx.Query("childId")),
+	})
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	return ctx.JSON(httperr.LegacyKeys(ctx, fiber.Map{
+		"message": "Premium Analytics Retrieved",
+		"data":    report,
+	}))
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type BadgeController struct {
+	badgeService *services.BadgeService
+}
+
+func NewBadgeController(badgeService *services.BadgeService) *BadgeController {
+	return &BadgeController{badgeService: badgeService}
+}
+
+func (c *BadgeController) GetBadges(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	badges, err := c.badgeService.GetBadges(familyID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(badges)
+}
+
+func (c *BadgeController) CreateBadge(ctx *fiber.Ctx) error {
+	var req services.BadgeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	badge, err := c.badgeService.CreateBadge(familyID, req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(badge)
+}
+
+func (c *BadgeController) UpdateBadge(ctx *fiber.Ctx) error {
+	var req services.BadgeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	badge, err := c.badgeService.UpdateBadge(familyID, ctx.Params("id"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(badge)
+}
+
+func (c *BadgeController) DeleteBadge(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	if err := c.badgeService.DeleteBadge(familyID, ctx.Params("id")); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type EntitlementController struct {
+	entitlementService *services.EntitlementService
+}
+
+func NewEntitlementController(entitlementService *services.EntitlementService) *EntitlementController {
+	return &EntitlementController{entitlementService: entitlementService}
+}
+
+// GetEntitlements — GET /api/family/entitlements: plan in effect, limits, usage and feature flags.
+func (c *EntitlementController) GetEntitlements(ctx *fiber.Ctx) error {
+	ent, err := c.entitlementService.GetEntitlements(ctx.Locals("familyID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(ent)
+}
+
+// GetPlans — GET /api/admin/plans
+func (c *EntitlementController) GetPlans(ctx *fiber.Ctx) error {
+	plans, err := c.entitlementService.GetPlans()
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(plans)
+}
+
+// CreatePlan — POST /api/admin/plans
+func (c *EntitlementController) CreatePlan(ctx *fiber.Ctx) error {
+	var req services.PlanInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	plan, err := c.entitlementService.CreatePlan(req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(plan)
+}
+
+// UpdatePlan — PUT /api/admin/plans/:code (replaces name, limits and features)
+func (c *EntitlementController) UpdatePlan(ctx *fiber.Ctx) error {
+	var req services.PlanInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	plan, err := c.entitlementService.UpdatePlan(ctx.Params("code"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(plan)
+}
+
+// DeletePlan — DELETE /api/admin/plans/:code
+func (c *EntitlementController) DeletePlan(ctx *fiber.Ctx) error {
+	if err := c.entitlementService.DeletePlan(ctx.Params("code")); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/events"
+)
+
+// streamKeepAlive keeps proxies from closing an idle stream.
+const streamKeepAlive = 25 * time.Second
+
+type EventController struct {
+	bus *events.Bus
+}
+
+func NewEventController(bus *events.Bus) *EventController {
+	return &EventController{bus: bus}
+}
+
+// Stream — GET /api/events/stream: Server-Sent Events for the caller's family.
+// Each event is "event: <type>" with the Event JSON as data. The stream ends
+// if the client falls too far behind; EventSource reconnects and the client
+// should refetch what it shows.
+func (c *EventController) Stream(ctx *fiber.Ctx) error {
+	sub := c.bus.Subscribe(ctx.Locals("familyID").(string))
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		fmt.Fprintf(w, "retry: 3000\n: connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(streamKeepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case ev, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(ev)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+			case <-ticker.C:
+				fmt.Fprintf(w, ": ping\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return // client went away
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/exports"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type ExportController struct {
+	exportService *services.ExportService
+}
+
+func NewExportController(exportService *services.ExportService) *ExportController {
+	return &ExportController{exportService: exportService}
+}
+
+// ExportLogs — GET /api/exports/logs?format=csv|xlsx&from=YYYY-MM-DD&to=YYYY-MM-DD&child_id=a,b
+func (c *ExportController) ExportLogs(ctx *fiber.Ctx) error {
+	return c.export(ctx, "log-ibadah", "Log", c.exportService.WriteLogs)
+}
+
+// ExportRedemptions — GET /api/exports/redemptions
+func (c *ExportController) ExportRedemptions(ctx *fiber.Ctx) error {
+	return c.export(ctx, "penukaran-hadiah", "Penukaran", c.exportService.WriteRedemptions)
+}
+
+// ExportPoints — GET /api/exports/points
+func (c *ExportController) ExportPoints(ctx *fiber.Ctx) error {
+	return c.export(ctx, "saldo-poin", "Poin", c.exportService.WritePoints)
+}
+
+// export validates the query up front, then streams the file so large families
+// are never buffered in memory. Errors after the first byte can only be logged.
+func (c *ExportController) export(ctx *fiber.Ctx, name, sheet string, write func(exports.RowWriter, *services.ExportQuery) error) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	format := ctx.Query("format", "csv")
+	contentType, ext, err := services.ExportFormat(format)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	q, err := c.exportService.NewExportQuery(familyID, ctx.Query("from"), ctx.Query("to"), services.ParseChildIDs(ctx.Query("childId")))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	filename := fmt.Sprintf("%s-%s-%s.%s", name, q.From, q.To, ext)
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		rw, err := exports.NewWriter(w, format, sheet)
+		if err == nil {
+			err = write(rw, q)
+		}
+		if err != nil {
+			log.Printf("export %s for family %s failed: %v", name, familyID, err)
+		}
+		w.Flush()
+	})
+	return nil
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type FamilyDataController struct {
+	familyDataService *services.FamilyDataService
+	deletionService   *services.AccountDeletionService
+	tokens            *utils.JWT
+}
+
+func NewFamilyDataController(familyDataService *services.FamilyDataService, deletionService *services.AccountDeletionService, tokens *utils.JWT) *FamilyDataController {
+	return &FamilyDataController{familyDataService: familyDataService, deletionService: deletionService, tokens: tokens}
+}
+
+// ExportArchive — GET /api/family/archive, downloads the whole family as versioned JSON.
+func (c *FamilyDataController) ExportArchive(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	archive, err := c.familyDataService.ExportArchive(familyID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	filename := fmt.Sprintf("arsip-%s-%s.json", utils.Slugify(archive.Family.Name), archive.ExportedAt.Format("2006-01-02"))
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return ctx.JSON(archive)
+}
+
+type ImportArchiveRequest struct {
+	Email      string                  `json:"email" validate:"required,email,max=255"`
+	Password   string                  `json:"password" validate:"required,max=72"`
+	FamilyName string                  `json:"familyName" validate:"max=100"`
+	Archive    *services.FamilyArchive `json:"archive" validate:"required"`
+}
+
+// ImportArchive — POST /api/auth/import, registers a new family from an archive.
+func (c *FamilyDataController) ImportArchive(ctx *fiber.Ctx) error {
+	var req ImportArchiveRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	hashed, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	result, err := c.familyDataService.ImportArchive(req.Archive, services.ImportOwner{
+		Email:        req.Email,
+		PasswordHash: hashed,
+		FamilyName:   req.FamilyName,
+	})
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	token, err := c.tokens.GenerateToken(result.OwnerID, result.FamilyID, "parent")
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(httperr.LegacyKeys(ctx, fiber.Map{
+		"token":  token,
+		"role":   "parent",
+		"import": result,
+	}))
+}
+
+// GetDeletion — GET /api/family/deletion
+func (c *FamilyDataController) GetDeletion(ctx *fiber.Ctx) error {
+	deletion, err := c.deletionService.GetDeletion(ctx.Locals("familyID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(deletion)
+}
+
+// RequestDeletion — POST /api/family/deletion, e-mails a confirmation token.
+func (c *FamilyDataController) RequestDeletion(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+	userID := ctx.Locals("userID").(string)
+
+	deletion, err := c.deletionService.RequestDeletion(ctx.Context(), familyID, userID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message":  "Confirmation code sent by e-mail",
+		"deletion": deletion,
+	})
+}
+
+type ConfirmDeletionRequest struct {
+	Token string `json:"token" validate:"required,max=100"`
+}
+
+// ConfirmDeletion — POST /api/family/deletion/confirm
+func (c *FamilyDataController) ConfirmDeletion(ctx *fiber.Ctx) error {
+	var req ConfirmDeletionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	deletion, err := c.deletionService.ConfirmDeletion(ctx.Locals("familyID").(string), strings.TrimSpace(req.Token))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{
+		"message":  "Family will be deleted after the cooling-off period",
+		"deletion": deletion,
+	})
+}
+
+// CancelDeletion — DELETE /api/family/deletion
+func (c *FamilyDataController) CancelDeletion(ctx *fiber.Ctx) error {
+	if err := c.deletionService.CancelDeletion(ctx.Locals("familyID").(string)); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"message": "Deletion cancelled"})
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type GoalController struct {
+	goalService *services.GoalService
+}
+
+func NewGoalController(goalService *services.GoalService) *GoalController {
+	return &GoalController{goalService: goalService}
+}
+
+func (c *GoalController) GetGoals(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	goals, err := c.goalService.GetGoals(familyID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(goals)
+}
+
+func (c *GoalController) GetProgress(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	progress, err := c.goalService.GetProgress(familyID, ctx.Params("id"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(progress)
+}
+
+func (c *GoalController) CreateGoal(ctx *fiber.Ctx) error {
+	var req services.GoalRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	goal, err := c.goalService.CreateGoal(familyID, req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(goal)
+}
+
+func (c *GoalController) UpdateGoal(ctx *fiber.Ctx) error {
+	var req services.GoalRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	goal, err := c.goalService.UpdateGoal(familyID, ctx.Params("id"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(goal)
+}
+
+func (c *GoalController) DeleteGoal(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	if err := c.goalService.DeleteGoal(familyID, ctx.Params("id")); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/jobs"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type JobController struct {
+	scheduler *jobs.Scheduler
+}
+
+func NewJobController(scheduler *jobs.Scheduler) *JobController {
+	return &JobController{scheduler: scheduler}
+}
+
+// jobError answers scheduler errors with their domain equivalents.
+func jobError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		err = services.ErrJobNotFound
+	case errors.Is(err, jobs.ErrPaused):
+		err = services.ErrJobPaused
+	}
+	return httperr.Respond(ctx, err)
+}
+
+// GetJobs — GET /api/admin/jobs
+func (c *JobController) GetJobs(ctx *fiber.Ctx) error {
+	list, err := c.scheduler.List()
+	if err != nil {
+		return jobError(ctx, err)
+	}
+	return ctx.JSON(list)
+}
+
+// GetRuns — GET /api/admin/jobs/:name/runs?limit=50
+func (c *JobController) GetRuns(ctx *fiber.Ctx) error {
+	runs, err := c.scheduler.Runs(ctx.Params("name"), ctx.QueryInt("limit", 50))
+	if err != nil {
+		return jobError(ctx, err)
+	}
+	return ctx.JSON(runs)
+}
+
+// PauseJob — PUT /api/admin/jobs/:name/pause
+func (c *JobController) PauseJob(ctx *fiber.Ctx) error {
+	if err := c.scheduler.Pause(ctx.Params("name")); err != nil {
+		return jobError(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"message": "Job paused"})
+}
+
+// ResumeJob — PUT /api/admin/jobs/:name/resume
+func (c *JobController) ResumeJob(ctx *fiber.Ctx) error {
+	if err := c.scheduler.Resume(ctx.Params("name")); err != nil {
+		return jobError(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"message": "Job resumed"})
+}
+
+// TriggerJob — POST /api/admin/jobs/:name/trigger, runs the job on the next poll.
+func (c *JobController) TriggerJob(ctx *fiber.Ctx) error {
+	if err := c.scheduler.Trigger(ctx.Params("name")); err != nil {
+		return jobError(ctx, err)
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "Job triggered"})
+}
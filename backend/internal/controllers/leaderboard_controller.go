@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type LeaderboardController struct {
+	leaderboardService *services.LeaderboardService
+}
+
+func NewLeaderboardController(leaderboardService *services.LeaderboardService) *LeaderboardController {
+	return &LeaderboardController{leaderboardService: leaderboardService}
+}
+
+// GetLeaderboard — ?period=daily|weekly|season|alltime&date=YYYY-MM-DD&rankBy=points|completion
+// date picks the day or week to show, so past weeks are available too.
+func (c *LeaderboardController) GetLeaderboard(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	board, err := c.leaderboardService.GetLeaderboard(familyID, services.LeaderboardQuery{
+		Period: ctx.Query("period"),
+		Date:   ctx.Query("date"),
+		RankBy: ctx.Query("rankBy"),
+	})
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	return ctx.JSON(board)
+}
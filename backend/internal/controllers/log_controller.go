@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
@@ -14,25 +17,47 @@ func NewLogController(logService *services.LogService) *LogController {
 }
 
 func (c *LogController) UndoTask(ctx *fiber.Ctx) error {
-	logID := ctx.Params("log_id")
-	if logID == "" {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "log_id is required"})
+	logID := ctx.Params("logId")
+	if err := services.Required("logId", logID); err != nil {
+		return httperr.Respond(ctx, err)
 	}
 
 	familyID := ctx.Locals("familyID").(string)
 
 	err := c.logService.UndoTask(familyID, logID)
 	if err != nil {
-		if err.Error() == "Log not found or belongs to another family" {
-			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
-		}
-		if err.Error() == "Log already undone or not verified" {
-			return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
-		}
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal server error"})
+		return httperr.Respond(ctx, err)
 	}
 
 	return ctx.JSON(fiber.Map{
 		"message": "Task undone successfully",
 	})
 }
+
+type SaveLogsRequest struct {
+	ChildID string                `json:"childId" validate:"required,uuid"`
+	Date    string                `json:"date" validate:"required,date"` // format YYYY-MM-DD
+	Tasks   []services.DailyCount `json:"tasks" validate:"required,max=100,dive"`
+}
+
+// SaveLogs — POST /api/logs (parent): sets how many times each task was done
+// on a day and returns a per-task diff with the child's new balance.
+func (c *LogController) SaveLogs(ctx *fiber.Ctx) error {
+	var req SaveLogsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date)
+
+	familyID := ctx.Locals("familyID").(string)
+
+	edit, err := c.logService.SetDailyCounts(familyID, req.ChildID, date, req.Tasks)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(edit)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type NotificationController struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationController(notificationService *services.NotificationService) *NotificationController {
+	return &NotificationController{notificationService: notificationService}
+}
+
+// GetVAPIDKey — GET /api/notifications/vapid-key: applicationServerKey for PushManager.subscribe().
+func (c *NotificationController) GetVAPIDKey(ctx *fiber.Ctx) error {
+	key, err := c.notificationService.PublicKey()
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"publicKey": key})
+}
+
+// Subscribe — POST /api/notifications/subscriptions: body is the browser's PushSubscription JSON.
+func (c *NotificationController) Subscribe(ctx *fiber.Ctx) error {
+	var input services.SubscriptionInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	sub, err := c.notificationService.Subscribe(
+		ctx.Locals("familyID").(string), ctx.Locals("userID").(string), ctx.Get(fiber.HeaderUserAgent), input)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{"id": sub.ID, "endpoint": sub.Endpoint})
+}
+
+// Unsubscribe — DELETE /api/notifications/subscriptions: body {"endpoint": "..."}.
+func (c *NotificationController) Unsubscribe(ctx *fiber.Ctx) error {
+	var req struct {
+		Endpoint string `json:"endpoint" validate:"required,max=1000"`
+	}
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	if err := c.notificationService.Unsubscribe(ctx.Locals("userID").(string), req.Endpoint); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// GetPreferences — GET /api/notifications/preferences
+func (c *NotificationController) GetPreferences(ctx *fiber.Ctx) error {
+	prefs, err := c.notificationService.GetPreferences(ctx.Locals("userID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(prefs)
+}
+
+// UpdatePreferences — PUT /api/notifications/preferences: only the fields sent change.
+func (c *NotificationController) UpdatePreferences(ctx *fiber.Ctx) error {
+	var input services.PreferencesInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	prefs, err := c.notificationService.UpdatePreferences(ctx.Locals("userID").(string), input)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(prefs)
+}
+
+// SendTest — POST /api/notifications/test: push to the caller's own devices.
+func (c *NotificationController) SendTest(ctx *fiber.Ctx) error {
+	devices, err := c.notificationService.SendTest(ctx.Locals("userID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{"devices": devices})
+}
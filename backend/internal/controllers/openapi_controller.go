@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/openapi"
+)
+
+type OpenAPIController struct {
+	spec *openapi.Spec
+}
+
+func NewOpenAPIController(spec *openapi.Spec) *OpenAPIController {
+	return &OpenAPIController{spec: spec}
+}
+
+// GetJSON — GET /api/v1/openapi.json
+func (c *OpenAPIController) GetJSON(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return ctx.Send(c.spec.JSON())
+}
+
+// GetYAML — GET /api/v1/openapi.yaml
+func (c *OpenAPIController) GetYAML(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, "application/yaml")
+	return ctx.Send(c.spec.YAML())
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/payments"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type PaymentController struct {
+	paymentService *services.PaymentService
+}
+
+func NewPaymentController(paymentService *services.PaymentService) *PaymentController {
+	return &PaymentController{paymentService: paymentService}
+}
+
+// GetPackages — GET /api/payments/packages
+func (c *PaymentController) GetPackages(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.paymentService.GetPackages())
+}
+
+type CheckoutRequest struct {
+	Package string `json:"package" validate:"required,max=40"`
+	Coupon  string `json:"coupon" validate:"max=40"`
+}
+
+// Checkout — POST /api/payments/checkout: returns the Snap token and redirect URL,
+// or a paid payment when a coupon covers the whole price.
+func (c *PaymentController) Checkout(ctx *fiber.Ctx) error {
+	var req CheckoutRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	payment, err := c.paymentService.Checkout(ctx.UserContext(),
+		ctx.Locals("familyID").(string), ctx.Locals("userID").(string), req.Package, req.Coupon)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(payment)
+}
+
+// GetPayments — GET /api/payments: the family's payment history.
+func (c *PaymentController) GetPayments(ctx *fiber.Ctx) error {
+	list, err := c.paymentService.GetPayments(ctx.Locals("familyID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(list)
+}
+
+// GetPayment — GET /api/payments/:orderId
+func (c *PaymentController) GetPayment(ctx *fiber.Ctx) error {
+	payment, err := c.paymentService.GetPayment(ctx.Locals("familyID").(string), ctx.Params("orderId"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(payment)
+}
+
+// Notification — POST /api/payments/midtrans/notification (public, signed by Midtrans).
+// Anything other than 2xx makes Midtrans retry, so only a bad signature or an
+// unknown order is rejected.
+func (c *PaymentController) Notification(ctx *fiber.Ctx) error {
+	var n payments.Notification
+	if err := json.Unmarshal(ctx.Body(), &n); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := c.paymentService.HandleNotification(n, ctx.Body()); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"message": "OK"})
+}
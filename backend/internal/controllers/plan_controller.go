@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type PlanController struct {
+	planService *services.PlanService
+}
+
+func NewPlanController(planService *services.PlanService) *PlanController {
+	return &PlanController{planService: planService}
+}
+
+// GetPlan — GET /api/family/plan: plan, expiry, grace period, usage and read-only items.
+func (c *PlanController) GetPlan(ctx *fiber.Ctx) error {
+	status, err := c.planService.GetPlanStatus(ctx.Locals("familyID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(status)
+}
+
+type SetPlanRequest struct {
+	Plan      string `json:"plan" validate:"required,max=20"`
+	ExpiresAt string `json:"expiresAt"`             // YYYY-MM-DD (end of that day, WIB) or RFC 3339
+	Days      int    `json:"days" validate:"min=0"` // alternative to expiresAt, counted from now
+}
+
+// SetPlan — PUT /api/admin/family/:id/plan (super admin)
+func (c *PlanController) SetPlan(ctx *fiber.Ctx) error {
+	var req SetPlanRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	var expiresAt *time.Time
+	switch {
+	case req.ExpiresAt != "":
+		t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			d, errDate := time.ParseInLocation("2006-01-02", req.ExpiresAt, utils.LoadLocation("Asia/Jakarta"))
+			if errDate != nil {
+				return httperr.Respond(ctx, services.InvalidField("expiresAt", "date_time", nil))
+			}
+			t = d.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		expiresAt = &t
+	case req.Days > 0:
+		t := time.Now().AddDate(0, 0, req.Days)
+		expiresAt = &t
+	}
+
+	family, err := c.planService.SetPlan(ctx.Params("id"), req.Plan, expiresAt)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(family)
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type PointRuleController struct {
+	pointRuleService *services.PointRuleService
+}
+
+func NewPointRuleController(pointRuleService *services.PointRuleService) *PointRuleController {
+	return &PointRuleController{pointRuleService: pointRuleService}
+}
+
+func (c *PointRuleController) GetRules(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	rules, err := c.pointRuleService.GetRules(familyID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(rules)
+}
+
+func (c *PointRuleController) CreateRule(ctx *fiber.Ctx) error {
+	var req services.PointRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	rule, err := c.pointRuleService.CreateRule(familyID, req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(rule)
+}
+
+func (c *PointRuleController) UpdateRule(ctx *fiber.Ctx) error {
+	var req services.PointRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	rule, err := c.pointRuleService.UpdateRule(familyID, ctx.Params("id"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(rule)
+}
+
+func (c *PointRuleController) DeleteRule(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	if err := c.pointRuleService.DeleteRule(familyID, ctx.Params("id")); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type PromoController struct {
+	promoService *services.PromoService
+}
+
+func NewPromoController(promoService *services.PromoService) *PromoController {
+	return &PromoController{promoService: promoService}
+}
+
+type CouponCodeRequest struct {
+	Code    string `json:"code" validate:"required,max=40"`
+	Package string `json:"package" validate:"max=40"` // required to quote
+}
+
+// QuoteCoupon — POST /api/coupons/validate: price of a package with the coupon applied.
+func (c *PromoController) QuoteCoupon(ctx *fiber.Ctx) error {
+	var req CouponCodeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	var v services.Violations
+	if req.Package == "" {
+		v.Add("package", "required", nil)
+	}
+	v.Validate(&req)
+	if err := v.Err(); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	quote, err := c.promoService.QuoteCoupon(ctx.Locals("familyID").(string), req.Code, req.Package)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(quote)
+}
+
+// RedeemCoupon — POST /api/coupons/redeem: free PREMIUM days from a free_days coupon.
+func (c *PromoController) RedeemCoupon(ctx *fiber.Ctx) error {
+	var req CouponCodeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	family, err := c.promoService.RedeemFreeDays(ctx.Locals("familyID").(string), req.Code)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"plan": family.Plan, "planExpiresAt": family.PlanExpiresAt})
+}
+
+// GetReferral — GET /api/referral: own code, bonus and invited families.
+func (c *PromoController) GetReferral(ctx *fiber.Ctx) error {
+	info, err := c.promoService.GetReferral(ctx.Locals("familyID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(info)
+}
+
+// ClaimReferral — POST /api/referral/claim { code }
+func (c *PromoController) ClaimReferral(ctx *fiber.Ctx) error {
+	var req CouponCodeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	if err := c.promoService.ClaimReferral(ctx.Locals("familyID").(string), req.Code); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"message": "Referral code applied"})
+}
+
+// GetCoupons — GET /api/admin/coupons (with usage stats)
+func (c *PromoController) GetCoupons(ctx *fiber.Ctx) error {
+	list, err := c.promoService.GetCoupons()
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(list)
+}
+
+// CreateCoupon — POST /api/admin/coupons
+func (c *PromoController) CreateCoupon(ctx *fiber.Ctx) error {
+	var req services.CouponInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	coupon, err := c.promoService.CreateCoupon(req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(coupon)
+}
+
+// UpdateCoupon — PUT /api/admin/coupons/:id
+func (c *PromoController) UpdateCoupon(ctx *fiber.Ctx) error {
+	var req services.CouponInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	coupon, err := c.promoService.UpdateCoupon(ctx.Params("id"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(coupon)
+}
+
+// DeleteCoupon — DELETE /api/admin/coupons/:id
+func (c *PromoController) DeleteCoupon(ctx *fiber.Ctx) error {
+	if err := c.promoService.DeleteCoupon(ctx.Params("id")); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// GetCouponUses — GET /api/admin/coupons/:id/redemptions
+func (c *PromoController) GetCouponUses(ctx *fiber.Ctx) error {
+	uses, err := c.promoService.GetCouponUses(ctx.Params("id"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(uses)
+}
+
+// GetReferralStats — GET /api/admin/referrals
+func (c *PromoController) GetReferralStats(ctx *fiber.Ctx) error {
+	stats, err := c.promoService.GetReferralStats()
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(stats)
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type ReportController struct {
+	reportService *services.ReportService
+}
+
+func NewReportController(reportService *services.ReportService) *ReportController {
+	return &ReportController{reportService: reportService}
+}
+
+// GetReport — GET /api/reports/:childId?period=week|season&date=YYYY-MM-DD&format=html|pdf
+func (c *ReportController) GetReport(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	report, err := c.reportService.BuildChildReport(familyID, ctx.Params("childId"), ctx.Query("period"), ctx.Query("date"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	format := ctx.Query("format", "pdf")
+	body, contentType, err := c.reportService.RenderChildReport(report, format)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	if format == "pdf" {
+		filename := fmt.Sprintf("rapor-%s-%s.pdf", utils.Slugify(report.ChildName), report.From)
+		ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	ctx.Set(fiber.HeaderContentType, contentType)
+	return ctx.Send(body)
+}
+
+type SendReportRequest struct {
+	Period string   `json:"period" validate:"oneof=week season"`
+	Date   string   `json:"date" validate:"date"`
+	To     []string `json:"to" validate:"max=10,dive,email"` // defaults to the requesting parent's e-mail
+}
+
+// SendReport — POST /api/reports/:childId/send, e.g. to share the rapor with grandparents.
+func (c *ReportController) SendReport(ctx *fiber.Ctx) error {
+	var req SendReportRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	if len(req.To) == 0 {
+		var parent models.User
+		if err := database.DB.First(&parent, "id = ?", ctx.Locals("userID")).Error; err == nil && parent.Email != nil {
+			req.To = []string{*parent.Email}
+		}
+	}
+
+	report, err := c.reportService.BuildChildReport(familyID, ctx.Params("childId"), req.Period, req.Date)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	if err := c.reportService.SendChildReport(ctx.Context(), report, req.To); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	return ctx.JSON(fiber.Map{"message": "Rapor sent", "to": req.To})
+}
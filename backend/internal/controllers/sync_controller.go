@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type SyncController struct {
+	syncService *services.SyncService
+}
+
+func NewSyncController(syncService *services.SyncService) *SyncController {
+	return &SyncController{syncService: syncService}
+}
+
+type SyncPushRequest struct {
+	DeviceID string               `json:"deviceId" validate:"max=100"`
+	Events   []services.SyncEvent `json:"events"` // checked one by one, see SyncService.Push
+}
+
+// Push — POST /api/sync: applies events recorded offline. The response has one
+// result per event, in request order; devices drop applied, duplicate and
+// rejected events and retry the ones with status "error".
+func (c *SyncController) Push(ctx *fiber.Ctx) error {
+	var req SyncPushRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	results, err := c.syncService.Push(ctx.Locals("familyID").(string), ctx.Locals("userID").(string), ctx.Locals("role").(string), req.Events)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	lang := httperr.Lang(ctx)
+	for i := range results {
+		results[i].Localize(lang)
+	}
+	return ctx.JSON(fiber.Map{"deviceId": req.DeviceID, "results": results})
+}
+
+// Changes — GET /api/sync/changes?since=<cursor>: what changed since the
+// cursor from the previous pull (a snapshot without one).
+func (c *SyncController) Changes(ctx *fiber.Ctx) error {
+	changes, err := c.syncService.Changes(ctx.Locals("familyID").(string), ctx.Locals("userID").(string), ctx.Locals("role").(string), ctx.Query("since"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(changes)
+}
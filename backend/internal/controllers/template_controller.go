@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type TemplateController struct {
+	templateService *services.TemplateService
+}
+
+func NewTemplateController(templateService *services.TemplateService) *TemplateController {
+	return &TemplateController{templateService: templateService}
+}
+
+// GetTemplates — GET /templates?ageBand=: the built-in catalog and the family's own templates.
+func (c *TemplateController) GetTemplates(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	templates, err := c.templateService.ListTemplates(familyID, ctx.Query("ageBand"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(templates)
+}
+
+func (c *TemplateController) GetTemplate(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	template, err := c.templateService.GetTemplate(familyID, ctx.Params("id"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(template)
+}
+
+// GetSharedTemplate — GET /templates/shared/:code: looks up a template another
+// family shared, to preview and apply it by ID.
+func (c *TemplateController) GetSharedTemplate(ctx *fiber.Ctx) error {
+	template, err := c.templateService.GetSharedTemplate(ctx.Params("code"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(template)
+}
+
+func (c *TemplateController) CreateTemplate(ctx *fiber.Ctx) error {
+	var req services.TemplateInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	template, err := c.templateService.CreateTemplate(familyID, req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(template)
+}
+
+// CreateFromFamily — POST /templates/from-family: saves the family's current
+// tasks, rewards, point rules and badges as a template.
+func (c *TemplateController) CreateFromFamily(ctx *fiber.Ctx) error {
+	var req services.TemplateFromFamilyRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	template, err := c.templateService.CreateFromFamily(familyID, req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(template)
+}
+
+func (c *TemplateController) UpdateTemplate(ctx *fiber.Ctx) error {
+	var req services.TemplateInput
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	template, err := c.templateService.UpdateTemplate(familyID, ctx.Params("id"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(template)
+}
+
+func (c *TemplateController) DeleteTemplate(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	if err := c.templateService.DeleteTemplate(familyID, ctx.Params("id")); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *TemplateController) ShareTemplate(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	template, err := c.templateService.ShareTemplate(familyID, ctx.Params("id"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(template)
+}
+
+func (c *TemplateController) UnshareTemplate(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	template, err := c.templateService.UnshareTemplate(familyID, ctx.Params("id"))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(template)
+}
+
+// Preview — POST /templates/:id/preview: what Apply would create, link,
+// leave alone or skip, without changing anything.
+func (c *TemplateController) Preview(ctx *fiber.Ctx) error {
+	return c.plan(ctx, c.templateService.Preview)
+}
+
+// Apply — POST /templates/:id/apply: applies the selected items. Applying
+// the same template again only adds what is missing.
+func (c *TemplateController) Apply(ctx *fiber.Ctx) error {
+	return c.plan(ctx, c.templateService.Apply)
+}
+
+func (c *TemplateController) plan(ctx *fiber.Ctx, run func(familyID, templateID string, sel services.TemplateSelection) (*services.TemplatePlan, error)) error {
+	var req services.TemplateSelection
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return httperr.Respond(ctx, services.ErrInvalidRequest)
+		}
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	plan, err := run(familyID, ctx.Params("id"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	plan.Localize(httperr.Lang(ctx))
+	return ctx.JSON(plan)
+}
+
+type TaskPresetRequest struct {
+	TemplateType string `json:"templateType" validate:"required,oneof=TK SD"`
+}
+
+// ApplyTaskPreset — POST /tasks/templates/apply: the tasks of the TK or SD
+// kelas 1-3 catalog template, for clients of the old magic template.
+func (c *TemplateController) ApplyTaskPreset(ctx *fiber.Ctx) error {
+	var req TaskPresetRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	tasks, err := c.templateService.ApplyTaskPreset(familyID, req.TemplateType)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Template applied successfully",
+		"tasks":   tasks,
+	})
+}
+
+type RewardPresetRequest struct {
+	Preset string `json:"preset" validate:"required,oneof=tk sd TK SD"`
+}
+
+// ApplyRewardPreset — POST /rewards/templates/apply: ApplyTaskPreset for rewards.
+func (c *TemplateController) ApplyRewardPreset(ctx *fiber.Ctx) error {
+	var req RewardPresetRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	rewards, err := c.templateService.ApplyRewardPreset(familyID, req.Preset)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Template Hadiah applied successfully",
+		"rewards": rewards,
+	})
+}
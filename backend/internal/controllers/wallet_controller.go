@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+type WalletController struct {
+	walletService *services.WalletService
+}
+
+func NewWalletController(walletService *services.WalletService) *WalletController {
+	return &WalletController{walletService: walletService}
+}
+
+// walletChildID resolves the child a wallet request is about. Children may only see their own wallet.
+func walletChildID(ctx *fiber.Ctx) (string, bool) {
+	childID := ctx.Params("childId")
+	if ctx.Locals("role") == "child" && childID != ctx.Locals("userID").(string) {
+		return "", false
+	}
+	return childID, true
+}
+
+func (c *WalletController) GetWallets(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+
+	wallets, err := c.walletService.GetWallets(familyID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(wallets)
+}
+
+func (c *WalletController) GetWallet(ctx *fiber.Ctx) error {
+	childID, ok := walletChildID(ctx)
+	if !ok {
+		return httperr.Respond(ctx, services.ErrForbidden)
+	}
+	familyID := ctx.Locals("familyID").(string)
+
+	wallet, err := c.walletService.GetWallet(familyID, childID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(wallet)
+}
+
+func (c *WalletController) ConfigureWallet(ctx *fiber.Ctx) error {
+	var req services.WalletSettings
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	wallet, err := c.walletService.ConfigureWallet(familyID, ctx.Params("childId"), req)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(wallet)
+}
+
+type CashoutRequest struct {
+	Points int    `json:"points" validate:"min=1"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+func (c *WalletController) RequestCashout(ctx *fiber.Ctx) error {
+	childID, ok := walletChildID(ctx)
+	if !ok {
+		return httperr.Respond(ctx, services.ErrForbidden)
+	}
+
+	var req CashoutRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	cashout, err := c.walletService.RequestCashout(familyID, childID, req.Points, req.Note)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(cashout)
+}
+
+type UpdateCashoutStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+}
+
+func (c *WalletController) UpdateCashoutStatus(ctx *fiber.Ctx) error {
+	var req UpdateCashoutStatusRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+
+	cashout, err := c.walletService.UpdateCashoutStatus(familyID, ctx.Params("id"), req.Status)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(cashout)
+}
+
+type PayoutRequest struct {
+	Amount int64  `json:"amount" validate:"min=1"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+func (c *WalletController) RecordPayout(ctx *fiber.Ctx) error {
+	var req PayoutRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(ctx, err)
+	}
+
+	familyID := ctx.Locals("familyID").(string)
+	parentID := ctx.Locals("userID").(string)
+
+	payout, err := c.walletService.RecordPayout(familyID, ctx.Params("childId"), parentID, req.Amount, req.Note)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(payout)
+}
+
+func (c *WalletController) GrantTHR(ctx *fiber.Ctx) error {
+	familyID := ctx.Locals("familyID").(string)
+	parentID := ctx.Locals("userID").(string)
+
+	thr, err := c.walletService.GrantTHR(familyID, ctx.Params("childId"), parentID)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(thr)
+}
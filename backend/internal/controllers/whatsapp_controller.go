@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
+)
+
+type WhatsappController struct {
+	whatsappService *services.WhatsappService
+}
+
+func NewWhatsappController(whatsappService *services.WhatsappService) *WhatsappController {
+	return &WhatsappController{whatsappService: whatsappService}
+}
+
+// GetSettings — GET /api/notifications/whatsapp: number and opt-in state.
+func (c *WhatsappController) GetSettings(ctx *fiber.Ctx) error {
+	settings, err := c.whatsappService.GetSettings(ctx.Locals("userID").(string))
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(settings)
+}
+
+// UpdateSettings — PUT /api/notifications/whatsapp: { whatsapp?, optIn? }.
+func (c *WhatsappController) UpdateSettings(ctx *fiber.Ctx) error {
+	var input services.WhatsappSettingsInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return httperr.Respond(ctx, services.ErrInvalidRequest)
+	}
+	settings, err := c.whatsappService.UpdateSettings(ctx.Locals("userID").(string), input)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(settings)
+}
+
+// VerifyWebhook — GET /api/whatsapp/webhook: Cloud API subscription handshake.
+func (c *WhatsappController) VerifyWebhook(ctx *fiber.Ctx) error {
+	if ctx.Query("hub.mode") != "subscribe" || !c.whatsappService.VerifyWebhook(ctx.Query("hub.verify_token")) {
+		return ctx.SendStatus(fiber.StatusForbidden)
+	}
+	return ctx.SendString(ctx.Query("hub.challenge"))
+}
+
+// Inbound — POST /api/whatsapp/webhook?token=...: incoming messages from any
+// provider; STOP / BERHENTI opts the sender out, MULAI / START back in.
+func (c *WhatsappController) Inbound(ctx *fiber.Ctx) error {
+	if !c.whatsappService.VerifyWebhook(ctx.Query("token")) {
+		return httperr.Respond(ctx, services.ErrInvalidToken)
+	}
+	messages := whatsapp.ParseInbound(ctx.Body(), ctx.Get(fiber.HeaderContentType))
+	updated, err := c.whatsappService.HandleInbound(messages)
+	if err != nil {
+		return httperr.Respond(ctx, err)
+	}
+	return ctx.JSON(fiber.Map{"received": len(messages), "updated": updated})
+}
@@ -1,29 +1,23 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
+	"strings"
 
-	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+	"github.com/username/ramadhan-ceria-backend/internal/migrations"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func ConnectDB() {
+func ConnectDB(cfg config.DB) {
 	var err error
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
-
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -36,31 +30,30 @@ func ConnectDB() {
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 
-	// Drop old unique index that prevented multiple completions per day
-	DB.Exec("DROP INDEX IF EXISTS idx_child_task_date")
+	log.Println("Database connected (PostgreSQL)")
+}
 
-	err = DB.AutoMigrate(
-		&models.Family{},
-		&models.User{},
-		&models.Task{},
-		&models.Reward{},
-		&models.DailyLog{},
-		&models.Redemption{},
-		&models.Announcement{},
-	)
+// RequireMigrated fails when the schema is behind this binary. The schema is
+// changed only by cmd/migrate, never on start-up.
+func RequireMigrated() error {
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Fatal("Failed to auto migrate database:", err)
+		return err
 	}
-
-	// Fix existing tasks: set repeatable tasks to unlimited (MaxPerDay=0)
-	DB.Exec(`UPDATE tasks SET max_per_day = 0 WHERE max_per_day IS NULL OR max_per_day = 1 AND (
-		LOWER(name) LIKE '%membantu%' OR LOWER(name) LIKE '%berbagi%' OR
-		LOWER(name) LIKE '%cuci%' OR LOWER(name) LIKE '%menyapu%' OR
-		LOWER(name) LIKE '%tadarus%' OR LOWER(name) LIKE '%quran%' OR
-		LOWER(name) LIKE '%al-quran%' OR LOWER(name) LIKE '%mengaji%' OR
-		LOWER(name) LIKE '%sedekah%' OR LOWER(name) LIKE '%infaq%' OR
-		LOWER(name) LIKE '%membaca%'
-	)`)
-
-	log.Println("Database connected and migrated successfully (PostgreSQL)")
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		names := make([]string, len(pending))
+		for i, m := range pending {
+			names[i] = fmt.Sprintf("%04d_%s", m.Version, m.Name)
+		}
+		return fmt.Errorf("%d pending migration(s): %s; run `go run ./cmd/migrate up`", len(pending), strings.Join(names, ", "))
+	}
+	return nil
 }
@@ -0,0 +1,225 @@
+// Package events is the per-family event bus behind the real-time stream.
+// Events are fanned out to subscribers in this process; with Listen they go
+// through Postgres NOTIFY so every API instance sees every event.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// Event types.
+const (
+	TaskCompleted      = "task.completed"
+	LogUndone          = "log.undone"
+	LogsUpdated        = "logs.updated"
+	RedemptionCreated  = "redemption.created"
+	RedemptionUpdated  = "redemption.updated"
+	AnnouncementPosted = "announcement.posted"
+	LeaderboardChanged = "leaderboard.changed"
+)
+
+// Channel is the Postgres NOTIFY channel shared by all instances.
+const Channel = "family_events"
+
+// maxNotifyPayload stays under Postgres' 8000 byte NOTIFY limit. Larger
+// events go out without data; clients refetch instead.
+const maxNotifyPayload = 7900
+
+// subscriberBuffer is how far a client may fall behind before it is dropped.
+const subscriberBuffer = 64
+
+type Event struct {
+	ID       string          `json:"id"`
+	FamilyID string          `json:"familyId,omitempty"` // empty = every family
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	At       time.Time       `json:"at"`
+}
+
+// Subscription receives a family's events on C. C is closed when the
+// subscription is closed or the client fell too far behind.
+type Subscription struct {
+	C        <-chan Event
+	c        chan Event
+	familyID string
+	bus      *Bus
+}
+
+func (s *Subscription) Close() {
+	s.bus.remove(s)
+}
+
+type Bus struct {
+	mu   sync.Mutex
+	subs map[string]map[*Subscription]struct{}
+
+	db   *gorm.DB // set by Listen; publishes through NOTIFY
+	dbMu sync.RWMutex
+}
+
+func New() *Bus {
+	return &Bus{subs: map[string]map[*Subscription]struct{}{}}
+}
+
+// Subscribe starts receiving events for a family.
+func (b *Bus) Subscribe(familyID string) *Subscription {
+	c := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: c, c: c, familyID: familyID, bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[familyID] == nil {
+		b.subs[familyID] = map[*Subscription]struct{}{}
+	}
+	b.subs[familyID][sub] = struct{}{}
+	return sub
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(sub)
+}
+
+func (b *Bus) removeLocked(sub *Subscription) {
+	family := b.subs[sub.familyID]
+	if _, ok := family[sub]; !ok {
+		return
+	}
+	delete(family, sub)
+	if len(family) == 0 {
+		delete(b.subs, sub.familyID)
+	}
+	close(sub.c)
+}
+
+// Subscribers counts open subscriptions on this instance.
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, family := range b.subs {
+		n += len(family)
+	}
+	return n
+}
+
+// dispatch hands an event to local subscribers. A subscriber whose buffer is
+// full is dropped rather than blocking everyone else.
+func (b *Bus) dispatch(ev Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	deliver := func(family map[*Subscription]struct{}) {
+		for sub := range family {
+			select {
+			case sub.c <- ev:
+			default:
+				b.removeLocked(sub)
+			}
+		}
+	}
+	if ev.FamilyID == "" {
+		for _, family := range b.subs {
+			deliver(family)
+		}
+		return
+	}
+	deliver(b.subs[ev.FamilyID])
+}
+
+// Publish sends an event to a family (or to everyone with an empty familyID).
+// It never fails the caller: errors are logged. A nil Bus ignores events.
+func (b *Bus) Publish(familyID, eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+	ev := Event{ID: uuid.New().String(), FamilyID: familyID, Type: eventType, At: time.Now()}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			log.Printf("events: %s: %v", eventType, err)
+			return
+		}
+		ev.Data = raw
+	}
+
+	b.dbMu.RLock()
+	db := b.db
+	b.dbMu.RUnlock()
+	if db == nil {
+		b.dispatch(ev)
+		return
+	}
+
+	payload, _ := json.Marshal(ev)
+	if len(payload) > maxNotifyPayload {
+		ev.Data = nil
+		payload, _ = json.Marshal(ev)
+	}
+	if err := db.Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error; err != nil {
+		log.Printf("events: notify %s: %v", eventType, err)
+		b.dispatch(ev) // at least this instance's clients get it
+	}
+}
+
+// Listen switches publishing to Postgres NOTIFY and keeps a dedicated
+// connection LISTENing on Channel, reconnecting with backoff, until ctx is done.
+func (b *Bus) Listen(ctx context.Context, db *gorm.DB, dsn string) {
+	b.dbMu.Lock()
+	b.db = db
+	b.dbMu.Unlock()
+
+	go func() {
+		backoff := time.Second
+		for ctx.Err() == nil {
+			started := time.Now()
+			err := b.listen(ctx, dsn)
+			if ctx.Err() != nil {
+				return
+			}
+			if time.Since(started) > time.Minute {
+				backoff = time.Second
+			}
+			log.Printf("events: listener stopped: %v (retrying in %s)", err, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+func (b *Bus) listen(ctx context.Context, dsn string) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			log.Printf("events: bad payload: %v", err)
+			continue
+		}
+		b.dispatch(ev)
+	}
+}
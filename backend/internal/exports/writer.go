@@ -0,0 +1,121 @@
+// Package exports writes tabular data as CSV or XLSX, row by row.
+package exports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RowWriter receives a header and then rows; Close flushes everything to the underlying writer.
+type RowWriter interface {
+	Header(columns []string) error
+	Row(values []interface{}) error
+	Close() error
+}
+
+// Formats lists the supported formats.
+var Formats = []string{"csv", "xlsx"}
+
+// ErrUnknownFormat is returned for a format other than csv or xlsx.
+var ErrUnknownFormat = errors.New("format must be csv or xlsx")
+
+// ContentType returns the MIME type and file extension of format.
+func ContentType(format string) (string, string, error) {
+	switch format {
+	case "", "csv":
+		return "text/csv; charset=utf-8", "csv", nil
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx", nil
+	}
+	return "", "", ErrUnknownFormat
+}
+
+// NewWriter returns a RowWriter for format ("csv" or "xlsx"). sheet names the XLSX worksheet.
+func NewWriter(w io.Writer, format, sheet string) (RowWriter, error) {
+	switch format {
+	case "", "csv":
+		// UTF-8 BOM so Excel opens names like "Sholat Isya ⭐" correctly.
+		if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "xlsx":
+		return newXLSXWriter(w, sheet)
+	}
+	return nil, ErrUnknownFormat
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Header(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) Row(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		if v != nil {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, sheet string) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: f, stream: stream, row: 1}, nil
+}
+
+func (x *xlsxWriter) Header(columns []string) error {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = c
+	}
+	return x.Row(values)
+}
+
+func (x *xlsxWriter) Row(values []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	x.row++
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
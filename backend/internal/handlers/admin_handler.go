@@ -6,84 +6,70 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/events"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 	"github.com/username/ramadhan-ceria-backend/internal/utils"
 )
 
 func GetAllFamilies(c *fiber.Ctx) error {
 	var families []models.Family
 	if err := database.DB.Preload("Users").Preload("Tasks").Preload("Rewards").Order("created_at DESC").Find(&families).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(families)
 }
 
-func UpdateFamilyPlan(c *fiber.Ctx) error {
-	id := c.Params("id")
-
-	type PlanRequest struct {
-		Plan string `json:"plan"`
-	}
-	var req PlanRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
-	}
-
-	var family models.Family
-	if err := database.DB.First(&family, "id = ?", id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Family not found"})
-	}
-
-	family.Plan = req.Plan
-	if err := database.DB.Save(&family).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not update plan"})
-	}
-
-	return c.JSON(family)
-}
-
 // --- Admin Create Family + Parent Account ---
 func AdminCreateFamily(c *fiber.Ctx) error {
 	type CreateFamilyRequest struct {
-		FamilyName string `json:"familyName"`
-		ParentName string `json:"parentName"`
-		Email      string `json:"email"`
-		Password   string `json:"password"`
-		Plan       string `json:"plan"`
+		FamilyName string `json:"familyName" validate:"required,max=100"`
+		ParentName string `json:"parentName" validate:"required,max=100"`
+		Email      string `json:"email" validate:"required,email,max=255"`
+		Password   string `json:"password" validate:"required,max=72"`
+		Plan       string `json:"plan" validate:"max=20"`
 	}
 
 	var req CreateFamilyRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Data tidak valid"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	if req.FamilyName == "" || req.ParentName == "" || req.Email == "" || req.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Semua field wajib diisi"})
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	// Check if email already exists
 	var existingUser models.User
 	if err := database.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Email sudah terdaftar"})
+		return httperr.Respond(c, services.ErrEmailTaken)
 	}
 
+	req.Plan = strings.ToUpper(req.Plan)
 	if req.Plan == "" {
-		req.Plan = "FREE"
+		req.Plan = services.PlanFree
+	}
+	if _, err := services.FindPlan(req.Plan); err != nil {
+		return httperr.Respond(c, services.InvalidField("plan", "unknown_value", map[string]interface{}{"value": req.Plan}))
 	}
 
 	// Create family
 	family := models.Family{
 		Name: req.FamilyName,
-		Plan: strings.ToUpper(req.Plan),
+		Plan: req.Plan,
+	}
+	if req.Plan != services.PlanFree {
+		expiresAt := time.Now().AddDate(0, 0, services.DefaultPremiumDays)
+		family.PlanExpiresAt = &expiresAt
 	}
 	if err := database.DB.Create(&family).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal membuat keluarga"})
+		return httperr.Respond(c, err)
 	}
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengenkripsi password"})
+		return httperr.Respond(c, err)
 	}
 
 	// Create parent user
@@ -99,7 +85,7 @@ func AdminCreateFamily(c *fiber.Ctx) error {
 	if err := database.DB.Create(&parentUser).Error; err != nil {
 		// Rollback family
 		database.DB.Unscoped().Delete(&family)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal membuat akun orang tua"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -115,32 +101,11 @@ func AdminDeleteFamily(c *fiber.Ctx) error {
 
 	var family models.Family
 	if err := database.DB.First(&family, "id = ?", id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Keluarga tidak ditemukan"})
+		return httperr.Respond(c, services.ErrFamilyNotFound)
 	}
 
-	// Delete all related data in order (foreign key constraints)
-	tx := database.DB.Begin()
-
-	// Get all children of this family
-	var userIDs []string
-	tx.Model(&models.User{}).Where("family_id = ?", id).Pluck("id", &userIDs)
-
-	if len(userIDs) > 0 {
-		// Delete redemptions by children
-		tx.Unscoped().Where("child_id IN ?", userIDs).Delete(&models.Redemption{})
-		// Delete daily logs by children
-		tx.Unscoped().Where("child_id IN ?", userIDs).Delete(&models.DailyLog{})
-	}
-
-	// Delete rewards, tasks, users, then family
-	tx.Unscoped().Where("family_id = ?", id).Delete(&models.Reward{})
-	tx.Unscoped().Where("family_id = ?", id).Delete(&models.Task{})
-	tx.Unscoped().Where("family_id = ?", id).Delete(&models.User{})
-	tx.Unscoped().Delete(&family)
-
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menghapus keluarga"})
+	if err := services.DeleteFamily(family.ID); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	return c.JSON(fiber.Map{"message": "Keluarga berhasil dihapus beserta semua datanya"})
@@ -181,25 +146,24 @@ func GetAdminStats(c *fiber.Ctx) error {
 func GetAnnouncements(c *fiber.Ctx) error {
 	var announcements []models.Announcement
 	if err := database.DB.Order("created_at DESC").Find(&announcements).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(announcements)
 }
 
 func CreateAnnouncement(c *fiber.Ctx) error {
 	type AnnouncementRequest struct {
-		Title   string `json:"title"`
-		Message string `json:"message"`
-		Type    string `json:"type"`
+		Title   string `json:"title" validate:"required,max=200"`
+		Message string `json:"message" validate:"required,max=2000"`
+		Type    string `json:"type" validate:"oneof=info warning promo"`
 	}
 
 	var req AnnouncementRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	if req.Title == "" || req.Message == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Title and message are required"})
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	if req.Type == "" {
@@ -214,8 +178,9 @@ func CreateAnnouncement(c *fiber.Ctx) error {
 	}
 
 	if err := database.DB.Create(&announcement).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create announcement"})
+		return httperr.Respond(c, err)
 	}
+	eventBus.Publish("", events.AnnouncementPosted, announcement)
 
 	return c.Status(fiber.StatusCreated).JSON(announcement)
 }
@@ -224,7 +189,7 @@ func DeleteAnnouncement(c *fiber.Ctx) error {
 	id := c.Params("id")
 	result := database.DB.Delete(&models.Announcement{}, "id = ?", id)
 	if result.RowsAffected == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Announcement not found"})
+		return httperr.Respond(c, services.ErrAnnouncementNotFound)
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -233,7 +198,7 @@ func DeleteAnnouncement(c *fiber.Ctx) error {
 func GetActiveAnnouncements(c *fiber.Ctx) error {
 	var announcements []models.Announcement
 	if err := database.DB.Where("is_active = true").Order("created_at DESC").Find(&announcements).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(announcements)
 }
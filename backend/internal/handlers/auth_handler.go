@@ -5,27 +5,47 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
 	"golang.org/x/oauth2"
 )
 
+// Set by UseAuth at startup.
+var (
+	tokens        *utils.JWT
+	googleOAuth   *oauth2.Config // nil when Google login is not configured
+	loginRedirect string         // frontend page that receives ?token= after Google login
+)
+
+func UseAuth(jwt *utils.JWT, google *oauth2.Config, frontendURL string) {
+	tokens = jwt
+	googleOAuth = google
+	loginRedirect = strings.TrimRight(frontendURL, "/") + "/login"
+}
+
 type RegisterRequest struct {
-	Email      string `json:"email"`
-	Password   string `json:"password"`
-	Name       string `json:"name"`
-	FamilyName string `json:"familyName"`
-	Whatsapp   string `json:"whatsapp"`
-	Slug       string `json:"slug"`
+	Email         string `json:"email" validate:"required,email,max=255"`
+	Password      string `json:"password" validate:"required,max=72"`
+	Name          string `json:"name" validate:"required,max=100"`
+	FamilyName    string `json:"familyName" validate:"max=100"`
+	Whatsapp      string `json:"whatsapp" validate:"max=30,whatsapp"`
+	WhatsappOptIn bool   `json:"whatsappOptIn"` // consent to WhatsApp summaries and reminders
+	Slug          string `json:"slug" validate:"max=100"`
+	ReferralCode  string `json:"referralCode" validate:"max=40"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,max=72"`
 }
 
 type AuthResponse struct {
@@ -36,11 +56,16 @@ type AuthResponse struct {
 func Register(c *fiber.Ctx) error {
 	var req RegisterRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
-	if req.Email == "" || req.Password == "" || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email, password, and name are required"})
+	var phone *string
+	if req.Whatsapp != "" {
+		normalized, _ := whatsapp.NormalizePhone(req.Whatsapp)
+		phone = &normalized
 	}
 
 	// Auto-generate slug from family name if not provided
@@ -51,12 +76,21 @@ func Register(c *fiber.Ctx) error {
 	// Check family name uniqueness (approximate substitute for slug)
 	var existingFamily models.Family
 	if err := database.DB.Where("name = ?", req.FamilyName).First(&existingFamily).Error; err == nil {
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Family name already taken"})
+		return httperr.Respond(c, services.ErrFamilyNameTaken)
+	}
+
+	var referrer *models.Family
+	if req.ReferralCode != "" {
+		found, err := services.FindReferrer(req.ReferralCode)
+		if err != nil {
+			return httperr.Respond(c, err)
+		}
+		referrer = found
 	}
 
 	hashed, err := utils.HashPassword(req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not hash password"})
+		return httperr.Respond(c, err)
 	}
 
 	// Pre-generate IDs so we can set OwnerID before creating family
@@ -77,18 +111,13 @@ func Register(c *fiber.Ctx) error {
 	}
 	if err := tx.Create(&family).Error; err != nil {
 		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create family"})
+		return httperr.Respond(c, err)
 	}
 
 	user := models.User{
-		ID:    userID,
-		Email: &req.Email,
-		Whatsapp: func() *string {
-			if req.Whatsapp != "" {
-				return &req.Whatsapp
-			}
-			return nil
-		}(),
+		ID:           userID,
+		Email:        &req.Email,
+		Whatsapp:     phone,
 		PasswordHash: &hashed,
 		Name:         req.Name,
 		Role:         "parent",
@@ -97,14 +126,28 @@ func Register(c *fiber.Ctx) error {
 	}
 	if err := tx.Create(&user).Error; err != nil {
 		tx.Rollback()
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user"})
+		return httperr.Respond(c, err)
+	}
+
+	if phone != nil && req.WhatsappOptIn {
+		if err := services.RecordWhatsappConsent(tx, userID, *phone, "register"); err != nil {
+			tx.Rollback()
+			return httperr.Respond(c, err)
+		}
+	}
+
+	if referrer != nil {
+		if err := services.CreateReferral(tx, referrer.ID, familyID); err != nil {
+			tx.Rollback()
+			return httperr.Respond(c, err)
+		}
 	}
 
 	tx.Commit()
 
-	token, err := utils.GenerateToken(user.ID, family.ID, user.Role)
+	token, err := tokens.GenerateToken(user.ID, family.ID, user.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not generate token"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(AuthResponse{Token: token, Role: user.Role})
@@ -113,22 +156,25 @@ func Register(c *fiber.Ctx) error {
 func Login(c *fiber.Ctx) error {
 	var req LoginRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	var user models.User
 	err := database.DB.Where("email = ?", req.Email).First(&user).Error
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid credentials"})
+		return httperr.Respond(c, services.ErrInvalidCredentials)
 	}
 
 	if user.PasswordHash == nil || !utils.CheckPasswordHash(req.Password, *user.PasswordHash) {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid credentials"})
+		return httperr.Respond(c, services.ErrInvalidCredentials)
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.FamilyID, user.Role)
+	token, err := tokens.GenerateToken(user.ID, user.FamilyID, user.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not generate token"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.JSON(AuthResponse{Token: token, Role: user.Role})
@@ -137,40 +183,46 @@ func Login(c *fiber.Ctx) error {
 // --- Google OAuth ---
 
 func GoogleLogin(c *fiber.Ctx) error {
-	url := utils.GetGoogleOAuthConfig().AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	if googleOAuth == nil {
+		return httperr.Respond(c, services.ErrGoogleLoginOff)
+	}
+	url := googleOAuth.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
 	return c.Redirect(url)
 }
 
 func GoogleCallback(c *fiber.Ctx) error {
 	code := c.Query("code")
 	if code == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Code not found"})
+		return httperr.Respond(c, services.InvalidField("code", "required", nil))
 	}
 
-	token, err := utils.GetGoogleOAuthConfig().Exchange(context.Background(), code)
+	if googleOAuth == nil {
+		return httperr.Respond(c, services.ErrGoogleLoginOff)
+	}
+	token, err := googleOAuth.Exchange(context.Background(), code)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to exchange token"})
+		return httperr.Respond(c, services.ErrGoogleLoginFailed)
 	}
 
 	resp, err := http.Get("https://www.googleapis.com/oauth2/v2/userinfo?access_token=" + token.AccessToken)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get user info"})
+		return httperr.Respond(c, services.ErrGoogleLoginFailed)
 	}
 	defer resp.Body.Close()
 
 	userData, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read user info"})
+		return httperr.Respond(c, services.ErrGoogleLoginFailed)
 	}
 
 	var googleUser map[string]interface{}
 	if err := json.Unmarshal(userData, &googleUser); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to parse user info"})
+		return httperr.Respond(c, services.ErrGoogleLoginFailed)
 	}
 
 	email, ok := googleUser["email"].(string)
 	if !ok || email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email not found from Google"})
+		return httperr.Respond(c, services.ErrGoogleLoginFailed)
 	}
 
 	name, _ := googleUser["name"].(string)
@@ -200,7 +252,7 @@ func GoogleCallback(c *fiber.Ctx) error {
 		}
 		if err := tx.Create(&family).Error; err != nil {
 			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create family"})
+			return httperr.Respond(c, err)
 		}
 
 		user = models.User{
@@ -213,56 +265,51 @@ func GoogleCallback(c *fiber.Ctx) error {
 		}
 		if err := tx.Create(&user).Error; err != nil {
 			tx.Rollback()
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user"})
+			return httperr.Respond(c, err)
 		}
 
 		tx.Commit()
 	}
 
-	jwtToken, err := utils.GenerateToken(user.ID, user.FamilyID, user.Role)
+	jwtToken, err := tokens.GenerateToken(user.ID, user.FamilyID, user.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not generate token"})
+		return httperr.Respond(c, err)
 	}
 
 	// Important: We send the token via redirect so the frontend can capture it
 	// In production, might be better to set a cookie directly
-	return c.Redirect("http://localhost:3000/login?token=" + jwtToken)
+	return c.Redirect(loginRedirect + "?token=" + url.QueryEscape(jwtToken))
 }
 
 // --- Child Login (Netflix-style: Avatar + PIN) ---
 
 type LoginChildRequest struct {
-	ChildID string `json:"childId"`
-	PIN     string `json:"pin"`
+	ChildID string `json:"childId" validate:"required,uuid"`
+	PIN     string `json:"pin" validate:"required,pin"`
 }
 
 func LoginChild(c *fiber.Ctx) error {
 	var req LoginChildRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
-	}
-
-	if req.ChildID == "" || req.PIN == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "childId and pin are required"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	if len(req.PIN) != 4 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "PIN must be 4 digits"})
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	var child models.User
 	err := database.DB.Where("id = ? AND role = 'child'", req.ChildID).First(&child).Error
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Child not found"})
+		return httperr.Respond(c, services.ErrChildNotFound)
 	}
 
 	if child.PINHash == nil || !utils.CheckPasswordHash(req.PIN, *child.PINHash) {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid PIN"})
+		return httperr.Respond(c, services.ErrInvalidPIN)
 	}
 
-	token, err := utils.GenerateToken(child.ID, child.FamilyID, child.Role)
+	token, err := tokens.GenerateToken(child.ID, child.FamilyID, child.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not generate token"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.JSON(AuthResponse{Token: token, Role: child.Role})
@@ -273,11 +320,10 @@ func LoginChild(c *fiber.Ctx) error {
 func VerifyChildPIN(c *fiber.Ctx) error {
 	var req LoginChildRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	if req.ChildID == "" || req.PIN == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "childId and pin are required"})
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	// Verify child belongs to parent's family
@@ -286,19 +332,19 @@ func VerifyChildPIN(c *fiber.Ctx) error {
 	var child models.User
 	err := database.DB.Where("id = ? AND role = 'child' AND family_id = ?", req.ChildID, familyID).First(&child).Error
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Child not found in your family"})
+		return httperr.Respond(c, services.ErrChildNotInFamily)
 	}
 
 	// If child has no PIN set, allow access
 	if child.PINHash == nil || *child.PINHash == "" {
-		return c.JSON(fiber.Map{"verified": true, "child_id": child.ID, "name": child.Name})
+		return c.JSON(httperr.LegacyKeys(c, fiber.Map{"verified": true, "childId": child.ID, "name": child.Name}))
 	}
 
 	if !utils.CheckPasswordHash(req.PIN, *child.PINHash) {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "PIN salah"})
+		return httperr.Respond(c, services.ErrInvalidPIN)
 	}
 
-	return c.JSON(fiber.Map{"verified": true, "child_id": child.ID, "name": child.Name})
+	return c.JSON(httperr.LegacyKeys(c, fiber.Map{"verified": true, "childId": child.ID, "name": child.Name}))
 }
 
 // --- Get children list for family (public, for login screen) ---
@@ -312,12 +358,12 @@ type ChildProfile struct {
 func GetFamilyChildren(c *fiber.Ctx) error {
 	slug := c.Params("slug")
 	if slug == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Family slug is required"})
+		return httperr.Respond(c, services.InvalidField("slug", "required", nil))
 	}
 
 	var family models.Family
 	if err := database.DB.Where("id = ?", slug).First(&family).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Family not found"})
+		return httperr.Respond(c, services.ErrFamilyNotFound)
 	}
 
 	var children []models.User
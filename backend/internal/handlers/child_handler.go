@@ -3,14 +3,22 @@ package handlers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 	"github.com/username/ramadhan-ceria-backend/internal/utils"
 )
 
 type CreateChildRequest struct {
-	Name   string `json:"name"`
-	Avatar string `json:"avatar"`
-	PIN    string `json:"pin"`
+	Name   string `json:"name" validate:"required,max=100"`
+	Avatar string `json:"avatar" validate:"emoji"`
+	PIN    string `json:"pin" validate:"required,pin"`
+}
+
+type UpdateChildRequest struct {
+	Name   string `json:"name" validate:"required,max=100"`
+	Avatar string `json:"avatar" validate:"emoji"`
+	PIN    string `json:"pin" validate:"pin"` // "" keeps the current PIN
 }
 
 func GetChildren(c *fiber.Ctx) error {
@@ -18,7 +26,7 @@ func GetChildren(c *fiber.Ctx) error {
 
 	var children []models.User
 	if err := database.DB.Where("family_id = ? AND role = 'child'", familyID).Find(&children).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(children)
 }
@@ -26,26 +34,17 @@ func GetChildren(c *fiber.Ctx) error {
 func CreateChild(c *fiber.Ctx) error {
 	familyID := c.Locals("familyID").(string)
 
-	ok, err := utils.CheckChildLimit(familyID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
-	}
-	if !ok {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Child limit reached for FREE plan"})
-	}
-
 	var req CreateChildRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	if len(req.PIN) != 4 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "PIN must be exactly 4 digits"})
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	hashed, err := utils.HashPassword(req.PIN)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not hash pin"})
+		return httperr.Respond(c, err)
 	}
 
 	child := models.User{
@@ -56,7 +55,7 @@ func CreateChild(c *fiber.Ctx) error {
 		FamilyID:   familyID,
 	}
 	if err := database.DB.Create(&child).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create child"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(child)
@@ -66,32 +65,38 @@ func UpdateChild(c *fiber.Ctx) error {
 	id := c.Params("id")
 	familyID := c.Locals("familyID").(string)
 
-	var req CreateChildRequest
+	var req UpdateChildRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	var child models.User
 	if err := database.DB.Where("id = ? AND family_id = ?", id, familyID).First(&child).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Child not found"})
+		return httperr.Respond(c, services.ErrChildNotFound)
+	}
+
+	if readOnly, err := services.IsChildReadOnly(familyID, child.ID); err != nil {
+		return httperr.Respond(c, err)
+	} else if readOnly {
+		return httperr.Respond(c, services.ErrChildReadOnly)
 	}
 
 	child.Name = req.Name
 	child.AvatarIcon = req.Avatar
 
 	if req.PIN != "" {
-		if len(req.PIN) != 4 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "PIN must be exactly 4 digits"})
-		}
 		hashed, err := utils.HashPassword(req.PIN)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not hash pin"})
+			return httperr.Respond(c, err)
 		}
 		child.PINHash = &hashed
 	}
 
 	if err := database.DB.Save(&child).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not update child"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(child)
 }
@@ -102,7 +107,7 @@ func DeleteChild(c *fiber.Ctx) error {
 
 	result := database.DB.Where("id = ? AND family_id = ?", id, familyID).Delete(&models.User{})
 	if result.RowsAffected == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Child not found"})
+		return httperr.Respond(c, services.ErrChildNotFound)
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -0,0 +1,11 @@
+package handlers
+
+import "github.com/username/ramadhan-ceria-backend/internal/events"
+
+// eventBus publishes changes made by these handlers to the family event
+// stream; set by UseEvents at startup. A nil bus drops events.
+var eventBus *events.Bus
+
+func UseEvents(bus *events.Bus) {
+	eventBus = bus
+}
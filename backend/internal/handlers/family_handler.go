@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
 type UpdateFamilyRequest struct {
-	Title string `json:"title"`
+	Title             *string `json:"title" validate:"notblank,max=100"`
+	Timezone          *string `json:"timezone" validate:"notblank,timezone"`
+	EnableLeaderboard *bool   `json:"enableLeaderboard"`
+	SeasonStart       *string `json:"seasonStart" validate:"date"` // YYYY-MM-DD, "" clears it
+	SeasonEnd         *string `json:"seasonEnd" validate:"date"`   // YYYY-MM-DD, "" clears it
 }
 
 func GetFamilySettings(c *fiber.Ctx) error {
@@ -15,7 +23,7 @@ func GetFamilySettings(c *fiber.Ctx) error {
 
 	var family models.Family
 	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Family not found"})
+		return httperr.Respond(c, services.ErrFamilyNotFound)
 	}
 	return c.JSON(family)
 }
@@ -25,17 +33,50 @@ func UpdateFamilySettings(c *fiber.Ctx) error {
 
 	var req UpdateFamilyRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	var family models.Family
 	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Family not found"})
+		return httperr.Respond(c, services.ErrFamilyNotFound)
+	}
+
+	if req.Title != nil {
+		family.Name = *req.Title
+	}
+	if req.Timezone != nil {
+		family.Timezone = *req.Timezone
+	}
+	if req.EnableLeaderboard != nil {
+		family.EnableLeaderboard = *req.EnableLeaderboard
 	}
 
-	family.Name = req.Title
+	seasonStart := parseOptionalDate(req.SeasonStart, family.SeasonStart)
+	seasonEnd := parseOptionalDate(req.SeasonEnd, family.SeasonEnd)
+	if seasonStart != nil && seasonEnd != nil && seasonEnd.Before(*seasonStart) {
+		return httperr.Respond(c, services.InvalidField("seasonEnd", "not_before", map[string]interface{}{"other": "seasonStart"}))
+	}
+	family.SeasonStart = seasonStart
+	family.SeasonEnd = seasonEnd
+
 	if err := database.DB.Save(&family).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not update family"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(family)
 }
+
+// parseOptionalDate keeps current when value is absent and clears it when
+// value is empty. value has already passed the date rule.
+func parseOptionalDate(value *string, current *time.Time) *time.Time {
+	if value == nil {
+		return current
+	}
+	if *value == "" {
+		return nil
+	}
+	d, _ := time.Parse("2006-01-02", *value)
+	return &d
+}
@@ -5,96 +5,47 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
-type LogEntry struct {
-	TaskID   string `json:"taskId"`
-	Quantity int    `json:"quantity"`
-}
-
-type SaveLogsRequest struct {
-	ChildID string     `json:"childId"`
-	Date    string     `json:"date"` // format YYYY-MM-DD
-	Logs    []LogEntry `json:"logs"`
-}
-
 func GetLogs(c *fiber.Ctx) error {
 	childID := c.Query("childId")
 	dateStr := c.Query("date")
 
-	if childID == "" || dateStr == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "childId and date required"})
+	if err := services.Required("childId", childID, "date", dateStr); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid date format"})
+		return httperr.Respond(c, services.InvalidField("date", "date", nil))
 	}
 
 	var logs []models.DailyLog
 	if err := database.DB.Where("child_id = ? AND completed_date = ?", childID, date).Find(&logs).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(logs)
 }
 
-func SaveLogs(c *fiber.Ctx) error {
-	var req SaveLogsRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
-	}
-
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid date format"})
-	}
-
-	tx := database.DB.Begin()
-	for _, entry := range req.Logs {
-		var log models.DailyLog
-		result := tx.Where("child_id = ? AND task_id = ? AND completed_date = ?", req.ChildID, entry.TaskID, date).First(&log)
-		if result.Error == nil {
-			log.EarnedPoints = entry.Quantity
-			log.Status = "verified"
-			if err := tx.Save(&log).Error; err != nil {
-				tx.Rollback()
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update log"})
-			}
-		} else {
-			newLog := models.DailyLog{
-				ChildID:       req.ChildID,
-				TaskID:        entry.TaskID,
-				CompletedDate: date,
-				EarnedPoints:  entry.Quantity,
-				Status:        "verified",
-			}
-			if err := tx.Create(&newLog).Error; err != nil {
-				tx.Rollback()
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create log"})
-			}
-		}
-	}
-	tx.Commit()
-	return c.JSON(fiber.Map{"message": "Logs saved"})
-}
-
 // UndoDailyLog - Parent "Undo" a child's task completion (Trust but Verify)
 func UndoDailyLog(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	var log models.DailyLog
 	if err := database.DB.First(&log, "id = ?", id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Log not found"})
+		return httperr.Respond(c, services.ErrLogNotFound)
 	}
 
 	if log.Status == "undone" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Log already undone"})
+		return httperr.Respond(c, services.ErrLogUndone)
 	}
 
 	log.Status = "undone"
 	if err := database.DB.Save(&log).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not undo log"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -109,16 +60,16 @@ func RedoDailyLog(c *fiber.Ctx) error {
 
 	var log models.DailyLog
 	if err := database.DB.First(&log, "id = ?", id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Log not found"})
+		return httperr.Respond(c, services.ErrLogNotFound)
 	}
 
 	if log.Status == "verified" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Log already verified"})
+		return httperr.Respond(c, services.ErrLogVerified)
 	}
 
 	log.Status = "verified"
 	if err := database.DB.Save(&log).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not redo log"})
+		return httperr.Respond(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -3,17 +3,27 @@ package handlers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
+// redemptionService creates and decides redemptions (with notifications and
+// events); set by UseRedemptions at startup.
+var redemptionService *services.RedemptionService
+
+func UseRedemptions(s *services.RedemptionService) {
+	redemptionService = s
+}
+
 type RedemptionRequest struct {
-	ChildID  string `json:"childId"`
-	RewardID string `json:"rewardId"`
-	Quantity int    `json:"quantity"`
+	ChildID  string `json:"childId" validate:"uuid"`
+	RewardID string `json:"rewardId" validate:"required,uuid"`
+	Quantity int    `json:"quantity" validate:"min=0,max=100"` // 0 = 1
 }
 
 type UpdateRedemptionStatusRequest struct {
-	Status string `json:"status"` // "approved" or "rejected"
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
 }
 
 func GetRedemptions(c *fiber.Ctx) error {
@@ -21,7 +31,7 @@ func GetRedemptions(c *fiber.Ctx) error {
 
 	var redemptions []models.Redemption
 	if err := database.DB.Preload("Child").Preload("Reward").Joins("JOIN users ON users.id = redemptions.child_id").Where("users.family_id = ?", familyID).Find(&redemptions).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(redemptions)
 }
@@ -31,7 +41,7 @@ func GetRedemptionsByChild(c *fiber.Ctx) error {
 
 	var redemptions []models.Redemption
 	if err := database.DB.Preload("Reward").Where("child_id = ?", childID).Find(&redemptions).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(redemptions)
 }
@@ -39,89 +49,36 @@ func GetRedemptionsByChild(c *fiber.Ctx) error {
 func CreateRedemption(c *fiber.Ctx) error {
 	var req RedemptionRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	if req.Quantity <= 0 {
-		req.Quantity = 1
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
-	var reward models.Reward
-	if err := database.DB.First(&reward, "id = ?", req.RewardID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Reward not found"})
+	userID := c.Locals("userID").(string)
+	if c.Locals("role") == "child" {
+		req.ChildID = userID // children can only spend their own points
 	}
 
-	pointsRequired := reward.PointsRequired * req.Quantity
-
-	// Calculate balance
-	var totalPoints int64
-	err := database.DB.Model(&models.DailyLog{}).
-		Where("child_id = ? AND status = 'verified'", req.ChildID).
-		Select("COALESCE(SUM(earned_points), 0)").
-		Scan(&totalPoints).Error
+	redemption, err := redemptionService.Create(c.Locals("familyID").(string), req.ChildID, req.RewardID, req.Quantity, userID, nil)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Error calculating points"})
+		return httperr.Respond(c, err)
 	}
-
-	var spentPoints int64
-	err = database.DB.Model(&models.Redemption{}).
-		Where("child_id = ? AND status = 'approved'", req.ChildID).
-		Select("COALESCE(SUM(points_spent), 0)").
-		Scan(&spentPoints).Error
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Error calculating points"})
-	}
-
-	var pendingSpentPoints int64
-	err = database.DB.Model(&models.Redemption{}).
-		Where("child_id = ? AND status = 'pending'", req.ChildID).
-		Select("COALESCE(SUM(points_spent), 0)").
-		Scan(&pendingSpentPoints).Error
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Error calculating points"})
-	}
-
-	balance := totalPoints - spentPoints - pendingSpentPoints
-
-	if balance < int64(pointsRequired) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Insufficient points"})
-	}
-
-	redemption := models.Redemption{
-		ChildID:     req.ChildID,
-		RewardID:    req.RewardID,
-		PointsSpent: pointsRequired,
-		Status:      "pending",
-	}
-
-	if err := database.DB.Create(&redemption).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create redemption request"})
-	}
-
 	return c.Status(fiber.StatusCreated).JSON(redemption)
 }
 
 func UpdateRedemptionStatus(c *fiber.Ctx) error {
-	id := c.Params("id")
-
 	var req UpdateRedemptionStatusRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
-	}
-
-	if req.Status != "approved" && req.Status != "rejected" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid status"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
 	}
-
-	var redemption models.Redemption
-	if err := database.DB.First(&redemption, "id = ?", id).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Redemption not found"})
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
-	redemption.Status = req.Status
-	if err := database.DB.Save(&redemption).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not update status"})
+	redemption, err := redemptionService.UpdateStatus(c.Locals("familyID").(string), c.Params("id"), req.Status, c.Locals("userID").(string))
+	if err != nil {
+		return httperr.Respond(c, err)
 	}
-
 	return c.JSON(redemption)
 }
@@ -3,14 +3,15 @@ package handlers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
-	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
 type RewardRequest struct {
-	Name           string `json:"name"`
-	Icon           string `json:"icon"`
-	PointsRequired int    `json:"pointsRequired"`
+	Name           string `json:"name" validate:"required,max=100"`
+	Icon           string `json:"icon" validate:"emoji"`
+	PointsRequired int    `json:"pointsRequired" validate:"min=1,max=100000"`
 }
 
 func GetRewards(c *fiber.Ctx) error {
@@ -18,7 +19,7 @@ func GetRewards(c *fiber.Ctx) error {
 
 	var rewards []models.Reward
 	if err := database.DB.Where("family_id = ?", familyID).Find(&rewards).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(rewards)
 }
@@ -26,17 +27,12 @@ func GetRewards(c *fiber.Ctx) error {
 func CreateReward(c *fiber.Ctx) error {
 	familyID := c.Locals("familyID").(string)
 
-	ok, err := utils.CheckRewardLimit(familyID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
-	}
-	if !ok {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Reward limit reached for FREE plan"})
-	}
-
 	var req RewardRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	reward := models.Reward{
@@ -46,7 +42,7 @@ func CreateReward(c *fiber.Ctx) error {
 		FamilyID:       familyID,
 	}
 	if err := database.DB.Create(&reward).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create reward"})
+		return httperr.Respond(c, err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(reward)
 }
@@ -57,19 +53,28 @@ func UpdateReward(c *fiber.Ctx) error {
 
 	var req RewardRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	var reward models.Reward
 	if err := database.DB.Where("id = ? AND family_id = ?", id, familyID).First(&reward).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Reward not found"})
+		return httperr.Respond(c, services.ErrRewardNotFound)
+	}
+
+	if readOnly, err := services.IsRewardReadOnly(familyID, reward.ID); err != nil {
+		return httperr.Respond(c, err)
+	} else if readOnly {
+		return httperr.Respond(c, services.ErrRewardReadOnly)
 	}
 
 	reward.Name = req.Name
 	reward.Icon = req.Icon
 	reward.PointsRequired = req.PointsRequired
 	if err := database.DB.Save(&reward).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not update reward"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(reward)
 }
@@ -80,7 +85,7 @@ func DeleteReward(c *fiber.Ctx) error {
 
 	result := database.DB.Where("id = ? AND family_id = ?", id, familyID).Delete(&models.Reward{})
 	if result.RowsAffected == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Reward not found"})
+		return httperr.Respond(c, services.ErrRewardNotFound)
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -3,17 +3,22 @@ package handlers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
-	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
 func intPtr(v int) *int { return &v }
 
+// TaskRequest creates or replaces a task. MaxPerDay is capped like the
+// bulk log editor caps unlimited tasks.
 type TaskRequest struct {
-	Name      string `json:"name"`
-	Icon      string `json:"icon"`
-	Points    int    `json:"points"`
-	MaxPerDay *int   `json:"max_per_day"` // nil = keep default (1), 0 = unlimited
+	Name      string `json:"name" validate:"required,max=100"`
+	Icon      string `json:"icon" validate:"emoji"`
+	Points    int    `json:"points" validate:"min=1,max=1000"`
+	MaxPerDay *int   `json:"maxPerDay" validate:"min=0,max=20"`              // nil = keep default (1), 0 = unlimited
+	Category  string `json:"category" validate:"oneof=general prayer quran"` // "" = keep (general for new tasks)
+	Quantity  *int   `json:"quantity" validate:"min=1,max=100"`              // units per completion, e.g. Quran pages; nil = keep (1)
 }
 
 func GetTasks(c *fiber.Ctx) error {
@@ -21,7 +26,7 @@ func GetTasks(c *fiber.Ctx) error {
 
 	var tasks []models.Task
 	if err := database.DB.Where("family_id = ?", familyID).Find(&tasks).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(tasks)
 }
@@ -29,17 +34,12 @@ func GetTasks(c *fiber.Ctx) error {
 func CreateTask(c *fiber.Ctx) error {
 	familyID := c.Locals("familyID").(string)
 
-	ok, err := utils.CheckTaskLimit(familyID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
-	}
-	if !ok {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Task limit reached for FREE plan"})
-	}
-
 	var req TaskRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	task := models.Task{
@@ -47,13 +47,21 @@ func CreateTask(c *fiber.Ctx) error {
 		Icon:        req.Icon,
 		PointReward: req.Points,
 		MaxPerDay:   intPtr(1),
+		Category:    models.TaskCategoryGeneral,
+		Quantity:    1,
 		FamilyID:    familyID,
 	}
 	if req.MaxPerDay != nil {
 		task.MaxPerDay = req.MaxPerDay
 	}
+	if req.Category != "" {
+		task.Category = req.Category
+	}
+	if req.Quantity != nil {
+		task.Quantity = *req.Quantity
+	}
 	if err := database.DB.Create(&task).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create task"})
+		return httperr.Respond(c, err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(task)
 }
@@ -64,12 +72,21 @@ func UpdateTask(c *fiber.Ctx) error {
 
 	var req TaskRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return httperr.Respond(c, services.ErrInvalidRequest)
+	}
+	if err := services.Validate(&req); err != nil {
+		return httperr.Respond(c, err)
 	}
 
 	var task models.Task
 	if err := database.DB.Where("id = ? AND family_id = ?", id, familyID).First(&task).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Task not found"})
+		return httperr.Respond(c, services.ErrTaskNotFound)
+	}
+
+	if readOnly, err := services.IsTaskReadOnly(familyID, task.ID); err != nil {
+		return httperr.Respond(c, err)
+	} else if readOnly {
+		return httperr.Respond(c, services.ErrTaskReadOnly)
 	}
 
 	task.Name = req.Name
@@ -78,8 +95,14 @@ func UpdateTask(c *fiber.Ctx) error {
 	if req.MaxPerDay != nil {
 		task.MaxPerDay = req.MaxPerDay
 	}
+	if req.Category != "" {
+		task.Category = req.Category
+	}
+	if req.Quantity != nil {
+		task.Quantity = *req.Quantity
+	}
 	if err := database.DB.Save(&task).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not update task"})
+		return httperr.Respond(c, err)
 	}
 	return c.JSON(task)
 }
@@ -90,7 +113,7 @@ func DeleteTask(c *fiber.Ctx) error {
 
 	result := database.DB.Where("id = ? AND family_id = ?", id, familyID).Delete(&models.Task{})
 	if result.RowsAffected == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Task not found"})
+		return httperr.Respond(c, services.ErrTaskNotFound)
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -0,0 +1,161 @@
+// Package httperr turns errors into HTTP responses. Every handler answers a
+// failure with Respond, so status codes and the error envelope
+//
+//	{"code": "task_not_found", "message": "Misi tidak ditemukan", "details": [...]}
+//
+// are decided in one place. Messages are localized from Accept-Language.
+package httperr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/i18n"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+// Envelope is the body of every error response.
+type Envelope struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []Detail `json:"details,omitempty"`
+	// Error repeats Message on deprecated /api routes, whose clients read it.
+	Error string `json:"error,omitempty"`
+}
+
+// Detail is one invalid field; Code is the rule it broke ("required").
+type Detail struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var statuses = map[services.ErrorKind]int{
+	services.KindInternal:      fiber.StatusInternalServerError,
+	services.KindInvalid:       fiber.StatusBadRequest,
+	services.KindUnauthorized:  fiber.StatusUnauthorized,
+	services.KindForbidden:     fiber.StatusForbidden,
+	services.KindNotFound:      fiber.StatusNotFound,
+	services.KindConflict:      fiber.StatusConflict,
+	services.KindTooLarge:      fiber.StatusRequestEntityTooLarge,
+	services.KindUnprocessable: fiber.StatusUnprocessableEntity,
+	services.KindUnavailable:   fiber.StatusServiceUnavailable,
+}
+
+const legacyKey = "httperr.legacy"
+
+// MarkLegacy makes error responses of this request carry "error" as well.
+func MarkLegacy(c *fiber.Ctx) {
+	c.Locals(legacyKey, true)
+}
+
+// IsLegacy reports whether the request came in through an unversioned alias.
+func IsLegacy(c *fiber.Ctx) bool {
+	legacy, _ := c.Locals(legacyKey).(bool)
+	return legacy
+}
+
+// LegacyKeys adds a snake_case copy of every camelCase key in body, nested
+// objects included, when the request came in through an unversioned alias
+// (childId → child_id), for clients written against the old response names.
+func LegacyKeys(c *fiber.Ctx, body interface{}) interface{} {
+	if !IsLegacy(c) {
+		return body
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return body
+	}
+	return snakeKeys(value)
+}
+
+func snakeKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = snakeKeys(item)
+		}
+		for key, item := range v {
+			if snake := snakeCase(key); snake != key {
+				v[snake] = item
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = snakeKeys(item)
+		}
+	}
+	return value
+}
+
+// snakeCase converts camelCase names; PascalCase model fields such as
+// FamilyID are left alone.
+func snakeCase(name string) string {
+	if name == "" || !unicode.IsLower(rune(name[0])) {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Lang is the response language negotiated from Accept-Language.
+func Lang(c *fiber.Ctx) string {
+	return i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// Respond writes err as an error envelope. Errors that are not a
+// *services.Error are logged and answered as internal_error.
+func Respond(c *fiber.Ctx, err error) error {
+	var domainErr *services.Error
+	if !errors.As(err, &domainErr) {
+		log.Printf("%s %s: %v", c.Method(), c.OriginalURL(), err)
+		domainErr = services.ErrInternal
+	}
+
+	lang := Lang(c)
+	env := Envelope{Code: domainErr.Code, Message: domainErr.Message(lang)}
+	for _, d := range domainErr.Details {
+		env.Details = append(env.Details, Detail{Field: d.Field, Code: d.Rule, Message: d.Message(lang)})
+	}
+	if IsLegacy(c) {
+		env.Error = env.Message
+	}
+
+	c.Set(fiber.HeaderContentLanguage, lang)
+	return c.Status(statuses[domainErr.Kind]).JSON(env)
+}
+
+// Handler is the app's fiber.Config.ErrorHandler. It covers errors raised
+// by Fiber itself, like unknown routes and oversized bodies.
+func Handler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		switch {
+		case fiberErr.Code == fiber.StatusNotFound:
+			err = services.ErrRouteNotFound
+		case fiberErr.Code == fiber.StatusRequestEntityTooLarge:
+			err = services.ErrPayloadTooLarge
+		case fiberErr.Code < fiber.StatusInternalServerError:
+			err = services.ErrInvalidRequest
+		}
+	}
+	return Respond(c, err)
+}
@@ -0,0 +1,171 @@
+package i18n
+
+var en = map[string]string{
+	// Requests and access
+	"invalid_request":         "Invalid request body",
+	"validation_failed":       "Validation failed",
+	"internal_error":          "Internal server error",
+	"route_not_found":         "Route not found",
+	"payload_too_large":       "Request body is too large",
+	"missing_token":           "Missing token",
+	"invalid_token":           "Invalid or expired token",
+	"forbidden":               "Forbidden",
+	"parent_required":         "Only parents can do this",
+	"child_required":          "Only children can do this",
+	"admin_required":          "Requires admin role",
+	"super_admin_required":    "Requires super admin role",
+	"idempotency_key_invalid": "Invalid Idempotency-Key",
+	"idempotency_key_reused":  "Idempotency-Key reused with a different request",
+	"idempotency_in_progress": "Request with this Idempotency-Key is still in progress",
+
+	// Accounts
+	"invalid_credentials": "Invalid email or password",
+	"invalid_pin":         "Invalid PIN",
+	"email_taken":         "Email already registered",
+	"family_name_taken":   "Family name already taken",
+	"google_login_failed": "Google sign-in failed, please try again",
+	"google_login_off":    "Google sign-in is not configured",
+	"family_not_found":    "Family not found",
+	"user_not_found":      "User not found",
+	"child_not_found":     "Child not found",
+	"child_not_in_family": "Child does not belong to your family",
+
+	// Tasks, logs and rewards
+	"task_not_found":         "Task not found",
+	"task_already_completed": "Task already completed today",
+	"log_not_found":          "Log not found",
+	"log_not_verified":       "Log already undone or not verified",
+	"log_already_undone":     "Log already undone",
+	"log_already_verified":   "Log already verified",
+	"no_tasks_to_update":     "No tasks to update",
+	"duplicate_task":         "Duplicate task in request",
+	"reward_not_found":       "Reward not found",
+	"redemption_not_found":   "Redemption not found",
+	"insufficient_points":    "Insufficient points",
+	"announcement_not_found": "Announcement not found",
+
+	// Templates and badges
+	"template_not_found":    "Template not found",
+	"template_built_in":     "Built-in templates cannot be changed",
+	"template_task_missing": "Needs the task \"{task}\" from this template, apply it too",
+	"badge_not_found":       "Badge not found",
+
+	// Plans and entitlements
+	"child_read_only":       "Child is read-only on the FREE plan",
+	"task_read_only":        "Task is read-only on the FREE plan",
+	"reward_read_only":      "Reward is read-only on the FREE plan",
+	"plan_limit_reached":    "{item} limit reached for the {plan} plan",
+	"plan_feature_missing":  "{feature} is not included in the {plan} plan. Please upgrade your plan.",
+	"plan_not_found":        "Plan not found",
+	"plan_exists":           "Plan already exists",
+	"plan_in_use":           "Plan is still in use",
+	"plan_built_in":         "Built-in plans cannot be deleted",
+	"limit.children":        "Child",
+	"limit.tasks":           "Task",
+	"limit.rewards":         "Reward",
+	"feature.analytics":     "Analytics",
+	"feature.leaderboard":   "Leaderboard",
+	"feature.reports":       "Reports",
+	"feature.exports":       "Exports",
+	"feature.custom_badges": "Custom badges",
+
+	// Family settings, insights and sync
+	"leaderboard_disabled":  "Leaderboard is disabled for this family",
+	"season_not_configured": "Season is not configured for this family",
+	"no_recipient":          "No recipient e-mail address",
+	"event_already_applied": "Event already applied",
+	"unknown_event_type":    "Unknown event type",
+
+	// Point rules, goals, wallets and jobs
+	"rule_not_found":              "Rule not found",
+	"goal_not_found":              "Goal not found",
+	"wallet_not_found":            "Wallet not found",
+	"wallet_inactive":             "Wallet is not active",
+	"insufficient_wallet_balance": "Insufficient wallet balance",
+	"cashout_not_found":           "Cash-out not found",
+	"cashout_processed":           "Cash-out already processed",
+	"thr_disabled":                "THR is not enabled for this wallet",
+	"thr_already_granted":         "THR already granted for this season",
+	"job_not_found":               "Job not found",
+	"job_paused":                  "Job is paused",
+	"deletion_not_found":          "No deletion request",
+	"deletion_scheduled":          "Deletion already scheduled",
+	"deletion_parent_only":        "Only a parent of this family can delete it",
+	"confirmation_expired":        "Confirmation token expired",
+	"confirmation_invalid":        "Invalid confirmation token",
+	"archive_unsupported_version": "Unsupported archive version",
+	"archive_no_parent":           "Archive has no parent account",
+	"archive_invalid_date":        "Invalid date in archive",
+	"push_not_configured":         "Push notifications are not configured",
+	"subscription_not_found":      "Subscription not found",
+	"no_push_subscriptions":       "No push subscriptions for this user",
+	"whatsapp_number_missing":     "Add a WhatsApp number before opting in",
+
+	// Payments, coupons and referrals
+	"payments_not_configured":     "Payments are not configured",
+	"payment_gateway_unavailable": "Payment gateway unavailable",
+	"payment_not_found":           "Payment not found",
+	"package_not_found":           "Package not found",
+	"payment_parent_only":         "Only a parent can pay for the family",
+	"invalid_signature":           "Invalid signature",
+	"amount_mismatch":             "Amount mismatch",
+	"coupon_not_found":            "Coupon not found",
+	"coupon_not_valid_now":        "Coupon is not valid right now",
+	"coupon_already_used":         "Coupon already used by this family",
+	"coupon_limit_reached":        "Coupon usage limit reached",
+	"coupon_not_at_checkout":      "Coupon cannot be used at checkout",
+	"coupon_checkout_only":        "Coupon is only valid at checkout",
+	"coupon_code_taken":           "Coupon code already exists",
+	"coupon_in_use":               "Coupon has been used, deactivate it instead",
+	"referral_invalid":            "Invalid referral code",
+	"referral_own":                "Cannot use your own referral code",
+	"referral_claimed":            "Referral already claimed",
+	"referral_after_payment":      "Referral codes can only be claimed before the first payment",
+
+	// Field rules; {field} is the JSON name
+	"field.required":       "{field} is required",
+	"field.positive":       "{field} must be greater than 0",
+	"field.not_negative":   "{field} must not be negative",
+	"field.between":        "{field} must be between {min} and {max}",
+	"field.min":            "{field} must be at least {min}",
+	"field.max":            "{field} must be at most {max}",
+	"field.min_length":     "{field} must be at least {min} characters",
+	"field.max_length":     "{field} must be at most {max} characters",
+	"field.length_between": "{field} must be {min}-{max} characters",
+	"field.min_items":      "{field} must have at least {min} items",
+	"field.max_items":      "{field} must have at most {max} items",
+	"field.one_of":         "{field} must be one of {values}",
+	"field.date":           "{field} must be a date (YYYY-MM-DD)",
+	"field.date_time":      "{field} must be an RFC 3339 timestamp",
+	"field.time":           "{field} must be a time (HH:MM)",
+	"field.uuid":           "{field} must be a UUID",
+	"field.email":          "{field} must be an e-mail address",
+	"field.format":         "{field} has an invalid format",
+	"field.plan_code":      "{field} must be 2-20 characters of A-Z, 0-9 and _",
+	"field.pin":            "{field} must be exactly 4 digits",
+	"field.emoji":          "{field} must be a single emoji",
+	"field.whatsapp":       "{field} is not a valid WhatsApp number",
+	"field.timezone":       "{field} is not a known time zone",
+	"field.not_before":     "{field} must not be before {other}",
+	"field.range_too_long": "{field} must be at most one year after {other}",
+	"field.future":         "{field} must be in the future",
+	"field.not_future":     "{field} must not be in the future",
+	"field.max_age":        "{field} must be within the last {days} days",
+	"field.near":           "{field} must be within {days} day of {other}",
+	"field.together":       "{field} and {other} must be set together",
+	"field.unknown_value":  "{field} has an unknown value: {value}",
+	"field.unique":         "{field} must be unique, {value} is used more than once",
+	"field.task_ref":       "{field} must be the key of a task in this template",
+	"field.unknown_field":  "{field} is not a known field",
+	"field.daily_limit":    "{field} exceeds the task's daily limit of {max}",
+	"field.cursor":         "{field} is not a valid cursor",
+	"field.not_null":       "{field} must not be null",
+	"field.string":         "{field} must be a string",
+	"field.number":         "{field} must be a number",
+	"field.integer":        "{field} must be an integer",
+	"field.boolean":        "{field} must be true or false",
+	"field.object":         "{field} must be an object",
+	"field.array":          "{field} must be an array",
+	"field.json":           "{field} is not valid JSON",
+	"field.content_type":   "{field} must be application/json",
+}
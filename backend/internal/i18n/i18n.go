@@ -0,0 +1,107 @@
+// Package i18n holds the user-facing messages of the API in Indonesian and
+// English. Keys are error codes ("task_not_found") and field rules
+// ("field.required"); templates fill {name} placeholders from params.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Key is a param value that is itself a message key, rendered in the same
+// language as the message around it.
+type Key string
+
+// Default is used when Accept-Language names no supported language.
+const Default = "id"
+
+var catalogs = map[string]map[string]string{
+	"id": id,
+	"en": en,
+}
+
+// Negotiate picks "id" or "en" from an Accept-Language header, honoring
+// q-values ("en-US,en;q=0.9,id;q=0.8" → "en"). Anything else is Default.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if i := strings.IndexByte(tag, '-'); i >= 0 {
+			tag = tag[:i]
+		}
+		if tag == "in" { // legacy code for Indonesian
+			tag = "id"
+		}
+		if _, ok := catalogs[tag]; !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// T renders key in lang, falling back to Default and then to the key itself.
+func T(lang, key string, params map[string]interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(params) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		if key, ok := value.(Key); ok {
+			value = T(lang, string(key), nil)
+		}
+		pairs = append(pairs, "{"+name+"}", format(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// Has reports whether key has a message.
+func Has(key string) bool {
+	_, ok := catalogs[Default][key]
+	return ok
+}
+
+// Missing lists keys that exist in one language but not in another.
+func Missing() []string {
+	var missing []string
+	for lang, catalog := range catalogs {
+		for other, otherCatalog := range catalogs {
+			for key := range otherCatalog {
+				if _, ok := catalog[key]; !ok {
+					missing = append(missing, fmt.Sprintf("%s: %s (from %s)", lang, key, other))
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
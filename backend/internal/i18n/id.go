@@ -0,0 +1,171 @@
+package i18n
+
+var id = map[string]string{
+	// Requests and access
+	"invalid_request":         "Format request tidak valid",
+	"validation_failed":       "Data tidak valid",
+	"internal_error":          "Terjadi kesalahan pada server",
+	"route_not_found":         "Alamat tidak ditemukan",
+	"payload_too_large":       "Ukuran data terlalu besar",
+	"missing_token":           "Silakan masuk terlebih dahulu",
+	"invalid_token":           "Sesi tidak valid atau sudah berakhir, silakan masuk lagi",
+	"forbidden":               "Akses ditolak",
+	"parent_required":         "Hanya orang tua yang bisa melakukan ini",
+	"child_required":          "Hanya anak yang bisa melakukan ini",
+	"admin_required":          "Hanya admin yang bisa melakukan ini",
+	"super_admin_required":    "Hanya super admin yang bisa melakukan ini",
+	"idempotency_key_invalid": "Idempotency-Key tidak valid",
+	"idempotency_key_reused":  "Idempotency-Key sudah dipakai untuk request lain",
+	"idempotency_in_progress": "Request dengan Idempotency-Key ini masih diproses",
+
+	// Accounts
+	"invalid_credentials": "Email atau password salah",
+	"invalid_pin":         "PIN salah",
+	"email_taken":         "Email sudah terdaftar",
+	"family_name_taken":   "Nama keluarga sudah dipakai",
+	"google_login_failed": "Masuk dengan Google gagal, silakan coba lagi",
+	"google_login_off":    "Masuk dengan Google belum dikonfigurasi",
+	"family_not_found":    "Keluarga tidak ditemukan",
+	"user_not_found":      "Pengguna tidak ditemukan",
+	"child_not_found":     "Anak tidak ditemukan",
+	"child_not_in_family": "Anak ini bukan anggota keluarga Anda",
+
+	// Tasks, logs and rewards
+	"task_not_found":         "Misi tidak ditemukan",
+	"task_already_completed": "Misi ini sudah diselesaikan hari ini",
+	"log_not_found":          "Catatan tidak ditemukan",
+	"log_not_verified":       "Catatan sudah dibatalkan atau belum terverifikasi",
+	"log_already_undone":     "Catatan ini sudah dibatalkan",
+	"log_already_verified":   "Catatan ini sudah terverifikasi",
+	"no_tasks_to_update":     "Tidak ada misi yang diubah",
+	"duplicate_task":         "Ada misi yang dikirim lebih dari sekali",
+	"reward_not_found":       "Hadiah tidak ditemukan",
+	"redemption_not_found":   "Penukaran hadiah tidak ditemukan",
+	"insufficient_points":    "Poin tidak cukup",
+	"announcement_not_found": "Pengumuman tidak ditemukan",
+
+	// Templates and badges
+	"template_not_found":    "Template tidak ditemukan",
+	"template_built_in":     "Template bawaan tidak dapat diubah",
+	"template_task_missing": "Membutuhkan misi \"{task}\" dari template ini, terapkan juga misi tersebut",
+	"badge_not_found":       "Lencana tidak ditemukan",
+
+	// Plans and entitlements
+	"child_read_only":       "Anak ini hanya bisa dilihat pada paket FREE",
+	"task_read_only":        "Misi ini hanya bisa dilihat pada paket FREE",
+	"reward_read_only":      "Hadiah ini hanya bisa dilihat pada paket FREE",
+	"plan_limit_reached":    "Batas {item} pada paket {plan} sudah tercapai",
+	"plan_feature_missing":  "Fitur {feature} tidak termasuk dalam paket {plan}. Silakan tingkatkan paket Anda.",
+	"plan_not_found":        "Paket tidak ditemukan",
+	"plan_exists":           "Paket sudah ada",
+	"plan_in_use":           "Paket masih dipakai keluarga lain",
+	"plan_built_in":         "Paket bawaan tidak bisa dihapus",
+	"limit.children":        "anak",
+	"limit.tasks":           "misi",
+	"limit.rewards":         "hadiah",
+	"feature.analytics":     "Analitik",
+	"feature.leaderboard":   "Papan peringkat",
+	"feature.reports":       "Laporan",
+	"feature.exports":       "Ekspor data",
+	"feature.custom_badges": "Lencana kustom",
+
+	// Family settings, insights and sync
+	"leaderboard_disabled":  "Papan peringkat dinonaktifkan untuk keluarga ini",
+	"season_not_configured": "Musim Ramadhan keluarga ini belum diatur",
+	"no_recipient":          "Belum ada alamat e-mail penerima",
+	"event_already_applied": "Event sudah pernah diproses",
+	"unknown_event_type":    "Jenis event tidak dikenal",
+
+	// Point rules, goals, wallets and jobs
+	"rule_not_found":              "Aturan poin tidak ditemukan",
+	"goal_not_found":              "Target keluarga tidak ditemukan",
+	"wallet_not_found":            "Dompet tidak ditemukan",
+	"wallet_inactive":             "Dompet belum diaktifkan",
+	"insufficient_wallet_balance": "Saldo dompet tidak cukup",
+	"cashout_not_found":           "Pencairan tidak ditemukan",
+	"cashout_processed":           "Pencairan sudah diproses",
+	"thr_disabled":                "THR belum diaktifkan untuk dompet ini",
+	"thr_already_granted":         "THR musim ini sudah diberikan",
+	"job_not_found":               "Job tidak ditemukan",
+	"job_paused":                  "Job sedang dijeda",
+	"deletion_not_found":          "Tidak ada permintaan hapus akun",
+	"deletion_scheduled":          "Penghapusan akun sudah dijadwalkan",
+	"deletion_parent_only":        "Hanya orang tua di keluarga ini yang bisa menghapusnya",
+	"confirmation_expired":        "Kode konfirmasi sudah kedaluwarsa",
+	"confirmation_invalid":        "Kode konfirmasi salah",
+	"archive_unsupported_version": "Versi arsip tidak didukung",
+	"archive_no_parent":           "Arsip tidak berisi akun orang tua",
+	"archive_invalid_date":        "Ada tanggal yang tidak valid di arsip",
+	"push_not_configured":         "Notifikasi push belum dikonfigurasi",
+	"subscription_not_found":      "Langganan notifikasi tidak ditemukan",
+	"no_push_subscriptions":       "Belum ada perangkat yang berlangganan notifikasi",
+	"whatsapp_number_missing":     "Tambahkan nomor WhatsApp sebelum berlangganan",
+
+	// Payments, coupons and referrals
+	"payments_not_configured":     "Pembayaran belum dikonfigurasi",
+	"payment_gateway_unavailable": "Layanan pembayaran sedang tidak tersedia",
+	"payment_not_found":           "Pembayaran tidak ditemukan",
+	"package_not_found":           "Paket tidak ditemukan",
+	"payment_parent_only":         "Hanya orang tua yang bisa membayar untuk keluarga",
+	"invalid_signature":           "Tanda tangan tidak valid",
+	"amount_mismatch":             "Jumlah pembayaran tidak sesuai",
+	"coupon_not_found":            "Kupon tidak ditemukan",
+	"coupon_not_valid_now":        "Kupon tidak berlaku saat ini",
+	"coupon_already_used":         "Kupon sudah dipakai keluarga ini",
+	"coupon_limit_reached":        "Kuota kupon sudah habis",
+	"coupon_not_at_checkout":      "Kupon ini tidak bisa dipakai saat pembayaran",
+	"coupon_checkout_only":        "Kupon ini hanya berlaku saat pembayaran",
+	"coupon_code_taken":           "Kode kupon sudah ada",
+	"coupon_in_use":               "Kupon sudah pernah dipakai, nonaktifkan saja",
+	"referral_invalid":            "Kode referral tidak valid",
+	"referral_own":                "Tidak bisa memakai kode referral sendiri",
+	"referral_claimed":            "Kode referral sudah pernah diklaim",
+	"referral_after_payment":      "Kode referral hanya bisa diklaim sebelum pembayaran pertama",
+
+	// Field rules; {field} is the JSON name
+	"field.required":       "{field} wajib diisi",
+	"field.positive":       "{field} harus lebih dari 0",
+	"field.not_negative":   "{field} tidak boleh negatif",
+	"field.between":        "{field} harus di antara {min} dan {max}",
+	"field.min":            "{field} minimal {min}",
+	"field.max":            "{field} maksimal {max}",
+	"field.min_length":     "{field} minimal {min} karakter",
+	"field.max_length":     "{field} maksimal {max} karakter",
+	"field.length_between": "{field} harus {min}-{max} karakter",
+	"field.min_items":      "{field} minimal berisi {min} item",
+	"field.max_items":      "{field} maksimal berisi {max} item",
+	"field.one_of":         "{field} harus salah satu dari {values}",
+	"field.date":           "{field} harus berupa tanggal (YYYY-MM-DD)",
+	"field.date_time":      "{field} harus berupa waktu RFC 3339",
+	"field.time":           "{field} harus berupa jam (HH:MM)",
+	"field.uuid":           "{field} harus berupa UUID",
+	"field.email":          "{field} harus berupa alamat e-mail",
+	"field.format":         "Format {field} tidak valid",
+	"field.plan_code":      "{field} harus 2-20 karakter A-Z, 0-9 dan _",
+	"field.pin":            "{field} harus 4 digit angka",
+	"field.emoji":          "{field} harus satu emoji",
+	"field.whatsapp":       "{field} bukan nomor WhatsApp yang valid",
+	"field.timezone":       "{field} bukan zona waktu yang dikenal",
+	"field.not_before":     "{field} tidak boleh sebelum {other}",
+	"field.range_too_long": "{field} maksimal satu tahun setelah {other}",
+	"field.future":         "{field} harus di masa depan",
+	"field.not_future":     "{field} tidak boleh di masa depan",
+	"field.max_age":        "{field} maksimal {days} hari yang lalu",
+	"field.near":           "{field} maksimal {days} hari dari {other}",
+	"field.together":       "{field} dan {other} harus diisi bersamaan",
+	"field.unknown_value":  "{field} berisi nilai yang tidak dikenal: {value}",
+	"field.unique":         "{field} harus unik, {value} dipakai lebih dari sekali",
+	"field.task_ref":       "{field} harus berupa kunci misi di template ini",
+	"field.unknown_field":  "{field} bukan field yang dikenal",
+	"field.daily_limit":    "{field} melebihi batas harian misi ({max})",
+	"field.cursor":         "{field} bukan cursor yang valid",
+	"field.not_null":       "{field} tidak boleh null",
+	"field.string":         "{field} harus berupa teks",
+	"field.number":         "{field} harus berupa angka",
+	"field.integer":        "{field} harus berupa bilangan bulat",
+	"field.boolean":        "{field} harus true atau false",
+	"field.object":         "{field} harus berupa objek",
+	"field.array":          "{field} harus berupa daftar",
+	"field.json":           "{field} bukan JSON yang valid",
+	"field.content_type":   "{field} harus application/json",
+}
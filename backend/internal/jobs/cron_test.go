@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	utc := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+	}
+	// 2026-03-08 02:00 EST jumps to 03:00 EDT; 2026-11-01 02:00 EDT falls back to 01:00 EST.
+	est := time.FixedZone("EST", -5*3600)
+	edt := time.FixedZone("EDT", -4*3600)
+	at := func(zone *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, zone).In(ny)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", utc(2026, 3, 1, 10, 0), utc(2026, 3, 1, 10, 1)},
+		{"strictly after", "0 10 * * *", utc(2026, 3, 1, 10, 0), utc(2026, 3, 2, 10, 0)},
+		{"seconds ignored", "0 10 * * *", utc(2026, 3, 1, 9, 59).Add(30 * time.Second), utc(2026, 3, 1, 10, 0)},
+		{"step", "*/15 * * * *", utc(2026, 3, 1, 10, 16), utc(2026, 3, 1, 10, 30)},
+		{"step from value", "10/20 * * * *", utc(2026, 3, 1, 10, 31), utc(2026, 3, 1, 10, 50)},
+		{"range", "0 9-11 * * *", utc(2026, 3, 1, 11, 0), utc(2026, 3, 2, 9, 0)},
+		{"range with step", "0 10-20/5 * * *", utc(2026, 3, 1, 15, 1), utc(2026, 3, 1, 20, 0)},
+		{"list", "0 0 1,15 * *", utc(2026, 3, 2, 0, 0), utc(2026, 3, 15, 0, 0)},
+		{"list of ranges", "0 8 * * 1-2,4-5", utc(2026, 3, 4, 9, 0), utc(2026, 3, 5, 8, 0)}, // Wednesday
+		{"sunday as 0", "0 19 * * 0", utc(2026, 3, 2, 0, 0), utc(2026, 3, 8, 19, 0)},
+		{"sunday as 7", "0 19 * * 7", utc(2026, 3, 2, 0, 0), utc(2026, 3, 8, 19, 0)},
+		{"saturday to sunday", "0 0 * * 6-7", utc(2026, 3, 7, 0, 0), utc(2026, 3, 8, 0, 0)},
+		{"either day field", "0 0 13 * 5", utc(2026, 3, 1, 0, 0), utc(2026, 3, 6, 0, 0)}, // first Friday beats the 13th
+		{"day restricted by dom only", "0 0 13 * *", utc(2026, 3, 1, 0, 0), utc(2026, 3, 13, 0, 0)},
+		{"weekly", "@weekly", utc(2026, 3, 1, 0, 0), utc(2026, 3, 8, 0, 0)},
+		{"hourly", "@hourly", utc(2026, 3, 1, 10, 0), utc(2026, 3, 1, 11, 0)},
+		{"next month", "0 0 1 * *", utc(2026, 1, 31, 12, 0), utc(2026, 2, 1, 0, 0)},
+		{"31st skips short months", "0 0 31 * *", utc(2026, 3, 31, 0, 0), utc(2026, 5, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", utc(2026, 1, 1, 0, 0), utc(2028, 2, 29, 0, 0)},
+		{"next year", "@yearly", utc(2026, 12, 31, 23, 59), utc(2027, 1, 1, 0, 0)},
+		{"never", "0 0 30 2 *", utc(2026, 1, 1, 0, 0), time.Time{}},
+		{"location kept", "0 19 * * *", at(est, 2026, 1, 5, 20, 0), at(est, 2026, 1, 6, 19, 0)},
+		{"spring forward skips the missing time", "30 2 * * *", at(est, 2026, 3, 8, 0, 0), at(edt, 2026, 3, 9, 2, 30)},
+		{"spring forward hour after", "0 3 * * *", at(est, 2026, 3, 8, 1, 59), at(edt, 2026, 3, 8, 3, 0)},
+		{"spring forward hourly", "0 * * * *", at(est, 2026, 3, 8, 1, 30), at(edt, 2026, 3, 8, 3, 0)},
+		{"fall back first pass", "30 1 * * *", at(edt, 2026, 11, 1, 0, 0), at(edt, 2026, 11, 1, 1, 30)},
+		{"fall back runs once", "30 1 * * *", at(edt, 2026, 11, 1, 1, 30), at(est, 2026, 11, 2, 1, 30)},
+		{"fall back hourly repeats the hour", "0 * * * *", at(edt, 2026, 11, 1, 1, 30), at(est, 2026, 11, 1, 1, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			got := s.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != tt.from.Location() {
+				t.Errorf("location = %s, want %s", got.Location(), tt.from.Location())
+			}
+		})
+	}
+}
+
+func TestParseCronRejects(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@fortnightly",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/-5 * * * *",
+		"1-x * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+// Package jobs runs periodic background work from a Postgres-backed job table.
+// Every replica may run a Scheduler: due jobs are leased with
+// FOR UPDATE SKIP LOCKED, so each run happens on exactly one of them.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Func is the work of a job. It should respect ctx, which is cancelled when the lease runs out.
+type Func func(ctx context.Context) error
+
+type Job struct {
+	Name        string
+	Schedule    string // cron expression, see ParseCron
+	Run         Func
+	MaxAttempts int           // attempts per scheduled run before giving up, default 3
+	Timeout     time.Duration // lease length, default 10 minutes
+	Paused      bool          // initial state when the job row is first created
+}
+
+type registered struct {
+	Job
+	schedule *Schedule
+}
+
+var (
+	ErrNotFound = errors.New("Job not found")
+	ErrPaused   = errors.New("Job is paused")
+)
+
+type Scheduler struct {
+	db       *gorm.DB
+	loc      *time.Location
+	worker   string
+	jobs     map[string]*registered
+	mu       sync.RWMutex
+	interval time.Duration
+}
+
+// New creates a scheduler that evaluates cron expressions in loc.
+func New(db *gorm.DB, loc *time.Location) *Scheduler {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return &Scheduler{
+		db:       db,
+		loc:      loc,
+		worker:   fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix)),
+		jobs:     map[string]*registered{},
+		interval: 15 * time.Second,
+	}
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(job Job) error {
+	schedule, err := ParseCron(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	if schedule.Next(time.Now().In(s.loc)).IsZero() {
+		return fmt.Errorf("job %s: schedule %q never fires", job.Name, job.Schedule)
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = 3
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = 10 * time.Minute
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.Name] = &registered{Job: job, schedule: schedule}
+	return nil
+}
+
+// Start creates missing job rows and polls for due jobs until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if err := s.sync(); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.RunDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("jobs: scheduler %s started with %d jobs", s.worker, len(s.jobs))
+	return nil
+}
+
+// sync inserts rows for new jobs and reschedules jobs whose cron expression changed.
+func (s *Scheduler) sync() error {
+	now := time.Now().In(s.loc)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, job := range s.jobs {
+		var row models.ScheduledJob
+		err := s.db.First(&row, "name = ?", job.Name).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			row = models.ScheduledJob{
+				Name:        job.Name,
+				Schedule:    job.Schedule,
+				IsPaused:    job.Paused,
+				NextRunAt:   job.schedule.Next(now),
+				MaxAttempts: job.MaxAttempts,
+			}
+			if err := s.db.Create(&row).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if row.Schedule != job.Schedule || row.MaxAttempts != job.MaxAttempts {
+			err := s.db.Model(&row).Updates(map[string]interface{}{
+				"schedule":     job.Schedule,
+				"max_attempts": job.MaxAttempts,
+				"next_run_at":  job.schedule.Next(now),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunDue runs due jobs one after another until none is left.
+func (s *Scheduler) RunDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		row, err := s.claim()
+		if err != nil {
+			log.Println("jobs: claim failed:", err)
+			return
+		}
+		if row == nil {
+			return
+		}
+		s.run(ctx, row)
+	}
+}
+
+// claim leases the most overdue job this process knows how to run.
+func (s *Scheduler) claim() (*models.ScheduledJob, error) {
+	names := s.names()
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	// The lease is set generously here and tightened to the job's timeout below.
+	now := time.Now()
+	var rows []models.ScheduledJob
+	err := s.db.Raw(`
+		UPDATE scheduled_jobs
+		SET locked_by = @worker, locked_until = @until, attempts = attempts + 1, updated_at = @now
+		WHERE name = (
+			SELECT name FROM scheduled_jobs
+			WHERE name IN @names AND is_paused = false AND next_run_at <= @now
+				AND (locked_until IS NULL OR locked_until < @now)
+			ORDER BY next_run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, map[string]interface{}{
+		"worker": s.worker,
+		"until":  now.Add(time.Hour),
+		"now":    now,
+		"names":  names,
+	}).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+// backoff is the delay before retry number attempt (1-based): 30s, 1m, 2m … capped at 1h.
+func backoff(attempt int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+func (s *Scheduler) run(ctx context.Context, row *models.ScheduledJob) {
+	s.mu.RLock()
+	job := s.jobs[row.Name]
+	s.mu.RUnlock()
+
+	started := time.Now()
+	until := started.Add(job.Timeout)
+	s.db.Model(&models.ScheduledJob{}).Where("name = ? AND locked_by = ?", row.Name, s.worker).Update("locked_until", until)
+
+	run := models.JobRun{
+		JobName:   row.Name,
+		Attempt:   row.Attempts,
+		Status:    "running",
+		Worker:    s.worker,
+		StartedAt: started,
+	}
+	if err := s.db.Create(&run).Error; err != nil {
+		log.Printf("jobs: could not record run of %s: %v", row.Name, err)
+	}
+
+	runCtx, cancel := context.WithDeadline(ctx, until)
+	err := safeRun(runCtx, job.Run)
+	cancel()
+
+	finished := time.Now()
+	runUpdate := map[string]interface{}{"status": "succeeded", "finished_at": finished}
+	jobUpdate := map[string]interface{}{
+		"locked_by":    "",
+		"locked_until": nil,
+		"last_run_at":  finished,
+		"updated_at":   finished,
+	}
+
+	if err != nil {
+		runUpdate["status"] = "failed"
+		runUpdate["error"] = err.Error()
+		jobUpdate["last_error"] = err.Error()
+		if row.Attempts < row.MaxAttempts {
+			jobUpdate["next_run_at"] = finished.Add(backoff(row.Attempts))
+			log.Printf("jobs: %s failed (attempt %d/%d), retrying: %v", row.Name, row.Attempts, row.MaxAttempts, err)
+		} else {
+			jobUpdate["attempts"] = 0
+			jobUpdate["next_run_at"] = job.schedule.Next(finished.In(s.loc))
+			log.Printf("jobs: %s failed after %d attempts: %v", row.Name, row.Attempts, err)
+		}
+	} else {
+		jobUpdate["attempts"] = 0
+		jobUpdate["last_error"] = ""
+		jobUpdate["next_run_at"] = job.schedule.Next(finished.In(s.loc))
+	}
+
+	if run.ID != "" {
+		s.db.Model(&models.JobRun{}).Where("id = ?", run.ID).Updates(runUpdate)
+	}
+	if err := s.db.Model(&models.ScheduledJob{}).Where("name = ? AND locked_by = ?", row.Name, s.worker).Updates(jobUpdate).Error; err != nil {
+		log.Printf("jobs: could not release %s: %v", row.Name, err)
+	}
+}
+
+// safeRun turns a panicking job into a failed run instead of crashing the API.
+func safeRun(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// JobStatus is a job row together with its most recent runs.
+type JobStatus struct {
+	models.ScheduledJob
+	Registered bool            `json:"registered"` // false for rows left behind by removed jobs
+	RecentRuns []models.JobRun `json:"recentRuns"`
+}
+
+func (s *Scheduler) List() ([]JobStatus, error) {
+	var rows []models.ScheduledJob
+	if err := s.db.Order("name").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]JobStatus, 0, len(rows))
+	for _, row := range rows {
+		status := JobStatus{ScheduledJob: row, RecentRuns: []models.JobRun{}}
+		_, status.Registered = s.jobs[row.Name]
+		if err := s.db.Where("job_name = ?", row.Name).Order("started_at DESC").Limit(5).Find(&status.RecentRuns).Error; err != nil {
+			return nil, err
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+func (s *Scheduler) Runs(name string, limit int) ([]models.JobRun, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	var runs []models.JobRun
+	err := s.db.Where("job_name = ?", name).Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	result := s.db.Model(&models.ScheduledJob{}).Where("name = ?", name).Update("is_paused", paused)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+// Resume unpauses a job. A run missed while paused happens on the next poll.
+func (s *Scheduler) Resume(name string) error {
+	return s.setPaused(name, false)
+}
+
+// Trigger makes a job due now; whichever replica polls first runs it.
+func (s *Scheduler) Trigger(name string) error {
+	var row models.ScheduledJob
+	if err := s.db.First(&row, "name = ?", name).Error; err != nil {
+		return ErrNotFound
+	}
+	if row.IsPaused {
+		return ErrPaused
+	}
+	return s.db.Model(&row).Update("next_run_at", time.Now()).Error
+}
+
+// PruneHistory returns a job that deletes finished runs older than keep.
+func (s *Scheduler) PruneHistory(keep time.Duration) Func {
+	return func(ctx context.Context) error {
+		return s.db.WithContext(ctx).
+			Where("started_at < ? AND status <> 'running'", time.Now().Add(-keep)).
+			Delete(&models.JobRun{}).Error
+	}
+}
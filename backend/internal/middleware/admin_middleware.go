@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
 func AdminMiddleware() fiber.Handler {
@@ -11,7 +13,7 @@ func AdminMiddleware() fiber.Handler {
 			// Note: typically 'admin' signifies super admin in some context. We'll enforce 'super_admin' explicitly if needed.
 			// However based on prompt: "parent" also does admin stuff on dashboard.
 			// We will specifically use SuperAdminMiddleware for the global admin.
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Forbidden - Requires Admin role"})
+			return httperr.Respond(c, services.ErrAdminRequired)
 		}
 		return c.Next()
 	}
@@ -21,7 +23,7 @@ func SuperAdminMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role := c.Locals("role")
 		if role != "super_admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Forbidden - Requires Super Admin role"})
+			return httperr.Respond(c, services.ErrSuperAdminRequired)
 		}
 		return c.Next()
 	}
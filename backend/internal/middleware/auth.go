@@ -4,29 +4,48 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 	"github.com/username/ramadhan-ceria-backend/internal/utils"
 )
 
-func AuthMiddleware() fiber.Handler {
+func AuthMiddleware(tokens *utils.JWT) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing token"})
+			return httperr.Respond(c, services.ErrMissingToken)
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token format"})
+			return httperr.Respond(c, services.ErrInvalidToken)
 		}
 
-		claims, err := utils.ValidateToken(parts[1])
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
+		return authenticate(c, tokens, parts[1])
+	}
+}
+
+// StreamAuthMiddleware is AuthMiddleware for the event stream. Browsers'
+// EventSource can't send headers, so the JWT may also come as ?access_token=.
+func StreamAuthMiddleware(tokens *utils.JWT) fiber.Handler {
+	header := AuthMiddleware(tokens)
+	return func(c *fiber.Ctx) error {
+		token := c.Query("access_token")
+		if token == "" {
+			return header(c)
 		}
+		return authenticate(c, tokens, token)
+	}
+}
 
-		c.Locals("userID", claims.UserID)
-		c.Locals("familyID", claims.FamilyID)
-		c.Locals("role", claims.Role)
-		return c.Next()
+func authenticate(c *fiber.Ctx, tokens *utils.JWT, token string) error {
+	claims, err := tokens.ValidateToken(token)
+	if err != nil {
+		return httperr.Respond(c, services.ErrInvalidToken)
 	}
+
+	c.Locals("userID", claims.UserID)
+	c.Locals("familyID", claims.FamilyID)
+	c.Locals("role", claims.Role)
+	return c.Next()
 }
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+)
+
+// Deprecated marks an unversioned route as an alias of successor, a Fiber
+// path such as /api/v1/logs/:logId/undo. Parameters are filled from the
+// current request, which must use the same parameter names. Error responses
+// keep the old "error" field next to the envelope.
+func Deprecated(successor string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		parts := strings.Split(successor, "/")
+		for i, part := range parts {
+			if strings.HasPrefix(part, ":") {
+				parts[i] = c.Params(strings.TrimPrefix(part, ":"))
+			}
+		}
+		httperr.MarkLegacy(c)
+		c.Set("Deprecation", "true")
+		c.Set("Link", "<"+strings.Join(parts, "/")+">; rel=\"successor-version\"")
+		return c.Next()
+	}
+}
+
+// LegacyNames renames snake_case JSON body keys and query parameters to the
+// camelCase names used by /api/v1 (child_id → childId), so old clients keep
+// working against handlers that only know the new names. renames are extra
+// old, new pairs of body keys, at any depth, for aliases whose request shape
+// changed (logs → tasks).
+func LegacyNames(renames ...string) fiber.Handler {
+	names := make(map[string]string, len(renames)/2)
+	for i := 0; i+1 < len(renames); i += 2 {
+		names[renames[i]] = renames[i+1]
+	}
+	return func(c *fiber.Ctx) error {
+		args := c.Request().URI().QueryArgs()
+		renamed := map[string][]string{}
+		args.VisitAll(func(key, value []byte) {
+			if bytes.IndexByte(key, '_') >= 0 {
+				renamed[string(key)] = append(renamed[string(key)], string(value))
+			}
+		})
+		for key, values := range renamed {
+			args.Del(key)
+			for _, value := range values {
+				args.Add(camelCase(key), value)
+			}
+		}
+
+		body := c.Body()
+		if len(body) > 0 && (bytes.Contains(body, []byte("_")) || len(names) > 0) && strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			var value interface{}
+			if err := dec.Decode(&value); err == nil {
+				if converted, err := json.Marshal(camelKeys(value, names)); err == nil {
+					c.Request().SetBody(converted)
+				}
+			}
+		}
+		return c.Next()
+	}
+}
+
+func camelKeys(value interface{}, names map[string]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			key = camelCase(key)
+			if name, ok := names[key]; ok {
+				key = name
+			}
+			out[key] = camelKeys(item, names)
+		}
+		return out
+	case []interface{}:
+		for i, item := range v {
+			v[i] = camelKeys(item, names)
+		}
+		return v
+	}
+	return value
+}
+
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLegacyNamesRenamesOldBodyShape(t *testing.T) {
+	app := fiber.New()
+	app.Post("/api/logs", LegacyNames("logs", "tasks", "quantity", "count"), func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	req := httptest.NewRequest("POST", "/api/logs",
+		strings.NewReader(`{"child_id":"c1","date":"2026-03-01","logs":[{"taskId":"t1","quantity":2}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+
+	want := `{"childId":"c1","date":"2026-03-01","tasks":[{"count":2,"taskId":"t1"}]}`
+	if string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
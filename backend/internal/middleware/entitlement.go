@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+// RequireEntitlement lets a request through only when the family's plan
+// includes the feature, or is still below the limit, called name.
+func RequireEntitlement(entitlements *services.EntitlementService, name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		familyID, _ := c.Locals("familyID").(string)
+		if err := entitlements.Check(familyID, name); err != nil {
+			return httperr.Respond(c, err)
+		}
+		return c.Next()
+	}
+}
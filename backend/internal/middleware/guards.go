@@ -2,13 +2,15 @@ package middleware
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
 )
 
 func ParentGuard() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role := c.Locals("role")
 		if role != "parent" && role != "super_admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Forbidden - Requires Parent role"})
+			return httperr.Respond(c, services.ErrParentRequired)
 		}
 		return c.Next()
 	}
@@ -18,7 +20,7 @@ func ChildGuard() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role := c.Locals("role")
 		if role != "child" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Forbidden - Requires Child role"})
+			return httperr.Respond(c, services.ErrChildRequired)
 		}
 		return c.Next()
 	}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+// Idempotency honors an Idempotency-Key header on POST and PUT. The first
+// response (below 500) is stored per idempotencyScope and key for services.IdempotencyTTL
+// and replayed on retries with "Idempotent-Replayed: true". The same key with
+// a different body is rejected with 422; a retry while the first request is
+// still running gets 409. Requests without the header pass through.
+func Idempotency(store *services.IdempotencyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("Idempotency-Key")
+		if key == "" || (c.Method() != fiber.MethodPost && c.Method() != fiber.MethodPut) {
+			return c.Next()
+		}
+
+		record, claimed, err := store.Begin(idempotencyScope(c), key, services.IdempotencyHash(c.Method(), c.Path(), c.Body()))
+		if err != nil {
+			return httperr.Respond(c, err)
+		}
+
+		if !claimed {
+			c.Set("Idempotent-Replayed", "true")
+			if record.ContentType != "" {
+				c.Set(fiber.HeaderContentType, record.ContentType)
+			}
+			return c.Status(record.StatusCode).Send(record.ResponseBody)
+		}
+
+		if err := c.Next(); err != nil {
+			store.Release(record)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			// Server errors are worth retrying; don't pin them to the key.
+			if err := store.Release(record); err != nil {
+				log.Printf("idempotency: release %s: %v", key, err)
+			}
+			return nil
+		}
+		body := append([]byte(nil), c.Response().Body()...)
+		if err := store.Complete(record, status, string(c.Response().Header.ContentType()), body); err != nil {
+			log.Printf("idempotency: store %s: %v", key, err)
+		}
+		return nil
+	}
+}
+
+// idempotencyScope is the user ID, or for anonymous requests a hash of the
+// route and client IP, so strangers picking the same key don't get each
+// other's responses. The hash keeps the scope within its 64 characters.
+func idempotencyScope(c *fiber.Ctx) string {
+	if userID, _ := c.Locals("userID").(string); userID != "" {
+		return userID
+	}
+	sum := sha256.Sum256([]byte(c.Method() + " " + c.Route().Path + " " + c.IP()))
+	return "public:" + hex.EncodeToString(sum[:16])
+}
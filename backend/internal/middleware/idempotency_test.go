@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIdempotencyScope(t *testing.T) {
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	scope := func(c *fiber.Ctx) error {
+		if user := c.Get("X-Test-User"); user != "" {
+			c.Locals("userID", user)
+		}
+		return c.SendString(idempotencyScope(c))
+	}
+	app.Post("/auth/register", scope)
+	app.Post("/redemptions", scope)
+
+	get := func(path, ip, user string) string {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	anonymous := get("/auth/register", "203.0.113.7", "")
+	if !strings.HasPrefix(anonymous, "public:") || len(anonymous) > 64 {
+		t.Errorf("anonymous scope = %q, want public:<hash> within 64 characters", anonymous)
+	}
+	if again := get("/auth/register", "203.0.113.7", ""); again != anonymous {
+		t.Errorf("same client and route: scope %q, then %q", anonymous, again)
+	}
+	if other := get("/auth/register", "198.51.100.2", ""); other == anonymous {
+		t.Errorf("another client shares scope %q", other)
+	}
+	if other := get("/redemptions", "203.0.113.7", ""); other == anonymous {
+		t.Errorf("another route shares scope %q", other)
+	}
+	if user := get("/redemptions", "203.0.113.7", "u1"); user != "u1" {
+		t.Errorf("signed-in scope = %q, want the user ID", user)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/username/ramadhan-ceria-backend/internal/httperr"
+	"github.com/username/ramadhan-ceria-backend/internal/openapi"
+	"github.com/username/ramadhan-ceria-backend/internal/services"
+)
+
+// ValidateRequest checks path and query parameters and the JSON body against
+// op and answers validation_failed with every violation in "details".
+func ValidateRequest(op *openapi.Operation) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := map[string]string{}
+		for _, name := range c.Route().Params {
+			params[name] = c.Params(name)
+		}
+
+		violations := op.Validate(openapi.Request{
+			PathParams:  params,
+			Query:       c.Queries(),
+			ContentType: c.Get(fiber.HeaderContentType),
+			Body:        c.Body(),
+		})
+		if len(violations) > 0 {
+			details := make([]services.FieldError, len(violations))
+			for i, v := range violations {
+				details[i] = services.FieldError{Field: v.Field, Rule: v.Rule, Params: v.Params}
+			}
+			return httperr.Respond(c, services.ValidationError(details...))
+		}
+		return c.Next()
+	}
+}
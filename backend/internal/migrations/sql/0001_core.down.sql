@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS announcements;
+DROP TABLE IF EXISTS redemptions;
+DROP TABLE IF EXISTS daily_logs;
+DROP TABLE IF EXISTS rewards;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS families;
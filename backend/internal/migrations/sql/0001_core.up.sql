@@ -0,0 +1,116 @@
+-- Families, their members, tasks, rewards and the logs tying them together.
+-- Written with IF NOT EXISTS so databases created by the old AutoMigrate
+-- start-up adopt it unchanged.
+
+CREATE TABLE IF NOT EXISTS families (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name varchar(100) NOT NULL,
+    plan varchar(20) DEFAULT 'FREE',
+    plan_expires_at timestamptz,
+    enable_leaderboard boolean DEFAULT true,
+    timezone varchar(50) DEFAULT 'Asia/Jakarta',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_families_deleted_at ON families (deleted_at);
+
+CREATE TABLE IF NOT EXISTS users (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    role varchar(20) NOT NULL,
+    name text NOT NULL,
+    avatar_icon text NOT NULL DEFAULT '👦',
+    email text,
+    whatsapp varchar(20),
+    password_hash text,
+    pin_hash text,
+    points_balance bigint DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT chk_users_points_balance CHECK (points_balance >= 0),
+    CONSTRAINT fk_families_users FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_users_family_id ON users (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    name text NOT NULL,
+    icon text DEFAULT '📋',
+    point_reward bigint NOT NULL,
+    max_per_day bigint DEFAULT 1,
+    task_type varchar(20) DEFAULT 'daily',
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_families_tasks FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_family_task_active ON tasks (family_id, is_active);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS rewards (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    name text NOT NULL,
+    icon text DEFAULT '🎁',
+    points_required bigint NOT NULL,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_families_rewards FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_rewards_family_id ON rewards (family_id);
+CREATE INDEX IF NOT EXISTS idx_rewards_deleted_at ON rewards (deleted_at);
+
+CREATE TABLE IF NOT EXISTS daily_logs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    child_id uuid NOT NULL,
+    task_id uuid NOT NULL,
+    completed_date date NOT NULL,
+    status varchar(20) DEFAULT 'verified',
+    earned_points bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_users_daily_logs FOREIGN KEY (child_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_tasks_daily_logs FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+-- Early databases had this index unique, which allowed one completion per
+-- task and day; repeatable tasks need it plain.
+DROP INDEX IF EXISTS idx_child_task_date;
+CREATE INDEX idx_child_task_date ON daily_logs (child_id, task_id, completed_date);
+CREATE INDEX IF NOT EXISTS idx_daily_logs_deleted_at ON daily_logs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS redemptions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    child_id uuid NOT NULL,
+    reward_id uuid NOT NULL,
+    points_spent bigint NOT NULL,
+    status varchar(20) DEFAULT 'pending',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_users_redemptions FOREIGN KEY (child_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_rewards_redemptions FOREIGN KEY (reward_id) REFERENCES rewards (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_redemptions_child_id ON redemptions (child_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_reward_id ON redemptions (reward_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_deleted_at ON redemptions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS announcements (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    title text NOT NULL,
+    message text NOT NULL,
+    type varchar(20) DEFAULT 'info',
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_announcements_deleted_at ON announcements (deleted_at);
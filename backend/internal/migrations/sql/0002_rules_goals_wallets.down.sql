@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS redemption_status_changes;
+DROP TABLE IF EXISTS family_goal_tasks;
+DROP TABLE IF EXISTS family_goals;
+DROP TABLE IF EXISTS point_rules;
+DROP TABLE IF EXISTS wallet_transactions;
+DROP TABLE IF EXISTS wallets;
+
+ALTER TABLE families DROP COLUMN IF EXISTS season_end;
+ALTER TABLE families DROP COLUMN IF EXISTS season_start;
+ALTER TABLE daily_logs DROP COLUMN IF EXISTS quantity;
+ALTER TABLE tasks DROP COLUMN IF EXISTS quantity;
+ALTER TABLE tasks DROP COLUMN IF EXISTS category;
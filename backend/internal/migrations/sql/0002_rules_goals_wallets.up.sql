@@ -0,0 +1,124 @@
+-- Point rules, family goals, pocket-money wallets, redemption history and
+-- the Ramadhan season they are measured against, plus the task categories
+-- and quantities the rapor counts prayers and Quran pages by.
+
+ALTER TABLE families ADD COLUMN IF NOT EXISTS season_start date;
+ALTER TABLE families ADD COLUMN IF NOT EXISTS season_end date;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS category varchar(20) NOT NULL DEFAULT 'general';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS quantity bigint NOT NULL DEFAULT 1;
+ALTER TABLE daily_logs ADD COLUMN IF NOT EXISTS quantity bigint NOT NULL DEFAULT 1;
+-- Tasks from before categories were told apart by name; carry that over once.
+UPDATE tasks SET category = 'prayer'
+    WHERE LOWER(name) LIKE 'sholat%' OR LOWER(name) LIKE 'tarawih%';
+UPDATE tasks SET category = 'quran'
+    WHERE category = 'general' AND (LOWER(name) LIKE '%tadarus%' OR LOWER(name) LIKE '%quran%'
+        OR LOWER(name) LIKE '%mengaji%' OR LOWER(name) LIKE '%iqro%');
+UPDATE tasks SET quantity = substring(name FROM '\((\d+) [Hh]alaman\)')::bigint
+    WHERE name ~ '\(\d+ [Hh]alaman\)';
+UPDATE daily_logs SET quantity = tasks.quantity
+    FROM tasks WHERE tasks.id = daily_logs.task_id AND tasks.quantity <> 1;
+
+CREATE TABLE IF NOT EXISTS wallets (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    child_id uuid NOT NULL,
+    is_active boolean DEFAULT true,
+    rupiah_per_point bigint NOT NULL DEFAULT 100,
+    thr_per_point bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_wallets_child FOREIGN KEY (child_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_wallets_family FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_wallets_family_id ON wallets (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallets_child_id ON wallets (child_id);
+CREATE INDEX IF NOT EXISTS idx_wallets_deleted_at ON wallets (deleted_at);
+
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    wallet_id uuid NOT NULL,
+    child_id uuid NOT NULL,
+    type varchar(20) NOT NULL,
+    status varchar(20) DEFAULT 'pending',
+    points bigint NOT NULL DEFAULT 0,
+    amount bigint NOT NULL,
+    note varchar(255),
+    reference varchar(100),
+    recorded_by uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_wallet_transactions_wallet FOREIGN KEY (wallet_id) REFERENCES wallets (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_wallet_id ON wallet_transactions (wallet_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_child_id ON wallet_transactions (child_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_reference ON wallet_transactions (reference);
+-- One THR per child and season.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_transactions_child_reference ON wallet_transactions (child_id, reference)
+    WHERE reference <> '' AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_deleted_at ON wallet_transactions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS point_rules (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    name text NOT NULL,
+    rule_type varchar(20) NOT NULL,
+    multiplier numeric(4,2) DEFAULT 1,
+    bonus_points bigint DEFAULT 0,
+    task_id uuid,
+    child_id uuid,
+    start_date date,
+    end_date date,
+    weekdays varchar(20),
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_point_rules_family FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_point_rules_family_id ON point_rules (family_id);
+CREATE INDEX IF NOT EXISTS idx_point_rules_task_id ON point_rules (task_id);
+CREATE INDEX IF NOT EXISTS idx_point_rules_child_id ON point_rules (child_id);
+CREATE INDEX IF NOT EXISTS idx_point_rules_deleted_at ON point_rules (deleted_at);
+
+CREATE TABLE IF NOT EXISTS family_goals (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    name text NOT NULL,
+    icon text DEFAULT '🤝',
+    goal_type varchar(20) NOT NULL,
+    metric varchar(20) DEFAULT 'completions',
+    target bigint NOT NULL,
+    daily_target bigint DEFAULT 0,
+    start_date date NOT NULL,
+    end_date date NOT NULL,
+    reward_name text NOT NULL,
+    reward_icon text DEFAULT '🎉',
+    achieved_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_family_goals_family FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_family_goals_family_id ON family_goals (family_id);
+CREATE INDEX IF NOT EXISTS idx_family_goals_deleted_at ON family_goals (deleted_at);
+
+CREATE TABLE IF NOT EXISTS family_goal_tasks (
+    family_goal_id uuid NOT NULL,
+    task_id uuid NOT NULL,
+    PRIMARY KEY (family_goal_id, task_id),
+    CONSTRAINT fk_family_goal_tasks_family_goal FOREIGN KEY (family_goal_id) REFERENCES family_goals (id) ON DELETE CASCADE,
+    CONSTRAINT fk_family_goal_tasks_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS redemption_status_changes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    redemption_id uuid NOT NULL,
+    status varchar(20) NOT NULL,
+    changed_by uuid,
+    created_at timestamptz,
+    CONSTRAINT fk_redemption_status_changes_redemption FOREIGN KEY (redemption_id) REFERENCES redemptions (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_redemption_status_changes_redemption_id ON redemption_status_changes (redemption_id);
@@ -0,0 +1,45 @@
+-- Background job leases and history, and scheduled family deletions.
+
+CREATE TABLE IF NOT EXISTS account_deletions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    requested_by uuid NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    status varchar(20) DEFAULT 'pending',
+    confirmed_at timestamptz,
+    scheduled_for timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_account_deletions_family FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_deletions_family_id ON account_deletions (family_id);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_scheduled_for ON account_deletions (scheduled_for);
+
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    name varchar(100) PRIMARY KEY,
+    schedule varchar(100) NOT NULL,
+    is_paused boolean DEFAULT false,
+    next_run_at timestamptz NOT NULL,
+    attempts bigint DEFAULT 0,
+    max_attempts bigint DEFAULT 3,
+    locked_by varchar(100),
+    locked_until timestamptz,
+    last_run_at timestamptz,
+    last_error text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_next_run_at ON scheduled_jobs (next_run_at);
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name varchar(100) NOT NULL,
+    attempt bigint NOT NULL,
+    status varchar(20) NOT NULL,
+    error text,
+    worker varchar(100),
+    started_at timestamptz NOT NULL,
+    finished_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name ON job_runs (job_name);
+CREATE INDEX IF NOT EXISTS idx_job_runs_started_at ON job_runs (started_at);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS referrals;
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
+DROP TABLE IF EXISTS payment_notifications;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS plans;
+
+DROP INDEX IF EXISTS idx_families_referral_code;
+ALTER TABLE families DROP COLUMN IF EXISTS referral_code;
+ALTER TABLE families DROP COLUMN IF EXISTS plan_reminder;
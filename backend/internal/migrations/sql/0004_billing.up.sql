@@ -0,0 +1,104 @@
+-- Plans, Midtrans payments, coupons and referrals.
+
+ALTER TABLE families ADD COLUMN IF NOT EXISTS plan_reminder varchar(20);
+ALTER TABLE families ADD COLUMN IF NOT EXISTS referral_code varchar(20);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_families_referral_code ON families (referral_code);
+
+CREATE TABLE IF NOT EXISTS plans (
+    code varchar(20) PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    description text,
+    limits jsonb NOT NULL DEFAULT '{}',
+    features jsonb NOT NULL DEFAULT '[]',
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+-- The two built-in plans. Later edits by a super admin are kept.
+INSERT INTO plans (code, name, description, limits, features, created_at, updated_at) VALUES
+    ('FREE', 'Gratis', 'Paket dasar untuk keluarga kecil',
+        '{"children": 2, "tasks": 10, "rewards": 5}',
+        '["leaderboard", "reports", "exports"]', now(), now()),
+    ('PREMIUM', 'Premium', 'Tanpa batas anak, misi dan hadiah, plus analitik',
+        '{}',
+        '["analytics", "leaderboard", "reports", "exports", "custom_badges"]', now(), now())
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS payments (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    order_id varchar(50) NOT NULL,
+    package_code varchar(50) NOT NULL,
+    days bigint NOT NULL,
+    amount bigint NOT NULL,
+    status varchar(20) DEFAULT 'pending',
+    payment_type varchar(30),
+    transaction_id varchar(100),
+    snap_token varchar(100),
+    redirect_url varchar(255),
+    requested_by uuid,
+    paid_at timestamptz,
+    refunded_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_payments_family FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS coupon_code varchar(40);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS discount bigint DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_payments_family_id ON payments (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
+
+CREATE TABLE IF NOT EXISTS payment_notifications (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id varchar(50) NOT NULL,
+    transaction_status varchar(30),
+    status_code varchar(10),
+    fraud_status varchar(20),
+    payload text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_payment_notifications_order_id ON payment_notifications (order_id);
+
+CREATE TABLE IF NOT EXISTS coupons (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    code varchar(40) NOT NULL,
+    description text,
+    kind varchar(20) NOT NULL,
+    value bigint NOT NULL,
+    valid_from timestamptz,
+    valid_until timestamptz,
+    max_uses bigint,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons (code);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    coupon_id uuid NOT NULL,
+    family_id uuid NOT NULL,
+    payment_id uuid,
+    status varchar(20) NOT NULL,
+    discount bigint DEFAULT 0,
+    days bigint DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_coupon_redemptions_coupon FOREIGN KEY (coupon_id) REFERENCES coupons (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupon_family ON coupon_redemptions (coupon_id, family_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_family_id ON coupon_redemptions (family_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_payment_id ON coupon_redemptions (payment_id);
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    referrer_family_id uuid NOT NULL,
+    invitee_family_id uuid NOT NULL,
+    status varchar(20) DEFAULT 'pending',
+    bonus_days bigint DEFAULT 0,
+    rewarded_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer_family_id ON referrals (referrer_family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_invitee_family_id ON referrals (invitee_family_id);
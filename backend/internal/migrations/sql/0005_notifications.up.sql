@@ -0,0 +1,77 @@
+-- Web Push subscriptions and outbox, notification preferences and WhatsApp.
+
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    family_id uuid NOT NULL,
+    endpoint text NOT NULL,
+    p256dh varchar(100) NOT NULL,
+    auth varchar(50) NOT NULL,
+    user_agent varchar(255),
+    last_success_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_push_subscriptions_user_id ON push_subscriptions (user_id);
+CREATE INDEX IF NOT EXISTS idx_push_subscriptions_family_id ON push_subscriptions (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_push_subscriptions_endpoint ON push_subscriptions (endpoint);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id uuid PRIMARY KEY,
+    redemptions boolean DEFAULT true,
+    approvals boolean DEFAULT true,
+    maghrib boolean DEFAULT true,
+    streaks boolean DEFAULT true,
+    quiet_start varchar(5),
+    quiet_end varchar(5),
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS push_messages (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    kind varchar(20) NOT NULL,
+    dedupe_key varchar(100),
+    payload text NOT NULL,
+    urgency varchar(10),
+    status varchar(20) DEFAULT 'pending',
+    attempts bigint DEFAULT 0,
+    next_attempt_at timestamptz,
+    expires_at timestamptz,
+    locked_until timestamptz,
+    last_error text,
+    sent_at timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_push_dedupe ON push_messages (subscription_id, dedupe_key);
+CREATE INDEX IF NOT EXISTS idx_push_messages_user_id ON push_messages (user_id);
+CREATE INDEX IF NOT EXISTS idx_push_messages_status ON push_messages (status);
+CREATE INDEX IF NOT EXISTS idx_push_messages_next_attempt_at ON push_messages (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS whatsapp_consents (
+    user_id uuid PRIMARY KEY,
+    phone varchar(20) NOT NULL,
+    opted_in boolean DEFAULT false,
+    source varchar(20),
+    opted_in_at timestamptz,
+    opted_out_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_whatsapp_consents_phone ON whatsapp_consents (phone);
+
+CREATE TABLE IF NOT EXISTS whatsapp_messages (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    dedupe_key varchar(100),
+    phone varchar(20) NOT NULL,
+    template varchar(50) NOT NULL,
+    params jsonb NOT NULL DEFAULT '[]',
+    provider varchar(20),
+    provider_message_id varchar(100),
+    status varchar(20) DEFAULT 'pending',
+    error text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_whatsapp_dedupe ON whatsapp_messages (user_id, dedupe_key);
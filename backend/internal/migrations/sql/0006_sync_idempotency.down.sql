@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS idempotency_keys;
+
+DROP INDEX IF EXISTS idx_redemptions_client_event_id;
+ALTER TABLE redemptions DROP COLUMN IF EXISTS client_event_id;
+DROP INDEX IF EXISTS idx_daily_logs_client_event_id;
+ALTER TABLE daily_logs DROP COLUMN IF EXISTS client_event_id;
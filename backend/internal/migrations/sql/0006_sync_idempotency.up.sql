@@ -0,0 +1,21 @@
+-- Offline sync event IDs and the Idempotency-Key response cache.
+
+ALTER TABLE daily_logs ADD COLUMN IF NOT EXISTS client_event_id uuid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_logs_client_event_id ON daily_logs (client_event_id);
+ALTER TABLE redemptions ADD COLUMN IF NOT EXISTS client_event_id uuid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_redemptions_client_event_id ON redemptions (client_event_id);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope varchar(64) NOT NULL,
+    key varchar(255) NOT NULL,
+    request_hash char(64) NOT NULL,
+    status_code bigint,
+    content_type varchar(100),
+    response_body bytea,
+    completed_at timestamptz,
+    expires_at timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_scope_key ON idempotency_keys (scope, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS badges;
+DROP TABLE IF EXISTS template_applications;
+DROP TABLE IF EXISTS templates;
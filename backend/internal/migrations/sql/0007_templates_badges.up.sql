@@ -0,0 +1,50 @@
+-- Task templates (built-in catalog and family-made) and badges.
+
+CREATE TABLE IF NOT EXISTS templates (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    key varchar(50),
+    family_id uuid,
+    name text NOT NULL,
+    description text,
+    age_band varchar(10) NOT NULL,
+    items jsonb NOT NULL DEFAULT '[]',
+    share_code varchar(20),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_templates_key ON templates (key);
+CREATE INDEX IF NOT EXISTS idx_templates_family_id ON templates (family_id);
+CREATE INDEX IF NOT EXISTS idx_templates_age_band ON templates (age_band);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_templates_share_code ON templates (share_code);
+CREATE INDEX IF NOT EXISTS idx_templates_deleted_at ON templates (deleted_at);
+
+CREATE TABLE IF NOT EXISTS template_applications (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    item_key varchar(50) NOT NULL,
+    kind varchar(20) NOT NULL,
+    target_id uuid NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_template_application ON template_applications (family_id, template_id, item_key);
+
+CREATE TABLE IF NOT EXISTS badges (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    family_id uuid NOT NULL,
+    name text NOT NULL,
+    icon text DEFAULT '🏅',
+    description text,
+    metric varchar(20) DEFAULT 'completions',
+    threshold bigint NOT NULL,
+    task_id uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_badges_family FOREIGN KEY (family_id) REFERENCES families (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_badges_family_id ON badges (family_id);
+CREATE INDEX IF NOT EXISTS idx_badges_task_id ON badges (task_id);
+CREATE INDEX IF NOT EXISTS idx_badges_deleted_at ON badges (deleted_at);
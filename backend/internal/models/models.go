@@ -11,11 +11,15 @@ type Family struct {
 	Name              string `gorm:"type:varchar(100);not null"`
 	Plan              string `gorm:"type:varchar(20);default:'FREE'"`
 	PlanExpiresAt     *time.Time
-	EnableLeaderboard bool     `gorm:"default:true"`
-	Timezone          string   `gorm:"type:varchar(50);default:'Asia/Jakarta'"`
-	Users             []User   `gorm:"foreignKey:FamilyID"`
-	Tasks             []Task   `gorm:"foreignKey:FamilyID"`
-	Rewards           []Reward `gorm:"foreignKey:FamilyID"`
+	PlanReminder      string     `gorm:"type:varchar(20)"`             // last expiry notice sent: 7d, 1d, grace, downgraded
+	ReferralCode      *string    `gorm:"type:varchar(20);uniqueIndex"` // generated on first use
+	EnableLeaderboard bool       `gorm:"default:true"`
+	Timezone          string     `gorm:"type:varchar(50);default:'Asia/Jakarta'"`
+	SeasonStart       *time.Time `gorm:"type:date"` // first day of Ramadhan for this family
+	SeasonEnd         *time.Time `gorm:"type:date"` // last day of the season (Idul Fitri eve)
+	Users             []User     `gorm:"foreignKey:FamilyID"`
+	Tasks             []Task     `gorm:"foreignKey:FamilyID"`
+	Rewards           []Reward   `gorm:"foreignKey:FamilyID"`
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeletedAt         gorm.DeletedAt `gorm:"index"`
@@ -40,6 +44,14 @@ type User struct {
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
 }
 
+// Task categories. Rapor and analytics count prayers and Quran reading by
+// category, never by task name.
+const (
+	TaskCategoryGeneral = "general"
+	TaskCategoryPrayer  = "prayer"
+	TaskCategoryQuran   = "quran"
+)
+
 type Task struct {
 	ID          string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	FamilyID    string     `gorm:"type:uuid;not null;index:idx_family_task_active"`
@@ -48,6 +60,8 @@ type Task struct {
 	PointReward int        `gorm:"not null"`
 	MaxPerDay   *int       `gorm:"default:1" json:"MaxPerDay"` // nil = default(1), 0 = unlimited, N = N times/day
 	TaskType    string     `gorm:"type:varchar(20);default:'daily'"`
+	Category    string     `gorm:"type:varchar(20);not null;default:'general'"` // general, prayer, quran
+	Quantity    int        `gorm:"not null;default:1"`                          // units one completion stands for, e.g. Quran pages
 	IsActive    bool       `gorm:"default:true;index:idx_family_task_active"`
 	Family      Family     `gorm:"constraint:OnDelete:CASCADE"`
 	DailyLogs   []DailyLog `gorm:"foreignKey:TaskID"`
@@ -77,6 +91,8 @@ type DailyLog struct {
 	CompletedDate time.Time `gorm:"type:date;not null;index:idx_child_task_date"`
 	Status        string    `gorm:"type:varchar(20);default:'verified'"`
 	EarnedPoints  int       `gorm:"not null"`
+	Quantity      int       `gorm:"not null;default:1"`    // the task's quantity when it was completed
+	ClientEventID *string   `gorm:"type:uuid;uniqueIndex"` // set when synced from an offline device
 	Child         User      `gorm:"constraint:OnDelete:CASCADE;foreignKey:ChildID"`
 	Task          Task      `gorm:"constraint:OnDelete:CASCADE"`
 	CreatedAt     time.Time
@@ -85,16 +101,17 @@ type DailyLog struct {
 }
 
 type Redemption struct {
-	ID          string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	ChildID     string `gorm:"type:uuid;not null;index"`
-	RewardID    string `gorm:"type:uuid;not null;index"`
-	PointsSpent int    `gorm:"not null"`
-	Status      string `gorm:"type:varchar(20);default:'pending'"`
-	Child       User   `gorm:"constraint:OnDelete:CASCADE;foreignKey:ChildID"`
-	Reward      Reward `gorm:"constraint:OnDelete:CASCADE"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID            string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ChildID       string  `gorm:"type:uuid;not null;index"`
+	RewardID      string  `gorm:"type:uuid;not null;index"`
+	PointsSpent   int     `gorm:"not null"`
+	Status        string  `gorm:"type:varchar(20);default:'pending'"`
+	ClientEventID *string `gorm:"type:uuid;uniqueIndex"` // set when synced from an offline device
+	Child         User    `gorm:"constraint:OnDelete:CASCADE;foreignKey:ChildID"`
+	Reward        Reward  `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
 }
 
 type Announcement struct {
@@ -107,3 +124,395 @@ type Announcement struct {
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
+
+// Wallet converts a child's points into rupiah pocket money (uang jajan).
+// It is optional and disabled until a parent configures it.
+type Wallet struct {
+	ID             string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID       string `gorm:"type:uuid;not null;index"`
+	ChildID        string `gorm:"type:uuid;not null;uniqueIndex"`
+	IsActive       bool   `gorm:"default:true"`
+	RupiahPerPoint int    `gorm:"not null;default:100"` // cash-out rate
+	THRPerPoint    int    `gorm:"not null;default:0"`   // Lebaran bonus rate over season points, 0 = disabled
+	Child          User   `gorm:"constraint:OnDelete:CASCADE;foreignKey:ChildID"`
+	Family         Family `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+// WalletTransaction is one ledger row of a wallet. Cash-outs start as pending
+// and follow the same approve/reject flow as redemptions; payouts and THR
+// bonuses are recorded by a parent and are completed immediately.
+type WalletTransaction struct {
+	ID         string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	WalletID   string  `gorm:"type:uuid;not null;index"`
+	ChildID    string  `gorm:"type:uuid;not null;index;uniqueIndex:idx_wallet_transactions_child_reference"`
+	Type       string  `gorm:"type:varchar(20);not null"`          // cashout, payout, thr
+	Status     string  `gorm:"type:varchar(20);default:'pending'"` // pending, approved, rejected, completed
+	Points     int     `gorm:"not null;default:0"`                 // points converted (cashout) or counted (thr)
+	Amount     int64   `gorm:"not null"`                           // rupiah, positive credits the wallet, negative debits it
+	Note       string  `gorm:"type:varchar(255)"`
+	Reference  string  `gorm:"type:varchar(100);index;uniqueIndex:idx_wallet_transactions_child_reference,where:reference <> '' AND deleted_at IS NULL"` // e.g. THR:2026-02-18:2026-03-19, unique per child
+	RecordedBy *string `gorm:"type:uuid"`
+	Wallet     Wallet  `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
+
+// PointRule is a time-bound multiplier or flat bonus applied when a task is completed,
+// e.g. double points on the last ten nights of Ramadhan or a bonus for Sholat Jumat.
+type PointRule struct {
+	ID          string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID    string     `gorm:"type:uuid;not null;index"`
+	Name        string     `gorm:"not null"`
+	RuleType    string     `gorm:"type:varchar(20);not null"` // multiplier, bonus
+	Multiplier  float64    `gorm:"type:numeric(4,2);default:1"`
+	BonusPoints int        `gorm:"default:0"`
+	TaskID      *string    `gorm:"type:uuid;index"`  // nil = every task
+	ChildID     *string    `gorm:"type:uuid;index"`  // nil = every child
+	StartDate   *time.Time `gorm:"type:date"`        // nil = no lower bound
+	EndDate     *time.Time `gorm:"type:date"`        // nil = no upper bound
+	Weekdays    string     `gorm:"type:varchar(20)"` // comma separated, 0=Sunday … 6=Saturday, empty = every day
+	IsActive    bool       `gorm:"default:true"`
+	Family      Family     `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// FamilyGoal is a cooperative goal shared by every child in the family.
+// "collective" goals add up everyone's completions (or points) of the goal's tasks;
+// "every_child_daily" goals count the days on which every child reached DailyTarget.
+type FamilyGoal struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID    string    `gorm:"type:uuid;not null;index"`
+	Name        string    `gorm:"not null"`
+	Icon        string    `gorm:"default:'🤝'"`
+	GoalType    string    `gorm:"type:varchar(20);not null"`              // collective, every_child_daily
+	Metric      string    `gorm:"type:varchar(20);default:'completions'"` // completions, points (collective only)
+	Target      int       `gorm:"not null"`                               // total for collective, number of days for every_child_daily
+	DailyTarget int       `gorm:"default:0"`                              // completions each child needs per day (every_child_daily only)
+	StartDate   time.Time `gorm:"type:date;not null"`
+	EndDate     time.Time `gorm:"type:date;not null"`
+	RewardName  string    `gorm:"not null"`
+	RewardIcon  string    `gorm:"default:'🎉'"`
+	AchievedAt  *time.Time
+	Tasks       []Task `gorm:"many2many:family_goal_tasks;constraint:OnDelete:CASCADE"`
+	Family      Family `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// RedemptionStatusChange records every status a redemption went through and who set it.
+type RedemptionStatusChange struct {
+	ID           string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	RedemptionID string     `gorm:"type:uuid;not null;index"`
+	Status       string     `gorm:"type:varchar(20);not null"`
+	ChangedBy    *string    `gorm:"type:uuid"`
+	Redemption   Redemption `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time
+}
+
+// AccountDeletion is a parent's request to delete the whole family. It must be
+// confirmed with the e-mailed token and is executed once ScheduledFor passes,
+// so it can still be cancelled during the cooling-off period.
+type AccountDeletion struct {
+	ID           string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID     string `gorm:"type:uuid;not null;uniqueIndex"`
+	RequestedBy  string `gorm:"type:uuid;not null"`
+	TokenHash    string `gorm:"type:varchar(64);not null" json:"-"` // sha256 of the confirmation token
+	Status       string `gorm:"type:varchar(20);default:'pending'"` // pending (awaiting confirmation), scheduled
+	ConfirmedAt  *time.Time
+	ScheduledFor *time.Time `gorm:"index"`
+	Family       Family     `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ScheduledJob is one periodic background job. Rows are created by the
+// scheduler on start-up; LockedBy/LockedUntil form the lease that keeps
+// several API replicas from running the same job twice.
+type ScheduledJob struct {
+	Name        string    `gorm:"primaryKey;type:varchar(100)"`
+	Schedule    string    `gorm:"type:varchar(100);not null"` // cron expression, evaluated in WIB
+	IsPaused    bool      `gorm:"default:false"`
+	NextRunAt   time.Time `gorm:"not null;index"`
+	Attempts    int       `gorm:"default:0"` // failed attempts of the current run
+	MaxAttempts int       `gorm:"default:3"`
+	LockedBy    string    `gorm:"type:varchar(100)"`
+	LockedUntil *time.Time
+	LastRunAt   *time.Time
+	LastError   string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// JobRun is the history of a ScheduledJob, one row per attempt.
+type JobRun struct {
+	ID         string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	JobName    string    `gorm:"type:varchar(100);not null;index"`
+	Attempt    int       `gorm:"not null"`
+	Status     string    `gorm:"type:varchar(20);not null"` // running, succeeded, failed
+	Error      string    `gorm:"type:text"`
+	Worker     string    `gorm:"type:varchar(100)"`
+	StartedAt  time.Time `gorm:"not null;index"`
+	FinishedAt *time.Time
+}
+
+// Plan is a package a family can be on, defined in the database so new plans
+// need no deploy. Limits caps counts by name (children, tasks, rewards); a
+// missing key means unlimited. Features lists the feature flags it unlocks.
+type Plan struct {
+	Code        string         `gorm:"primaryKey;type:varchar(20)"`
+	Name        string         `gorm:"type:varchar(100);not null"`
+	Description string         `gorm:"type:text"`
+	Limits      map[string]int `gorm:"type:jsonb;serializer:json;not null;default:'{}'"`
+	Features    []string       `gorm:"type:jsonb;serializer:json;not null;default:'[]'"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Payment is one Midtrans checkout for a PREMIUM package. OrderID is what
+// Midtrans knows the transaction by.
+type Payment struct {
+	ID            string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID      string  `gorm:"type:uuid;not null;index"`
+	OrderID       string  `gorm:"type:varchar(50);not null;uniqueIndex"`
+	PackageCode   string  `gorm:"type:varchar(50);not null"`
+	Days          int     `gorm:"not null"`                           // PREMIUM days granted on settlement
+	Amount        int64   `gorm:"not null"`                           // rupiah charged
+	Status        string  `gorm:"type:varchar(20);default:'pending'"` // pending, paid, failed, expired, refunded
+	PaymentType   string  `gorm:"type:varchar(30)"`
+	TransactionID string  `gorm:"type:varchar(100)"`
+	SnapToken     string  `gorm:"type:varchar(100)"`
+	RedirectURL   string  `gorm:"type:varchar(255)"`
+	RequestedBy   *string `gorm:"type:uuid"`
+	CouponCode    string  `gorm:"type:varchar(40)"`
+	Discount      int64   `gorm:"default:0"` // rupiah taken off the package price
+	PaidAt        *time.Time
+	RefundedAt    *time.Time
+	Family        Family `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// PaymentNotification is every webhook call received, kept for auditing and
+// to recognise redeliveries.
+type PaymentNotification struct {
+	ID                string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	OrderID           string `gorm:"type:varchar(50);not null;index"`
+	TransactionStatus string `gorm:"type:varchar(30)"`
+	StatusCode        string `gorm:"type:varchar(10)"`
+	FraudStatus       string `gorm:"type:varchar(20)"`
+	Payload           string `gorm:"type:text"`
+	CreatedAt         time.Time
+}
+
+// Coupon is a promo code. Percent and fixed coupons discount a checkout;
+// free_days coupons grant PREMIUM days directly. Each family may use a coupon once.
+type Coupon struct {
+	ID          string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Code        string `gorm:"type:varchar(40);not null;uniqueIndex"`
+	Description string `gorm:"type:text"`
+	Kind        string `gorm:"type:varchar(20);not null"` // percent, fixed, free_days
+	Value       int64  `gorm:"not null"`                  // percent off, rupiah off, or days granted
+	ValidFrom   *time.Time
+	ValidUntil  *time.Time
+	MaxUses     *int // nil = unlimited
+	IsActive    bool `gorm:"default:true"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CouponRedemption records a family's use of a coupon. A checkout reserves it
+// until the payment settles; a failed or expired payment releases it again.
+type CouponRedemption struct {
+	ID        string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CouponID  string  `gorm:"type:uuid;not null;uniqueIndex:idx_coupon_family"`
+	FamilyID  string  `gorm:"type:uuid;not null;uniqueIndex:idx_coupon_family;index"`
+	PaymentID *string `gorm:"type:uuid;index"`
+	Status    string  `gorm:"type:varchar(20);not null"` // reserved, used
+	Discount  int64   `gorm:"default:0"`                 // rupiah off for checkout coupons
+	Days      int     `gorm:"default:0"`                 // days granted for free_days coupons
+	Coupon    Coupon  `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Referral links an invited family to the family whose code it signed up
+// with. Both get bonus days once the invitee's first payment settles.
+type Referral struct {
+	ID               string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ReferrerFamilyID string `gorm:"type:uuid;not null;index"`
+	InviteeFamilyID  string `gorm:"type:uuid;not null;uniqueIndex"`
+	Status           string `gorm:"type:varchar(20);default:'pending'"` // pending, rewarded
+	BonusDays        int    `gorm:"default:0"`
+	RewardedAt       *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PushSubscription is one browser or device registered for Web Push.
+type PushSubscription struct {
+	ID            string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID        string `gorm:"type:uuid;not null;index"`
+	FamilyID      string `gorm:"type:uuid;not null;index"`
+	Endpoint      string `gorm:"type:text;not null;uniqueIndex"`
+	P256dh        string `gorm:"type:varchar(100);not null"`
+	Auth          string `gorm:"type:varchar(50);not null"`
+	UserAgent     string `gorm:"type:varchar(255)"`
+	LastSuccessAt *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NotificationPreference holds a user's opt-outs. Quiet hours are "HH:MM" in
+// the family timezone; pushes falling inside them wait until they end.
+type NotificationPreference struct {
+	UserID      string `gorm:"primaryKey;type:uuid"`
+	Redemptions bool   `gorm:"default:true"`
+	Approvals   bool   `gorm:"default:true"`
+	Maghrib     bool   `gorm:"default:true"`
+	Streaks     bool   `gorm:"default:true"`
+	QuietStart  string `gorm:"type:varchar(5)"`
+	QuietEnd    string `gorm:"type:varchar(5)"`
+	UpdatedAt   time.Time
+}
+
+// PushMessage is the delivery outbox: one row per subscription, retried with
+// backoff until sent, expired or the subscription is gone.
+type PushMessage struct {
+	ID             string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SubscriptionID string    `gorm:"type:uuid;not null;uniqueIndex:idx_push_dedupe"`
+	UserID         string    `gorm:"type:uuid;not null;index"`
+	Kind           string    `gorm:"type:varchar(20);not null"`
+	DedupeKey      *string   `gorm:"type:varchar(100);uniqueIndex:idx_push_dedupe"`
+	Payload        string    `gorm:"type:text;not null"`
+	Urgency        string    `gorm:"type:varchar(10)"`
+	Status         string    `gorm:"type:varchar(20);default:'pending';index"` // pending, sent, failed, expired, gone
+	Attempts       int       `gorm:"default:0"`
+	NextAttemptAt  time.Time `gorm:"index"`
+	ExpiresAt      time.Time
+	LockedUntil    *time.Time
+	LastError      string `gorm:"type:text"`
+	SentAt         *time.Time
+	CreatedAt      time.Time
+}
+
+// WhatsappConsent is a parent's opt-in to WhatsApp messages. Consent belongs
+// to the number: changing it requires opting in again.
+type WhatsappConsent struct {
+	UserID     string `gorm:"primaryKey;type:uuid"`
+	Phone      string `gorm:"type:varchar(20);not null;index"` // E.164
+	OptedIn    bool   `gorm:"default:false"`
+	Source     string `gorm:"type:varchar(20)"` // register, settings, reply
+	OptedInAt  *time.Time
+	OptedOutAt *time.Time
+	UpdatedAt  time.Time
+}
+
+// WhatsappMessage logs every WhatsApp send. The dedupe key keeps scheduled
+// messages (daily summary, expiry reminders) to one per user.
+type WhatsappMessage struct {
+	ID                string   `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID            string   `gorm:"type:uuid;not null;uniqueIndex:idx_whatsapp_dedupe"`
+	DedupeKey         *string  `gorm:"type:varchar(100);uniqueIndex:idx_whatsapp_dedupe"`
+	Phone             string   `gorm:"type:varchar(20);not null"`
+	Template          string   `gorm:"type:varchar(50);not null"`
+	Params            []string `gorm:"type:jsonb;serializer:json;not null;default:'[]'"`
+	Provider          string   `gorm:"type:varchar(20)"`
+	ProviderMessageID string   `gorm:"type:varchar(100)"`
+	Status            string   `gorm:"type:varchar(20);default:'pending'"` // pending, sent, failed
+	Error             string   `gorm:"type:text"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// IdempotencyKey remembers the first response to a request sent with an
+// Idempotency-Key header so retries get the same answer. Scope is the user ID,
+// or "public:" and a hash of the route and client IP for anonymous requests.
+type IdempotencyKey struct {
+	ID           string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Scope        string `gorm:"type:varchar(64);not null;uniqueIndex:idx_idempotency_scope_key"`
+	Key          string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_scope_key"`
+	RequestHash  string `gorm:"type:char(64);not null"` // SHA-256 of method, path and body
+	StatusCode   int
+	ContentType  string     `gorm:"type:varchar(100)"`
+	ResponseBody []byte     `gorm:"type:bytea"`
+	CompletedAt  *time.Time // nil while the first request is still running
+	ExpiresAt    time.Time  `gorm:"index"`
+	CreatedAt    time.Time
+}
+
+// Template is a set of tasks, rewards, schedules and badges a family applies
+// in one go. Built-in templates come from the embedded catalog (Key set,
+// FamilyID nil); families write their own and may share them by ShareCode.
+type Template struct {
+	ID          string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Key         *string        `gorm:"type:varchar(50);uniqueIndex"` // catalog key, built-in templates only
+	FamilyID    *string        `gorm:"type:uuid;index"`              // author, nil for built-in templates
+	Name        string         `gorm:"not null"`
+	Description string         `gorm:"type:text"`
+	AgeBand     string         `gorm:"type:varchar(10);not null;index"` // tk, sd_1_3, sd_4_6, smp
+	Items       []TemplateItem `gorm:"type:jsonb;serializer:json;not null;default:'[]'"`
+	ShareCode   *string        `gorm:"type:varchar(20);uniqueIndex"` // set while shared
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// TemplateItem is one task, reward, schedule (point rule) or badge of a
+// template; see catalog.Item for which fields each kind uses.
+type TemplateItem struct {
+	Key            string
+	Kind           string
+	Name           string
+	Icon           string
+	Description    string
+	Points         int
+	MaxPerDay      *int
+	Category       string
+	Quantity       int
+	PointsRequired int
+	RuleType       string
+	Multiplier     float64
+	BonusPoints    int
+	Weekdays       []int
+	LastDays       int
+	Task           string
+	Metric         string
+	Threshold      int
+}
+
+// TemplateApplication remembers what a template item became in a family, so
+// applying the template again skips it instead of creating a duplicate.
+type TemplateApplication struct {
+	ID         string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID   string `gorm:"type:uuid;not null;uniqueIndex:idx_template_application"`
+	TemplateID string `gorm:"type:uuid;not null;uniqueIndex:idx_template_application"`
+	ItemKey    string `gorm:"type:varchar(50);not null;uniqueIndex:idx_template_application"`
+	Kind       string `gorm:"type:varchar(20);not null"`
+	TargetID   string `gorm:"type:uuid;not null"` // the task, reward, point rule or badge
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Badge is earned by a child once their verified completions (or points) reach
+// Threshold, counting one task or all of them.
+type Badge struct {
+	ID          string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FamilyID    string  `gorm:"type:uuid;not null;index"`
+	Name        string  `gorm:"not null"`
+	Icon        string  `gorm:"default:'🏅'"`
+	Description string  `gorm:"type:text"`
+	Metric      string  `gorm:"type:varchar(20);default:'completions'"` // completions, points
+	Threshold   int     `gorm:"not null"`
+	TaskID      *string `gorm:"type:uuid;index"` // nil = every task
+	Family      Family  `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,237 @@
+// Package openapi holds the OpenAPI 3 description of /api/v1 and validates
+// requests against it. The document is embedded in the binary, served from
+// the API and used to generate the Go client in /client.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+// Prefix is where the versioned API is mounted.
+const Prefix = "/api/v1"
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties,omitempty"` // false or a schema
+	Items                *Schema            `json:"items,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+
+	additional *Schema // parsed AdditionalProperties when it is a schema
+	closed     bool    // additionalProperties: false
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref,omitempty"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // path, query or header
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is one entry of an operation's responses, keyed by status code
+// ("200", "4XX", "default").
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+
+	Method string `json:"-"`
+	Path   string `json:"-"` // OpenAPI template, e.g. /tasks/{id}
+	spec   *Spec
+}
+
+type Spec struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas    map[string]*Schema    `json:"schemas"`
+		Parameters map[string]*Parameter `json:"parameters"`
+		Responses  map[string]*Response  `json:"responses"`
+	} `json:"components"`
+
+	json []byte
+}
+
+// Load parses the embedded document.
+func Load() (*Spec, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(document, &raw); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	doc, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	spec := &Spec{json: doc}
+	if err := json.Unmarshal(doc, spec); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	for _, schema := range spec.Components.Schemas {
+		if err := spec.prepare(schema); err != nil {
+			return nil, err
+		}
+	}
+	for _, res := range spec.Components.Responses {
+		if err := spec.prepareContent(res.Content); err != nil {
+			return nil, err
+		}
+	}
+	for path, item := range spec.Paths {
+		for method, op := range item {
+			op.Method, op.Path, op.spec = strings.ToUpper(method), path, spec
+			for i, p := range op.Parameters {
+				if p.Ref != "" {
+					resolved, ok := spec.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+					if !ok {
+						return nil, fmt.Errorf("openapi: %s %s: unknown parameter %s", method, path, p.Ref)
+					}
+					op.Parameters[i] = resolved
+				}
+				if err := spec.prepare(op.Parameters[i].Schema); err != nil {
+					return nil, err
+				}
+			}
+			if op.RequestBody != nil {
+				if err := spec.prepareContent(op.RequestBody.Content); err != nil {
+					return nil, err
+				}
+			}
+			for status, res := range op.Responses {
+				if res.Ref != "" {
+					resolved, ok := spec.Components.Responses[strings.TrimPrefix(res.Ref, "#/components/responses/")]
+					if !ok {
+						return nil, fmt.Errorf("openapi: %s %s: unknown response %s", method, path, res.Ref)
+					}
+					op.Responses[status] = resolved
+					continue
+				}
+				if err := spec.prepareContent(res.Content); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return spec, nil
+}
+
+// prepare checks references and patterns and parses additionalProperties.
+func (s *Spec) prepare(schema *Schema) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		if _, ok := s.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]; !ok {
+			return fmt.Errorf("openapi: unknown schema %s", schema.Ref)
+		}
+		return nil
+	}
+	if schema.Pattern != "" {
+		if _, err := compilePattern(schema.Pattern); err != nil {
+			return fmt.Errorf("openapi: pattern %q: %w", schema.Pattern, err)
+		}
+	}
+	switch raw := strings.TrimSpace(string(schema.AdditionalProperties)); {
+	case raw == "false":
+		schema.closed = true
+	case strings.HasPrefix(raw, "{"):
+		schema.additional = &Schema{}
+		if err := json.Unmarshal(schema.AdditionalProperties, schema.additional); err != nil {
+			return fmt.Errorf("openapi: additionalProperties: %w", err)
+		}
+		if err := s.prepare(schema.additional); err != nil {
+			return err
+		}
+	}
+	for _, prop := range schema.Properties {
+		if err := s.prepare(prop); err != nil {
+			return err
+		}
+	}
+	return s.prepare(schema.Items)
+}
+
+func (s *Spec) prepareContent(content map[string]MediaType) error {
+	for _, media := range content {
+		if err := s.prepare(media.Schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// YAML is the document as written.
+func (s *Spec) YAML() []byte {
+	return document
+}
+
+// JSON is the document converted to JSON.
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+// Operation finds the operation for a Fiber route under Prefix, e.g.
+// ("PUT", "/api/v1/tasks/:id"). ok is false when the spec doesn't describe it.
+func (s *Spec) Operation(method, route string) (*Operation, bool) {
+	item, ok := s.Paths[TemplatePath(route)]
+	if !ok {
+		return nil, false
+	}
+	op, ok := item[strings.ToLower(method)]
+	return op, ok
+}
+
+// TemplatePath turns a Fiber route under Prefix into an OpenAPI path:
+// /api/v1/tasks/:id → /tasks/{id}.
+func TemplatePath(route string) string {
+	parts := strings.Split(strings.TrimPrefix(route, Prefix), "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + strings.TrimPrefix(part, ":") + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
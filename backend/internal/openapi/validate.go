@@ -0,0 +1,311 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Violation is one way a request doesn't match the spec. Field is a path like
+// "tasks[0].count", or the parameter name. Rule names the check that failed
+// ("required", "max_length"); Params hold its limits for the message.
+type Violation struct {
+	Field  string
+	Rule   string
+	Params map[string]interface{}
+}
+
+// Request is what Validate looks at.
+type Request struct {
+	PathParams  map[string]string
+	Query       map[string]string
+	ContentType string
+	Body        []byte
+}
+
+// Validate checks parameters and the JSON body and reports every violation,
+// not just the first.
+func (op *Operation) Validate(req Request) []Violation {
+	v := &validator{spec: op.spec}
+
+	for _, p := range op.Parameters {
+		var value string
+		var present bool
+		switch p.In {
+		case "path":
+			value, present = req.PathParams[p.Name]
+		case "query":
+			value, present = req.Query[p.Name]
+		default:
+			continue
+		}
+		if !present || value == "" {
+			if p.Required {
+				v.add(p.Name, "required", nil)
+			}
+			continue
+		}
+		v.param(p.Name, value, v.spec.resolve(p.Schema))
+	}
+
+	if op.RequestBody == nil {
+		return v.violations
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return v.violations
+	}
+	if len(bytes.TrimSpace(req.Body)) == 0 {
+		if op.RequestBody.Required {
+			v.add("body", "required", nil)
+		}
+		return v.violations
+	}
+	if req.ContentType != "" && !strings.HasPrefix(req.ContentType, "application/json") {
+		v.add("body", "content_type", nil)
+		return v.violations
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(req.Body))
+	dec.UseNumber()
+	var body interface{}
+	if err := dec.Decode(&body); err != nil {
+		v.add("body", "json", nil)
+		return v.violations
+	}
+	v.value("", body, media.Schema)
+	return v.violations
+}
+
+type validator struct {
+	spec       *Spec
+	violations []Violation
+}
+
+func (v *validator) add(field, rule string, params map[string]interface{}) {
+	if field == "" {
+		field = "body"
+	}
+	v.violations = append(v.violations, Violation{Field: field, Rule: rule, Params: params})
+}
+
+// param converts a path or query string to the schema's type, then validates it.
+func (v *validator) param(name, raw string, schema *Schema) {
+	if schema == nil {
+		return
+	}
+	var value interface{} = raw
+	switch schema.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			v.add(name, "integer", nil)
+			return
+		}
+		value = json.Number(raw)
+	case "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			v.add(name, "number", nil)
+			return
+		}
+		value = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			v.add(name, "boolean", nil)
+			return
+		}
+		value = b
+	}
+	v.value(name, value, schema)
+}
+
+func child(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func (v *validator) value(field string, value interface{}, schema *Schema) {
+	schema = v.spec.resolve(schema)
+	if schema == nil {
+		return
+	}
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			v.add(field, "not_null", nil)
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.add(field, "object", nil)
+			return
+		}
+		v.object(field, obj, schema)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			v.add(field, "array", nil)
+			return
+		}
+		if schema.MinItems != nil && len(arr) < *schema.MinItems {
+			v.add(field, "min_items", map[string]interface{}{"min": *schema.MinItems})
+		}
+		if schema.MaxItems != nil && len(arr) > *schema.MaxItems {
+			v.add(field, "max_items", map[string]interface{}{"max": *schema.MaxItems})
+		}
+		for i, item := range arr {
+			v.value(fmt.Sprintf("%s[%d]", field, i), item, schema.Items)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.add(field, "string", nil)
+			return
+		}
+		v.string(field, s, schema)
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			v.add(field, "number", nil)
+			return
+		}
+		v.number(field, n, schema)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.add(field, "boolean", nil)
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		v.add(field, "one_of", map[string]interface{}{"values": enumList(schema.Enum)})
+	}
+}
+
+func (v *validator) object(field string, obj map[string]interface{}, schema *Schema) {
+	for _, name := range schema.Required {
+		if value, ok := obj[name]; !ok || value == nil {
+			v.add(child(field, name), "required", nil)
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if prop, ok := schema.Properties[name]; ok {
+			v.value(child(field, name), obj[name], prop)
+		} else if schema.additional != nil {
+			v.value(child(field, name), obj[name], schema.additional)
+		} else if schema.closed {
+			v.add(child(field, name), "unknown_field", nil)
+		}
+	}
+}
+
+func (v *validator) string(field, s string, schema *Schema) {
+	length := len([]rune(s))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		if *schema.MinLength == 1 {
+			v.add(field, "required", nil)
+		} else {
+			v.add(field, "min_length", map[string]interface{}{"min": *schema.MinLength})
+		}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.add(field, "max_length", map[string]interface{}{"max": *schema.MaxLength})
+	}
+	if schema.Pattern != "" && s != "" {
+		if re, err := compilePattern(schema.Pattern); err == nil && !re.MatchString(s) {
+			v.add(field, "format", nil)
+		}
+	}
+	if s == "" {
+		return // emptiness is up to minLength; optional fields may be cleared with ""
+	}
+	switch schema.Format {
+	case "uuid":
+		if uuid.Validate(s) != nil {
+			v.add(field, "uuid", nil)
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			v.add(field, "date", nil)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			v.add(field, "date_time", nil)
+		}
+	case "email":
+		if _, err := mail.ParseAddress(s); err != nil {
+			v.add(field, "email", nil)
+		}
+	}
+}
+
+func (v *validator) number(field string, n json.Number, schema *Schema) {
+	if schema.Type == "integer" {
+		if _, err := n.Int64(); err != nil {
+			v.add(field, "integer", nil)
+			return
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		v.add(field, "number", nil)
+		return
+	}
+	if schema.Minimum != nil && f < *schema.Minimum {
+		v.add(field, "min", map[string]interface{}{"min": *schema.Minimum})
+	}
+	if schema.Maximum != nil && f > *schema.Maximum {
+		v.add(field, "max", map[string]interface{}{"max": *schema.Maximum})
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []interface{}) []string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return values
+}
+
+var patterns sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,272 @@
+// Package fakemidtrans is an in-memory stand-in for the Midtrans Snap and Core
+// APIs. It issues Snap tokens, answers status queries and sends correctly
+// signed HTTP notifications, so the payment flow can be exercised end to end
+// without sandbox credentials. cmd/fakemidtrans serves it standalone.
+package fakemidtrans
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/payments"
+)
+
+type order struct {
+	ID            string
+	Token         string
+	GrossAmount   int64
+	Status        string
+	PaymentType   string
+	TransactionID string
+	CreatedAt     time.Time
+}
+
+type Server struct {
+	ServerKey string
+	// BaseURL is this server's own URL, used in redirect_url.
+	BaseURL string
+	// NotificationURL receives the HTTP notifications, like the dashboard setting on Midtrans.
+	NotificationURL string
+
+	mu      sync.Mutex
+	orders  map[string]*order
+	byToken map[string]*order
+	client  *http.Client
+}
+
+func New(serverKey, baseURL, notificationURL string) *Server {
+	return &Server{
+		ServerKey:       serverKey,
+		BaseURL:         strings.TrimRight(baseURL, "/"),
+		NotificationURL: notificationURL,
+		orders:          map[string]*order{},
+		byToken:         map[string]*order{},
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusCodes mirrors the status_code Midtrans sends for each transaction_status.
+var statusCodes = map[string]string{
+	"capture":        "200",
+	"settlement":     "200",
+	"pending":        "201",
+	"deny":           "202",
+	"cancel":         "200",
+	"expire":         "407",
+	"refund":         "200",
+	"partial_refund": "200",
+	"chargeback":     "200",
+}
+
+func (s *Server) notification(o *order) payments.Notification {
+	gross := fmt.Sprintf("%d.00", o.GrossAmount)
+	code := statusCodes[o.Status]
+	n := payments.Notification{
+		TransactionID:     o.TransactionID,
+		TransactionStatus: o.Status,
+		TransactionTime:   o.CreatedAt.Format("2006-01-02 15:04:05"),
+		StatusCode:        code,
+		StatusMessage:     "midtrans payment notification",
+		OrderID:           o.ID,
+		GrossAmount:       gross,
+		PaymentType:       o.PaymentType,
+		SignatureKey:      payments.Signature(o.ID, code, gross, s.ServerKey),
+	}
+	if o.Status == "capture" {
+		n.FraudStatus = "accept"
+	}
+	return n
+}
+
+// SetStatus changes an order's status and returns the signed notification for it.
+func (s *Server) SetStatus(orderID, status string) (*payments.Notification, error) {
+	if _, ok := statusCodes[status]; !ok {
+		return nil, fmt.Errorf("fakemidtrans: unknown status %q", status)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[orderID]
+	if !ok {
+		return nil, errors.New("fakemidtrans: order not found")
+	}
+	o.Status = status
+	if o.PaymentType == "" && status != "pending" {
+		o.PaymentType = "qris"
+	}
+	n := s.notification(o)
+	return &n, nil
+}
+
+// Notify sets the status and posts the notification to NotificationURL.
+// It returns the HTTP status the webhook answered with.
+func (s *Server) Notify(ctx context.Context, orderID, status string) (int, error) {
+	n, err := s.SetStatus(orderID, status)
+	if err != nil {
+		return 0, err
+	}
+	body, _ := json.Marshal(n)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.NotificationURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	user, _, ok := r.BasicAuth()
+	return ok && user == s.ServerKey
+}
+
+// Handler serves:
+//
+//	POST /snap/v1/transactions         create a Snap transaction
+//	GET  /v2/{order_id}/status         Core API status
+//	GET  /snap/v2/vtweb/{token}        a bare payment page with buttons
+//	POST /fake/orders/{order_id}/{status}  change status and send the notification
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/snap/v1/transactions", s.handleCreate)
+	mux.HandleFunc("/v2/", s.handleStatus)
+	mux.HandleFunc("/snap/v2/vtweb/", s.handlePage)
+	mux.HandleFunc("/fake/orders/", s.handleControl)
+	return mux
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+			"error_messages": []string{"Access denied due to unauthorized transaction, please check client or server key"},
+		})
+		return
+	}
+
+	var req payments.SnapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TransactionDetails.OrderID == "" || req.TransactionDetails.GrossAmount < 1 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error_messages": []string{"transaction_details.order_id and gross_amount are required"},
+		})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.orders[req.TransactionDetails.OrderID]; exists {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error_messages": []string{"transaction_details.order_id has already been taken"},
+		})
+		return
+	}
+	o := &order{
+		ID:            req.TransactionDetails.OrderID,
+		Token:         randomHex(16),
+		GrossAmount:   req.TransactionDetails.GrossAmount,
+		Status:        "pending",
+		TransactionID: randomHex(16),
+		CreatedAt:     time.Now(),
+	}
+	s.orders[o.ID] = o
+	s.byToken[o.Token] = o
+
+	writeJSON(w, http.StatusCreated, payments.SnapResponse{
+		Token:       o.Token,
+		RedirectURL: s.BaseURL + "/snap/v2/vtweb/" + o.Token,
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	orderID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/"), "/status")
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"status_code": "401", "status_message": "Unauthorized"})
+		return
+	}
+	s.mu.Lock()
+	o, ok := s.orders[orderID]
+	var n payments.Notification
+	if ok {
+		n = s.notification(o)
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"status_code": "404", "status_message": "Transaction doesn't exist."})
+		return
+	}
+	writeJSON(w, http.StatusOK, n)
+}
+
+var page = template.Must(template.New("page").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>Fake Midtrans</title></head>
+<body style="font-family:sans-serif;max-width:420px;margin:40px auto">
+<h2>Fake Midtrans</h2>
+<p>Order <b>{{.ID}}</b><br>Rp {{.GrossAmount}} — status <b>{{.Status}}</b></p>
+{{range .Actions}}<form method="post" action="/fake/orders/{{$.ID}}/{{.}}" style="display:inline"><button>{{.}}</button></form> {{end}}
+</body></html>`))
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, "/snap/v2/vtweb/")
+	s.mu.Lock()
+	o, ok := s.byToken[token]
+	var view struct {
+		order
+		Actions []string
+	}
+	if ok {
+		view.order = *o
+		view.Actions = []string{"settlement", "expire", "cancel", "deny", "refund"}
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	page.Execute(w, view)
+}
+
+func (s *Server) handleControl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/fake/orders/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	code, err := s.Notify(r.Context(), parts[0], parts[1])
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"order_id": parts[0], "status": parts[1], "webhook_status": code})
+}
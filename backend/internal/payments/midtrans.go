@@ -0,0 +1,183 @@
+// Package payments talks to the Midtrans Snap and Core APIs.
+package payments
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+)
+
+const (
+	sandboxSnapURL    = "https://app.sandbox.midtrans.com"
+	sandboxAPIURL     = "https://api.sandbox.midtrans.com"
+	productionSnapURL = "https://app.midtrans.com"
+	productionAPIURL  = "https://api.midtrans.com"
+)
+
+type Client struct {
+	ServerKey string
+	SnapURL   string // base URL of the Snap API, e.g. https://app.sandbox.midtrans.com
+	APIURL    string // base URL of the Core API, e.g. https://api.sandbox.midtrans.com
+	HTTP      *http.Client
+}
+
+// NewClient uses the sandbox or production hosts by cfg.Env unless
+// cfg.SnapURL and cfg.APIURL override them, e.g. to point at cmd/fakemidtrans.
+func NewClient(cfg config.Midtrans) *Client {
+	c := &Client{
+		ServerKey: cfg.ServerKey,
+		SnapURL:   sandboxSnapURL,
+		APIURL:    sandboxAPIURL,
+		HTTP:      &http.Client{Timeout: 15 * time.Second},
+	}
+	if cfg.Env == "production" {
+		c.SnapURL, c.APIURL = productionSnapURL, productionAPIURL
+	}
+	if cfg.SnapURL != "" {
+		c.SnapURL = strings.TrimRight(cfg.SnapURL, "/")
+	}
+	if cfg.APIURL != "" {
+		c.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	}
+	return c
+}
+
+type TransactionDetails struct {
+	OrderID     string `json:"order_id"`
+	GrossAmount int64  `json:"gross_amount"`
+}
+
+type ItemDetail struct {
+	ID       string `json:"id"`
+	Price    int64  `json:"price"`
+	Quantity int    `json:"quantity"`
+	Name     string `json:"name"`
+}
+
+type CustomerDetails struct {
+	FirstName string `json:"first_name"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+}
+
+type Expiry struct {
+	Unit     string `json:"unit"` // minute, hour, day
+	Duration int    `json:"duration"`
+}
+
+type SnapRequest struct {
+	TransactionDetails TransactionDetails `json:"transaction_details"`
+	ItemDetails        []ItemDetail       `json:"item_details,omitempty"`
+	CustomerDetails    *CustomerDetails   `json:"customer_details,omitempty"`
+	Expiry             *Expiry            `json:"expiry,omitempty"`
+}
+
+type SnapResponse struct {
+	Token       string `json:"token"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+// Notification is the HTTP notification Midtrans posts on every status change.
+// It has the same shape as the transaction status response.
+type Notification struct {
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"` // capture, settlement, pending, deny, cancel, expire, refund, partial_refund, chargeback
+	TransactionTime   string `json:"transaction_time"`
+	StatusCode        string `json:"status_code"`
+	StatusMessage     string `json:"status_message"`
+	SignatureKey      string `json:"signature_key"`
+	OrderID           string `json:"order_id"`
+	GrossAmount       string `json:"gross_amount"` // e.g. "29000.00"
+	PaymentType       string `json:"payment_type"`
+	FraudStatus       string `json:"fraud_status"`
+}
+
+type apiError struct {
+	StatusCode    string   `json:"status_code"`
+	StatusMessage string   `json:"status_message"`
+	ErrorMessages []string `json:"error_messages"`
+}
+
+func (c *Client) do(ctx context.Context, method, url string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.ServerKey, "")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var e apiError
+		json.Unmarshal(data, &e)
+		msg := strings.Join(e.ErrorMessages, "; ")
+		if msg == "" {
+			msg = e.StatusMessage
+		}
+		return fmt.Errorf("midtrans: %s %s: HTTP %d %s", method, url, resp.StatusCode, msg)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// CreateSnapTransaction starts a Snap checkout and returns the token for snap.js
+// and the hosted payment page URL.
+func (c *Client) CreateSnapTransaction(ctx context.Context, req SnapRequest) (*SnapResponse, error) {
+	var resp SnapResponse
+	if err := c.do(ctx, http.MethodPost, c.SnapURL+"/snap/v1/transactions", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetStatus fetches the current status of an order from the Core API.
+func (c *Client) GetStatus(ctx context.Context, orderID string) (*Notification, error) {
+	var resp Notification
+	if err := c.do(ctx, http.MethodGet, c.APIURL+"/v2/"+orderID+"/status", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Signature computes SHA512(order_id + status_code + gross_amount + server_key) as hex.
+func Signature(orderID, statusCode, grossAmount, serverKey string) string {
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySignature checks that a notification was signed with our server key.
+func (c *Client) VerifySignature(n Notification) bool {
+	if c.ServerKey == "" || n.SignatureKey == "" {
+		return false
+	}
+	expected := Signature(n.OrderID, n.StatusCode, n.GrossAmount, c.ServerKey)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(n.SignatureKey))) == 1
+}
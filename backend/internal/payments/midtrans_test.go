@@ -0,0 +1,145 @@
+package payments_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+	"github.com/username/ramadhan-ceria-backend/internal/payments"
+	"github.com/username/ramadhan-ceria-backend/internal/payments/fakemidtrans"
+)
+
+const serverKey = "SB-Mid-server-test"
+
+// newFake serves fakemidtrans on a local port and returns a client pointed at it.
+func newFake(t *testing.T) (*fakemidtrans.Server, *payments.Client) {
+	t.Helper()
+	fake := fakemidtrans.New(serverKey, "", "")
+	srv := httptest.NewServer(fake.Handler())
+	t.Cleanup(srv.Close)
+	fake.BaseURL = srv.URL
+	client := payments.NewClient(config.Midtrans{ServerKey: serverKey, SnapURL: srv.URL, APIURL: srv.URL})
+	return fake, client
+}
+
+func snapRequest(orderID string, amount int64) payments.SnapRequest {
+	return payments.SnapRequest{
+		TransactionDetails: payments.TransactionDetails{OrderID: orderID, GrossAmount: amount},
+		ItemDetails:        []payments.ItemDetail{{ID: "premium-30", Price: amount, Quantity: 1, Name: "PREMIUM 1 Bulan"}},
+	}
+}
+
+func TestCreateSnapTransaction(t *testing.T) {
+	fake, client := newFake(t)
+
+	snap, err := client.CreateSnapTransaction(context.Background(), snapRequest("RC-TEST-1", 29000))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if snap.Token == "" {
+		t.Fatal("empty snap token")
+	}
+	if want := fake.BaseURL + "/snap/v2/vtweb/" + snap.Token; snap.RedirectURL != want {
+		t.Errorf("redirect_url = %q, want %q", snap.RedirectURL, want)
+	}
+
+	if _, err := client.CreateSnapTransaction(context.Background(), snapRequest("RC-TEST-1", 29000)); err == nil {
+		t.Error("reusing an order_id should fail")
+	}
+}
+
+func TestCreateSnapTransactionSendsServerKey(t *testing.T) {
+	var got payments.SnapRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/snap/v1/transactions" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != serverKey || pass != "" {
+			t.Errorf("basic auth = %q, %q, %v", user, pass, ok)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"tok","redirect_url":"https://pay.example/tok"}`))
+	}))
+	defer srv.Close()
+
+	client := payments.NewClient(config.Midtrans{ServerKey: serverKey, SnapURL: srv.URL})
+	snap, err := client.CreateSnapTransaction(context.Background(), snapRequest("RC-TEST-2", 79000))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if snap.Token != "tok" {
+		t.Errorf("token = %q", snap.Token)
+	}
+	if got.TransactionDetails.OrderID != "RC-TEST-2" || got.TransactionDetails.GrossAmount != 79000 {
+		t.Errorf("transaction_details = %+v", got.TransactionDetails)
+	}
+}
+
+func TestCreateSnapTransactionWrongKey(t *testing.T) {
+	fake, _ := newFake(t)
+	client := payments.NewClient(config.Midtrans{ServerKey: "SB-Mid-server-other", SnapURL: fake.BaseURL})
+
+	_, err := client.CreateSnapTransaction(context.Background(), snapRequest("RC-TEST-3", 29000))
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("err = %v, want a 401 error", err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	fake, client := newFake(t)
+	if _, err := client.CreateSnapTransaction(context.Background(), snapRequest("RC-TEST-4", 29000)); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := fake.SetStatus("RC-TEST-4", "settlement"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := client.GetStatus(context.Background(), "RC-TEST-4")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if n.TransactionStatus != "settlement" || n.GrossAmount != "29000.00" {
+		t.Errorf("status = %s %s", n.TransactionStatus, n.GrossAmount)
+	}
+	if !client.VerifySignature(*n) {
+		t.Error("status response should carry a valid signature")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	fake, client := newFake(t)
+	if _, err := client.CreateSnapTransaction(context.Background(), snapRequest("RC-TEST-5", 29000)); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	n, err := fake.SetStatus("RC-TEST-5", "settlement")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !client.VerifySignature(*n) {
+		t.Fatal("valid signature rejected")
+	}
+
+	tampered := *n
+	tampered.GrossAmount = "1.00"
+	if client.VerifySignature(tampered) {
+		t.Error("signature accepted for a changed gross_amount")
+	}
+
+	forged := *n
+	forged.SignatureKey = payments.Signature(n.OrderID, n.StatusCode, n.GrossAmount, "SB-Mid-server-other")
+	if client.VerifySignature(forged) {
+		t.Error("signature made with another server key accepted")
+	}
+
+	empty := *n
+	empty.SignatureKey = ""
+	if client.VerifySignature(empty) {
+		t.Error("missing signature accepted")
+	}
+}
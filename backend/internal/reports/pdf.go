@@ -0,0 +1,90 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// RenderPDF writes the rapor as an A4 PDF. The core PDF fonts have no emoji,
+// so avatars and reward icons are left out.
+func RenderPDF(w io.Writer, r ChildReport) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Rapor Ramadhan "+r.ChildName, true)
+	pdf.SetMargins(18, 18, 18)
+	pdf.AddPage()
+
+	pdf.SetTextColor(15, 118, 110)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(0, 10, tr("Rapor Ramadhan "+r.ChildName), "", 1, "L", false, 0, "")
+
+	pdf.SetTextColor(124, 116, 104)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("%s - %s (%s s/d %s)", r.FamilyName, r.PeriodLabel, r.From, r.To)), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	stats := [][2]string{
+		{formatThousands(r.PointsEarned), "Poin didapat"},
+		{fmt.Sprintf("%d/%d", r.PrayersKept, r.PrayersPossible), fmt.Sprintf("Sholat terjaga (%d%%)", r.PrayerPercent())},
+		{formatThousands(r.PagesRead), "Halaman dibaca"},
+		{formatThousands(r.Completions), "Misi selesai"},
+		{fmt.Sprintf("%d hari", r.CurrentStreak), "Streak saat ini"},
+		{fmt.Sprintf("%d hari", r.LongestStreak), "Streak terpanjang"},
+	}
+
+	boxW, boxH, gap := 56.0, 20.0, 5.0
+	left, top := pdf.GetX(), pdf.GetY()
+	for i, s := range stats {
+		x := left + float64(i%3)*(boxW+gap)
+		y := top + float64(i/3)*(boxH+gap)
+		pdf.SetFillColor(240, 253, 250)
+		pdf.Rect(x, y, boxW, boxH, "F")
+
+		pdf.SetXY(x, y+3)
+		pdf.SetTextColor(15, 118, 110)
+		pdf.SetFont("Helvetica", "B", 15)
+		pdf.CellFormat(boxW, 8, tr(s[0]), "", 0, "C", false, 0, "")
+
+		pdf.SetXY(x, y+11)
+		pdf.SetTextColor(91, 85, 73)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(boxW, 6, tr(s[1]), "", 0, "C", false, 0, "")
+	}
+	pdf.SetXY(left, top+2*(boxH+gap)+4)
+
+	pdf.SetTextColor(45, 42, 38)
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, "Hadiah yang ditukar", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	if len(r.Rewards) == 0 {
+		pdf.SetTextColor(124, 116, 104)
+		pdf.CellFormat(0, 7, "Belum ada hadiah yang ditukar pada periode ini.", "", 1, "L", false, 0, "")
+	} else {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 7, "Tanggal", "B", 0, "L", false, 0, "")
+		pdf.CellFormat(100, 7, "Hadiah", "B", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, "Poin", "B", 1, "R", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		for _, rw := range r.Rewards {
+			pdf.CellFormat(40, 7, rw.Date, "B", 0, "L", false, 0, "")
+			pdf.CellFormat(100, 7, tr(rw.Name), "B", 0, "L", false, 0, "")
+			pdf.CellFormat(0, 7, formatThousands(rw.Points), "B", 1, "R", false, 0, "")
+		}
+	}
+
+	pdf.Ln(8)
+	pdf.SetFillColor(254, 243, 199)
+	pdf.SetTextColor(45, 42, 38)
+	pdf.SetFont("Helvetica", "I", 11)
+	pdf.MultiCell(0, 7, tr("\""+r.Encouragement+"\""), "", "L", true)
+
+	pdf.Ln(6)
+	pdf.SetTextColor(154, 146, 133)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.CellFormat(0, 5, "Dibuat oleh Ramadhan Ceria - "+r.GeneratedAt.Format("02 Jan 2006 15:04"), "", 1, "C", false, 0, "")
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,83 @@
+// Package reports renders the "Rapor Ramadhan" digest of a child to HTML and PDF.
+package reports
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var htmlTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"number": formatThousands,
+}).ParseFS(templateFS, "templates/*.html"))
+
+type RedeemedReward struct {
+	Name   string
+	Icon   string
+	Points int64
+	Date   string
+}
+
+// ChildReport is everything a rapor shows about one child for one period.
+type ChildReport struct {
+	FamilyName      string
+	ChildName       string
+	Avatar          string
+	Period          string // week or season
+	PeriodLabel     string // e.g. "Pekan 3 Maret 2026" or "Ramadhan 1447 H"
+	From            string
+	To              string
+	PointsEarned    int64
+	Completions     int64
+	PrayersKept     int64
+	PrayersPossible int64
+	PagesRead       int64
+	CurrentStreak   int64
+	LongestStreak   int64
+	Rewards         []RedeemedReward
+	Encouragement   string
+	GeneratedAt     time.Time
+}
+
+// PrayerPercent is the share of prayers kept, 0-100.
+func (r ChildReport) PrayerPercent() int64 {
+	if r.PrayersPossible == 0 {
+		return 0
+	}
+	p := r.PrayersKept * 100 / r.PrayersPossible
+	if p > 100 {
+		p = 100
+	}
+	return p
+}
+
+// RenderHTML writes the rapor as a standalone HTML page.
+func RenderHTML(w io.Writer, r ChildReport) error {
+	return htmlTemplates.ExecuteTemplate(w, "rapor.html", r)
+}
+
+func formatThousands(v int64) string {
+	neg := v < 0
+	if neg {
+		v = -v
+	}
+	digits := []byte{}
+	for i := 0; v > 0 || i == 0; i++ {
+		if i > 0 && i%3 == 0 {
+			digits = append(digits, '.')
+		}
+		digits = append(digits, byte('0'+v%10))
+		v /= 10
+	}
+	if neg {
+		digits = append(digits, '-')
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return string(digits)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/mailer"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+const (
+	// DeletionCoolingOff is how long a confirmed deletion can still be cancelled.
+	DeletionCoolingOff = 7 * 24 * time.Hour
+	// deletionTokenTTL is how long the e-mailed confirmation token stays valid.
+	deletionTokenTTL = 24 * time.Hour
+)
+
+type AccountDeletionService struct {
+	mailer mailer.Mailer
+}
+
+func NewAccountDeletionService(m mailer.Mailer) *AccountDeletionService {
+	return &AccountDeletionService{mailer: m}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *AccountDeletionService) GetDeletion(familyID string) (*models.AccountDeletion, error) {
+	var deletion models.AccountDeletion
+	if err := database.DB.Where("family_id = ?", familyID).First(&deletion).Error; err != nil {
+		return nil, ErrDeletionNotFound
+	}
+	return &deletion, nil
+}
+
+// RequestDeletion starts a deletion and e-mails the confirmation token to the
+// requesting parent. Requesting again replaces an unconfirmed request.
+func (s *AccountDeletionService) RequestDeletion(ctx context.Context, familyID, userID string) (*models.AccountDeletion, error) {
+	var parent models.User
+	if err := database.DB.Where("id = ? AND family_id = ? AND role = 'parent'", userID, familyID).First(&parent).Error; err != nil {
+		return nil, ErrDeletionParentOnly
+	}
+	if parent.Email == nil {
+		return nil, ErrNoRecipient
+	}
+
+	if existing, err := s.GetDeletion(familyID); err == nil && existing.Status == "scheduled" {
+		return nil, ErrDeletionScheduled
+	}
+
+	token, err := utils.RandomHex(24)
+	if err != nil {
+		return nil, err
+	}
+
+	deletion := models.AccountDeletion{
+		FamilyID:    familyID,
+		RequestedBy: userID,
+		TokenHash:   hashToken(token),
+		Status:      "pending",
+	}
+	tx := database.DB.Begin()
+	if err := tx.Where("family_id = ?", familyID).Delete(&models.AccountDeletion{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(&deletion).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      []string{*parent.Email},
+		Subject: "Konfirmasi penghapusan akun keluarga",
+		HTML: fmt.Sprintf(`<p>Assalamu'alaikum %s,</p>
+<p>Kami menerima permintaan untuk menghapus akun keluarga beserta seluruh datanya.
+Masukkan kode berikut di aplikasi untuk mengonfirmasi (berlaku 24 jam):</p>
+<p style="font-size:20px;font-family:monospace"><b>%s</b></p>
+<p>Setelah dikonfirmasi, data baru dihapus %d hari kemudian dan masih bisa dibatalkan sebelum itu.
+Unduh arsip data keluarga terlebih dahulu jika ingin menyimpannya.</p>
+<p>Jika Anda tidak meminta ini, abaikan e-mail ini.</p>`, parent.Name, token, int(DeletionCoolingOff.Hours()/24)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+// ConfirmDeletion checks the token and schedules the deletion after the cooling-off period.
+func (s *AccountDeletionService) ConfirmDeletion(familyID, token string) (*models.AccountDeletion, error) {
+	deletion, err := s.GetDeletion(familyID)
+	if err != nil {
+		return nil, err
+	}
+	if deletion.Status != "pending" {
+		return nil, ErrDeletionScheduled
+	}
+	if time.Since(deletion.CreatedAt) > deletionTokenTTL {
+		return nil, ErrConfirmationExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(deletion.TokenHash)) != 1 {
+		return nil, ErrConfirmationInvalid
+	}
+
+	now := time.Now()
+	scheduled := now.Add(DeletionCoolingOff)
+	deletion.Status = "scheduled"
+	deletion.ConfirmedAt = &now
+	deletion.ScheduledFor = &scheduled
+	if err := database.DB.Save(deletion).Error; err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// CancelDeletion withdraws a pending or scheduled deletion.
+func (s *AccountDeletionService) CancelDeletion(familyID string) error {
+	result := database.DB.Where("family_id = ?", familyID).Delete(&models.AccountDeletion{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeletionNotFound
+	}
+	return nil
+}
+
+// ProcessDueDeletions deletes every family whose cooling-off period is over.
+func (s *AccountDeletionService) ProcessDueDeletions() (int, error) {
+	var due []models.AccountDeletion
+	if err := database.DB.Where("status = 'scheduled' AND scheduled_for <= ?", time.Now()).Find(&due).Error; err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, d := range due {
+		if err := DeleteFamily(d.FamilyID); err != nil {
+			return deleted, err
+		}
+		log.Printf("account deletion: family %s deleted (requested by %s)", d.FamilyID, d.RequestedBy)
+		deleted++
+	}
+	return deleted, nil
+}
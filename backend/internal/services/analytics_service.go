@@ -0,0 +1,382 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type AnalyticsService struct{}
+
+func NewAnalyticsService() *AnalyticsService {
+	return &AnalyticsService{}
+}
+
+// maxAnalyticsDays bounds the range so generate_series stays cheap.
+const maxAnalyticsDays = 366
+
+type AnalyticsQuery struct {
+	From     string   // YYYY-MM-DD, default 29 days before To
+	To       string   // YYYY-MM-DD, default today in the family timezone
+	ChildIDs []string // empty = every child
+}
+
+type AnalyticsChild struct {
+	ChildID   string `json:"childId"`
+	ChildName string `json:"childName"`
+	Avatar    string `json:"avatar"`
+}
+
+type AnalyticsSummary struct {
+	TotalTasks    int64 `json:"totalTasks"`
+	TotalRewards  int64 `json:"totalRewards"`
+	TotalChildren int64 `json:"totalChildren"`
+	Completions   int64 `json:"completions"`
+	PointsEarned  int64 `json:"pointsEarned"`
+	PointsSpent   int64 `json:"pointsSpent"`
+}
+
+type CompletionPoint struct {
+	Date        string  `json:"date"` // day, or Monday of the week for weekly series
+	ChildID     string  `json:"childId"`
+	Completions int64   `json:"completions"`
+	Rate        float64 `json:"rate"` // 0-100
+}
+
+type PointsPoint struct {
+	Date   string `json:"date"`
+	Earned int64  `json:"earned"`
+	Spent  int64  `json:"spent"`
+}
+
+type TaskInsight struct {
+	TaskID      string `json:"taskId"`
+	Name        string `json:"name"`
+	Icon        string `json:"icon"`
+	Completions int64  `json:"completions"`
+	Skipped     int64  `json:"skipped"` // child-days without a single completion
+}
+
+type HeatmapCell struct {
+	Date    string `json:"date"`
+	ChildID string `json:"childId"`
+	Prayers int64  `json:"prayers"` // prayers kept that day, over tasks in the prayer category
+}
+
+type StreakInfo struct {
+	ChildID string `json:"childId"`
+	Current int64  `json:"current"`
+	Longest int64  `json:"longest"`
+}
+
+type AnalyticsReport struct {
+	From             string            `json:"from"`
+	To               string            `json:"to"`
+	Children         []AnalyticsChild  `json:"children"`
+	Summary          AnalyticsSummary  `json:"summary"`
+	CompletionDaily  []CompletionPoint `json:"completionDaily"`
+	CompletionWeekly []CompletionPoint `json:"completionWeekly"`
+	Points           []PointsPoint     `json:"points"`
+	MostCompleted    []TaskInsight     `json:"mostCompleted"`
+	MostSkipped      []TaskInsight     `json:"mostSkipped"`
+	PrayerHeatmap    []HeatmapCell     `json:"prayerHeatmap"`
+	Streaks          []StreakInfo      `json:"streaks"`
+}
+
+// completionCTE yields one row per day and child with completed slots over available slots,
+// using the same slot definition as the leaderboard.
+const completionCTE = `
+	WITH capacity AS (
+		SELECT COALESCE(SUM(GREATEST(COALESCE(max_per_day, 1), 1)), 0) AS slots_per_day
+		FROM tasks
+		WHERE family_id = @family AND is_active = true AND deleted_at IS NULL
+	),
+	days AS (
+		SELECT d::date AS day FROM generate_series(@from::date, @to::date, interval '1 day') d
+	),
+	slots AS (
+		SELECT dl.child_id, dl.completed_date,
+			COUNT(*) AS completions,
+			LEAST(COUNT(*), GREATEST(COALESCE(t.max_per_day, 1), 1)) AS done
+		FROM daily_logs dl
+		JOIN tasks t ON t.id = dl.task_id
+		WHERE dl.status = 'verified' AND dl.deleted_at IS NULL
+			AND dl.child_id IN @children AND dl.completed_date BETWEEN @from AND @to
+		GROUP BY dl.child_id, dl.task_id, dl.completed_date, t.max_per_day
+	),
+	daily AS (
+		SELECT days.day, k.id AS child_id,
+			COALESCE(SUM(s.completions), 0) AS completions,
+			COALESCE(SUM(s.done), 0) AS done,
+			MAX(cap.slots_per_day) AS slots_per_day
+		FROM days
+		CROSS JOIN capacity cap
+		CROSS JOIN (SELECT id FROM users WHERE id IN @children) k
+		LEFT JOIN slots s ON s.child_id = k.id AND s.completed_date = days.day
+		GROUP BY days.day, k.id
+	)`
+
+func (s *AnalyticsService) GetAnalytics(familyID string, q AnalyticsQuery) (*AnalyticsReport, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	today := utils.Today(utils.LoadLocation(family.Timezone))
+	to := today
+	if q.To != "" {
+		d, err := time.Parse("2006-01-02", q.To)
+		if err != nil {
+			return nil, InvalidField("to", "date", nil)
+		}
+		to = d
+	}
+	from := to.AddDate(0, 0, -29)
+	if q.From != "" {
+		d, err := time.Parse("2006-01-02", q.From)
+		if err != nil {
+			return nil, InvalidField("from", "date", nil)
+		}
+		from = d
+	}
+	if to.Before(from) {
+		return nil, InvalidField("to", "not_before", map[string]interface{}{"other": "from"})
+	}
+	if to.Sub(from).Hours()/24 >= maxAnalyticsDays {
+		return nil, InvalidField("to", "range_too_long", map[string]interface{}{"other": "from"})
+	}
+
+	childQuery := database.DB.Model(&models.User{}).Where("family_id = ? AND role = 'child'", familyID)
+	if len(q.ChildIDs) > 0 {
+		childQuery = childQuery.Where("id IN ?", q.ChildIDs)
+	}
+	var children []models.User
+	if err := childQuery.Order("name").Find(&children).Error; err != nil {
+		return nil, err
+	}
+	if len(q.ChildIDs) > 0 && len(children) != len(q.ChildIDs) {
+		return nil, ErrChildNotFound
+	}
+
+	report := &AnalyticsReport{
+		From:             from.Format("2006-01-02"),
+		To:               to.Format("2006-01-02"),
+		Children:         make([]AnalyticsChild, 0, len(children)),
+		CompletionDaily:  []CompletionPoint{},
+		CompletionWeekly: []CompletionPoint{},
+		Points:           []PointsPoint{},
+		MostCompleted:    []TaskInsight{},
+		MostSkipped:      []TaskInsight{},
+		PrayerHeatmap:    []HeatmapCell{},
+		Streaks:          []StreakInfo{},
+	}
+
+	childIDs := make([]string, 0, len(children))
+	for _, c := range children {
+		childIDs = append(childIDs, c.ID)
+		report.Children = append(report.Children, AnalyticsChild{ChildID: c.ID, ChildName: c.Name, Avatar: c.AvatarIcon})
+	}
+
+	database.DB.Model(&models.Task{}).Where("family_id = ?", familyID).Count(&report.Summary.TotalTasks)
+	database.DB.Model(&models.Reward{}).Where("family_id = ?", familyID).Count(&report.Summary.TotalRewards)
+	report.Summary.TotalChildren = int64(len(children))
+
+	if len(childIDs) == 0 {
+		return report, nil
+	}
+
+	params := map[string]interface{}{
+		"family":      familyID,
+		"children":    childIDs,
+		"child_count": len(childIDs),
+		"from":        from.Format("2006-01-02"),
+		"to":          to.Format("2006-01-02"),
+		"today":       today.Format("2006-01-02"),
+		"tz":          utils.TimezoneName(family.Timezone),
+	}
+
+	steps := []func(*AnalyticsReport, map[string]interface{}) error{
+		s.completionSeries,
+		s.pointsTimeline,
+		s.taskInsights,
+		s.prayerHeatmap,
+		s.streaks,
+	}
+	for _, step := range steps {
+		if err := step(report, params); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range report.Points {
+		report.Summary.PointsEarned += p.Earned
+		report.Summary.PointsSpent += p.Spent
+	}
+	for _, c := range report.CompletionDaily {
+		report.Summary.Completions += c.Completions
+	}
+
+	return report, nil
+}
+
+func (s *AnalyticsService) completionSeries(r *AnalyticsReport, params map[string]interface{}) error {
+	err := database.DB.Raw(completionCTE+`
+		SELECT to_char(day, 'YYYY-MM-DD') AS date, child_id, completions,
+			COALESCE(ROUND(done * 100.0 / NULLIF(slots_per_day, 0), 1), 0) AS rate
+		FROM daily
+		ORDER BY day, child_id`, params).Scan(&r.CompletionDaily).Error
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Raw(completionCTE+`
+		SELECT to_char(date_trunc('week', day), 'YYYY-MM-DD') AS date, child_id,
+			SUM(completions) AS completions,
+			COALESCE(ROUND(SUM(done) * 100.0 / NULLIF(SUM(slots_per_day), 0), 1), 0) AS rate
+		FROM daily
+		GROUP BY date_trunc('week', day), child_id
+		ORDER BY date_trunc('week', day), child_id`, params).Scan(&r.CompletionWeekly).Error
+}
+
+// pointsTimeline compares points earned from verified logs with points spent on
+// approved redemptions and cash-outs, per day in the family timezone.
+func (s *AnalyticsService) pointsTimeline(r *AnalyticsReport, params map[string]interface{}) error {
+	return database.DB.Raw(`
+		WITH days AS (
+			SELECT d::date AS day FROM generate_series(@from::date, @to::date, interval '1 day') d
+		),
+		earned AS (
+			SELECT completed_date AS day, SUM(earned_points) AS points
+			FROM daily_logs
+			WHERE status = 'verified' AND deleted_at IS NULL
+				AND child_id IN @children AND completed_date BETWEEN @from AND @to
+			GROUP BY completed_date
+		),
+		spent AS (
+			SELECT day, SUM(points) AS points FROM (
+				SELECT (updated_at AT TIME ZONE @tz)::date AS day, points_spent AS points
+				FROM redemptions
+				WHERE status = 'approved' AND deleted_at IS NULL AND child_id IN @children
+				UNION ALL
+				SELECT (updated_at AT TIME ZONE @tz)::date AS day, points
+				FROM wallet_transactions
+				WHERE type = 'cashout' AND status = 'approved' AND deleted_at IS NULL AND child_id IN @children
+			) x
+			WHERE day BETWEEN @from AND @to
+			GROUP BY day
+		)
+		SELECT to_char(days.day, 'YYYY-MM-DD') AS date,
+			COALESCE(earned.points, 0) AS earned,
+			COALESCE(spent.points, 0) AS spent
+		FROM days
+		LEFT JOIN earned ON earned.day = days.day
+		LEFT JOIN spent ON spent.day = days.day
+		ORDER BY days.day`, params).Scan(&r.Points).Error
+}
+
+// taskInsights ranks active tasks by completions and by child-days skipped.
+func (s *AnalyticsService) taskInsights(r *AnalyticsReport, params map[string]interface{}) error {
+	var insights []TaskInsight
+	err := database.DB.Raw(`
+		SELECT t.id AS task_id, t.name, t.icon,
+			COUNT(dl.id) AS completions,
+			GREATEST((@to::date - @from::date + 1) * @child_count - COUNT(DISTINCT (dl.child_id, dl.completed_date)), 0) AS skipped
+		FROM tasks t
+		LEFT JOIN daily_logs dl ON dl.task_id = t.id
+			AND dl.status = 'verified' AND dl.deleted_at IS NULL
+			AND dl.child_id IN @children AND dl.completed_date BETWEEN @from AND @to
+		WHERE t.family_id = @family AND t.is_active = true AND t.deleted_at IS NULL
+		GROUP BY t.id, t.name, t.icon`, params).Scan(&insights).Error
+	if err != nil {
+		return err
+	}
+
+	r.MostCompleted = topTasks(insights, func(a, b TaskInsight) bool { return a.Completions > b.Completions })
+	r.MostSkipped = topTasks(insights, func(a, b TaskInsight) bool { return a.Skipped > b.Skipped })
+	return nil
+}
+
+func topTasks(insights []TaskInsight, less func(a, b TaskInsight) bool) []TaskInsight {
+	sorted := make([]TaskInsight, len(insights))
+	copy(sorted, insights)
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	if len(sorted) > 5 {
+		sorted = sorted[:5]
+	}
+	return sorted
+}
+
+// prayerHeatmap counts the prayers each child kept per day: completions of
+// prayer-category tasks, up to each task's MaxPerDay (one when unlimited), as
+// in the rapor.
+func (s *AnalyticsService) prayerHeatmap(r *AnalyticsReport, params map[string]interface{}) error {
+	return database.DB.Raw(`
+		WITH days AS (
+			SELECT d::date AS day FROM generate_series(@from::date, @to::date, interval '1 day') d
+		),
+		per_task AS (
+			SELECT dl.child_id, dl.completed_date, LEAST(COUNT(*), COALESCE(NULLIF(t.max_per_day, 0), 1)) AS kept
+			FROM daily_logs dl
+			JOIN tasks t ON t.id = dl.task_id
+			WHERE dl.status = 'verified' AND dl.deleted_at IS NULL
+				AND dl.child_id IN @children AND dl.completed_date BETWEEN @from AND @to
+				AND t.category = 'prayer'
+			GROUP BY dl.child_id, dl.completed_date, dl.task_id, t.max_per_day
+		),
+		prayers AS (
+			SELECT child_id, completed_date, SUM(kept) AS prayers
+			FROM per_task
+			GROUP BY child_id, completed_date
+		)
+		SELECT to_char(days.day, 'YYYY-MM-DD') AS date, k.id AS child_id, COALESCE(p.prayers, 0) AS prayers
+		FROM days
+		CROSS JOIN (SELECT id FROM users WHERE id IN @children) k
+		LEFT JOIN prayers p ON p.child_id = k.id AND p.completed_date = days.day
+		ORDER BY days.day, k.id`, params).Scan(&r.PrayerHeatmap).Error
+}
+
+// streakQuery finds runs of consecutive days with at least one verified completion
+// (gaps and islands). The current streak may end yesterday since today isn't over.
+const streakQuery = `
+	WITH active_days AS (
+		SELECT DISTINCT child_id, completed_date
+		FROM daily_logs
+		WHERE status = 'verified' AND deleted_at IS NULL
+			AND child_id IN @children AND completed_date <= @to
+	),
+	islands AS (
+		SELECT child_id, completed_date - (ROW_NUMBER() OVER (PARTITION BY child_id ORDER BY completed_date))::int AS grp,
+			completed_date
+		FROM active_days
+	),
+	runs AS (
+		SELECT child_id, MAX(completed_date) AS last_day, COUNT(*) AS length
+		FROM islands
+		GROUP BY child_id, grp
+	)
+	SELECT k.id AS child_id,
+		COALESCE(MAX(runs.length) FILTER (WHERE runs.last_day >= LEAST(@to::date, @today::date) - 1), 0) AS current,
+		COALESCE(MAX(runs.length), 0) AS longest
+	FROM (SELECT id FROM users WHERE id IN @children) k
+	LEFT JOIN runs ON runs.child_id = k.id
+	GROUP BY k.id
+	ORDER BY k.id`
+
+func (s *AnalyticsService) streaks(r *AnalyticsReport, params map[string]interface{}) error {
+	return database.DB.Raw(streakQuery, params).Scan(&r.Streaks).Error
+}
+
+// ParseChildIDs splits a comma separated child_id query parameter.
+func ParseChildIDs(raw string) []string {
+	ids := []string{}
+	for _, id := range strings.Split(raw, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
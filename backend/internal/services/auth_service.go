@@ -1,33 +1,33 @@
 package services
 
 import (
-	"errors"
-
 	"github.com/username/ramadhan-ceria-backend/internal/database"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
 	"github.com/username/ramadhan-ceria-backend/internal/utils"
 )
 
-type AuthService struct{}
+type AuthService struct {
+	tokens *utils.JWT
+}
 
-func NewAuthService() *AuthService {
-	return &AuthService{}
+func NewAuthService(tokens *utils.JWT) *AuthService {
+	return &AuthService{tokens: tokens}
 }
 
 func (s *AuthService) LoginChild(childID, pin string) (string, string, error) {
 	var child models.User
 	err := database.DB.Where("id = ? AND role = 'child'", childID).First(&child).Error
 	if err != nil {
-		return "", "", errors.New("Child not found")
+		return "", "", ErrChildNotFound
 	}
 
 	if child.PINHash == nil || !utils.CheckPasswordHash(pin, *child.PINHash) {
-		return "", "", errors.New("Invalid PIN")
+		return "", "", ErrInvalidPIN
 	}
 
-	token, err := utils.GenerateToken(child.ID, child.FamilyID, child.Role)
+	token, err := s.tokens.GenerateToken(child.ID, child.FamilyID, child.Role)
 	if err != nil {
-		return "", "", errors.New("Could not generate token")
+		return "", "", err
 	}
 
 	return token, child.Role, nil
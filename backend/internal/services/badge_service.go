@@ -0,0 +1,157 @@
+package services
+
+import (
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+)
+
+type BadgeService struct{}
+
+func NewBadgeService() *BadgeService {
+	return &BadgeService{}
+}
+
+type BadgeRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Icon        string  `json:"icon" validate:"emoji"`
+	Description string  `json:"description" validate:"max=500"`
+	Metric      string  `json:"metric" validate:"oneof=completions points"` // default completions
+	Threshold   int     `json:"threshold" validate:"min=1,max=100000"`
+	TaskID      *string `json:"taskId" validate:"uuid"` // nil = every task
+}
+
+type BadgeHolder struct {
+	ChildID   string `json:"childId"`
+	ChildName string `json:"childName"`
+	Avatar    string `json:"avatar"`
+	Value     int64  `json:"value"` // verified completions or points so far
+	Percent   int    `json:"percent"`
+	Earned    bool   `json:"earned"`
+}
+
+type BadgeProgress struct {
+	Badge    models.Badge  `json:"badge"`
+	Children []BadgeHolder `json:"children"`
+}
+
+// GetBadges returns every badge of the family with each child's progress
+// towards it, counted from verified logs.
+func (s *BadgeService) GetBadges(familyID string) ([]BadgeProgress, error) {
+	var badges []models.Badge
+	if err := database.DB.Where("family_id = ?", familyID).Order("created_at").Find(&badges).Error; err != nil {
+		return nil, err
+	}
+	var children []models.User
+	if err := database.DB.Where("family_id = ? AND role = 'child'", familyID).Order("created_at").Find(&children).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]BadgeProgress, 0, len(badges))
+	for _, b := range badges {
+		valueExpr := "COUNT(*)"
+		if b.Metric == "points" {
+			valueExpr = "COALESCE(SUM(earned_points), 0)"
+		}
+		q := database.DB.Model(&models.DailyLog{}).
+			Select("child_id, "+valueExpr+" AS value").
+			Joins("JOIN users ON users.id = daily_logs.child_id AND users.family_id = ? AND users.deleted_at IS NULL", familyID).
+			Where("daily_logs.status = 'verified'")
+		if b.TaskID != nil {
+			q = q.Where("daily_logs.task_id = ?", *b.TaskID)
+		}
+		var rows []struct {
+			ChildID string
+			Value   int64
+		}
+		if err := q.Group("child_id").Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		values := map[string]int64{}
+		for _, r := range rows {
+			values[r.ChildID] = r.Value
+		}
+
+		p := BadgeProgress{Badge: b, Children: make([]BadgeHolder, 0, len(children))}
+		for _, c := range children {
+			value := values[c.ID]
+			percent := 100
+			if value < int64(b.Threshold) {
+				percent = int(value * 100 / int64(b.Threshold))
+			}
+			p.Children = append(p.Children, BadgeHolder{
+				ChildID:   c.ID,
+				ChildName: c.Name,
+				Avatar:    c.AvatarIcon,
+				Value:     value,
+				Percent:   percent,
+				Earned:    value >= int64(b.Threshold),
+			})
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (s *BadgeService) CreateBadge(familyID string, req BadgeRequest) (*models.Badge, error) {
+	badge := models.Badge{FamilyID: familyID}
+	if err := s.fill(familyID, &badge, req); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Create(&badge).Error; err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (s *BadgeService) UpdateBadge(familyID, badgeID string, req BadgeRequest) (*models.Badge, error) {
+	var badge models.Badge
+	if err := database.DB.Where("id = ? AND family_id = ?", badgeID, familyID).First(&badge).Error; err != nil {
+		return nil, ErrBadgeNotFound
+	}
+	if err := s.fill(familyID, &badge, req); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Save(&badge).Error; err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (s *BadgeService) DeleteBadge(familyID, badgeID string) error {
+	result := database.DB.Where("id = ? AND family_id = ?", badgeID, familyID).Delete(&models.Badge{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBadgeNotFound
+	}
+	return nil
+}
+
+func (s *BadgeService) fill(familyID string, badge *models.Badge, req BadgeRequest) error {
+	if err := Validate(req); err != nil {
+		return err
+	}
+
+	badge.TaskID = nil
+	if req.TaskID != nil && *req.TaskID != "" {
+		var count int64
+		database.DB.Model(&models.Task{}).Where("id = ? AND family_id = ?", *req.TaskID, familyID).Count(&count)
+		if count == 0 {
+			return ErrTaskNotFound
+		}
+		badge.TaskID = req.TaskID
+	}
+
+	badge.Name = req.Name
+	if req.Icon != "" {
+		badge.Icon = req.Icon
+	}
+	badge.Description = req.Description
+	badge.Metric = req.Metric
+	if badge.Metric == "" {
+		badge.Metric = "completions"
+	}
+	badge.Threshold = req.Threshold
+	return nil
+}
@@ -0,0 +1,486 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/i18n"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+)
+
+const (
+	// PlanFree is the plan every family falls back to when a paid plan runs out.
+	PlanFree = "FREE"
+	// PlanPremium is the plan sold through payments.
+	PlanPremium = "PREMIUM"
+
+	// PlanGracePeriod keeps a plan's entitlements after PlanExpiresAt so a late renewal loses nothing.
+	PlanGracePeriod = 3 * 24 * time.Hour
+)
+
+// Limits, counted per family.
+const (
+	LimitChildren = "children"
+	LimitTasks    = "tasks"
+	LimitRewards  = "rewards"
+)
+
+// Feature flags.
+const (
+	FeatureAnalytics    = "analytics"
+	FeatureLeaderboard  = "leaderboard"
+	FeatureReports      = "reports"
+	FeatureExports      = "exports"
+	FeatureCustomBadges = "custom_badges"
+)
+
+var (
+	knownLimits   = []string{LimitChildren, LimitTasks, LimitRewards}
+	knownFeatures = []string{FeatureAnalytics, FeatureLeaderboard, FeatureReports, FeatureExports, FeatureCustomBadges}
+)
+
+// limitQueries select the rows a limit counts, oldest first decides which
+// ones stay writable after a downgrade.
+var limitQueries = map[string]string{
+	LimitChildren: `SELECT id, created_at FROM users WHERE family_id = ? AND role = 'child' AND deleted_at IS NULL`,
+	LimitTasks:    `SELECT id, created_at FROM tasks WHERE family_id = ? AND deleted_at IS NULL`,
+	LimitRewards:  `SELECT id, created_at FROM rewards WHERE family_id = ? AND deleted_at IS NULL`,
+}
+
+// planCacheTTL bounds how long another replica may serve a plan edited elsewhere.
+const planCacheTTL = time.Minute
+
+var planCache struct {
+	sync.Mutex
+	plans    map[string]models.Plan
+	loadedAt time.Time
+}
+
+func loadPlans() (map[string]models.Plan, error) {
+	planCache.Lock()
+	defer planCache.Unlock()
+	if planCache.plans != nil && time.Since(planCache.loadedAt) < planCacheTTL {
+		return planCache.plans, nil
+	}
+	var list []models.Plan
+	if err := database.DB.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	plans := make(map[string]models.Plan, len(list))
+	for _, p := range list {
+		plans[p.Code] = p
+	}
+	planCache.plans = plans
+	planCache.loadedAt = time.Now()
+	return plans, nil
+}
+
+func invalidatePlans() {
+	planCache.Lock()
+	planCache.plans = nil
+	planCache.Unlock()
+}
+
+// FindPlan returns the plan with the given code.
+func FindPlan(code string) (*models.Plan, error) {
+	plans, err := loadPlans()
+	if err != nil {
+		return nil, err
+	}
+	plan, ok := plans[code]
+	if !ok {
+		return nil, ErrPlanNotFound
+	}
+	return &plan, nil
+}
+
+// PlanActive reports whether the family's own plan is in effect right now: FREE
+// always is, other plans until PlanExpiresAt plus the grace period. A plan
+// without PlanExpiresAt never expires.
+func PlanActive(family models.Family) bool {
+	if family.Plan == PlanFree || family.PlanExpiresAt == nil {
+		return true
+	}
+	return time.Now().Before(family.PlanExpiresAt.Add(PlanGracePeriod))
+}
+
+// IsPremium reports whether the family is on an unexpired PREMIUM plan.
+func IsPremium(family models.Family) bool {
+	return family.Plan == PlanPremium && PlanActive(family)
+}
+
+// EffectivePlan is the plan whose entitlements apply: the family's own plan
+// while active, FREE after it expired. An unknown plan code also falls back to FREE.
+func EffectivePlan(family models.Family) (*models.Plan, error) {
+	if PlanActive(family) {
+		if plan, err := FindPlan(family.Plan); err == nil {
+			return plan, nil
+		}
+	}
+	plan, err := FindPlan(PlanFree)
+	if err != nil {
+		return nil, fmt.Errorf("default plan %s is missing: %w", PlanFree, err)
+	}
+	return plan, nil
+}
+
+func hasFeature(plan *models.Plan, feature string) bool {
+	for _, f := range plan.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+func countLimit(familyID, limit string) (int64, error) {
+	var count int64
+	err := database.DB.Raw(`SELECT COUNT(*) FROM (`+limitQueries[limit]+`) items`, familyID).Scan(&count).Error
+	return count, err
+}
+
+// ReadOnlySet lists what a family keeps but can no longer change: everything
+// beyond the limits of the plan in effect, newest first to go. Nothing is
+// deleted on downgrade.
+type ReadOnlySet struct {
+	Children []string `json:"children"`
+	Tasks    []string `json:"tasks"`
+	Rewards  []string `json:"rewards"`
+}
+
+// excessIDs returns the IDs past the first limit rows, oldest first.
+func excessIDs(familyID, limit string, keep int) ([]string, error) {
+	ids := []string{}
+	err := database.DB.Raw(`SELECT id FROM (`+limitQueries[limit]+`) items ORDER BY created_at, id OFFSET ?`, familyID, keep).Scan(&ids).Error
+	return ids, err
+}
+
+func readOnlyItems(familyID string, plan *models.Plan) (*ReadOnlySet, error) {
+	set := &ReadOnlySet{Children: []string{}, Tasks: []string{}, Rewards: []string{}}
+	targets := map[string]*[]string{
+		LimitChildren: &set.Children,
+		LimitTasks:    &set.Tasks,
+		LimitRewards:  &set.Rewards,
+	}
+	for limit, ids := range targets {
+		keep, ok := plan.Limits[limit]
+		if !ok {
+			continue
+		}
+		found, err := excessIDs(familyID, limit, keep)
+		if err != nil {
+			return nil, err
+		}
+		*ids = found
+	}
+	return set, nil
+}
+
+// ReadOnlyItems returns the read-only children, tasks and rewards of a family.
+func ReadOnlyItems(familyID string) (*ReadOnlySet, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	plan, err := EffectivePlan(family)
+	if err != nil {
+		return nil, err
+	}
+	return readOnlyItems(familyID, plan)
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func IsChildReadOnly(familyID, childID string) (bool, error) {
+	set, err := ReadOnlyItems(familyID)
+	if err != nil {
+		return false, err
+	}
+	return contains(set.Children, childID), nil
+}
+
+func IsTaskReadOnly(familyID, taskID string) (bool, error) {
+	set, err := ReadOnlyItems(familyID)
+	if err != nil {
+		return false, err
+	}
+	return contains(set.Tasks, taskID), nil
+}
+
+func IsRewardReadOnly(familyID, rewardID string) (bool, error) {
+	set, err := ReadOnlyItems(familyID)
+	if err != nil {
+		return false, err
+	}
+	return contains(set.Rewards, rewardID), nil
+}
+
+type EntitlementService struct{}
+
+func NewEntitlementService() *EntitlementService {
+	return &EntitlementService{}
+}
+
+// Entitlements is what the frontend needs to decide what to show.
+type Entitlements struct {
+	Plan      string           `json:"plan"` // plan in effect, FREE once a paid plan has expired
+	PlanName  string           `json:"planName"`
+	Family    string           `json:"familyPlan"` // plan the family is subscribed to
+	ExpiresAt *time.Time       `json:"expiresAt"`
+	Limits    map[string]*int  `json:"limits"` // null = unlimited
+	Usage     map[string]int64 `json:"usage"`
+	Features  map[string]bool  `json:"features"`
+	ReadOnly  *ReadOnlySet     `json:"readOnly"`
+}
+
+func (s *EntitlementService) GetEntitlements(familyID string) (*Entitlements, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	plan, err := EffectivePlan(family)
+	if err != nil {
+		return nil, err
+	}
+
+	ent := &Entitlements{
+		Plan:      plan.Code,
+		PlanName:  plan.Name,
+		Family:    family.Plan,
+		ExpiresAt: family.PlanExpiresAt,
+		Limits:    map[string]*int{},
+		Usage:     map[string]int64{},
+		Features:  map[string]bool{},
+	}
+	for _, limit := range knownLimits {
+		if n, ok := plan.Limits[limit]; ok {
+			ent.Limits[limit] = &n
+		} else {
+			ent.Limits[limit] = nil
+		}
+		count, err := countLimit(familyID, limit)
+		if err != nil {
+			return nil, err
+		}
+		ent.Usage[limit] = count
+	}
+	for _, feature := range knownFeatures {
+		ent.Features[feature] = hasFeature(plan, feature)
+	}
+	if ent.ReadOnly, err = readOnlyItems(familyID, plan); err != nil {
+		return nil, err
+	}
+	return ent, nil
+}
+
+// Check answers "can this family do name", where name is a feature flag or a
+// limit (allowed while usage is below it). When not allowed, it returns
+// ErrPlanLimitReached or ErrPlanFeatureMissing.
+func (s *EntitlementService) Check(familyID, name string) error {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return ErrFamilyNotFound
+	}
+	plan, err := EffectivePlan(family)
+	if err != nil {
+		return err
+	}
+
+	if _, isLimit := limitQueries[name]; isLimit {
+		n, limited := plan.Limits[name]
+		if !limited {
+			return nil
+		}
+		count, err := countLimit(familyID, name)
+		if err != nil {
+			return err
+		}
+		if count >= int64(n) {
+			return ErrPlanLimitReached.With(map[string]interface{}{"item": i18n.Key("limit." + name), "plan": plan.Code})
+		}
+		return nil
+	}
+
+	if !hasFeature(plan, name) {
+		return ErrPlanFeatureMissing.With(map[string]interface{}{"feature": i18n.Key("feature." + name), "plan": plan.Code})
+	}
+	return nil
+}
+
+// Remaining is how many more items limit lets the family create, -1 when
+// its plan has no such limit. full is the error Check returns once none are left.
+func (s *EntitlementService) Remaining(familyID, limit string) (remaining int, full *Error, err error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return 0, nil, ErrFamilyNotFound
+	}
+	plan, err := EffectivePlan(family)
+	if err != nil {
+		return 0, nil, err
+	}
+	full = ErrPlanLimitReached.With(map[string]interface{}{"item": i18n.Key("limit." + limit), "plan": plan.Code})
+	n, limited := plan.Limits[limit]
+	if !limited {
+		return -1, full, nil
+	}
+	count, err := countLimit(familyID, limit)
+	if err != nil {
+		return 0, nil, err
+	}
+	if remaining = n - int(count); remaining < 0 {
+		remaining = 0
+	}
+	return remaining, full, nil
+}
+
+// PlanSummary is a plan with the number of families currently on it.
+type PlanSummary struct {
+	models.Plan
+	Families int64
+}
+
+func (s *EntitlementService) GetPlans() ([]PlanSummary, error) {
+	list := []PlanSummary{}
+	var plans []models.Plan
+	if err := database.DB.Order("created_at, code").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range plans {
+		var count int64
+		database.DB.Model(&models.Family{}).Where("plan = ?", p.Code).Count(&count)
+		list = append(list, PlanSummary{Plan: p, Families: count})
+	}
+	return list, nil
+}
+
+type PlanInput struct {
+	Code        string         `json:"code"` // create only
+	Name        string         `json:"name" validate:"required,max=100"`
+	Description string         `json:"description" validate:"max=500"`
+	Limits      map[string]int `json:"limits"`
+	Features    []string       `json:"features"`
+}
+
+func (in PlanInput) Check(v *Violations) {
+	names := make([]string, 0, len(in.Limits))
+	for name := range in.Limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := limitQueries[name]; !ok {
+			v.Add("limits", "unknown_value", map[string]interface{}{"value": name})
+		} else if in.Limits[name] < 0 {
+			v.Add("limits."+name, "not_negative", nil)
+		}
+	}
+	for _, f := range in.Features {
+		if !contains(knownFeatures, f) {
+			v.Add("features", "unknown_value", map[string]interface{}{"value": f})
+		}
+	}
+}
+
+var planCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,19}$`)
+
+// validatePlanInput checks in (and its code when creating) and normalizes
+// its limits and features.
+func validatePlanInput(in *PlanInput, create bool) error {
+	in.Name = strings.TrimSpace(in.Name)
+	var v Violations
+	if create && !planCodePattern.MatchString(in.Code) {
+		v.Add("code", "plan_code", nil)
+	}
+	v.Validate(in)
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	if in.Limits == nil {
+		in.Limits = map[string]int{}
+	}
+	features := []string{}
+	for _, f := range in.Features {
+		if !contains(features, f) {
+			features = append(features, f)
+		}
+	}
+	in.Features = features
+	return nil
+}
+
+func (s *EntitlementService) CreatePlan(in PlanInput) (*models.Plan, error) {
+	in.Code = strings.ToUpper(strings.TrimSpace(in.Code))
+	if err := validatePlanInput(&in, true); err != nil {
+		return nil, err
+	}
+	if _, err := FindPlan(in.Code); err == nil {
+		return nil, ErrPlanExists
+	}
+
+	plan := models.Plan{
+		Code:        in.Code,
+		Name:        in.Name,
+		Description: in.Description,
+		Limits:      in.Limits,
+		Features:    in.Features,
+	}
+	if err := database.DB.Create(&plan).Error; err != nil {
+		return nil, err
+	}
+	invalidatePlans()
+	return &plan, nil
+}
+
+func (s *EntitlementService) UpdatePlan(code string, in PlanInput) (*models.Plan, error) {
+	var plan models.Plan
+	if err := database.DB.First(&plan, "code = ?", code).Error; err != nil {
+		return nil, ErrPlanNotFound
+	}
+	if err := validatePlanInput(&in, false); err != nil {
+		return nil, err
+	}
+
+	plan.Name = in.Name
+	plan.Description = in.Description
+	plan.Limits = in.Limits
+	plan.Features = in.Features
+	if err := database.DB.Save(&plan).Error; err != nil {
+		return nil, err
+	}
+	invalidatePlans()
+	return &plan, nil
+}
+
+// DeletePlan removes a plan nobody is on. FREE and PREMIUM are built in.
+func (s *EntitlementService) DeletePlan(code string) error {
+	if code == PlanFree || code == PlanPremium {
+		return ErrPlanBuiltIn
+	}
+	var count int64
+	database.DB.Model(&models.Family{}).Where("plan = ?", code).Count(&count)
+	if count > 0 {
+		return ErrPlanInUse
+	}
+	result := database.DB.Delete(&models.Plan{}, "code = ?", code)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPlanNotFound
+	}
+	invalidatePlans()
+	return nil
+}
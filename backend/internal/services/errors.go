@@ -0,0 +1,226 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/username/ramadhan-ceria-backend/internal/i18n"
+)
+
+// ErrorKind says what kind of failure an Error is; httperr maps it to a status.
+type ErrorKind int
+
+const (
+	KindInternal      ErrorKind = iota
+	KindInvalid                 // 400
+	KindUnauthorized            // 401
+	KindForbidden               // 403
+	KindNotFound                // 404
+	KindConflict                // 409
+	KindTooLarge                // 413
+	KindUnprocessable           // 422
+	KindUnavailable             // 503
+)
+
+// Error is a domain error with a machine-readable code. Its text lives in
+// the i18n catalog under Code; Error() is the English text for logs.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Params  map[string]interface{} // fills placeholders in the message
+	Details []FieldError           // per-field problems for validation_failed
+}
+
+// FieldError is one invalid field. Rule is an i18n "field.<rule>" key.
+type FieldError struct {
+	Field  string
+	Rule   string
+	Params map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message("en")
+}
+
+// Message is the error text in lang. A validation error with one detail
+// reads as that detail.
+func (e *Error) Message(lang string) string {
+	if e.Code == CodeValidationFailed && len(e.Details) > 0 {
+		parts := make([]string, len(e.Details))
+		for i, d := range e.Details {
+			parts[i] = d.Message(lang)
+		}
+		if len(parts) == 1 {
+			return parts[0]
+		}
+		return i18n.T(lang, e.Code, e.Params) + ": " + strings.Join(parts, "; ")
+	}
+	return i18n.T(lang, e.Code, e.Params)
+}
+
+// Is matches errors with the same code, so errors.Is(err, ErrTaskNotFound)
+// holds for copies made with With.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// With returns a copy of e with params for its message.
+func (e *Error) With(params map[string]interface{}) *Error {
+	with := *e
+	with.Params = params
+	return &with
+}
+
+// Message is the field problem in lang, e.g. "points must be greater than 0".
+func (f FieldError) Message(lang string) string {
+	params := map[string]interface{}{"field": f.Field}
+	for k, v := range f.Params {
+		params[k] = v
+	}
+	return i18n.T(lang, "field."+f.Rule, params)
+}
+
+func newError(kind ErrorKind, code string) *Error {
+	return &Error{Kind: kind, Code: code}
+}
+
+const CodeValidationFailed = "validation_failed"
+
+// ValidationError reports every invalid field at once.
+func ValidationError(details ...FieldError) *Error {
+	return &Error{Kind: KindInvalid, Code: CodeValidationFailed, Details: details}
+}
+
+// Required is a validation error naming every empty field, or nil when all
+// are set. Arguments alternate field name and value.
+func Required(namesAndValues ...string) error {
+	var details []FieldError
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		if strings.TrimSpace(namesAndValues[i+1]) == "" {
+			details = append(details, FieldError{Field: namesAndValues[i], Rule: "required"})
+		}
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return ValidationError(details...)
+}
+
+// InvalidField is a validation error for a single field.
+func InvalidField(field, rule string, params map[string]interface{}) *Error {
+	return ValidationError(FieldError{Field: field, Rule: rule, Params: params})
+}
+
+var (
+	// Requests and access
+	ErrInvalidRequest      = newError(KindInvalid, "invalid_request")
+	ErrInternal            = newError(KindInternal, "internal_error")
+	ErrRouteNotFound       = newError(KindNotFound, "route_not_found")
+	ErrPayloadTooLarge     = newError(KindTooLarge, "payload_too_large")
+	ErrMissingToken        = newError(KindUnauthorized, "missing_token")
+	ErrInvalidToken        = newError(KindUnauthorized, "invalid_token")
+	ErrForbidden           = newError(KindForbidden, "forbidden")
+	ErrParentRequired      = newError(KindForbidden, "parent_required")
+	ErrChildRequired       = newError(KindForbidden, "child_required")
+	ErrAdminRequired       = newError(KindForbidden, "admin_required")
+	ErrSuperAdminRequired  = newError(KindForbidden, "super_admin_required")
+	ErrIdempotencyKey      = newError(KindInvalid, "idempotency_key_invalid")
+	ErrIdempotencyReused   = newError(KindUnprocessable, "idempotency_key_reused")
+	ErrIdempotencyInFlight = newError(KindConflict, "idempotency_in_progress")
+
+	// Accounts
+	ErrInvalidCredentials = newError(KindUnauthorized, "invalid_credentials")
+	ErrInvalidPIN         = newError(KindUnauthorized, "invalid_pin")
+	ErrEmailTaken         = newError(KindConflict, "email_taken")
+	ErrFamilyNameTaken    = newError(KindConflict, "family_name_taken")
+	ErrGoogleLoginFailed  = newError(KindUnauthorized, "google_login_failed")
+	ErrGoogleLoginOff     = newError(KindUnavailable, "google_login_off")
+	ErrFamilyNotFound     = newError(KindNotFound, "family_not_found")
+	ErrUserNotFound       = newError(KindNotFound, "user_not_found")
+	ErrChildNotFound      = newError(KindNotFound, "child_not_found")
+	ErrChildNotInFamily   = newError(KindForbidden, "child_not_in_family")
+
+	// Tasks, logs and rewards
+	ErrTaskNotFound         = newError(KindNotFound, "task_not_found")
+	ErrTaskCompleted        = newError(KindConflict, "task_already_completed")
+	ErrLogNotFound          = newError(KindNotFound, "log_not_found")
+	ErrLogNotVerified       = newError(KindConflict, "log_not_verified")
+	ErrLogUndone            = newError(KindConflict, "log_already_undone")
+	ErrLogVerified          = newError(KindConflict, "log_already_verified")
+	ErrNoTasksToUpdate      = newError(KindInvalid, "no_tasks_to_update")
+	ErrDuplicateTask        = newError(KindInvalid, "duplicate_task")
+	ErrRewardNotFound       = newError(KindNotFound, "reward_not_found")
+	ErrRedemptionNotFound   = newError(KindNotFound, "redemption_not_found")
+	ErrInsufficientPoints   = newError(KindInvalid, "insufficient_points")
+	ErrAnnouncementNotFound = newError(KindNotFound, "announcement_not_found")
+
+	// Templates and badges
+	ErrTemplateNotFound    = newError(KindNotFound, "template_not_found")
+	ErrTemplateBuiltIn     = newError(KindForbidden, "template_built_in")
+	ErrTemplateTaskMissing = newError(KindInvalid, "template_task_missing")
+	ErrBadgeNotFound       = newError(KindNotFound, "badge_not_found")
+
+	// Plans and entitlements
+	ErrChildReadOnly      = newError(KindForbidden, "child_read_only")
+	ErrTaskReadOnly       = newError(KindForbidden, "task_read_only")
+	ErrRewardReadOnly     = newError(KindForbidden, "reward_read_only")
+	ErrPlanLimitReached   = newError(KindForbidden, "plan_limit_reached")
+	ErrPlanFeatureMissing = newError(KindForbidden, "plan_feature_missing")
+	ErrPlanNotFound       = newError(KindNotFound, "plan_not_found")
+	ErrPlanExists         = newError(KindConflict, "plan_exists")
+	ErrPlanInUse          = newError(KindConflict, "plan_in_use")
+	ErrPlanBuiltIn        = newError(KindConflict, "plan_built_in")
+
+	// Family settings, insights and sync
+	ErrLeaderboardDisabled = newError(KindForbidden, "leaderboard_disabled")
+	ErrSeasonNotConfigured = newError(KindInvalid, "season_not_configured")
+	ErrNoRecipient         = newError(KindInvalid, "no_recipient")
+	ErrEventApplied        = newError(KindConflict, "event_already_applied")
+	ErrUnknownEventType    = newError(KindInvalid, "unknown_event_type")
+
+	// Point rules, goals, wallets and jobs
+	ErrRuleNotFound          = newError(KindNotFound, "rule_not_found")
+	ErrGoalNotFound          = newError(KindNotFound, "goal_not_found")
+	ErrWalletNotFound        = newError(KindNotFound, "wallet_not_found")
+	ErrWalletInactive        = newError(KindForbidden, "wallet_inactive")
+	ErrInsufficientWallet    = newError(KindInvalid, "insufficient_wallet_balance")
+	ErrCashoutNotFound       = newError(KindNotFound, "cashout_not_found")
+	ErrCashoutProcessed      = newError(KindConflict, "cashout_processed")
+	ErrTHRDisabled           = newError(KindForbidden, "thr_disabled")
+	ErrTHRGranted            = newError(KindConflict, "thr_already_granted")
+	ErrJobNotFound           = newError(KindNotFound, "job_not_found")
+	ErrJobPaused             = newError(KindConflict, "job_paused")
+	ErrDeletionNotFound      = newError(KindNotFound, "deletion_not_found")
+	ErrDeletionScheduled     = newError(KindConflict, "deletion_scheduled")
+	ErrDeletionParentOnly    = newError(KindForbidden, "deletion_parent_only")
+	ErrConfirmationExpired   = newError(KindInvalid, "confirmation_expired")
+	ErrConfirmationInvalid   = newError(KindInvalid, "confirmation_invalid")
+	ErrArchiveVersion        = newError(KindInvalid, "archive_unsupported_version")
+	ErrArchiveNoParent       = newError(KindInvalid, "archive_no_parent")
+	ErrArchiveInvalidDate    = newError(KindInvalid, "archive_invalid_date")
+	ErrPushNotConfigured     = newError(KindUnavailable, "push_not_configured")
+	ErrSubscriptionNotFound  = newError(KindNotFound, "subscription_not_found")
+	ErrNoPushSubscriptions   = newError(KindNotFound, "no_push_subscriptions")
+	ErrWhatsappNumberMissing = newError(KindInvalid, "whatsapp_number_missing")
+
+	// Payments, coupons and referrals
+	ErrPaymentsNotConfigured = newError(KindUnavailable, "payments_not_configured")
+	ErrPaymentGatewayDown    = newError(KindUnavailable, "payment_gateway_unavailable")
+	ErrPaymentNotFound       = newError(KindNotFound, "payment_not_found")
+	ErrPackageNotFound       = newError(KindNotFound, "package_not_found")
+	ErrParentPaymentOnly     = newError(KindForbidden, "payment_parent_only")
+	ErrInvalidSignature      = newError(KindUnauthorized, "invalid_signature")
+	ErrAmountMismatch        = newError(KindInvalid, "amount_mismatch")
+	ErrCouponNotFound        = newError(KindNotFound, "coupon_not_found")
+	ErrCouponNotValidNow     = newError(KindInvalid, "coupon_not_valid_now")
+	ErrCouponUsed            = newError(KindInvalid, "coupon_already_used")
+	ErrCouponLimitReached    = newError(KindInvalid, "coupon_limit_reached")
+	ErrCouponNotAtCheckout   = newError(KindInvalid, "coupon_not_at_checkout")
+	ErrCouponCheckoutOnly    = newError(KindInvalid, "coupon_checkout_only")
+	ErrCouponCodeTaken       = newError(KindConflict, "coupon_code_taken")
+	ErrCouponInUse           = newError(KindConflict, "coupon_in_use")
+	ErrReferralInvalid       = newError(KindInvalid, "referral_invalid")
+	ErrReferralOwn           = newError(KindInvalid, "referral_own")
+	ErrReferralClaimed       = newError(KindConflict, "referral_claimed")
+	ErrReferralAfterPayment  = newError(KindInvalid, "referral_after_payment")
+)
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/exports"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type ExportService struct{}
+
+func NewExportService() *ExportService {
+	return &ExportService{}
+}
+
+// ExportQuery is a validated export filter. Dates are inclusive calendar days
+// in the family's timezone.
+type ExportQuery struct {
+	FamilyID string
+	From     string
+	To       string
+	ChildIDs []string
+	Timezone string
+}
+
+// NewExportQuery validates the raw filters. from defaults to the family's
+// creation day and to defaults to today.
+func (s *ExportService) NewExportQuery(familyID, from, to string, childIDs []string) (*ExportQuery, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	loc := utils.LoadLocation(family.Timezone)
+
+	q := &ExportQuery{
+		FamilyID: familyID,
+		From:     family.CreatedAt.In(loc).Format("2006-01-02"),
+		To:       utils.Today(loc).Format("2006-01-02"),
+		ChildIDs: childIDs,
+		Timezone: utils.TimezoneName(family.Timezone),
+	}
+	if from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			return nil, InvalidField("from", "date", nil)
+		}
+		q.From = from
+	}
+	if to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			return nil, InvalidField("to", "date", nil)
+		}
+		q.To = to
+	}
+	if q.To < q.From {
+		return nil, InvalidField("to", "not_before", map[string]interface{}{"other": "from"})
+	}
+	return q, nil
+}
+
+// ExportFormat returns the MIME type and file extension of an export format,
+// rejecting anything but csv and xlsx as an invalid field.
+func ExportFormat(format string) (contentType, ext string, err error) {
+	contentType, ext, err = exports.ContentType(format)
+	if errors.Is(err, exports.ErrUnknownFormat) {
+		return "", "", InvalidField("format", "one_of", map[string]interface{}{"values": exports.Formats})
+	}
+	return contentType, ext, err
+}
+
+func (q *ExportQuery) params() map[string]interface{} {
+	children := q.ChildIDs
+	if len(children) == 0 {
+		children = []string{""}
+	}
+	return map[string]interface{}{
+		"family":       q.FamilyID,
+		"from":         q.From,
+		"to":           q.To,
+		"tz":           q.Timezone,
+		"children":     children,
+		"all_children": len(q.ChildIDs) == 0,
+	}
+}
+
+// childFilter limits rows to the requested children; @all_children short-circuits
+// it because IN () is not valid SQL.
+const childFilter = `(@all_children OR u.id IN @children)`
+
+// WriteLogs exports daily_logs joined with child and task names.
+func (s *ExportService) WriteLogs(w exports.RowWriter, q *ExportQuery) error {
+	params := q.params()
+
+	rows, err := database.DB.Raw(`
+		SELECT to_char(dl.completed_date, 'YYYY-MM-DD'), u.name, t.name, t.icon, dl.earned_points, dl.status,
+			to_char(dl.created_at AT TIME ZONE @tz, 'YYYY-MM-DD HH24:MI')
+		FROM daily_logs dl
+		JOIN users u ON u.id = dl.child_id
+		JOIN tasks t ON t.id = dl.task_id
+		WHERE u.family_id = @family AND dl.deleted_at IS NULL
+			AND dl.completed_date BETWEEN @from AND @to
+			AND `+childFilter+`
+		ORDER BY dl.completed_date, u.name, dl.created_at`, params).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := w.Header([]string{"Tanggal", "Anak", "Misi", "Ikon", "Poin", "Status", "Dicatat Pada"}); err != nil {
+		return err
+	}
+	for rows.Next() {
+		var date, child, task, icon, status, recorded string
+		var points int
+		if err := rows.Scan(&date, &child, &task, &icon, &points, &status, &recorded); err != nil {
+			return err
+		}
+		if err := w.Row([]interface{}{date, child, task, icon, points, status, recorded}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// WriteRedemptions exports redemptions with reward names and their status history.
+func (s *ExportService) WriteRedemptions(w exports.RowWriter, q *ExportQuery) error {
+	params := q.params()
+
+	rows, err := database.DB.Raw(`
+		SELECT to_char(r.created_at AT TIME ZONE @tz, 'YYYY-MM-DD HH24:MI'), u.name, rw.name, r.points_spent, r.status,
+			COALESCE((
+				SELECT string_agg(h.status || ' ' || to_char(h.created_at AT TIME ZONE @tz, 'YYYY-MM-DD HH24:MI'), '; ' ORDER BY h.created_at)
+				FROM redemption_status_changes h
+				WHERE h.redemption_id = r.id
+			), '')
+		FROM redemptions r
+		JOIN users u ON u.id = r.child_id
+		JOIN rewards rw ON rw.id = r.reward_id
+		WHERE u.family_id = @family AND r.deleted_at IS NULL
+			AND (r.created_at AT TIME ZONE @tz)::date BETWEEN @from AND @to
+			AND `+childFilter+`
+		ORDER BY r.created_at`, params).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := w.Header([]string{"Dibuat", "Anak", "Hadiah", "Poin", "Status", "Riwayat Status"}); err != nil {
+		return err
+	}
+	for rows.Next() {
+		var created, child, reward, status, history string
+		var points int
+		if err := rows.Scan(&created, &child, &reward, &points, &status, &history); err != nil {
+			return err
+		}
+		if err := w.Row([]interface{}{created, child, reward, points, status, history}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// WritePoints exports one row per child: points earned and spent in the range and the current balance.
+func (s *ExportService) WritePoints(w exports.RowWriter, q *ExportQuery) error {
+	params := q.params()
+
+	var children []models.User
+	err := database.DB.Raw(`
+		SELECT u.* FROM users u
+		WHERE u.family_id = @family AND u.role = 'child' AND u.deleted_at IS NULL AND `+childFilter+`
+		ORDER BY u.name`, params).Scan(&children).Error
+	if err != nil {
+		return err
+	}
+
+	if err := w.Header([]string{"Anak", "Periode", "Poin Masuk", "Poin Ditukar", "Saldo Saat Ini", "Menunggu Persetujuan"}); err != nil {
+		return err
+	}
+	for _, child := range children {
+		var earned, spent sql.NullInt64
+		err := database.DB.Raw(`
+			SELECT COALESCE(SUM(earned_points), 0) FROM daily_logs
+			WHERE child_id = ? AND status = 'verified' AND deleted_at IS NULL AND completed_date BETWEEN ? AND ?`,
+			child.ID, q.From, q.To).Scan(&earned).Error
+		if err != nil {
+			return err
+		}
+		// Spent counts approved redemptions and cash-outs, as the analytics points timeline does.
+		err = database.DB.Raw(`
+			SELECT COALESCE(SUM(points), 0) FROM (
+				SELECT points_spent AS points, updated_at FROM redemptions
+				WHERE child_id = @child AND status = 'approved' AND deleted_at IS NULL
+				UNION ALL
+				SELECT points, updated_at FROM wallet_transactions
+				WHERE child_id = @child AND type = 'cashout' AND status = 'approved' AND deleted_at IS NULL
+			) x
+			WHERE (updated_at AT TIME ZONE @tz)::date BETWEEN @from AND @to`,
+			map[string]interface{}{"child": child.ID, "tz": q.Timezone, "from": q.From, "to": q.To}).Scan(&spent).Error
+		if err != nil {
+			return err
+		}
+		var pendingCashout sql.NullInt64
+		err = database.DB.Raw(`
+			SELECT COALESCE(SUM(points), 0) FROM wallet_transactions
+			WHERE child_id = ? AND type = 'cashout' AND status = 'pending' AND deleted_at IS NULL`,
+			child.ID).Scan(&pendingCashout).Error
+		if err != nil {
+			return err
+		}
+
+		summary, err := ChildPointSummary(database.DB, child.ID)
+		if err != nil {
+			return err
+		}
+
+		err = w.Row([]interface{}{
+			child.Name,
+			q.From + " s/d " + q.To,
+			earned.Int64,
+			spent.Int64,
+			summary.Balance,
+			summary.PendingPoints + pendingCashout.Int64,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
@@ -0,0 +1,711 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ArchiveVersion is bumped whenever the archive layout changes incompatibly.
+const ArchiveVersion = 1
+
+type FamilyDataService struct{}
+
+func NewFamilyDataService() *FamilyDataService {
+	return &FamilyDataService{}
+}
+
+// FamilyArchive is the portable JSON export of a family. IDs are the original
+// ones and only serve to link records together; import assigns new IDs.
+// Password and PIN hashes are never included.
+type FamilyArchive struct {
+	Version     int                  `json:"version"`
+	ExportedAt  time.Time            `json:"exported_at"`
+	Family      ArchivedFamily       `json:"family"`
+	Users       []ArchivedUser       `json:"users"`
+	Tasks       []ArchivedTask       `json:"tasks"`
+	Rewards     []ArchivedReward     `json:"rewards"`
+	DailyLogs   []ArchivedLog        `json:"daily_logs"`
+	Redemptions []ArchivedRedemption `json:"redemptions"`
+	PointRules  []ArchivedPointRule  `json:"point_rules"`
+	Goals       []ArchivedGoal       `json:"goals"`
+	Wallets     []ArchivedWallet     `json:"wallets"`
+}
+
+type ArchivedFamily struct {
+	Name              string     `json:"name"`
+	Plan              string     `json:"plan"` // informational, imported families start on FREE
+	EnableLeaderboard bool       `json:"enable_leaderboard"`
+	Timezone          string     `json:"timezone"`
+	SeasonStart       string     `json:"season_start,omitempty"`
+	SeasonEnd         string     `json:"season_end,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	PlanExpiresAt     *time.Time `json:"plan_expires_at,omitempty"`
+}
+
+type ArchivedUser struct {
+	ID            string    `json:"id"`
+	Role          string    `json:"role"`
+	Name          string    `json:"name"`
+	AvatarIcon    string    `json:"avatar_icon"`
+	Email         *string   `json:"email,omitempty"`
+	Whatsapp      *string   `json:"whatsapp,omitempty"`
+	PointsBalance int       `json:"points_balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ArchivedTask struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Icon        string     `json:"icon"`
+	PointReward int        `json:"point_reward"`
+	MaxPerDay   *int       `json:"max_per_day"`
+	TaskType    string     `json:"task_type"`
+	Category    string     `json:"category,omitempty"` // absent in archives made before categories, imported as general
+	Quantity    int        `json:"quantity,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // kept so old logs still have their task
+}
+
+type ArchivedReward struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Icon           string     `json:"icon"`
+	PointsRequired int        `json:"points_required"`
+	IsActive       bool       `json:"is_active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ArchivedLog struct {
+	ChildID       string    `json:"child_id"`
+	TaskID        string    `json:"task_id"`
+	CompletedDate string    `json:"completed_date"`
+	Status        string    `json:"status"`
+	EarnedPoints  int       `json:"earned_points"`
+	Quantity      int       `json:"quantity,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ArchivedRedemption struct {
+	ID          string                 `json:"id"`
+	ChildID     string                 `json:"child_id"`
+	RewardID    string                 `json:"reward_id"`
+	PointsSpent int                    `json:"points_spent"`
+	Status      string                 `json:"status"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	History     []ArchivedStatusChange `json:"history"`
+}
+
+type ArchivedStatusChange struct {
+	Status    string    `json:"status"`
+	ChangedBy *string   `json:"changed_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ArchivedPointRule struct {
+	Name        string  `json:"name"`
+	RuleType    string  `json:"rule_type"`
+	Multiplier  float64 `json:"multiplier"`
+	BonusPoints int     `json:"bonus_points"`
+	TaskID      *string `json:"task_id,omitempty"`
+	ChildID     *string `json:"child_id,omitempty"`
+	StartDate   string  `json:"start_date,omitempty"`
+	EndDate     string  `json:"end_date,omitempty"`
+	Weekdays    string  `json:"weekdays"`
+	IsActive    bool    `json:"is_active"`
+}
+
+type ArchivedGoal struct {
+	Name        string     `json:"name"`
+	Icon        string     `json:"icon"`
+	GoalType    string     `json:"goal_type"`
+	Metric      string     `json:"metric"`
+	Target      int        `json:"target"`
+	DailyTarget int        `json:"daily_target"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	RewardName  string     `json:"reward_name"`
+	RewardIcon  string     `json:"reward_icon"`
+	AchievedAt  *time.Time `json:"achieved_at,omitempty"`
+	TaskIDs     []string   `json:"task_ids"`
+}
+
+type ArchivedWallet struct {
+	ChildID        string                `json:"child_id"`
+	IsActive       bool                  `json:"is_active"`
+	RupiahPerPoint int                   `json:"rupiah_per_point"`
+	THRPerPoint    int                   `json:"thr_per_point"`
+	Transactions   []ArchivedTransaction `json:"transactions"`
+}
+
+type ArchivedTransaction struct {
+	Type       string    `json:"type"`
+	Status     string    `json:"status"`
+	Points     int       `json:"points"`
+	Amount     int64     `json:"amount"`
+	Note       string    `json:"note"`
+	Reference  string    `json:"reference"`
+	RecordedBy *string   `json:"recorded_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func parseArchiveDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, ErrArchiveInvalidDate
+	}
+	return &t, nil
+}
+
+// ExportArchive collects everything that belongs to a family.
+func (s *FamilyDataService) ExportArchive(familyID string) (*FamilyArchive, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	archive := &FamilyArchive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Family: ArchivedFamily{
+			Name:              family.Name,
+			Plan:              family.Plan,
+			EnableLeaderboard: family.EnableLeaderboard,
+			Timezone:          family.Timezone,
+			SeasonStart:       formatDate(family.SeasonStart),
+			SeasonEnd:         formatDate(family.SeasonEnd),
+			CreatedAt:         family.CreatedAt,
+			PlanExpiresAt:     family.PlanExpiresAt,
+		},
+	}
+
+	var users []models.User
+	if err := database.DB.Where("family_id = ?", familyID).Order("created_at").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+		archive.Users = append(archive.Users, ArchivedUser{
+			ID: u.ID, Role: u.Role, Name: u.Name, AvatarIcon: u.AvatarIcon,
+			Email: u.Email, Whatsapp: u.Whatsapp, PointsBalance: u.PointsBalance, CreatedAt: u.CreatedAt,
+		})
+	}
+
+	var tasks []models.Task
+	if err := database.DB.Unscoped().Where("family_id = ?", familyID).Order("created_at").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		archived := ArchivedTask{
+			ID: t.ID, Name: t.Name, Icon: t.Icon, PointReward: t.PointReward, MaxPerDay: t.MaxPerDay,
+			TaskType: t.TaskType, Category: t.Category, Quantity: t.Quantity, IsActive: t.IsActive, CreatedAt: t.CreatedAt,
+		}
+		if t.DeletedAt.Valid {
+			archived.DeletedAt = &t.DeletedAt.Time
+		}
+		archive.Tasks = append(archive.Tasks, archived)
+	}
+
+	var rewards []models.Reward
+	if err := database.DB.Unscoped().Where("family_id = ?", familyID).Order("created_at").Find(&rewards).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rewards {
+		archived := ArchivedReward{
+			ID: r.ID, Name: r.Name, Icon: r.Icon, PointsRequired: r.PointsRequired, IsActive: r.IsActive, CreatedAt: r.CreatedAt,
+		}
+		if r.DeletedAt.Valid {
+			archived.DeletedAt = &r.DeletedAt.Time
+		}
+		archive.Rewards = append(archive.Rewards, archived)
+	}
+
+	var logs []models.DailyLog
+	if err := database.DB.Where("child_id IN ?", userIDs).Order("completed_date, created_at").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		archive.DailyLogs = append(archive.DailyLogs, ArchivedLog{
+			ChildID: l.ChildID, TaskID: l.TaskID, CompletedDate: l.CompletedDate.Format("2006-01-02"),
+			Status: l.Status, EarnedPoints: l.EarnedPoints, Quantity: l.Quantity, CreatedAt: l.CreatedAt,
+		})
+	}
+
+	var redemptions []models.Redemption
+	if err := database.DB.Where("child_id IN ?", userIDs).Order("created_at").Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+	redemptionIDs := make([]string, 0, len(redemptions))
+	for _, r := range redemptions {
+		redemptionIDs = append(redemptionIDs, r.ID)
+	}
+	var changes []models.RedemptionStatusChange
+	if err := database.DB.Where("redemption_id IN ?", redemptionIDs).Order("created_at").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	history := map[string][]ArchivedStatusChange{}
+	for _, c := range changes {
+		history[c.RedemptionID] = append(history[c.RedemptionID], ArchivedStatusChange{
+			Status: c.Status, ChangedBy: c.ChangedBy, CreatedAt: c.CreatedAt,
+		})
+	}
+	for _, r := range redemptions {
+		archive.Redemptions = append(archive.Redemptions, ArchivedRedemption{
+			ID: r.ID, ChildID: r.ChildID, RewardID: r.RewardID, PointsSpent: r.PointsSpent, Status: r.Status,
+			CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt, History: history[r.ID],
+		})
+	}
+
+	var rules []models.PointRule
+	if err := database.DB.Where("family_id = ?", familyID).Order("created_at").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		archive.PointRules = append(archive.PointRules, ArchivedPointRule{
+			Name: r.Name, RuleType: r.RuleType, Multiplier: r.Multiplier, BonusPoints: r.BonusPoints,
+			TaskID: r.TaskID, ChildID: r.ChildID, StartDate: formatDate(r.StartDate), EndDate: formatDate(r.EndDate),
+			Weekdays: r.Weekdays, IsActive: r.IsActive,
+		})
+	}
+
+	var goals []models.FamilyGoal
+	if err := database.DB.Preload("Tasks").Where("family_id = ?", familyID).Order("created_at").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	for _, g := range goals {
+		taskIDs := make([]string, 0, len(g.Tasks))
+		for _, t := range g.Tasks {
+			taskIDs = append(taskIDs, t.ID)
+		}
+		archive.Goals = append(archive.Goals, ArchivedGoal{
+			Name: g.Name, Icon: g.Icon, GoalType: g.GoalType, Metric: g.Metric, Target: g.Target,
+			DailyTarget: g.DailyTarget, StartDate: formatDate(&g.StartDate), EndDate: formatDate(&g.EndDate),
+			RewardName: g.RewardName, RewardIcon: g.RewardIcon, AchievedAt: g.AchievedAt, TaskIDs: taskIDs,
+		})
+	}
+
+	var wallets []models.Wallet
+	if err := database.DB.Where("family_id = ?", familyID).Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	for _, w := range wallets {
+		var txs []models.WalletTransaction
+		if err := database.DB.Where("wallet_id = ?", w.ID).Order("created_at").Find(&txs).Error; err != nil {
+			return nil, err
+		}
+		archived := ArchivedWallet{
+			ChildID: w.ChildID, IsActive: w.IsActive, RupiahPerPoint: w.RupiahPerPoint, THRPerPoint: w.THRPerPoint,
+		}
+		for _, t := range txs {
+			archived.Transactions = append(archived.Transactions, ArchivedTransaction{
+				Type: t.Type, Status: t.Status, Points: t.Points, Amount: t.Amount, Note: t.Note,
+				Reference: t.Reference, RecordedBy: t.RecordedBy, CreatedAt: t.CreatedAt,
+			})
+		}
+		archive.Wallets = append(archive.Wallets, archived)
+	}
+
+	return archive, nil
+}
+
+// ImportOwner is the parent account that will own the imported family.
+type ImportOwner struct {
+	Email        string
+	PasswordHash string
+	FamilyName   string // optional, overrides the archived name
+}
+
+type ImportResult struct {
+	FamilyID string         `json:"familyId"`
+	OwnerID  string         `json:"ownerId"`
+	Imported map[string]int `json:"imported"`
+	Skipped  int            `json:"skipped"` // records pointing at users, tasks or rewards missing from the archive
+	// Children have no PIN after an import; a parent has to set one before they can log in.
+	ChildrenWithoutPIN []string `json:"childrenWithoutPin"`
+	// Parents other than the owner come in without an e-mail, so an archive
+	// can't claim someone else's address; they have no way to log in yet.
+	ParentsWithoutEmail []string `json:"parentsWithoutEmail"`
+}
+
+// ImportArchive restores an archive into a brand-new family with fresh IDs.
+// The owner becomes the archived parent with the same e-mail, or the first
+// parent. Other parents are imported without an e-mail or password.
+func (s *FamilyDataService) ImportArchive(archive *FamilyArchive, owner ImportOwner) (*ImportResult, error) {
+	if archive.Version != ArchiveVersion {
+		return nil, ErrArchiveVersion
+	}
+
+	name := strings.TrimSpace(owner.FamilyName)
+	if name == "" {
+		name = archive.Family.Name
+	}
+	if name == "" {
+		return nil, InvalidField("familyName", "required", nil)
+	}
+
+	var existing int64
+	database.DB.Model(&models.User{}).Where("email = ?", owner.Email).Count(&existing)
+	if existing > 0 {
+		return nil, ErrEmailTaken
+	}
+	database.DB.Model(&models.Family{}).Where("name = ?", name).Count(&existing)
+	if existing > 0 {
+		return nil, ErrFamilyNameTaken
+	}
+
+	ownerIndex := -1
+	for i, u := range archive.Users {
+		if u.Role != "parent" {
+			continue
+		}
+		if ownerIndex == -1 || (u.Email != nil && strings.EqualFold(*u.Email, owner.Email)) {
+			ownerIndex = i
+		}
+	}
+	if ownerIndex == -1 {
+		return nil, ErrArchiveNoParent
+	}
+
+	seasonStart, err := parseArchiveDate(archive.Family.SeasonStart)
+	if err != nil {
+		return nil, err
+	}
+	seasonEnd, err := parseArchiveDate(archive.Family.SeasonEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Imported: map[string]int{}, ChildrenWithoutPIN: []string{}, ParentsWithoutEmail: []string{}}
+	userIDs := map[string]string{}
+	taskIDs := map[string]string{}
+	rewardIDs := map[string]string{}
+
+	remap := func(ids map[string]string, old *string) *string {
+		if old == nil {
+			return nil
+		}
+		if id, ok := ids[*old]; ok {
+			return &id
+		}
+		return nil
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	family := models.Family{
+		ID:                uuid.New().String(),
+		Name:              name,
+		Plan:              "FREE",
+		EnableLeaderboard: archive.Family.EnableLeaderboard,
+		Timezone:          archive.Family.Timezone,
+		SeasonStart:       seasonStart,
+		SeasonEnd:         seasonEnd,
+	}
+	if family.Timezone == "" {
+		family.Timezone = "Asia/Jakarta"
+	}
+	if err := tx.Create(&family).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// EnableLeaderboard=false would be replaced by the column default on insert.
+	if err := tx.Model(&family).Update("enable_leaderboard", archive.Family.EnableLeaderboard).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	result.FamilyID = family.ID
+
+	for i, u := range archive.Users {
+		if u.Role != "parent" && u.Role != "child" {
+			result.Skipped++
+			continue
+		}
+		user := models.User{
+			ID:            uuid.New().String(),
+			FamilyID:      family.ID,
+			Role:          u.Role,
+			Name:          u.Name,
+			AvatarIcon:    u.AvatarIcon,
+			Whatsapp:      u.Whatsapp,
+			PointsBalance: u.PointsBalance,
+			CreatedAt:     u.CreatedAt,
+		}
+		if user.AvatarIcon == "" {
+			user.AvatarIcon = "👦"
+		}
+		if i == ownerIndex {
+			email, hash := owner.Email, owner.PasswordHash
+			user.Email, user.PasswordHash = &email, &hash
+			result.OwnerID = user.ID
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		userIDs[u.ID] = user.ID
+		switch {
+		case u.Role == "child":
+			result.ChildrenWithoutPIN = append(result.ChildrenWithoutPIN, user.Name)
+		case i != ownerIndex:
+			result.ParentsWithoutEmail = append(result.ParentsWithoutEmail, user.Name)
+		}
+		result.Imported["users"]++
+	}
+
+	for _, t := range archive.Tasks {
+		task := models.Task{
+			ID: uuid.New().String(), FamilyID: family.ID, Name: t.Name, Icon: t.Icon, PointReward: t.PointReward,
+			MaxPerDay: t.MaxPerDay, TaskType: t.TaskType, Category: taskCategory(t.Category), Quantity: taskQuantity(t.Quantity),
+			IsActive: t.IsActive, CreatedAt: t.CreatedAt,
+		}
+		if t.DeletedAt != nil {
+			task.DeletedAt = gorm.DeletedAt{Time: *t.DeletedAt, Valid: true}
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if !t.IsActive {
+			if err := tx.Unscoped().Model(&task).Update("is_active", false).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		taskIDs[t.ID] = task.ID
+		result.Imported["tasks"]++
+	}
+
+	for _, r := range archive.Rewards {
+		reward := models.Reward{
+			ID: uuid.New().String(), FamilyID: family.ID, Name: r.Name, Icon: r.Icon,
+			PointsRequired: r.PointsRequired, IsActive: r.IsActive, CreatedAt: r.CreatedAt,
+		}
+		if r.DeletedAt != nil {
+			reward.DeletedAt = gorm.DeletedAt{Time: *r.DeletedAt, Valid: true}
+		}
+		if err := tx.Create(&reward).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if !r.IsActive {
+			if err := tx.Unscoped().Model(&reward).Update("is_active", false).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		rewardIDs[r.ID] = reward.ID
+		result.Imported["rewards"]++
+	}
+
+	logs := make([]models.DailyLog, 0, len(archive.DailyLogs))
+	for _, l := range archive.DailyLogs {
+		childID, okChild := userIDs[l.ChildID]
+		taskID, okTask := taskIDs[l.TaskID]
+		date, err := time.Parse("2006-01-02", l.CompletedDate)
+		if !okChild || !okTask || err != nil {
+			result.Skipped++
+			continue
+		}
+		logs = append(logs, models.DailyLog{
+			ChildID: childID, TaskID: taskID, CompletedDate: date, Status: l.Status,
+			EarnedPoints: l.EarnedPoints, Quantity: taskQuantity(l.Quantity), CreatedAt: l.CreatedAt,
+		})
+	}
+	if len(logs) > 0 {
+		if err := tx.CreateInBatches(&logs, 500).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	result.Imported["daily_logs"] = len(logs)
+
+	for _, r := range archive.Redemptions {
+		childID, okChild := userIDs[r.ChildID]
+		rewardID, okReward := rewardIDs[r.RewardID]
+		if !okChild || !okReward {
+			result.Skipped++
+			continue
+		}
+		redemption := models.Redemption{
+			ID: uuid.New().String(), ChildID: childID, RewardID: rewardID, PointsSpent: r.PointsSpent,
+			Status: r.Status, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+		}
+		if err := tx.Create(&redemption).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		for _, h := range r.History {
+			change := models.RedemptionStatusChange{
+				RedemptionID: redemption.ID, Status: h.Status, ChangedBy: remap(userIDs, h.ChangedBy), CreatedAt: h.CreatedAt,
+			}
+			if err := tx.Create(&change).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		result.Imported["redemptions"]++
+	}
+
+	for _, r := range archive.PointRules {
+		start, err := parseArchiveDate(r.StartDate)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		end, err := parseArchiveDate(r.EndDate)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		rule := models.PointRule{
+			FamilyID: family.ID, Name: r.Name, RuleType: r.RuleType, Multiplier: r.Multiplier,
+			BonusPoints: r.BonusPoints, TaskID: remap(taskIDs, r.TaskID), ChildID: remap(userIDs, r.ChildID),
+			StartDate: start, EndDate: end, Weekdays: r.Weekdays, IsActive: r.IsActive,
+		}
+		// A rule scoped to a task or child that did not survive would silently apply to everyone.
+		if (r.TaskID != nil && rule.TaskID == nil) || (r.ChildID != nil && rule.ChildID == nil) {
+			result.Skipped++
+			continue
+		}
+		if err := tx.Create(&rule).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if !r.IsActive {
+			if err := tx.Model(&rule).Update("is_active", false).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		result.Imported["point_rules"]++
+	}
+
+	for _, g := range archive.Goals {
+		start, errStart := parseArchiveDate(g.StartDate)
+		end, errEnd := parseArchiveDate(g.EndDate)
+		if errStart != nil || errEnd != nil || start == nil || end == nil {
+			result.Skipped++
+			continue
+		}
+		goal := models.FamilyGoal{
+			FamilyID: family.ID, Name: g.Name, Icon: g.Icon, GoalType: g.GoalType, Metric: g.Metric,
+			Target: g.Target, DailyTarget: g.DailyTarget, StartDate: *start, EndDate: *end,
+			RewardName: g.RewardName, RewardIcon: g.RewardIcon, AchievedAt: g.AchievedAt,
+		}
+		for _, id := range g.TaskIDs {
+			if newID, ok := taskIDs[id]; ok {
+				goal.Tasks = append(goal.Tasks, models.Task{ID: newID})
+			}
+		}
+		if err := tx.Omit("Tasks.*").Create(&goal).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		result.Imported["goals"]++
+	}
+
+	for _, w := range archive.Wallets {
+		childID, ok := userIDs[w.ChildID]
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		wallet := models.Wallet{
+			FamilyID: family.ID, ChildID: childID, IsActive: w.IsActive,
+			RupiahPerPoint: w.RupiahPerPoint, THRPerPoint: w.THRPerPoint,
+		}
+		if err := tx.Create(&wallet).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if !w.IsActive {
+			if err := tx.Model(&wallet).Update("is_active", false).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		for _, t := range w.Transactions {
+			txn := models.WalletTransaction{
+				WalletID: wallet.ID, ChildID: childID, Type: t.Type, Status: t.Status, Points: t.Points,
+				Amount: t.Amount, Note: t.Note, Reference: t.Reference, RecordedBy: remap(userIDs, t.RecordedBy),
+				CreatedAt: t.CreatedAt,
+			}
+			if err := tx.Create(&txn).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		result.Imported["wallets"]++
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteFamily permanently removes a family and everything that belongs to it.
+// Children first, so it works whether or not the foreign keys cascade.
+func DeleteFamily(familyID string) error {
+	steps := []string{
+		`DELETE FROM wallet_transactions WHERE wallet_id IN (SELECT id FROM wallets WHERE family_id = ?)`,
+		`DELETE FROM wallets WHERE family_id = ?`,
+		`DELETE FROM redemption_status_changes WHERE redemption_id IN (
+			SELECT r.id FROM redemptions r JOIN users u ON u.id = r.child_id WHERE u.family_id = ?)`,
+		`DELETE FROM redemptions WHERE child_id IN (SELECT id FROM users WHERE family_id = ?)`,
+		`DELETE FROM daily_logs WHERE child_id IN (SELECT id FROM users WHERE family_id = ?)`,
+		`DELETE FROM family_goal_tasks WHERE family_goal_id IN (SELECT id FROM family_goals WHERE family_id = ?)`,
+		`DELETE FROM family_goals WHERE family_id = ?`,
+		`DELETE FROM point_rules WHERE family_id = ?`,
+		`DELETE FROM badges WHERE family_id = ?`,
+		`DELETE FROM template_applications WHERE family_id = ?`,
+		`DELETE FROM templates WHERE family_id = ?`,
+		`DELETE FROM account_deletions WHERE family_id = ?`,
+		`DELETE FROM coupon_redemptions WHERE family_id = ?`,
+		`DELETE FROM referrals WHERE ? IN (referrer_family_id, invitee_family_id)`,
+		`DELETE FROM payment_notifications WHERE order_id IN (SELECT order_id FROM payments WHERE family_id = ?)`,
+		`DELETE FROM payments WHERE family_id = ?`,
+		`DELETE FROM push_messages WHERE user_id IN (SELECT id FROM users WHERE family_id = ?)`,
+		`DELETE FROM push_subscriptions WHERE family_id = ?`,
+		`DELETE FROM notification_preferences WHERE user_id IN (SELECT id FROM users WHERE family_id = ?)`,
+		`DELETE FROM whatsapp_messages WHERE user_id IN (SELECT id FROM users WHERE family_id = ?)`,
+		`DELETE FROM whatsapp_consents WHERE user_id IN (SELECT id FROM users WHERE family_id = ?)`,
+		`DELETE FROM idempotency_keys WHERE scope IN (SELECT id::text FROM users WHERE family_id = ?)`,
+		`DELETE FROM rewards WHERE family_id = ?`,
+		`DELETE FROM tasks WHERE family_id = ?`,
+		`DELETE FROM users WHERE family_id = ?`,
+		`DELETE FROM families WHERE id = ?`,
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, step := range steps {
+			if err := tx.Exec(step, familyID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+)
+
+type GoalService struct{}
+
+func NewGoalService() *GoalService {
+	return &GoalService{}
+}
+
+type GoalRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Icon        string   `json:"icon" validate:"emoji"`
+	GoalType    string   `json:"goalType" validate:"required,oneof=collective every_child_daily"`
+	Metric      string   `json:"metric" validate:"oneof=completions points"` // collective only, default completions
+	Target      int      `json:"target" validate:"min=1"`
+	DailyTarget int      `json:"dailyTarget" validate:"min=0"` // every_child_daily only
+	TaskIDs     []string `json:"taskIds" validate:"required,max=100,dive,uuid"`
+	StartDate   string   `json:"startDate" validate:"required,date"` // YYYY-MM-DD
+	EndDate     string   `json:"endDate" validate:"required,date"`   // YYYY-MM-DD
+	RewardName  string   `json:"rewardName" validate:"required,max=100"`
+	RewardIcon  string   `json:"rewardIcon" validate:"emoji"`
+}
+
+func (r GoalRequest) Check(v *Violations) {
+	if r.GoalType == "every_child_daily" && r.DailyTarget <= 0 && !v.Has("dailyTarget") {
+		v.Add("dailyTarget", "positive", nil)
+	}
+	if !v.Has("startDate") && !v.Has("endDate") && r.EndDate < r.StartDate {
+		v.Add("endDate", "not_before", map[string]interface{}{"other": "startDate"})
+	}
+}
+
+type ChildContribution struct {
+	ChildID   string `json:"childId"`
+	ChildName string `json:"childName"`
+	Avatar    string `json:"avatar"`
+	Value     int64  `json:"value"` // completions/points for collective, days on target for every_child_daily
+}
+
+type GoalProgress struct {
+	Goal          models.FamilyGoal   `json:"goal"`
+	Current       int64               `json:"current"`
+	Target        int                 `json:"target"`
+	Percent       int                 `json:"percent"`
+	Unlocked      bool                `json:"unlocked"`
+	Contributions []ChildContribution `json:"contributions"`
+}
+
+func (s *GoalService) GetGoals(familyID string) ([]GoalProgress, error) {
+	var goals []models.FamilyGoal
+	if err := database.DB.Preload("Tasks").Where("family_id = ?", familyID).Order("start_date DESC").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+
+	progress := make([]GoalProgress, 0, len(goals))
+	for _, g := range goals {
+		p, err := s.progress(g)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, *p)
+	}
+	return progress, nil
+}
+
+func (s *GoalService) GetProgress(familyID, goalID string) (*GoalProgress, error) {
+	var goal models.FamilyGoal
+	if err := database.DB.Preload("Tasks").Where("id = ? AND family_id = ?", goalID, familyID).First(&goal).Error; err != nil {
+		return nil, ErrGoalNotFound
+	}
+	return s.progress(goal)
+}
+
+func (s *GoalService) CreateGoal(familyID string, req GoalRequest) (*models.FamilyGoal, error) {
+	goal := models.FamilyGoal{FamilyID: familyID}
+	if err := s.fill(familyID, &goal, req); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Create(&goal).Error; err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (s *GoalService) UpdateGoal(familyID, goalID string, req GoalRequest) (*models.FamilyGoal, error) {
+	var goal models.FamilyGoal
+	if err := database.DB.Where("id = ? AND family_id = ?", goalID, familyID).First(&goal).Error; err != nil {
+		return nil, ErrGoalNotFound
+	}
+	if err := s.fill(familyID, &goal, req); err != nil {
+		return nil, err
+	}
+
+	// Changing the target may re-lock the goal; progress recomputes it.
+	goal.AchievedAt = nil
+
+	tx := database.DB.Begin()
+	if err := tx.Omit("Tasks").Save(&goal).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Model(&goal).Association("Tasks").Replace(goal.Tasks); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	tx.Commit()
+	return &goal, nil
+}
+
+func (s *GoalService) DeleteGoal(familyID, goalID string) error {
+	result := database.DB.Where("id = ? AND family_id = ?", goalID, familyID).Delete(&models.FamilyGoal{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+func (s *GoalService) fill(familyID string, goal *models.FamilyGoal, req GoalRequest) error {
+	if err := Validate(req); err != nil {
+		return err
+	}
+
+	if req.GoalType == "collective" {
+		if req.Metric == "" {
+			req.Metric = "completions"
+		}
+		req.DailyTarget = 0
+	} else {
+		req.Metric = "completions"
+	}
+
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+	end, _ := time.Parse("2006-01-02", req.EndDate)
+
+	var tasks []models.Task
+	if err := database.DB.Where("id IN ? AND family_id = ?", req.TaskIDs, familyID).Find(&tasks).Error; err != nil {
+		return err
+	}
+	if len(tasks) != len(req.TaskIDs) {
+		return ErrTaskNotFound
+	}
+
+	goal.Name = req.Name
+	if req.Icon != "" {
+		goal.Icon = req.Icon
+	}
+	goal.GoalType = req.GoalType
+	goal.Metric = req.Metric
+	goal.Target = req.Target
+	goal.DailyTarget = req.DailyTarget
+	goal.StartDate = start
+	goal.EndDate = end
+	goal.RewardName = req.RewardName
+	if req.RewardIcon != "" {
+		goal.RewardIcon = req.RewardIcon
+	}
+	goal.Tasks = tasks
+	return nil
+}
+
+// progress computes a goal's shared progress bar from daily_logs and marks it
+// achieved the first time the target is reached.
+func (s *GoalService) progress(goal models.FamilyGoal) (*GoalProgress, error) {
+	var children []models.User
+	if err := database.DB.Where("family_id = ? AND role = 'child'", goal.FamilyID).Find(&children).Error; err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]string, 0, len(goal.Tasks))
+	for _, t := range goal.Tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	perChild := map[string]int64{}
+	var current int64
+
+	if len(children) > 0 && len(taskIDs) > 0 {
+		var rows []struct {
+			ChildID string
+			Value   int64
+		}
+
+		switch goal.GoalType {
+		case "collective":
+			valueExpr := "COUNT(*)"
+			if goal.Metric == "points" {
+				valueExpr = "COALESCE(SUM(earned_points), 0)"
+			}
+			err := database.DB.Model(&models.DailyLog{}).
+				Select("child_id, "+valueExpr+" AS value").
+				Joins("JOIN users ON users.id = daily_logs.child_id AND users.family_id = ? AND users.deleted_at IS NULL", goal.FamilyID).
+				Where("daily_logs.status = 'verified' AND daily_logs.task_id IN ? AND daily_logs.completed_date BETWEEN ? AND ?", taskIDs, goal.StartDate, goal.EndDate).
+				Group("child_id").
+				Scan(&rows).Error
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rows {
+				perChild[r.ChildID] = r.Value
+				current += r.Value
+			}
+
+		case "every_child_daily":
+			// Days on which each child reached the daily target.
+			err := database.DB.Raw(`
+				SELECT child_id, COUNT(*) AS value FROM (
+					SELECT dl.child_id, dl.completed_date
+					FROM daily_logs dl
+					JOIN users u ON u.id = dl.child_id AND u.family_id = ? AND u.deleted_at IS NULL
+					WHERE dl.status = 'verified' AND dl.deleted_at IS NULL
+						AND dl.task_id IN ? AND dl.completed_date BETWEEN ? AND ?
+					GROUP BY dl.child_id, dl.completed_date
+					HAVING COUNT(*) >= ?
+				) d GROUP BY child_id`,
+				goal.FamilyID, taskIDs, goal.StartDate, goal.EndDate, goal.DailyTarget).
+				Scan(&rows).Error
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rows {
+				perChild[r.ChildID] = r.Value
+			}
+
+			// Days on which every child reached it together.
+			err = database.DB.Raw(`
+				SELECT COUNT(*) FROM (
+					SELECT completed_date FROM (
+						SELECT dl.child_id, dl.completed_date
+						FROM daily_logs dl
+						JOIN users u ON u.id = dl.child_id AND u.family_id = ? AND u.role = 'child' AND u.deleted_at IS NULL
+						WHERE dl.status = 'verified' AND dl.deleted_at IS NULL
+							AND dl.task_id IN ? AND dl.completed_date BETWEEN ? AND ?
+						GROUP BY dl.child_id, dl.completed_date
+						HAVING COUNT(*) >= ?
+					) c GROUP BY completed_date HAVING COUNT(*) >= ?
+				) d`,
+				goal.FamilyID, taskIDs, goal.StartDate, goal.EndDate, goal.DailyTarget, len(children)).
+				Scan(&current).Error
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	contributions := make([]ChildContribution, 0, len(children))
+	for _, child := range children {
+		contributions = append(contributions, ChildContribution{
+			ChildID:   child.ID,
+			ChildName: child.Name,
+			Avatar:    child.AvatarIcon,
+			Value:     perChild[child.ID],
+		})
+	}
+
+	percent := int(current * 100 / int64(goal.Target))
+	if percent > 100 {
+		percent = 100
+	}
+	unlocked := current >= int64(goal.Target)
+
+	if unlocked && goal.AchievedAt == nil {
+		now := time.Now()
+		goal.AchievedAt = &now
+		database.DB.Model(&models.FamilyGoal{}).Where("id = ?", goal.ID).Update("achieved_at", now)
+	}
+
+	return &GoalProgress{
+		Goal:          goal,
+		Current:       current,
+		Target:        goal.Target,
+		Percent:       percent,
+		Unlocked:      unlocked,
+		Contributions: contributions,
+	}, nil
+}
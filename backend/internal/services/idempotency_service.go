@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// IdempotencyTTL is how long a stored response is replayed.
+	IdempotencyTTL = 24 * time.Hour
+	// idempotencyLockTimeout frees a key whose first request never finished
+	// (the instance died mid-request).
+	idempotencyLockTimeout  = time.Minute
+	MaxIdempotencyKeyLength = 255
+)
+
+// IdempotencyService stores the first response per user and Idempotency-Key.
+type IdempotencyService struct{}
+
+func NewIdempotencyService() *IdempotencyService {
+	return &IdempotencyService{}
+}
+
+// IdempotencyHash fingerprints a request; the same key with another
+// fingerprint is a client bug, not a retry.
+func IdempotencyHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin claims key for a new request. It returns claimed=true when the caller
+// should run the request and then Complete or Release it. Otherwise it
+// returns the stored record to replay, or an error:
+// "Idempotency-Key reused with a different request" or
+// "Request with this Idempotency-Key is still in progress".
+func (s *IdempotencyService) Begin(scope, key, hash string) (record *models.IdempotencyKey, claimed bool, err error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, false, ErrIdempotencyKey
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		record = &models.IdempotencyKey{Scope: scope, Key: key, RequestHash: hash, ExpiresAt: now.Add(IdempotencyTTL)}
+		result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return record, true, nil
+		}
+
+		var existing models.IdempotencyKey
+		if err := database.DB.First(&existing, "scope = ? AND key = ?", scope, key).Error; err != nil {
+			continue // deleted in between; try to claim again
+		}
+		abandoned := existing.CompletedAt == nil && existing.CreatedAt.Before(now.Add(-idempotencyLockTimeout))
+		if existing.ExpiresAt.Before(now) || abandoned {
+			database.DB.Where("id = ?", existing.ID).Delete(&models.IdempotencyKey{})
+			continue
+		}
+		if existing.RequestHash != hash {
+			return nil, false, ErrIdempotencyReused
+		}
+		if existing.CompletedAt == nil {
+			return nil, false, ErrIdempotencyInFlight
+		}
+		return &existing, false, nil
+	}
+	return nil, false, ErrIdempotencyInFlight
+}
+
+// Complete stores the response of a claimed request for replay.
+func (s *IdempotencyService) Complete(record *models.IdempotencyKey, status int, contentType string, body []byte) error {
+	now := time.Now()
+	return database.DB.Model(record).Updates(map[string]interface{}{
+		"status_code":   status,
+		"content_type":  contentType,
+		"response_body": body,
+		"completed_at":  now,
+	}).Error
+}
+
+// Release frees a claimed key without storing a response, so the client may
+// retry (used for server errors).
+func (s *IdempotencyService) Release(record *models.IdempotencyKey) error {
+	return database.DB.Delete(record).Error
+}
+
+// Prune deletes expired keys.
+func (s *IdempotencyService) Prune(ctx context.Context) error {
+	return database.DB.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyKey{}).Error
+}
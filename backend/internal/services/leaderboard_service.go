@@ -0,0 +1,177 @@
+package services
+
+import (
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type LeaderboardService struct{}
+
+func NewLeaderboardService() *LeaderboardService {
+	return &LeaderboardService{}
+}
+
+type LeaderboardQuery struct {
+	Period string // daily, weekly, season, alltime
+	Date   string // YYYY-MM-DD inside the wanted day/week, default today
+	RankBy string // points or completion
+}
+
+type LeaderboardEntry struct {
+	Rank           int     `json:"rank"`
+	ChildID        string  `json:"childId"`
+	ChildName      string  `json:"childName"`
+	Avatar         string  `json:"avatar"`
+	Points         int64   `json:"points"`
+	WeekPoints     int64   `json:"weekPoints"` // kept for older clients, same as Points
+	Completions    int64   `json:"completions"`
+	CompletionRate float64 `json:"completionRate"` // 0-100, completed slots over available slots
+}
+
+type Leaderboard struct {
+	Period      string             `json:"period"`
+	RankBy      string             `json:"rankBy"`
+	PeriodStart string             `json:"periodStart"`
+	PeriodEnd   string             `json:"periodEnd"`
+	WeekStart   string             `json:"weekStart"` // kept for older clients
+	WeekEnd     string             `json:"weekEnd"`
+	Entries     []LeaderboardEntry `json:"leaderboard"`
+}
+
+// periodBounds resolves the inclusive date range of a leaderboard period in the family's timezone.
+func periodBounds(family models.Family, period, dateStr string) (time.Time, time.Time, error) {
+	loc := utils.LoadLocation(family.Timezone)
+	today := utils.Today(loc)
+
+	date := today
+	if dateStr != "" {
+		d, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, InvalidField("date", "date", nil)
+		}
+		date = d
+	}
+
+	switch period {
+	case "daily":
+		return date, date, nil
+	case "", "weekly":
+		monday, sunday := utils.WeekBounds(date)
+		return monday, sunday, nil
+	case "season":
+		if family.SeasonStart == nil || family.SeasonEnd == nil {
+			return time.Time{}, time.Time{}, ErrSeasonNotConfigured
+		}
+		return *family.SeasonStart, *family.SeasonEnd, nil
+	case "alltime":
+		created := family.CreatedAt.In(loc)
+		return time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC), today, nil
+	}
+	return time.Time{}, time.Time{}, InvalidField("period", "one_of", map[string]interface{}{"values": []string{"daily", "weekly", "season", "alltime"}})
+}
+
+// GetLeaderboard ranks the family's children in one grouped query. Ranks are
+// dense, so tied children share a rank and the next rank is not skipped.
+func (s *LeaderboardService) GetLeaderboard(familyID string, q LeaderboardQuery) (*Leaderboard, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	if !family.EnableLeaderboard {
+		return nil, ErrLeaderboardDisabled
+	}
+
+	if q.RankBy == "" {
+		q.RankBy = "points"
+	}
+	if q.RankBy != "points" && q.RankBy != "completion" {
+		return nil, InvalidField("rankBy", "one_of", map[string]interface{}{"values": []string{"points", "completion"}})
+	}
+	if q.Period == "" {
+		q.Period = "weekly"
+	}
+
+	start, end, err := periodBounds(family, q.Period, q.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only days that already happened count towards the available slots.
+	days := int(end.Sub(start).Hours()/24) + 1
+	if today := utils.Today(utils.LoadLocation(family.Timezone)); today.Before(end) {
+		days = int(today.Sub(start).Hours()/24) + 1
+	}
+	if days < 0 {
+		days = 0
+	}
+
+	orderBy := "points"
+	if q.RankBy == "completion" {
+		orderBy = "completion_rate"
+	}
+
+	// A slot is one allowed completion of a task on a day. Unlimited tasks
+	// (max_per_day = 0) count as one slot so they can't inflate the rate.
+	// Done slots come from the same active tasks as the capacity; logs of
+	// paused or deleted tasks still count for points and completions.
+	var entries []LeaderboardEntry
+	err = database.DB.Raw(`
+		WITH capacity AS (
+			SELECT COALESCE(SUM(GREATEST(COALESCE(max_per_day, 1), 1)), 0) AS slots_per_day
+			FROM tasks
+			WHERE family_id = @family AND is_active = true AND deleted_at IS NULL
+		),
+		slots AS (
+			SELECT dl.child_id,
+				SUM(dl.earned_points) AS points,
+				COUNT(*) AS completions,
+				CASE WHEN t.is_active AND t.deleted_at IS NULL
+					THEN LEAST(COUNT(*), GREATEST(COALESCE(t.max_per_day, 1), 1)) ELSE 0 END AS done
+			FROM daily_logs dl
+			JOIN tasks t ON t.id = dl.task_id
+			JOIN users c ON c.id = dl.child_id AND c.family_id = @family
+			WHERE dl.status = 'verified' AND dl.deleted_at IS NULL
+				AND dl.completed_date BETWEEN @start AND @end
+			GROUP BY dl.child_id, dl.task_id, dl.completed_date, t.max_per_day, t.is_active, t.deleted_at
+		),
+		totals AS (
+			SELECT u.id AS child_id, u.name AS child_name, u.avatar_icon AS avatar,
+				COALESCE(SUM(s.points), 0) AS points,
+				COALESCE(SUM(s.completions), 0) AS completions,
+				LEAST(COALESCE(ROUND(SUM(s.done) * 100.0 / NULLIF((SELECT slots_per_day FROM capacity) * @days, 0), 1), 0), 100) AS completion_rate
+			FROM users u
+			LEFT JOIN slots s ON s.child_id = u.id
+			WHERE u.family_id = @family AND u.role = 'child' AND u.deleted_at IS NULL
+			GROUP BY u.id, u.name, u.avatar_icon
+		)
+		SELECT *, points AS week_points, DENSE_RANK() OVER (ORDER BY `+orderBy+` DESC) AS rank
+		FROM totals
+		ORDER BY rank, child_name`,
+		map[string]interface{}{
+			"family": familyID,
+			"start":  start,
+			"end":    end,
+			"days":   days,
+		}).Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []LeaderboardEntry{}
+	}
+
+	return &Leaderboard{
+		Period:      q.Period,
+		RankBy:      q.RankBy,
+		PeriodStart: start.Format("2006-01-02"),
+		PeriodEnd:   end.Format("2006-01-02"),
+		WeekStart:   start.Format("2006-01-02"),
+		WeekEnd:     end.Format("2006-01-02"),
+		Entries:     entries,
+	}, nil
+}
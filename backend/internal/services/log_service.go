@@ -1,16 +1,48 @@
 package services
 
 import (
-	"errors"
+	"fmt"
+	"time"
 
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/events"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type LogService struct{}
+// maxUnlimitedCompletions bounds the bulk editor for tasks without a
+// MaxPerDay, so a typo can't create thousands of logs.
+const maxUnlimitedCompletions = 20
 
-func NewLogService() *LogService {
-	return &LogService{}
+type LogService struct {
+	events *events.Bus
+}
+
+func NewLogService(bus *events.Bus) *LogService {
+	return &LogService{events: bus}
+}
+
+// reverseCompletion undoes a verified log and takes its points back from
+// child; the counterpart of creditCompletion. child is updated in place. The
+// balance never goes below zero, so the points actually debited may be fewer
+// than the log earned; they are returned.
+func reverseCompletion(tx *gorm.DB, log *models.DailyLog, child *models.User) (int, error) {
+	log.Status = "undone"
+	if err := tx.Save(log).Error; err != nil {
+		return 0, err
+	}
+
+	debited := log.EarnedPoints
+	if child.PointsBalance < debited {
+		debited = child.PointsBalance
+	}
+	child.PointsBalance -= debited
+	if err := tx.Save(child).Error; err != nil {
+		return 0, err
+	}
+	return debited, nil
 }
 
 func (s *LogService) UndoTask(familyID, logID string) error {
@@ -24,43 +56,165 @@ func (s *LogService) UndoTask(familyID, logID string) error {
 	var log models.DailyLog
 	if err := tx.Preload("Child").Where("id = ?", logID).First(&log).Error; err != nil {
 		tx.Rollback()
-		return errors.New("Log not found or belongs to another family")
+		return ErrLogNotFound
 	}
 
 	if log.Child.FamilyID != familyID {
 		tx.Rollback()
-		return errors.New("Log not found or belongs to another family")
+		return ErrLogNotFound
 	}
 
 	if log.Status != "verified" {
 		tx.Rollback()
-		return errors.New("Log already undone or not verified")
+		return ErrLogNotVerified
 	}
 
-	log.Status = "undone"
-	if err := tx.Save(&log).Error; err != nil {
+	var child models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", log.ChildID).First(&child).Error; err != nil {
 		tx.Rollback()
-		return errors.New("Could not undo log")
+		return ErrUserNotFound
 	}
 
-	var child models.User
-	if err := tx.Where("id = ?", log.ChildID).First(&child).Error; err != nil {
+	debited, err := reverseCompletion(tx, &log, &child)
+	if err != nil {
 		tx.Rollback()
-		return errors.New("Could not find user")
+		return err
 	}
 
-	if child.PointsBalance >= log.EarnedPoints {
-		child.PointsBalance -= log.EarnedPoints
-	} else {
-		child.PointsBalance = 0
+	tx.Commit()
+
+	s.events.Publish(familyID, events.LogUndone, map[string]interface{}{
+		"logId":      log.ID,
+		"childId":    log.ChildID,
+		"taskId":     log.TaskID,
+		"date":       log.CompletedDate.Format("2006-01-02"),
+		"points":     log.EarnedPoints,
+		"debited":    debited,
+		"newBalance": child.PointsBalance,
+	})
+	s.events.Publish(familyID, events.LeaderboardChanged, map[string]interface{}{"childId": log.ChildID})
+
+	return nil
+}
+
+// DailyCount is how many times a task should count as done on a day.
+type DailyCount struct {
+	TaskID string `json:"taskId" validate:"required,uuid"`
+	Count  int    `json:"count" validate:"min=0,max=20"` // further capped by the task's MaxPerDay
+}
+
+// LogDiff is what the bulk editor changed for one task.
+type LogDiff struct {
+	TaskID      string `json:"taskId"`
+	TaskName    string `json:"taskName"`
+	Before      int    `json:"before"`
+	After       int    `json:"after"`
+	Added       int    `json:"added"`
+	Removed     int    `json:"removed"`
+	PointsDelta int    `json:"pointsDelta"` // change to the balance: points credited minus points actually debited
+}
+
+type DailyLogEdit struct {
+	ChildID    string    `json:"childId"`
+	Date       string    `json:"date"`
+	Changes    []LogDiff `json:"changes"`
+	NewBalance int       `json:"newBalance"`
+}
+
+// SetDailyCounts sets the number of verified completions per task for a
+// child's day. Missing completions go through the same path as CompleteTask
+// (MaxPerDay, point rules, balance); extra ones are undone newest first, like
+// UndoTask. Points always come from the task, never from the client. The
+// whole request is validated first and applied in one transaction.
+func (s *LogService) SetDailyCounts(familyID, childID string, date time.Time, counts []DailyCount) (*DailyLogEdit, error) {
+	if len(counts) == 0 {
+		return nil, ErrNoTasksToUpdate
 	}
 
-	if err := tx.Save(&child).Error; err != nil {
-		tx.Rollback()
-		return errors.New("Could not update user balance")
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	if date.After(utils.Today(utils.LoadLocation(family.Timezone))) {
+		return nil, InvalidField("date", "not_future", nil)
 	}
 
-	tx.Commit()
+	var child models.User
+	if err := database.DB.Where("id = ? AND family_id = ? AND role = 'child'", childID, familyID).First(&child).Error; err != nil {
+		return nil, ErrChildNotFound
+	}
 
-	return nil
+	tasks := make([]models.Task, len(counts))
+	seen := map[string]bool{}
+	for i, entry := range counts {
+		if seen[entry.TaskID] {
+			return nil, ErrDuplicateTask
+		}
+		seen[entry.TaskID] = true
+
+		if err := database.DB.Where("id = ? AND family_id = ?", entry.TaskID, familyID).First(&tasks[i]).Error; err != nil {
+			return nil, ErrTaskNotFound
+		}
+		limit := taskMaxPerDay(tasks[i])
+		if limit == 0 {
+			limit = maxUnlimitedCompletions
+		}
+		if entry.Count > limit {
+			return nil, InvalidField(fmt.Sprintf("tasks[%d].count", i), "daily_limit", map[string]interface{}{"max": limit})
+		}
+	}
+
+	edit := &DailyLogEdit{ChildID: childID, Date: date.Format("2006-01-02"), Changes: []LogDiff{}}
+	changed := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&child, "id = ?", childID).Error; err != nil {
+			return err
+		}
+
+		for i, entry := range counts {
+			task := tasks[i]
+			var current []models.DailyLog
+			if err := tx.Where("child_id = ? AND task_id = ? AND completed_date = ? AND status = 'verified'", childID, task.ID, date).
+				Order("created_at DESC").Find(&current).Error; err != nil {
+				return err
+			}
+
+			diff := LogDiff{TaskID: task.ID, TaskName: task.Name, Before: len(current), After: entry.Count}
+			if diff.Before != diff.After {
+				if err := checkCompletable(task, childID); err != nil {
+					return err
+				}
+			}
+			for n := diff.Before; n < diff.After; n++ {
+				log, _, err := creditCompletion(tx, task, &child, date, nil)
+				if err != nil {
+					return err
+				}
+				diff.Added++
+				diff.PointsDelta += log.EarnedPoints
+			}
+			for n := 0; n < diff.Before-diff.After; n++ {
+				debited, err := reverseCompletion(tx, &current[n], &child)
+				if err != nil {
+					return err
+				}
+				diff.Removed++
+				diff.PointsDelta -= debited
+			}
+
+			changed = changed || diff.Added > 0 || diff.Removed > 0
+			edit.Changes = append(edit.Changes, diff)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	edit.NewBalance = child.PointsBalance
+
+	if changed {
+		s.events.Publish(familyID, events.LogsUpdated, edit)
+		s.events.Publish(familyID, events.LeaderboardChanged, map[string]interface{}{"childId": childID})
+	}
+	return edit, nil
 }
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+)
+
+func TestSetDailyCountsReportsPointsDebited(t *testing.T) {
+	testDB(t)
+	familyID, _ := newTestFamily(t)
+	child := models.User{FamilyID: familyID, Role: "child", Name: "Adik", PointsBalance: 3}
+	if err := database.DB.Create(&child).Error; err != nil {
+		t.Fatal(err)
+	}
+	task := models.Task{FamilyID: familyID, Name: "Tadarus", PointReward: 5}
+	if err := database.DB.Create(&task).Error; err != nil {
+		t.Fatal(err)
+	}
+	// The child already spent part of what this completion earned.
+	date := time.Now().UTC().AddDate(0, 0, -1).Truncate(24 * time.Hour)
+	log := models.DailyLog{ChildID: child.ID, TaskID: task.ID, CompletedDate: date, Status: "verified", EarnedPoints: 5}
+	if err := database.DB.Create(&log).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	edit, err := NewLogService(nil).SetDailyCounts(familyID, child.ID, date, []DailyCount{{TaskID: task.ID, Count: 0}})
+	if err != nil {
+		t.Fatalf("set counts: %v", err)
+	}
+	if len(edit.Changes) != 1 || edit.Changes[0].Removed != 1 {
+		t.Fatalf("changes = %+v, want one removal", edit.Changes)
+	}
+	if got := edit.Changes[0].PointsDelta; got != -3 {
+		t.Errorf("pointsDelta = %d, want -3 (balance floored at 0)", got)
+	}
+	if edit.NewBalance != 0 {
+		t.Errorf("newBalance = %d, want 0", edit.NewBalance)
+	}
+}
@@ -0,0 +1,670 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/webpush"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Notification kinds; each maps to a preference toggle except test.
+const (
+	NotifyRedemption = "redemption"
+	NotifyApproval   = "approval"
+	NotifyMaghrib    = "maghrib"
+	NotifyStreak     = "streak"
+	NotifyTest       = "test"
+)
+
+const (
+	pushBatchSize   = 50
+	pushMaxAttempts = 5
+	pushLockFor     = 2 * time.Minute
+	pushDefaultTTL  = 24 * time.Hour
+)
+
+// Notification is one message for a user; it fans out to all their subscriptions.
+type Notification struct {
+	Kind    string
+	Title   string
+	Body    string
+	URL     string
+	Urgency string        // very-low, low, normal (default), high
+	TTL     time.Duration // dropped when it can't be delivered in time
+	// DedupeKey makes enqueueing idempotent per subscription, e.g. "maghrib:2026-03-01".
+	DedupeKey string
+}
+
+type pushPayload struct {
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+	Tag   string `json:"tag,omitempty"`
+}
+
+type NotificationService struct {
+	sender *webpush.Sender
+	kick   chan struct{}
+}
+
+// NewNotificationService takes the VAPID sender; with a nil sender push is
+// disabled and every Notify is a no-op.
+func NewNotificationService(sender *webpush.Sender) *NotificationService {
+	return &NotificationService{sender: sender, kick: make(chan struct{}, 1)}
+}
+
+// Enabled reports whether VAPID keys are configured.
+func (s *NotificationService) Enabled() bool {
+	return s != nil && s.sender != nil
+}
+
+// PublicKey is the applicationServerKey browsers subscribe with.
+func (s *NotificationService) PublicKey() (string, error) {
+	if !s.Enabled() {
+		return "", ErrPushNotConfigured
+	}
+	return s.sender.PublicKey, nil
+}
+
+// Start delivers freshly enqueued messages right away instead of waiting for
+// the push-outbox job. It returns when ctx is done.
+func (s *NotificationService) Start(ctx context.Context) {
+	if !s.Enabled() {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.kick:
+				if err := s.DeliverOutbox(ctx); err != nil {
+					log.Printf("push outbox: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *NotificationService) wake() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+type SubscriptionInput struct {
+	Endpoint string `json:"endpoint" validate:"required,max=1000"`
+	Keys     struct {
+		P256dh string `json:"p256dh" validate:"required"`
+		Auth   string `json:"auth" validate:"required"`
+	} `json:"keys" validate:"dive"`
+}
+
+// Subscribe stores a browser subscription. The endpoint is unique, so a
+// device that changes hands moves to the new user.
+func (s *NotificationService) Subscribe(familyID, userID, userAgent string, input SubscriptionInput) (*models.PushSubscription, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+	probe := webpush.Subscription{Endpoint: input.Endpoint}
+	probe.Keys.P256dh, probe.Keys.Auth = input.Keys.P256dh, input.Keys.Auth
+	if _, err := webpush.Encrypt(probe, nil); err != nil {
+		return nil, InvalidField("keys", "format", nil)
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	sub := models.PushSubscription{
+		UserID:    userID,
+		FamilyID:  familyID,
+		Endpoint:  input.Endpoint,
+		P256dh:    input.Keys.P256dh,
+		Auth:      input.Keys.Auth,
+		UserAgent: userAgent,
+	}
+	err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "family_id", "p256dh", "auth", "user_agent", "updated_at"}),
+	}).Create(&sub).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("endpoint = ?", input.Endpoint).First(&sub).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// Unsubscribe removes the user's subscription for endpoint and its queued messages.
+func (s *NotificationService) Unsubscribe(userID, endpoint string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var sub models.PushSubscription
+		if err := tx.Where("user_id = ? AND endpoint = ?", userID, endpoint).First(&sub).Error; err != nil {
+			return ErrSubscriptionNotFound
+		}
+		return removeSubscription(tx, sub.ID)
+	})
+}
+
+func removeSubscription(tx *gorm.DB, subscriptionID string) error {
+	if err := tx.Model(&models.PushMessage{}).
+		Where("subscription_id = ? AND status = 'pending'", subscriptionID).
+		Updates(map[string]interface{}{"status": "gone", "locked_until": nil}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.PushSubscription{}, "id = ?", subscriptionID).Error
+}
+
+func defaultPreferences(userID string) models.NotificationPreference {
+	return models.NotificationPreference{UserID: userID, Redemptions: true, Approvals: true, Maghrib: true, Streaks: true}
+}
+
+// GetPreferences returns the user's settings; everything is on until changed.
+func (s *NotificationService) GetPreferences(userID string) (*models.NotificationPreference, error) {
+	prefs := defaultPreferences(userID)
+	err := database.DB.Where("user_id = ?", userID).First(&prefs).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+type PreferencesInput struct {
+	Redemptions *bool   `json:"redemptions"`
+	Approvals   *bool   `json:"approvals"`
+	Maghrib     *bool   `json:"maghrib"`
+	Streaks     *bool   `json:"streaks"`
+	QuietStart  *string `json:"quietStart" validate:"time"` // HH:MM, "" clears quiet hours
+	QuietEnd    *string `json:"quietEnd" validate:"time"`
+}
+
+func (s *NotificationService) UpdatePreferences(userID string, input PreferencesInput) (*models.NotificationPreference, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+	prefs, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Redemptions != nil {
+		prefs.Redemptions = *input.Redemptions
+	}
+	if input.Approvals != nil {
+		prefs.Approvals = *input.Approvals
+	}
+	if input.Maghrib != nil {
+		prefs.Maghrib = *input.Maghrib
+	}
+	if input.Streaks != nil {
+		prefs.Streaks = *input.Streaks
+	}
+	if input.QuietStart != nil {
+		prefs.QuietStart = *input.QuietStart
+	}
+	if input.QuietEnd != nil {
+		prefs.QuietEnd = *input.QuietEnd
+	}
+
+	if (prefs.QuietStart == "") != (prefs.QuietEnd == "") {
+		return nil, InvalidField("quietStart", "together", map[string]interface{}{"other": "quietEnd"})
+	}
+
+	if err := database.DB.Save(prefs).Error; err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func kindEnabled(prefs *models.NotificationPreference, kind string) bool {
+	switch kind {
+	case NotifyRedemption:
+		return prefs.Redemptions
+	case NotifyApproval:
+		return prefs.Approvals
+	case NotifyMaghrib:
+		return prefs.Maghrib
+	case NotifyStreak:
+		return prefs.Streaks
+	}
+	return true
+}
+
+// quietUntil returns when the user's quiet hours end if now falls inside
+// them. Windows may wrap midnight, e.g. 21:00-05:00.
+func quietUntil(prefs *models.NotificationPreference, now time.Time, loc *time.Location) (time.Time, bool) {
+	if prefs.QuietStart == "" || prefs.QuietEnd == "" || prefs.QuietStart == prefs.QuietEnd {
+		return time.Time{}, false
+	}
+	start, err1 := time.Parse("15:04", prefs.QuietStart)
+	end, err2 := time.Parse("15:04", prefs.QuietEnd)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	at := func(t time.Time, days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, t.Hour(), t.Minute(), 0, 0, loc)
+	}
+	startToday, endToday := at(start, 0), at(end, 0)
+
+	if startToday.Before(endToday) {
+		if !local.Before(startToday) && local.Before(endToday) {
+			return endToday, true
+		}
+		return time.Time{}, false
+	}
+	if !local.Before(startToday) {
+		return at(end, 1), true
+	}
+	if local.Before(endToday) {
+		return endToday, true
+	}
+	return time.Time{}, false
+}
+
+// Notify queues n for every subscription of the user, honouring their
+// preferences and quiet hours. Delivery happens in the background.
+func (s *NotificationService) Notify(userID string, n Notification) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	prefs, err := s.GetPreferences(userID)
+	if err != nil {
+		return err
+	}
+	if !kindEnabled(prefs, n.Kind) {
+		return nil
+	}
+
+	var subs []models.PushSubscription
+	if err := database.DB.Where("user_id = ?", userID).Find(&subs).Error; err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	var family models.Family
+	if err := database.DB.Joins("JOIN users ON users.family_id = families.id").
+		Where("users.id = ?", userID).First(&family).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ttl := n.TTL
+	if ttl <= 0 {
+		ttl = pushDefaultTTL
+	}
+	expiresAt := now.Add(ttl)
+	nextAttempt := now
+	if until, quiet := quietUntil(prefs, now, utils.LoadLocation(family.Timezone)); quiet && n.Kind != NotifyTest {
+		if !until.Before(expiresAt) {
+			return nil // stale by the time quiet hours end
+		}
+		nextAttempt = until
+	}
+
+	tag := n.DedupeKey
+	if tag == "" {
+		tag = n.Kind
+	}
+	payload, err := json.Marshal(pushPayload{Kind: n.Kind, Title: n.Title, Body: n.Body, URL: n.URL, Tag: tag})
+	if err != nil {
+		return err
+	}
+
+	var dedupe *string
+	if n.DedupeKey != "" {
+		dedupe = &n.DedupeKey
+	}
+	messages := make([]models.PushMessage, 0, len(subs))
+	for _, sub := range subs {
+		messages = append(messages, models.PushMessage{
+			SubscriptionID: sub.ID,
+			UserID:         userID,
+			Kind:           n.Kind,
+			DedupeKey:      dedupe,
+			Payload:        string(payload),
+			Urgency:        n.Urgency,
+			Status:         "pending",
+			NextAttemptAt:  nextAttempt,
+			ExpiresAt:      expiresAt,
+		})
+	}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&messages).Error; err != nil {
+		return err
+	}
+
+	if !nextAttempt.After(now) {
+		s.wake()
+	}
+	return nil
+}
+
+// NotifyParents sends n to every parent of the family.
+func (s *NotificationService) NotifyParents(familyID string, n Notification) error {
+	if !s.Enabled() {
+		return nil
+	}
+	var parentIDs []string
+	if err := database.DB.Model(&models.User{}).
+		Where("family_id = ? AND role = 'parent'", familyID).Pluck("id", &parentIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range parentIDs {
+		if err := s.Notify(id, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendTest queues a test push to the caller's own devices.
+func (s *NotificationService) SendTest(userID string) (int64, error) {
+	if !s.Enabled() {
+		return 0, ErrPushNotConfigured
+	}
+	var count int64
+	if err := database.DB.Model(&models.PushSubscription{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, ErrNoPushSubscriptions
+	}
+	return count, s.Notify(userID, Notification{
+		Kind:  NotifyTest,
+		Title: "Notifikasi aktif 🔔",
+		Body:  "Perangkat ini akan menerima pengingat Ramadhan Ceria.",
+		TTL:   time.Hour,
+	})
+}
+
+// pushBackoff is 1, 2, 4, 8 minutes after the 1st..4th failure.
+func pushBackoff(attempts int) time.Duration {
+	return time.Minute << (attempts - 1)
+}
+
+// claimPushMessages locks a batch of due messages for this process. SKIP
+// LOCKED lets several API instances drain the outbox without double sends.
+func claimPushMessages(ctx context.Context, now time.Time) ([]models.PushMessage, error) {
+	var batch []models.PushMessage
+	err := database.DB.WithContext(ctx).Raw(`
+		UPDATE push_messages SET locked_until = @until
+		WHERE id IN (
+			SELECT id FROM push_messages
+			WHERE status = 'pending' AND next_attempt_at <= @now
+				AND (locked_until IS NULL OR locked_until < @now)
+			ORDER BY next_attempt_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		map[string]interface{}{"now": now, "until": now.Add(pushLockFor), "limit": pushBatchSize}).
+		Scan(&batch).Error
+	return batch, err
+}
+
+// DeliverOutbox sends every due message, retrying failures with exponential
+// backoff and dropping subscriptions the push service reports as gone.
+func (s *NotificationService) DeliverOutbox(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := claimPushMessages(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		subIDs := make([]string, 0, len(batch))
+		for _, m := range batch {
+			subIDs = append(subIDs, m.SubscriptionID)
+		}
+		var subs []models.PushSubscription
+		if err := database.DB.Where("id IN ?", subIDs).Find(&subs).Error; err != nil {
+			return err
+		}
+		byID := make(map[string]models.PushSubscription, len(subs))
+		for _, sub := range subs {
+			byID[sub.ID] = sub
+		}
+
+		for _, m := range batch {
+			if err := s.deliver(ctx, m, byID); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *NotificationService) deliver(ctx context.Context, m models.PushMessage, subs map[string]models.PushSubscription) error {
+	now := time.Now()
+	update := map[string]interface{}{"locked_until": nil}
+
+	sub, ok := subs[m.SubscriptionID]
+	switch {
+	case !ok:
+		update["status"] = "gone"
+	case !now.Before(m.ExpiresAt):
+		update["status"] = "expired"
+	default:
+		target := webpush.Subscription{Endpoint: sub.Endpoint}
+		target.Keys.P256dh, target.Keys.Auth = sub.P256dh, sub.Auth
+		err := s.sender.Send(ctx, target, []byte(m.Payload), webpush.Options{TTL: m.ExpiresAt.Sub(now), Urgency: m.Urgency})
+
+		var statusErr *webpush.StatusError
+		update["attempts"] = m.Attempts + 1
+		switch {
+		case err == nil:
+			update["status"] = "sent"
+			update["sent_at"] = now
+			update["last_error"] = ""
+			database.DB.Model(&models.PushSubscription{}).Where("id = ?", sub.ID).Update("last_success_at", now)
+		case errors.Is(err, webpush.ErrGone):
+			update["status"] = "gone"
+			update["last_error"] = err.Error()
+			if err := database.DB.Transaction(func(tx *gorm.DB) error { return removeSubscription(tx, sub.ID) }); err != nil {
+				return err
+			}
+		case ctx.Err() != nil:
+			return ctx.Err() // shutting down; the lock expires and another run retries
+		case errors.As(err, &statusErr) && !statusErr.Retryable(), errors.Is(err, webpush.ErrPayloadTooLarge),
+			m.Attempts+1 >= pushMaxAttempts:
+			update["status"] = "failed"
+			update["last_error"] = err.Error()
+		default:
+			update["next_attempt_at"] = now.Add(pushBackoff(m.Attempts + 1))
+			update["last_error"] = err.Error()
+		}
+	}
+	return database.DB.Model(&models.PushMessage{}).Where("id = ?", m.ID).Updates(update).Error
+}
+
+// PruneOutbox deletes finished messages older than keep.
+func (s *NotificationService) PruneOutbox(keep time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return database.DB.WithContext(ctx).
+			Where("status <> 'pending' AND created_at < ?", time.Now().Add(-keep)).
+			Delete(&models.PushMessage{}).Error
+	}
+}
+
+// pushFamilies are the families with at least one subscribed device.
+func pushFamilies(ctx context.Context) ([]models.Family, error) {
+	var families []models.Family
+	err := database.DB.WithContext(ctx).
+		Where("id IN (SELECT DISTINCT family_id FROM push_subscriptions)").
+		Find(&families).Error
+	return families, err
+}
+
+// inSeason reports whether date is within the family's Ramadhan season; a
+// family without a season gets reminders every day.
+func inSeason(family models.Family, date time.Time) bool {
+	if family.SeasonStart == nil || family.SeasonEnd == nil {
+		return true
+	}
+	return !date.Before(*family.SeasonStart) && !date.After(*family.SeasonEnd)
+}
+
+// maghribLead is how long before Maghrib the reminder goes out. The job runs
+// every 5 minutes, so the window is wider than the schedule step.
+const maghribLead = 10 * time.Minute
+
+// SendMaghribReminders reminds every family member shortly before Maghrib in
+// their timezone. The dedupe key keeps it to one per device per day.
+func (s *NotificationService) SendMaghribReminders(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+	families, err := pushFamilies(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, family := range families {
+		loc := utils.LoadLocation(family.Timezone)
+		today := utils.Today(loc)
+		if !inSeason(family, today) {
+			continue
+		}
+		maghrib := utils.MaghribTime(today, family.Timezone)
+		if now.Before(maghrib.Add(-maghribLead)) || !now.Before(maghrib) {
+			continue
+		}
+
+		var userIDs []string
+		if err := database.DB.Model(&models.User{}).Where("family_id = ?", family.ID).Pluck("id", &userIDs).Error; err != nil {
+			return err
+		}
+		for _, id := range userIDs {
+			err := s.Notify(id, Notification{
+				Kind:      NotifyMaghrib,
+				Title:     "Sebentar lagi Maghrib 🌙",
+				Body:      fmt.Sprintf("Waktu berbuka pukul %s. Jangan lupa doa berbuka puasa!", maghrib.Format("15:04")),
+				URL:       "/",
+				Urgency:   "high",
+				TTL:       maghrib.Add(15 * time.Minute).Sub(now),
+				DedupeKey: "maghrib:" + today.Format("2006-01-02"),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// streakWarningHour is the family-local hour when streak warnings go out.
+const streakWarningHour = 19
+
+type streakAtRisk struct {
+	ChildID string
+	Name    string
+	Length  int
+}
+
+// atRiskQuery finds children whose current run of active days (two or more)
+// ended yesterday: nothing completed yet today.
+const atRiskQuery = `
+	WITH active_days AS (
+		SELECT DISTINCT child_id, completed_date
+		FROM daily_logs
+		WHERE status = 'verified' AND deleted_at IS NULL
+			AND child_id IN (SELECT id FROM users WHERE family_id = @family AND role = 'child' AND deleted_at IS NULL)
+			AND completed_date <= @today
+	),
+	islands AS (
+		SELECT child_id, completed_date - (ROW_NUMBER() OVER (PARTITION BY child_id ORDER BY completed_date))::int AS grp,
+			completed_date
+		FROM active_days
+	),
+	runs AS (
+		SELECT child_id, MAX(completed_date) AS last_day, COUNT(*) AS length
+		FROM islands
+		GROUP BY child_id, grp
+	)
+	SELECT runs.child_id, users.name, runs.length
+	FROM runs JOIN users ON users.id = runs.child_id
+	WHERE runs.last_day = @yesterday::date AND runs.length >= 2`
+
+// SendStreakWarnings runs hourly and, at 19:00 family time, warns children
+// (and their parents) whose streak breaks unless they complete a task today.
+func (s *NotificationService) SendStreakWarnings(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+	families, err := pushFamilies(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, family := range families {
+		loc := utils.LoadLocation(family.Timezone)
+		local := now.In(loc)
+		if local.Hour() != streakWarningHour {
+			continue
+		}
+		today := utils.Today(loc)
+		if !inSeason(family, today) {
+			continue
+		}
+
+		var children []streakAtRisk
+		if err := database.DB.WithContext(ctx).Raw(atRiskQuery, map[string]interface{}{
+			"family":    family.ID,
+			"today":     today,
+			"yesterday": today.AddDate(0, 0, -1),
+		}).Scan(&children).Error; err != nil {
+			return err
+		}
+
+		midnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+		date := today.Format("2006-01-02")
+		for _, child := range children {
+			err := s.Notify(child.ChildID, Notification{
+				Kind:      NotifyStreak,
+				Title:     fmt.Sprintf("🔥 Streak %d hari hampir putus!", child.Length),
+				Body:      "Selesaikan satu misi hari ini supaya streak-mu tetap menyala.",
+				URL:       "/",
+				TTL:       midnight.Sub(now),
+				DedupeKey: "streak:" + child.ChildID + ":" + date,
+			})
+			if err != nil {
+				return err
+			}
+			err = s.NotifyParents(family.ID, Notification{
+				Kind:      NotifyStreak,
+				Title:     fmt.Sprintf("Streak %s hampir putus", child.Name),
+				Body:      fmt.Sprintf("%s belum menyelesaikan misi hari ini (streak %d hari).", child.Name, child.Length),
+				URL:       "/dashboard",
+				TTL:       midnight.Sub(now),
+				DedupeKey: "streak:" + child.ChildID + ":" + date,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
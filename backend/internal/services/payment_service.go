@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/payments"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PlanPackage is something a family can buy: Days of PREMIUM for Price rupiah.
+type PlanPackage struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Days  int    `json:"days"`
+	Price int64  `json:"price"`
+}
+
+var PlanPackages = []PlanPackage{
+	{Code: "premium-30", Name: "PREMIUM 1 Bulan", Days: 30, Price: 29000},
+	{Code: "premium-90", Name: "PREMIUM 3 Bulan", Days: 90, Price: 79000},
+	{Code: "premium-365", Name: "PREMIUM 1 Tahun", Days: 365, Price: 249000},
+}
+
+type PaymentService struct {
+	midtrans *payments.Client
+}
+
+func NewPaymentService(midtrans *payments.Client) *PaymentService {
+	return &PaymentService{midtrans: midtrans}
+}
+
+func (s *PaymentService) GetPackages() []PlanPackage {
+	return PlanPackages
+}
+
+func findPackage(code string) (PlanPackage, bool) {
+	for _, p := range PlanPackages {
+		if p.Code == code {
+			return p, true
+		}
+	}
+	return PlanPackage{}, false
+}
+
+func newOrderID() (string, error) {
+	suffix, err := utils.RandomHex(5)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("RC-%s-%s", time.Now().Format("20060102"), strings.ToUpper(suffix)), nil
+}
+
+// Checkout creates a pending payment and a Snap transaction for it. A coupon
+// is reserved for the payment; one that makes the package free settles at once.
+func (s *PaymentService) Checkout(ctx context.Context, familyID, userID, packageCode, couponCode string) (*models.Payment, error) {
+	if s.midtrans.ServerKey == "" {
+		return nil, ErrPaymentsNotConfigured
+	}
+	pkg, ok := findPackage(packageCode)
+	if !ok {
+		return nil, ErrPackageNotFound
+	}
+
+	var parent models.User
+	if err := database.DB.Where("id = ? AND family_id = ? AND role = 'parent'", userID, familyID).First(&parent).Error; err != nil {
+		return nil, ErrParentPaymentOnly
+	}
+
+	orderID, err := newOrderID()
+	if err != nil {
+		return nil, err
+	}
+
+	payment := models.Payment{
+		FamilyID:    familyID,
+		OrderID:     orderID,
+		PackageCode: pkg.Code,
+		Days:        pkg.Days,
+		Amount:      pkg.Price,
+		Status:      "pending",
+		RequestedBy: &userID,
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var redemption *models.CouponRedemption
+	if couponCode != "" {
+		if redemption, err = reserveCoupon(tx, couponCode, familyID, pkg.Price); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		payment.CouponCode = normalizeCode(couponCode)
+		payment.Discount = redemption.Discount
+		payment.Amount -= redemption.Discount
+	}
+	if err := tx.Create(&payment).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if redemption != nil {
+		if err := tx.Model(redemption).Update("payment_id", payment.ID).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if payment.Amount == 0 {
+		// Nothing to charge, so Midtrans is not involved.
+		now := time.Now()
+		payment.Status = "paid"
+		payment.PaidAt = &now
+		if err := settlePayment(tx, &payment); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Model(&payment).Updates(map[string]interface{}{"status": "paid", "paid_at": now}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		return &payment, tx.Commit().Error
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	customer := &payments.CustomerDetails{FirstName: parent.Name}
+	if parent.Email != nil {
+		customer.Email = *parent.Email
+	}
+	if parent.Whatsapp != nil {
+		customer.Phone = *parent.Whatsapp
+	}
+
+	snap, err := s.midtrans.CreateSnapTransaction(ctx, payments.SnapRequest{
+		TransactionDetails: payments.TransactionDetails{OrderID: payment.OrderID, GrossAmount: payment.Amount},
+		ItemDetails:        itemDetails(pkg, &payment),
+		CustomerDetails:    customer,
+		Expiry:             &payments.Expiry{Unit: "hour", Duration: 24},
+	})
+	if err != nil {
+		log.Printf("midtrans checkout for %s failed: %v", payment.OrderID, err)
+		database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&payment).Update("status", "failed").Error; err != nil {
+				return err
+			}
+			return releaseCoupon(tx, payment.ID)
+		})
+		return nil, ErrPaymentGatewayDown
+	}
+
+	payment.SnapToken = snap.Token
+	payment.RedirectURL = snap.RedirectURL
+	if err := database.DB.Model(&payment).Updates(map[string]interface{}{
+		"snap_token":   snap.Token,
+		"redirect_url": snap.RedirectURL,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// itemDetails lists the package and, when a coupon applies, a negative line
+// for the discount. Midtrans requires the items to add up to gross_amount.
+func itemDetails(pkg PlanPackage, payment *models.Payment) []payments.ItemDetail {
+	items := []payments.ItemDetail{{ID: pkg.Code, Price: pkg.Price, Quantity: 1, Name: pkg.Name}}
+	if payment.Discount > 0 {
+		items = append(items, payments.ItemDetail{ID: "coupon", Price: -payment.Discount, Quantity: 1, Name: "Kupon " + payment.CouponCode})
+	}
+	return items
+}
+
+// settlePayment grants what a paid payment bought: the PREMIUM days, the
+// coupon and, on a first payment, the referral bonus.
+func settlePayment(tx *gorm.DB, payment *models.Payment) error {
+	if _, err := ExtendPremium(tx, payment.FamilyID, payment.Days); err != nil {
+		return err
+	}
+	if err := confirmCoupon(tx, payment); err != nil {
+		return err
+	}
+	return rewardReferral(tx, payment.FamilyID)
+}
+
+// refundPayment takes back everything settlePayment granted: the PREMIUM
+// days, the coupon and the referral bonus if this payment earned it.
+func refundPayment(tx *gorm.DB, payment *models.Payment) error {
+	if err := RevokePremium(tx, payment.FamilyID, payment.Days); err != nil {
+		return err
+	}
+	if err := revokeCoupon(tx, payment.ID); err != nil {
+		return err
+	}
+	return revokeReferral(tx, payment.FamilyID, payment.ID)
+}
+
+func (s *PaymentService) GetPayments(familyID string) ([]models.Payment, error) {
+	var list []models.Payment
+	err := database.DB.Where("family_id = ?", familyID).Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (s *PaymentService) GetPayment(familyID, orderID string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := database.DB.Where("family_id = ? AND order_id = ?", familyID, orderID).First(&payment).Error; err != nil {
+		return nil, ErrPaymentNotFound
+	}
+	return &payment, nil
+}
+
+// paymentStatus maps a Midtrans transaction_status to our status. An empty
+// result means the notification does not change anything.
+func paymentStatus(n payments.Notification) string {
+	switch n.TransactionStatus {
+	case "settlement":
+		return "paid"
+	case "capture":
+		// Card payments: only an accepted capture is money we keep.
+		if n.FraudStatus == "" || n.FraudStatus == "accept" {
+			return "paid"
+		}
+		return "pending"
+	case "pending":
+		return "pending"
+	case "deny", "cancel", "failure":
+		return "failed"
+	case "expire":
+		return "expired"
+	case "refund", "chargeback":
+		return "refunded"
+	}
+	return "" // partial_refund, authorize, … are only recorded
+}
+
+// HandleNotification applies a Midtrans webhook call. Midtrans redelivers
+// notifications and may send them out of order, so every transition is
+// checked against the stored status: PREMIUM, the coupon and the referral
+// bonus are granted once per order and taken back once on refund.
+func (s *PaymentService) HandleNotification(n payments.Notification, payload []byte) error {
+	if !s.midtrans.VerifySignature(n) {
+		return ErrInvalidSignature
+	}
+
+	database.DB.Create(&models.PaymentNotification{
+		OrderID:           n.OrderID,
+		TransactionStatus: n.TransactionStatus,
+		StatusCode:        n.StatusCode,
+		FraudStatus:       n.FraudStatus,
+		Payload:           string(payload),
+	})
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var payment models.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_id = ?", n.OrderID).First(&payment).Error; err != nil {
+		tx.Rollback()
+		return ErrPaymentNotFound
+	}
+
+	gross, err := strconv.ParseFloat(n.GrossAmount, 64)
+	if err != nil || int64(math.Round(gross)) != payment.Amount {
+		tx.Rollback()
+		return ErrAmountMismatch
+	}
+
+	updates := map[string]interface{}{}
+	if n.PaymentType != "" {
+		updates["payment_type"] = n.PaymentType
+	}
+	if n.TransactionID != "" {
+		updates["transaction_id"] = n.TransactionID
+	}
+
+	switch status := paymentStatus(n); {
+	case status == "paid" && (payment.Status == "pending" || payment.Status == "failed" || payment.Status == "expired"):
+		if err := settlePayment(tx, &payment); err != nil {
+			tx.Rollback()
+			return err
+		}
+		updates["status"] = "paid"
+		updates["paid_at"] = time.Now()
+	case status == "refunded" && payment.Status == "paid":
+		if err := refundPayment(tx, &payment); err != nil {
+			tx.Rollback()
+			return err
+		}
+		updates["status"] = "refunded"
+		updates["refunded_at"] = time.Now()
+	case (status == "failed" || status == "expired") && payment.Status == "pending":
+		if err := releaseCoupon(tx, payment.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		updates["status"] = status
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&payment).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/migrations"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/payments"
+	"github.com/username/ramadhan-ceria-backend/internal/payments/fakemidtrans"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testServerKey = "SB-Mid-server-test"
+
+var migrateOnce sync.Once
+
+// testDB points database.DB at the Postgres in TEST_DATABASE_DSN, migrated
+// to the latest schema. Tests that need it are skipped when it is unset:
+//
+//	TEST_DATABASE_DSN="host=localhost user=postgres dbname=ramadhan_test sslmode=disable" go test ./internal/services/
+func testDB(t *testing.T) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	var err error
+	migrateOnce.Do(func() {
+		database.DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil {
+			return
+		}
+		sqlDB, e := database.DB.DB()
+		if e != nil {
+			err = e
+			return
+		}
+		migrator, e := migrations.New(sqlDB)
+		if e != nil {
+			err = e
+			return
+		}
+		_, err = migrator.Up(context.Background())
+	})
+	if err != nil {
+		t.Fatalf("test database: %v", err)
+	}
+	if database.DB == nil {
+		t.Fatal("test database failed to open in an earlier test")
+	}
+}
+
+// paymentEnv is a PaymentService wired to fakemidtrans, whose notifications
+// go to a local webhook that calls HandleNotification like the controller.
+type paymentEnv struct {
+	service *PaymentService
+	fake    *fakemidtrans.Server
+}
+
+func newPaymentEnv(t *testing.T) *paymentEnv {
+	t.Helper()
+	env := &paymentEnv{}
+
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		var n payments.Notification
+		if err == nil {
+			err = json.Unmarshal(body, &n)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := env.service.HandleNotification(n, body); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrInvalidSignature) {
+				status = http.StatusUnauthorized
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.Write([]byte(`{"message":"OK"}`))
+	}))
+	t.Cleanup(webhook.Close)
+
+	env.fake = fakemidtrans.New(testServerKey, "", webhook.URL)
+	gateway := httptest.NewServer(env.fake.Handler())
+	t.Cleanup(gateway.Close)
+	env.fake.BaseURL = gateway.URL
+
+	env.service = NewPaymentService(payments.NewClient(config.Midtrans{
+		ServerKey: testServerKey,
+		SnapURL:   gateway.URL,
+		APIURL:    gateway.URL,
+	}))
+	return env
+}
+
+// notify makes fakemidtrans send a notification and expects the webhook to accept it.
+func (env *paymentEnv) notify(t *testing.T, orderID, status string) {
+	t.Helper()
+	code, err := env.fake.Notify(context.Background(), orderID, status)
+	if err != nil {
+		t.Fatalf("notify %s: %v", status, err)
+	}
+	if code != http.StatusOK {
+		t.Fatalf("webhook answered %d to %s", code, status)
+	}
+}
+
+// newTestFamily creates a FREE family with one parent and returns both ids.
+func newTestFamily(t *testing.T) (familyID, parentID string) {
+	t.Helper()
+	family := models.Family{Name: "Keluarga Uji"}
+	if err := database.DB.Create(&family).Error; err != nil {
+		t.Fatal(err)
+	}
+	parent := models.User{FamilyID: family.ID, Role: "parent", Name: "Ayah"}
+	if err := database.DB.Create(&parent).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Unscoped().Delete(&models.Family{}, "id = ?", family.ID) })
+	return family.ID, parent.ID
+}
+
+func loadFamily(t *testing.T, id string) models.Family {
+	t.Helper()
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return family
+}
+
+func loadPayment(t *testing.T, id string) models.Payment {
+	t.Helper()
+	var payment models.Payment
+	if err := database.DB.First(&payment, "id = ?", id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return payment
+}
+
+// premiumDaysLeft rounds the time until the family's PREMIUM ends to whole days.
+func premiumDaysLeft(family models.Family) int {
+	if family.Plan != PlanPremium || family.PlanExpiresAt == nil {
+		return 0
+	}
+	return int(time.Until(*family.PlanExpiresAt).Round(24*time.Hour) / (24 * time.Hour))
+}
+
+func TestHandleNotificationRejectsBadSignature(t *testing.T) {
+	service := NewPaymentService(payments.NewClient(config.Midtrans{ServerKey: testServerKey}))
+	n := payments.Notification{
+		OrderID:           "RC-20260101-ABCDEF1234",
+		StatusCode:        "200",
+		GrossAmount:       "29000.00",
+		TransactionStatus: "settlement",
+	}
+
+	n.SignatureKey = payments.Signature(n.OrderID, n.StatusCode, n.GrossAmount, "SB-Mid-server-other")
+	if err := service.HandleNotification(n, nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("foreign key: err = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	n.SignatureKey = payments.Signature(n.OrderID, n.StatusCode, "1.00", testServerKey)
+	if err := service.HandleNotification(n, nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("changed amount: err = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestCheckoutCreatesSnapTransaction(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+	familyID, parentID := newTestFamily(t)
+
+	payment, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-30", "")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if payment.Status != "pending" || payment.Amount != 29000 || payment.Days != 30 {
+		t.Errorf("payment = %s Rp%d %d days", payment.Status, payment.Amount, payment.Days)
+	}
+	if payment.SnapToken == "" || !strings.HasPrefix(payment.RedirectURL, env.fake.BaseURL+"/snap/v2/vtweb/") {
+		t.Errorf("snap token %q, redirect %q", payment.SnapToken, payment.RedirectURL)
+	}
+	stored := loadPayment(t, payment.ID)
+	if stored.SnapToken != payment.SnapToken {
+		t.Errorf("stored snap token = %q", stored.SnapToken)
+	}
+}
+
+func TestNotificationSettlesOnce(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+	familyID, parentID := newTestFamily(t)
+
+	payment, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-30", "")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	env.notify(t, payment.OrderID, "settlement")
+	if got := loadPayment(t, payment.ID); got.Status != "paid" || got.PaidAt == nil || got.PaymentType == "" {
+		t.Fatalf("after settlement: status %s, paid_at %v, type %q", got.Status, got.PaidAt, got.PaymentType)
+	}
+	settled := loadFamily(t, familyID)
+	if days := premiumDaysLeft(settled); days != 30 {
+		t.Fatalf("PREMIUM days after settlement = %d, want 30", days)
+	}
+
+	// Midtrans redelivers and may send stale statuses afterwards.
+	env.notify(t, payment.OrderID, "settlement")
+	env.notify(t, payment.OrderID, "pending")
+	env.notify(t, payment.OrderID, "expire")
+
+	if got := loadPayment(t, payment.ID); got.Status != "paid" {
+		t.Errorf("status after replays = %s, want paid", got.Status)
+	}
+	if got := loadFamily(t, familyID); !got.PlanExpiresAt.Equal(*settled.PlanExpiresAt) {
+		t.Errorf("replay moved plan_expires_at from %v to %v", settled.PlanExpiresAt, got.PlanExpiresAt)
+	}
+	var received int64
+	database.DB.Model(&models.PaymentNotification{}).Where("order_id = ?", payment.OrderID).Count(&received)
+	if received != 4 {
+		t.Errorf("logged %d notifications, want 4", received)
+	}
+}
+
+func TestNotificationAmountMismatch(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+	familyID, parentID := newTestFamily(t)
+
+	payment, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-30", "")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	// Correctly signed, but for a different amount than the order.
+	n, err := env.fake.SetStatus(payment.OrderID, "settlement")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.GrossAmount = "1000.00"
+	n.SignatureKey = payments.Signature(n.OrderID, n.StatusCode, n.GrossAmount, testServerKey)
+	if err := env.service.HandleNotification(*n, nil); !errors.Is(err, ErrAmountMismatch) {
+		t.Fatalf("err = %v, want %v", err, ErrAmountMismatch)
+	}
+
+	if got := loadPayment(t, payment.ID); got.Status != "pending" {
+		t.Errorf("status = %s, want pending", got.Status)
+	}
+	if got := loadFamily(t, familyID); got.Plan != PlanFree {
+		t.Errorf("plan = %s, want %s", got.Plan, PlanFree)
+	}
+}
+
+func TestNotificationUnknownOrder(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+
+	n := payments.Notification{OrderID: "RC-19990101-NOSUCHORDER", StatusCode: "200", GrossAmount: "29000.00", TransactionStatus: "settlement"}
+	n.SignatureKey = payments.Signature(n.OrderID, n.StatusCode, n.GrossAmount, testServerKey)
+	if err := env.service.HandleNotification(n, nil); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("err = %v, want %v", err, ErrPaymentNotFound)
+	}
+}
+
+func TestRefundReversesSettlement(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+	referrerID, _ := newTestFamily(t)
+	familyID, parentID := newTestFamily(t)
+
+	if err := CreateReferral(database.DB, referrerID, familyID); err != nil {
+		t.Fatal(err)
+	}
+	suffix, _ := utils.RandomHex(4)
+	coupon := models.Coupon{Code: "UJI" + strings.ToUpper(suffix), Kind: CouponPercent, Value: 10, IsActive: true}
+	if err := database.DB.Create(&coupon).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Delete(&coupon) })
+
+	payment, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-30", coupon.Code)
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if payment.Amount != 26100 {
+		t.Fatalf("amount with coupon = %d, want 26100", payment.Amount)
+	}
+
+	env.notify(t, payment.OrderID, "settlement")
+	if days := premiumDaysLeft(loadFamily(t, familyID)); days != 30+ReferralBonusDays {
+		t.Fatalf("invitee PREMIUM days = %d, want %d", days, 30+ReferralBonusDays)
+	}
+	if days := premiumDaysLeft(loadFamily(t, referrerID)); days != ReferralBonusDays {
+		t.Fatalf("referrer PREMIUM days = %d, want %d", days, ReferralBonusDays)
+	}
+
+	env.notify(t, payment.OrderID, "refund")
+	env.notify(t, payment.OrderID, "refund") // redelivered
+
+	got := loadPayment(t, payment.ID)
+	if got.Status != "refunded" || got.RefundedAt == nil {
+		t.Errorf("payment = %s, refunded_at %v", got.Status, got.RefundedAt)
+	}
+	for name, id := range map[string]string{"invitee": familyID, "referrer": referrerID} {
+		if family := loadFamily(t, id); family.Plan != PlanFree {
+			t.Errorf("%s plan = %s with %d days left, want %s", name, family.Plan, premiumDaysLeft(family), PlanFree)
+		}
+	}
+
+	var redemptions int64
+	database.DB.Model(&models.CouponRedemption{}).Where("coupon_id = ?", coupon.ID).Count(&redemptions)
+	if redemptions != 0 {
+		t.Errorf("%d coupon redemptions left, want 0", redemptions)
+	}
+
+	var referral models.Referral
+	if err := database.DB.First(&referral, "invitee_family_id = ?", familyID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if referral.Status != "pending" || referral.BonusDays != 0 || referral.RewardedAt != nil {
+		t.Errorf("referral = %s, %d bonus days, rewarded_at %v", referral.Status, referral.BonusDays, referral.RewardedAt)
+	}
+}
+
+func TestRefundKeepsReferralEarnedEarlier(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+	referrerID, _ := newTestFamily(t)
+	familyID, parentID := newTestFamily(t)
+
+	if err := CreateReferral(database.DB, referrerID, familyID); err != nil {
+		t.Fatal(err)
+	}
+	first, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-30", "")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	env.notify(t, first.OrderID, "settlement")
+	second, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-90", "")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	env.notify(t, second.OrderID, "settlement")
+
+	env.notify(t, second.OrderID, "refund")
+
+	if days := premiumDaysLeft(loadFamily(t, familyID)); days != 30+ReferralBonusDays {
+		t.Errorf("invitee PREMIUM days = %d, want %d", days, 30+ReferralBonusDays)
+	}
+	if days := premiumDaysLeft(loadFamily(t, referrerID)); days != ReferralBonusDays {
+		t.Errorf("referrer PREMIUM days = %d, want %d", days, ReferralBonusDays)
+	}
+	var referral models.Referral
+	database.DB.First(&referral, "invitee_family_id = ?", familyID)
+	if referral.Status != "rewarded" {
+		t.Errorf("referral = %s, want rewarded", referral.Status)
+	}
+}
+
+func TestLateSettlementRecordsCouponUse(t *testing.T) {
+	testDB(t)
+	env := newPaymentEnv(t)
+	familyID, parentID := newTestFamily(t)
+
+	suffix, _ := utils.RandomHex(4)
+	maxUses := 1
+	coupon := models.Coupon{Code: "UJI" + strings.ToUpper(suffix), Kind: CouponPercent, Value: 10, IsActive: true, MaxUses: &maxUses}
+	if err := database.DB.Create(&coupon).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Delete(&coupon) })
+
+	payment, err := env.service.Checkout(context.Background(), familyID, parentID, "premium-30", coupon.Code)
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	// The payment expires, freeing the coupon, then settles after all.
+	env.notify(t, payment.OrderID, "expire")
+	env.notify(t, payment.OrderID, "settlement")
+
+	if got := loadPayment(t, payment.ID); got.Status != "paid" {
+		t.Fatalf("status = %s, want paid", got.Status)
+	}
+	var redemption models.CouponRedemption
+	if err := database.DB.First(&redemption, "payment_id = ?", payment.ID).Error; err != nil {
+		t.Fatalf("no coupon use recorded: %v", err)
+	}
+	if redemption.Status != "used" || redemption.Discount != payment.Discount {
+		t.Errorf("redemption = %s, Rp%d off; want used, Rp%d", redemption.Status, redemption.Discount, payment.Discount)
+	}
+
+	otherID, otherParentID := newTestFamily(t)
+	if _, err := env.service.Checkout(context.Background(), otherID, otherParentID, "premium-30", coupon.Code); !errors.Is(err, ErrCouponLimitReached) {
+		t.Errorf("second family's checkout: err = %v, want %v", err, ErrCouponLimitReached)
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/mailer"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
+	"gorm.io/gorm"
+)
+
+// DefaultPremiumDays is the length of an upgrade when no expiry is given.
+const DefaultPremiumDays = 30
+
+type PlanService struct {
+	mailer   mailer.Mailer
+	whatsapp *WhatsappService
+}
+
+func NewPlanService(m mailer.Mailer, wa *WhatsappService) *PlanService {
+	return &PlanService{mailer: m, whatsapp: wa}
+}
+
+type PlanStatus struct {
+	Plan          string           `json:"plan"`
+	IsPremium     bool             `json:"isPremium"` // PREMIUM features unlocked right now
+	ExpiresAt     *time.Time       `json:"expiresAt"`
+	GraceUntil    *time.Time       `json:"graceUntil"`
+	InGracePeriod bool             `json:"inGracePeriod"`
+	DaysLeft      *int             `json:"daysLeft"`
+	Limits        map[string]int   `json:"limits,omitempty"` // limits of the plan in effect; absent = unlimited
+	Usage         map[string]int64 `json:"usage"`
+	ReadOnly      *ReadOnlySet     `json:"readOnly"`
+}
+
+// GetPlanStatus describes the family's plan, its expiry and what is read-only.
+func (s *PlanService) GetPlanStatus(familyID string) (*PlanStatus, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	plan, err := EffectivePlan(family)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &PlanStatus{
+		Plan:      family.Plan,
+		IsPremium: IsPremium(family),
+		Usage:     map[string]int64{},
+	}
+	if family.Plan != PlanFree && family.PlanExpiresAt != nil {
+		grace := family.PlanExpiresAt.Add(PlanGracePeriod)
+		days := int(time.Until(*family.PlanExpiresAt).Hours() / 24)
+		if days < 0 {
+			days = 0
+		}
+		status.ExpiresAt = family.PlanExpiresAt
+		status.GraceUntil = &grace
+		status.InGracePeriod = time.Now().After(*family.PlanExpiresAt) && PlanActive(family)
+		status.DaysLeft = &days
+	}
+	if len(plan.Limits) > 0 {
+		status.Limits = plan.Limits
+	}
+
+	for _, limit := range knownLimits {
+		count, err := countLimit(familyID, limit)
+		if err != nil {
+			return nil, err
+		}
+		status.Usage[limit] = count
+	}
+
+	if status.ReadOnly, err = readOnlyItems(familyID, plan); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// SetPlan is the super admin override. Moving to any plan but FREE without
+// expiresAt grants DefaultPremiumDays from now; FREE clears the expiry.
+func (s *PlanService) SetPlan(familyID, plan string, expiresAt *time.Time) (*models.Family, error) {
+	plan = strings.ToUpper(strings.TrimSpace(plan))
+	if _, err := FindPlan(plan); err != nil {
+		return nil, err
+	}
+
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	updates := map[string]interface{}{"plan": plan, "plan_reminder": ""}
+	if plan != PlanFree {
+		if expiresAt == nil {
+			t := time.Now().AddDate(0, 0, DefaultPremiumDays)
+			expiresAt = &t
+		}
+		if !expiresAt.After(time.Now()) {
+			return nil, InvalidField("expiresAt", "future", nil)
+		}
+		updates["plan_expires_at"] = *expiresAt
+	} else {
+		updates["plan_expires_at"] = nil
+	}
+
+	if err := database.DB.Model(&family).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, err
+	}
+	return &family, nil
+}
+
+// ExtendPremium adds days of PREMIUM, counted from the current expiry when the
+// family is still premium (grace included) and from now otherwise. db may be a transaction.
+func ExtendPremium(db *gorm.DB, familyID string, days int) (*models.Family, error) {
+	if days <= 0 {
+		return nil, InvalidField("days", "positive", nil)
+	}
+
+	var family models.Family
+	if err := db.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	if family.Plan == PlanPremium && family.PlanExpiresAt == nil {
+		// Never-expiring premium stays that way.
+		return &family, nil
+	}
+	from := time.Now()
+	if IsPremium(family) && family.PlanExpiresAt.After(from) {
+		from = *family.PlanExpiresAt
+	}
+	expiresAt := from.AddDate(0, 0, days)
+
+	err := db.Model(&family).Updates(map[string]interface{}{
+		"plan":            PlanPremium,
+		"plan_expires_at": expiresAt,
+		"plan_reminder":   "",
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	family.Plan = PlanPremium
+	family.PlanExpiresAt = &expiresAt
+	family.PlanReminder = ""
+	return &family, nil
+}
+
+func parentEmails(familyID string) ([]string, error) {
+	var emails []string
+	err := database.DB.Model(&models.User{}).
+		Where("family_id = ? AND role = 'parent' AND email IS NOT NULL", familyID).
+		Pluck("email", &emails).Error
+	return emails, err
+}
+
+// reminderStage is the notice a family on an expiring plan should have received by now.
+// Stages only move forward: "" → 7d → 1d → grace.
+func reminderStage(expiresAt, now time.Time) string {
+	switch {
+	case now.After(expiresAt):
+		return "grace"
+	case expiresAt.Sub(now) <= 24*time.Hour:
+		return "1d"
+	case expiresAt.Sub(now) <= 7*24*time.Hour:
+		return "7d"
+	}
+	return ""
+}
+
+var reminderRank = map[string]int{"": 0, "7d": 1, "1d": 2, "grace": 3, "downgraded": 4}
+
+func (s *PlanService) notify(ctx context.Context, family models.Family, subject, body string) error {
+	to, err := parentEmails(family.ID)
+	if err != nil || len(to) == 0 {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      to,
+		Subject: subject,
+		HTML:    fmt.Sprintf("<p>Assalamu'alaikum keluarga %s,</p>%s", family.Name, body),
+	})
+}
+
+// SendExpiryReminders e-mails parents 7 days and 1 day before their plan
+// expires and once more when the grace period starts; opted-in parents also
+// get it on WhatsApp.
+func (s *PlanService) SendExpiryReminders(ctx context.Context) error {
+	now := time.Now()
+	var families []models.Family
+	err := database.DB.
+		Where("plan <> ? AND plan_expires_at IS NOT NULL AND plan_expires_at <= ?", PlanFree, now.Add(7*24*time.Hour)).
+		Find(&families).Error
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		stage := reminderStage(*family.PlanExpiresAt, now)
+		if reminderRank[stage] <= reminderRank[family.PlanReminder] {
+			continue
+		}
+
+		loc := utils.LoadLocation(family.Timezone)
+		expires := family.PlanExpiresAt.In(loc).Format("02-01-2006 15:04")
+		grace := family.PlanExpiresAt.Add(PlanGracePeriod).In(loc).Format("02-01-2006")
+
+		var subject, body, next string
+		if stage == "grace" {
+			subject = fmt.Sprintf("Paket %s sudah berakhir", family.Plan)
+			body = fmt.Sprintf(`<p>Paket %s keluarga Anda berakhir pada %s. Semua fitur masih aktif sampai %s.
+Setelah itu akun kembali ke paket FREE: data tidak dihapus, tetapi anak, misi dan hadiah di atas batas FREE hanya bisa dilihat.</p>`, family.Plan, expires, grace)
+			next = fmt.Sprintf("Semua fitur masih aktif sampai %s, setelah itu akun kembali ke paket FREE.", grace)
+		} else {
+			subject = fmt.Sprintf("Paket %s segera berakhir", family.Plan)
+			body = fmt.Sprintf(`<p>Paket %s keluarga Anda akan berakhir pada %s. Perpanjang sekarang agar fiturnya tetap bisa dinikmati.</p>`, family.Plan, expires)
+			next = "Perpanjang sekarang agar fiturnya tetap bisa dinikmati."
+		}
+
+		if err := s.notify(ctx, family, subject, body); err != nil {
+			log.Printf("plan reminder for family %s failed: %v", family.ID, err)
+			continue
+		}
+		database.DB.Model(&family).Update("plan_reminder", stage)
+
+		params := []string{family.Plan, family.Name, expires, next}
+		dedupe := fmt.Sprintf("plan:%s:%s", stage, family.PlanExpiresAt.Format("2006-01-02"))
+		if err := s.whatsapp.NotifyParents(ctx, family.ID, whatsapp.TemplatePlanExpiry, params, dedupe); err != nil {
+			log.Printf("plan reminder WhatsApp for family %s failed: %v", family.ID, err)
+		}
+	}
+	return nil
+}
+
+// DowngradeExpired moves families whose grace period is over back to FREE.
+// Nothing is deleted; excess items become read-only through ReadOnlyItems.
+func (s *PlanService) DowngradeExpired(ctx context.Context) (int, error) {
+	var families []models.Family
+	err := database.DB.
+		Where("plan <> ? AND plan_expires_at IS NOT NULL AND plan_expires_at < ?", PlanFree, time.Now().Add(-PlanGracePeriod)).
+		Find(&families).Error
+	if err != nil {
+		return 0, err
+	}
+
+	downgraded := 0
+	for _, family := range families {
+		result := database.DB.Model(&models.Family{}).
+			Where("id = ? AND plan = ? AND plan_expires_at = ?", family.ID, family.Plan, family.PlanExpiresAt).
+			Updates(map[string]interface{}{"plan": PlanFree, "plan_reminder": "downgraded"})
+		if result.Error != nil {
+			return downgraded, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue // renewed in the meantime
+		}
+		downgraded++
+
+		err := s.notify(ctx, family, "Akun kembali ke paket FREE", `<p>Masa tenggang paket berbayar sudah lewat, sehingga akun keluarga kembali ke paket FREE.
+Semua data tetap tersimpan. Anak, misi dan hadiah di atas batas FREE kini hanya bisa dilihat sampai Anda upgrade lagi.</p>`)
+		if err != nil {
+			log.Printf("downgrade notice for family %s failed: %v", family.ID, err)
+		}
+	}
+	return downgraded, nil
+}
+
+// RevokePremium takes back days of PREMIUM, e.g. after a refund. When nothing
+// is left the family drops to FREE at once, without a grace period.
+func RevokePremium(db *gorm.DB, familyID string, days int) error {
+	var family models.Family
+	if err := db.First(&family, "id = ?", familyID).Error; err != nil {
+		return ErrFamilyNotFound
+	}
+	if family.Plan != PlanPremium || family.PlanExpiresAt == nil {
+		return nil
+	}
+
+	expiresAt := family.PlanExpiresAt.AddDate(0, 0, -days)
+	updates := map[string]interface{}{"plan_expires_at": expiresAt}
+	if !expiresAt.After(time.Now()) {
+		updates["plan"] = PlanFree
+		updates["plan_reminder"] = "downgraded"
+	}
+	return db.Model(&family).Updates(updates).Error
+}
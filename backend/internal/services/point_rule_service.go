@@ -0,0 +1,217 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PointRuleService struct{}
+
+func NewPointRuleService() *PointRuleService {
+	return &PointRuleService{}
+}
+
+type PointRuleRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	RuleType    string  `json:"ruleType" validate:"required,oneof=multiplier bonus"`
+	Multiplier  float64 `json:"multiplier"`  // multiplier rules, above 1 up to 10
+	BonusPoints int     `json:"bonusPoints"` // bonus rules, at least 1
+	TaskID      *string `json:"taskId" validate:"uuid"`
+	ChildID     *string `json:"childId" validate:"uuid"`
+	StartDate   string  `json:"startDate" validate:"date"`                  // YYYY-MM-DD, optional
+	EndDate     string  `json:"endDate" validate:"date"`                    // YYYY-MM-DD, optional
+	Weekdays    []int   `json:"weekdays" validate:"max=7,dive,min=0,max=6"` // 0=Sunday … 6=Saturday, optional
+	IsActive    *bool   `json:"isActive"`
+}
+
+func (r PointRuleRequest) Check(v *Violations) {
+	switch r.RuleType {
+	case "multiplier":
+		if r.Multiplier <= 1 || r.Multiplier > 10 {
+			v.Add("multiplier", "between", map[string]interface{}{"min": 1, "max": 10})
+		}
+	case "bonus":
+		if r.BonusPoints <= 0 {
+			v.Add("bonusPoints", "positive", nil)
+		}
+	}
+	if r.StartDate != "" && r.EndDate != "" && !v.Has("startDate") && !v.Has("endDate") && r.EndDate < r.StartDate {
+		v.Add("endDate", "not_before", map[string]interface{}{"other": "startDate"})
+	}
+}
+
+// AppliedRule explains how a rule changed the points of a completion.
+type AppliedRule struct {
+	RuleID      string  `json:"ruleId"`
+	Name        string  `json:"name"`
+	RuleType    string  `json:"ruleType"`
+	Multiplier  float64 `json:"multiplier,omitempty"`
+	BonusPoints int     `json:"bonusPoints,omitempty"`
+}
+
+// EvaluatePointRules returns the points earned for completing task on date after
+// applying the family's rules. Only the strongest multiplier applies; flat
+// bonuses from every matching rule are added on top.
+func EvaluatePointRules(db *gorm.DB, childID string, task models.Task, date time.Time) (int, []AppliedRule, error) {
+	var rules []models.PointRule
+	err := db.Where("family_id = ? AND is_active = true", task.FamilyID).
+		Where("task_id IS NULL OR task_id = ?", task.ID).
+		Where("child_id IS NULL OR child_id = ?", childID).
+		Where("start_date IS NULL OR start_date <= ?", date).
+		Where("end_date IS NULL OR end_date >= ?", date).
+		Find(&rules).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var best *models.PointRule
+	bonus := 0
+	applied := []AppliedRule{}
+	for i := range rules {
+		rule := &rules[i]
+		if !matchesWeekday(rule.Weekdays, date.Weekday()) {
+			continue
+		}
+		switch rule.RuleType {
+		case "multiplier":
+			if rule.Multiplier > 1 && (best == nil || rule.Multiplier > best.Multiplier) {
+				best = rule
+			}
+		case "bonus":
+			if rule.BonusPoints > 0 {
+				bonus += rule.BonusPoints
+				applied = append(applied, AppliedRule{RuleID: rule.ID, Name: rule.Name, RuleType: rule.RuleType, BonusPoints: rule.BonusPoints})
+			}
+		}
+	}
+
+	points := task.PointReward
+	if best != nil {
+		points = int(math.Round(float64(points) * best.Multiplier))
+		applied = append([]AppliedRule{{RuleID: best.ID, Name: best.Name, RuleType: best.RuleType, Multiplier: best.Multiplier}}, applied...)
+	}
+
+	return points + bonus, applied, nil
+}
+
+func matchesWeekday(weekdays string, day time.Weekday) bool {
+	if weekdays == "" {
+		return true
+	}
+	for _, d := range strings.Split(weekdays, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(d)); err == nil && time.Weekday(n) == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PointRuleService) GetRules(familyID string) ([]models.PointRule, error) {
+	var rules []models.PointRule
+	if err := database.DB.Where("family_id = ?", familyID).Order("created_at DESC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (s *PointRuleService) CreateRule(familyID string, req PointRuleRequest) (*models.PointRule, error) {
+	rule := models.PointRule{FamilyID: familyID, IsActive: true}
+	if err := s.fill(familyID, &rule, req); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *PointRuleService) UpdateRule(familyID, ruleID string, req PointRuleRequest) (*models.PointRule, error) {
+	var rule models.PointRule
+	if err := database.DB.Where("id = ? AND family_id = ?", ruleID, familyID).First(&rule).Error; err != nil {
+		return nil, ErrRuleNotFound
+	}
+	if err := s.fill(familyID, &rule, req); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Save(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *PointRuleService) DeleteRule(familyID, ruleID string) error {
+	result := database.DB.Where("id = ? AND family_id = ?", ruleID, familyID).Delete(&models.PointRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// fill validates req and copies it onto rule, making sure scoped tasks and children belong to the family.
+func (s *PointRuleService) fill(familyID string, rule *models.PointRule, req PointRuleRequest) error {
+	if err := Validate(req); err != nil {
+		return err
+	}
+
+	if req.RuleType == "multiplier" {
+		rule.Multiplier = req.Multiplier
+		rule.BonusPoints = 0
+	} else {
+		rule.Multiplier = 1
+		rule.BonusPoints = req.BonusPoints
+	}
+
+	if req.TaskID != nil && *req.TaskID != "" {
+		var count int64
+		database.DB.Model(&models.Task{}).Where("id = ? AND family_id = ?", *req.TaskID, familyID).Count(&count)
+		if count == 0 {
+			return ErrTaskNotFound
+		}
+		rule.TaskID = req.TaskID
+	} else {
+		rule.TaskID = nil
+	}
+
+	if req.ChildID != nil && *req.ChildID != "" {
+		var count int64
+		database.DB.Model(&models.User{}).Where("id = ? AND family_id = ? AND role = 'child'", *req.ChildID, familyID).Count(&count)
+		if count == 0 {
+			return ErrChildNotFound
+		}
+		rule.ChildID = req.ChildID
+	} else {
+		rule.ChildID = nil
+	}
+
+	rule.StartDate = nil
+	if req.StartDate != "" {
+		d, _ := time.Parse("2006-01-02", req.StartDate)
+		rule.StartDate = &d
+	}
+	rule.EndDate = nil
+	if req.EndDate != "" {
+		d, _ := time.Parse("2006-01-02", req.EndDate)
+		rule.EndDate = &d
+	}
+
+	days := make([]string, 0, len(req.Weekdays))
+	for _, d := range req.Weekdays {
+		days = append(days, strconv.Itoa(d))
+	}
+	rule.Weekdays = strings.Join(days, ",")
+
+	rule.Name = req.Name
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	return nil
+}
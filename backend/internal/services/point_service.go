@@ -0,0 +1,55 @@
+package services
+
+import (
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PointSummary struct {
+	TotalPoints   int64 `json:"totalPoints"`
+	SpentPoints   int64 `json:"spentPoints"`
+	PendingPoints int64 `json:"pendingPoints"`
+	CashoutPoints int64 `json:"cashoutPoints"`
+	Balance       int64 `json:"balance"`
+}
+
+// ChildPointSummary computes the spendable points of a child. Pending
+// redemptions and cash-outs are reserved so a child can't spend twice.
+func ChildPointSummary(db *gorm.DB, childID string) (PointSummary, error) {
+	var s PointSummary
+
+	err := db.Model(&models.DailyLog{}).
+		Where("child_id = ? AND status = 'verified'", childID).
+		Select("COALESCE(SUM(earned_points), 0)").
+		Scan(&s.TotalPoints).Error
+	if err != nil {
+		return s, err
+	}
+
+	err = db.Model(&models.Redemption{}).
+		Where("child_id = ? AND status = 'approved'", childID).
+		Select("COALESCE(SUM(points_spent), 0)").
+		Scan(&s.SpentPoints).Error
+	if err != nil {
+		return s, err
+	}
+
+	err = db.Model(&models.Redemption{}).
+		Where("child_id = ? AND status = 'pending'", childID).
+		Select("COALESCE(SUM(points_spent), 0)").
+		Scan(&s.PendingPoints).Error
+	if err != nil {
+		return s, err
+	}
+
+	err = db.Model(&models.WalletTransaction{}).
+		Where("child_id = ? AND type = 'cashout' AND status IN ('pending', 'approved')", childID).
+		Select("COALESCE(SUM(points), 0)").
+		Scan(&s.CashoutPoints).Error
+	if err != nil {
+		return s, err
+	}
+
+	s.Balance = s.TotalPoints - s.SpentPoints - s.PendingPoints - s.CashoutPoints
+	return s, nil
+}
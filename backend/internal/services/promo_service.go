@@ -0,0 +1,545 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReferralBonusDays is what both families get when an invitee first pays.
+const ReferralBonusDays = 14
+
+const (
+	CouponPercent  = "percent"
+	CouponFixed    = "fixed"
+	CouponFreeDays = "free_days"
+)
+
+type PromoService struct{}
+
+func NewPromoService() *PromoService {
+	return &PromoService{}
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// lockCoupon loads a coupon FOR UPDATE and checks that familyID may use it now.
+func lockCoupon(tx *gorm.DB, code, familyID string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", normalizeCode(code)).First(&coupon).Error; err != nil {
+		return nil, ErrCouponNotFound
+	}
+
+	now := time.Now()
+	if !coupon.IsActive || (coupon.ValidFrom != nil && now.Before(*coupon.ValidFrom)) || (coupon.ValidUntil != nil && now.After(*coupon.ValidUntil)) {
+		return nil, ErrCouponNotValidNow
+	}
+
+	var used int64
+	tx.Model(&models.CouponRedemption{}).Where("coupon_id = ? AND family_id = ?", coupon.ID, familyID).Count(&used)
+	if used > 0 {
+		return nil, ErrCouponUsed
+	}
+	if coupon.MaxUses != nil {
+		tx.Model(&models.CouponRedemption{}).Where("coupon_id = ?", coupon.ID).Count(&used)
+		if used >= int64(*coupon.MaxUses) {
+			return nil, ErrCouponLimitReached
+		}
+	}
+	return &coupon, nil
+}
+
+// couponDiscount is the rupiah a checkout coupon takes off price.
+func couponDiscount(coupon *models.Coupon, price int64) int64 {
+	var discount int64
+	switch coupon.Kind {
+	case CouponPercent:
+		discount = price * coupon.Value / 100
+	case CouponFixed:
+		discount = coupon.Value
+	}
+	if discount > price {
+		discount = price
+	}
+	return discount
+}
+
+// reserveCoupon holds a checkout coupon for a pending payment and returns the discount.
+func reserveCoupon(tx *gorm.DB, code, familyID string, price int64) (*models.CouponRedemption, error) {
+	coupon, err := lockCoupon(tx, code, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if coupon.Kind == CouponFreeDays {
+		return nil, ErrCouponNotAtCheckout
+	}
+	redemption := &models.CouponRedemption{
+		CouponID: coupon.ID,
+		FamilyID: familyID,
+		Status:   "reserved",
+		Discount: couponDiscount(coupon, price),
+	}
+	if err := tx.Create(redemption).Error; err != nil {
+		return nil, err
+	}
+	return redemption, nil
+}
+
+// confirmCoupon marks the coupon of a settled payment as used. A payment that
+// failed or expired first lost its reservation, yet Midtrans may still settle
+// it at the discounted price; the use is then recorded again so it counts
+// against the coupon's limits. If the family holds another redemption of the
+// coupon meanwhile, that row already counts and is left alone.
+func confirmCoupon(tx *gorm.DB, payment *models.Payment) error {
+	result := tx.Model(&models.CouponRedemption{}).Where("payment_id = ?", payment.ID).Update("status", "used")
+	if result.Error != nil || result.RowsAffected > 0 || payment.CouponCode == "" {
+		return result.Error
+	}
+
+	var coupon models.Coupon
+	if err := tx.Where("code = ?", payment.CouponCode).First(&coupon).Error; err != nil {
+		log.Printf("payment %s settled with coupon %s, which no longer exists", payment.OrderID, payment.CouponCode)
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.CouponRedemption{
+		CouponID:  coupon.ID,
+		FamilyID:  payment.FamilyID,
+		PaymentID: &payment.ID,
+		Status:    "used",
+		Discount:  payment.Discount,
+	}).Error
+}
+
+// releaseCoupon frees the coupon of a payment that will not settle.
+func releaseCoupon(tx *gorm.DB, paymentID string) error {
+	return tx.Where("payment_id = ? AND status = 'reserved'", paymentID).Delete(&models.CouponRedemption{}).Error
+}
+
+// revokeCoupon takes back the coupon of a refunded payment, so it no longer
+// counts against the coupon's limit and the family may use it again.
+func revokeCoupon(tx *gorm.DB, paymentID string) error {
+	return tx.Where("payment_id = ?", paymentID).Delete(&models.CouponRedemption{}).Error
+}
+
+// CouponQuote is what a checkout with a coupon would cost.
+type CouponQuote struct {
+	Code     string `json:"code"`
+	Package  string `json:"package"`
+	Price    int64  `json:"price"`
+	Discount int64  `json:"discount"`
+	Amount   int64  `json:"amount"`
+}
+
+// QuoteCoupon checks a checkout coupon against a package without reserving it.
+func (s *PromoService) QuoteCoupon(familyID, code, packageCode string) (*CouponQuote, error) {
+	pkg, ok := findPackage(packageCode)
+	if !ok {
+		return nil, ErrPackageNotFound
+	}
+
+	var quote *CouponQuote
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		coupon, err := lockCoupon(tx, code, familyID)
+		if err != nil {
+			return err
+		}
+		if coupon.Kind == CouponFreeDays {
+			return ErrCouponNotAtCheckout
+		}
+		discount := couponDiscount(coupon, pkg.Price)
+		quote = &CouponQuote{Code: coupon.Code, Package: pkg.Code, Price: pkg.Price, Discount: discount, Amount: pkg.Price - discount}
+		return nil
+	})
+	return quote, err
+}
+
+// RedeemFreeDays applies a free_days coupon straight to the family's PREMIUM expiry.
+func (s *PromoService) RedeemFreeDays(familyID, code string) (*models.Family, error) {
+	var family *models.Family
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		coupon, err := lockCoupon(tx, code, familyID)
+		if err != nil {
+			return err
+		}
+		if coupon.Kind != CouponFreeDays {
+			return ErrCouponCheckoutOnly
+		}
+		if err := tx.Create(&models.CouponRedemption{
+			CouponID: coupon.ID,
+			FamilyID: familyID,
+			Status:   "used",
+			Days:     int(coupon.Value),
+		}).Error; err != nil {
+			return err
+		}
+		family, err = ExtendPremium(tx, familyID, int(coupon.Value))
+		return err
+	})
+	return family, err
+}
+
+// --- Coupon admin ---
+
+type CouponInput struct {
+	Code        string     `json:"code"` // create only
+	Description string     `json:"description" validate:"max=255"`
+	Kind        string     `json:"kind" validate:"required,oneof=percent fixed free_days"`
+	Value       int64      `json:"value"`
+	ValidFrom   *time.Time `json:"validFrom"`
+	ValidUntil  *time.Time `json:"validUntil"`
+	MaxUses     *int       `json:"maxUses" validate:"min=1"`
+	IsActive    *bool      `json:"isActive"`
+}
+
+func (in CouponInput) Check(v *Violations) {
+	if in.Kind == CouponPercent {
+		if in.Value < 1 || in.Value > 100 {
+			v.Add("value", "between", map[string]interface{}{"min": 1, "max": 100})
+		}
+	} else if in.Value < 1 {
+		v.Add("value", "positive", nil)
+	}
+	if in.ValidFrom != nil && in.ValidUntil != nil && in.ValidUntil.Before(*in.ValidFrom) {
+		v.Add("validUntil", "not_before", map[string]interface{}{"other": "validFrom"})
+	}
+}
+
+// CouponStats is a coupon with its usage so far.
+type CouponStats struct {
+	models.Coupon
+	Used          int64 // settled checkouts and free-day grants
+	Reserved      int64 // pending checkouts
+	TotalDiscount int64 // rupiah given away on settled checkouts
+	Revenue       int64 // rupiah received on settled checkouts
+}
+
+func (s *PromoService) GetCoupons() ([]CouponStats, error) {
+	list := []CouponStats{}
+	err := database.DB.Raw(`
+		SELECT c.*,
+			COUNT(cr.id) FILTER (WHERE cr.status = 'used') AS used,
+			COUNT(cr.id) FILTER (WHERE cr.status = 'reserved') AS reserved,
+			COALESCE(SUM(cr.discount) FILTER (WHERE cr.status = 'used'), 0) AS total_discount,
+			COALESCE(SUM(p.amount) FILTER (WHERE cr.status = 'used' AND p.status = 'paid'), 0) AS revenue
+		FROM coupons c
+		LEFT JOIN coupon_redemptions cr ON cr.coupon_id = c.id
+		LEFT JOIN payments p ON p.id = cr.payment_id
+		GROUP BY c.id
+		ORDER BY c.created_at DESC`).Scan(&list).Error
+	return list, err
+}
+
+func (s *PromoService) CreateCoupon(in CouponInput) (*models.Coupon, error) {
+	in.Code = normalizeCode(in.Code)
+	var v Violations
+	if len(in.Code) < 3 || len(in.Code) > 40 {
+		v.Add("code", "length_between", map[string]interface{}{"min": 3, "max": 40})
+	}
+	v.Validate(in)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	var count int64
+	database.DB.Model(&models.Coupon{}).Where("code = ?", in.Code).Count(&count)
+	if count > 0 {
+		return nil, ErrCouponCodeTaken
+	}
+
+	coupon := models.Coupon{
+		Code:        in.Code,
+		Description: in.Description,
+		Kind:        in.Kind,
+		Value:       in.Value,
+		ValidFrom:   in.ValidFrom,
+		ValidUntil:  in.ValidUntil,
+		MaxUses:     in.MaxUses,
+		IsActive:    true,
+	}
+	if err := database.DB.Create(&coupon).Error; err != nil {
+		return nil, err
+	}
+	if in.IsActive != nil && !*in.IsActive {
+		database.DB.Model(&coupon).Update("is_active", false)
+		coupon.IsActive = false
+	}
+	return &coupon, nil
+}
+
+// UpdateCoupon changes everything but the code, which may already be printed on banners.
+func (s *PromoService) UpdateCoupon(id string, in CouponInput) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := database.DB.First(&coupon, "id = ?", id).Error; err != nil {
+		return nil, ErrCouponNotFound
+	}
+	if err := Validate(in); err != nil {
+		return nil, err
+	}
+
+	coupon.Description = in.Description
+	coupon.Kind = in.Kind
+	coupon.Value = in.Value
+	coupon.ValidFrom = in.ValidFrom
+	coupon.ValidUntil = in.ValidUntil
+	coupon.MaxUses = in.MaxUses
+	if in.IsActive != nil {
+		coupon.IsActive = *in.IsActive
+	}
+	if err := database.DB.Save(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// DeleteCoupon removes an unused coupon. Used ones are kept for the payment
+// history and can only be deactivated.
+func (s *PromoService) DeleteCoupon(id string) error {
+	var count int64
+	database.DB.Model(&models.CouponRedemption{}).Where("coupon_id = ?", id).Count(&count)
+	if count > 0 {
+		return ErrCouponInUse
+	}
+	result := database.DB.Delete(&models.Coupon{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCouponNotFound
+	}
+	return nil
+}
+
+// CouponUse is one row of a coupon's usage list.
+type CouponUse struct {
+	FamilyID   string
+	FamilyName string
+	Status     string
+	Discount   int64
+	Days       int
+	OrderID    *string
+	CreatedAt  time.Time
+}
+
+func (s *PromoService) GetCouponUses(id string) ([]CouponUse, error) {
+	uses := []CouponUse{}
+	err := database.DB.Raw(`
+		SELECT cr.family_id, f.name AS family_name, cr.status, cr.discount, cr.days, p.order_id, cr.created_at
+		FROM coupon_redemptions cr
+		JOIN families f ON f.id = cr.family_id
+		LEFT JOIN payments p ON p.id = cr.payment_id
+		WHERE cr.coupon_id = ?
+		ORDER BY cr.created_at DESC`, id).Scan(&uses).Error
+	return uses, err
+}
+
+// --- Referrals ---
+
+// FindReferrer returns the family that owns a referral code.
+func FindReferrer(code string) (*models.Family, error) {
+	var family models.Family
+	if err := database.DB.Where("referral_code = ?", normalizeCode(code)).First(&family).Error; err != nil {
+		return nil, ErrReferralInvalid
+	}
+	return &family, nil
+}
+
+// CreateReferral links a new family to its referrer. db may be a transaction.
+func CreateReferral(db *gorm.DB, referrerID, inviteeID string) error {
+	if referrerID == inviteeID {
+		return ErrReferralOwn
+	}
+	return db.Create(&models.Referral{ReferrerFamilyID: referrerID, InviteeFamilyID: inviteeID, Status: "pending"}).Error
+}
+
+// rewardReferral grants both sides their bonus the first time the invitee pays.
+func rewardReferral(tx *gorm.DB, inviteeID string) error {
+	var referral models.Referral
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("invitee_family_id = ? AND status = 'pending'", inviteeID).First(&referral).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := ExtendPremium(tx, referral.InviteeFamilyID, ReferralBonusDays); err != nil {
+		return err
+	}
+	if _, err := ExtendPremium(tx, referral.ReferrerFamilyID, ReferralBonusDays); err != nil && !errors.Is(err, ErrFamilyNotFound) {
+		return err
+	}
+	now := time.Now()
+	return tx.Model(&referral).Updates(map[string]interface{}{
+		"status":      "rewarded",
+		"bonus_days":  ReferralBonusDays,
+		"rewarded_at": now,
+	}).Error
+}
+
+// revokeReferral takes back both sides' bonus when the payment that earned it
+// is refunded, that is when the invitee has no other paid payment left. The
+// referral goes back to pending so a later payment earns it again.
+func revokeReferral(tx *gorm.DB, inviteeID, refundedPaymentID string) error {
+	var referral models.Referral
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("invitee_family_id = ? AND status = 'rewarded'", inviteeID).First(&referral).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var paid int64
+	if err := tx.Model(&models.Payment{}).
+		Where("family_id = ? AND status = 'paid' AND id <> ?", inviteeID, refundedPaymentID).
+		Count(&paid).Error; err != nil {
+		return err
+	}
+	if paid > 0 {
+		return nil
+	}
+
+	if err := RevokePremium(tx, referral.InviteeFamilyID, referral.BonusDays); err != nil {
+		return err
+	}
+	if err := RevokePremium(tx, referral.ReferrerFamilyID, referral.BonusDays); err != nil && !errors.Is(err, ErrFamilyNotFound) {
+		return err
+	}
+	return tx.Model(&referral).Updates(map[string]interface{}{
+		"status":      "pending",
+		"bonus_days":  0,
+		"rewarded_at": nil,
+	}).Error
+}
+
+type ReferralInvite struct {
+	FamilyName string
+	Status     string
+	RewardedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type ReferralInfo struct {
+	Code       string           `json:"code"`
+	BonusDays  int              `json:"bonusDays"`
+	ReferredBy *string          `json:"referredBy"` // referrer family name
+	Invites    []ReferralInvite `json:"invites"`
+}
+
+// GetReferral returns the family's referral code, creating it on first use,
+// and the families it invited.
+func (s *PromoService) GetReferral(familyID string) (*ReferralInfo, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	if family.ReferralCode == nil {
+		for attempt := 0; ; attempt++ {
+			suffix, err := utils.RandomHex(4)
+			if err != nil {
+				return nil, err
+			}
+			code := strings.ToUpper(suffix)
+			result := database.DB.Model(&models.Family{}).
+				Where("id = ? AND referral_code IS NULL", familyID).
+				Update("referral_code", code)
+			if result.Error == nil {
+				break
+			}
+			if attempt == 4 {
+				return nil, result.Error
+			}
+		}
+		if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	info := &ReferralInfo{Code: *family.ReferralCode, BonusDays: ReferralBonusDays, Invites: []ReferralInvite{}}
+	err := database.DB.Raw(`
+		SELECT f.name AS family_name, r.status, r.rewarded_at, r.created_at
+		FROM referrals r JOIN families f ON f.id = r.invitee_family_id
+		WHERE r.referrer_family_id = ?
+		ORDER BY r.created_at DESC`, familyID).Scan(&info.Invites).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var referrer string
+	if database.DB.Raw(`SELECT f.name FROM referrals r JOIN families f ON f.id = r.referrer_family_id
+		WHERE r.invitee_family_id = ?`, familyID).Scan(&referrer).RowsAffected > 0 {
+		info.ReferredBy = &referrer
+	}
+	return info, nil
+}
+
+// ClaimReferral lets a family that signed up without a code add one later,
+// as long as it has never paid.
+func (s *PromoService) ClaimReferral(familyID, code string) error {
+	referrer, err := FindReferrer(code)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	database.DB.Model(&models.Referral{}).Where("invitee_family_id = ?", familyID).Count(&count)
+	if count > 0 {
+		return ErrReferralClaimed
+	}
+	database.DB.Model(&models.Payment{}).Where("family_id = ? AND status IN ('paid', 'refunded')", familyID).Count(&count)
+	if count > 0 {
+		return ErrReferralAfterPayment
+	}
+	return CreateReferral(database.DB, referrer.ID, familyID)
+}
+
+type TopReferrer struct {
+	FamilyID   string
+	FamilyName string
+	Invites    int64
+	Rewarded   int64
+}
+
+// ReferralStats summarises the referral programme for super admins.
+type ReferralStats struct {
+	Total        int64
+	Rewarded     int64
+	BonusDays    int64 // granted to both sides together
+	TopReferrers []TopReferrer
+}
+
+func (s *PromoService) GetReferralStats() (*ReferralStats, error) {
+	var totals struct {
+		Total     int64
+		Rewarded  int64
+		BonusDays int64
+	}
+	err := database.DB.Raw(`
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'rewarded') AS rewarded,
+			COALESCE(SUM(bonus_days) * 2, 0) AS bonus_days
+		FROM referrals`).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	stats := &ReferralStats{Total: totals.Total, Rewarded: totals.Rewarded, BonusDays: totals.BonusDays, TopReferrers: []TopReferrer{}}
+	err = database.DB.Raw(`
+		SELECT r.referrer_family_id AS family_id, f.name AS family_name,
+			COUNT(*) AS invites, COUNT(*) FILTER (WHERE r.status = 'rewarded') AS rewarded
+		FROM referrals r JOIN families f ON f.id = r.referrer_family_id
+		GROUP BY r.referrer_family_id, f.name
+		ORDER BY rewarded DESC, invites DESC
+		LIMIT 20`).Scan(&stats.TopReferrers).Error
+	return stats, err
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/events"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RedemptionService creates and decides reward redemptions and tells the
+// family about them (push, WhatsApp and the event stream).
+type RedemptionService struct {
+	notifications *NotificationService
+	whatsapp      *WhatsappService
+	events        *events.Bus
+}
+
+func NewRedemptionService(notifications *NotificationService, wa *WhatsappService, bus *events.Bus) *RedemptionService {
+	return &RedemptionService{notifications: notifications, whatsapp: wa, events: bus}
+}
+
+// recordRedemptionStatus appends to the redemption's status history; exports rely on it.
+func recordRedemptionStatus(tx *gorm.DB, redemption models.Redemption, changedBy string) error {
+	change := models.RedemptionStatusChange{RedemptionID: redemption.ID, Status: redemption.Status}
+	if changedBy != "" {
+		change.ChangedBy = &changedBy
+	}
+	return tx.Create(&change).Error
+}
+
+func redemptionEvent(redemption models.Redemption) map[string]interface{} {
+	return map[string]interface{}{
+		"redemptionId": redemption.ID,
+		"childId":      redemption.ChildID,
+		"rewardId":     redemption.RewardID,
+		"pointsSpent":  redemption.PointsSpent,
+		"status":       redemption.Status,
+	}
+}
+
+// Create requests quantity × reward for a child of the family. The points are
+// reserved until a parent decides. clientEventID makes synced requests
+// idempotent ("Event already applied").
+func (s *RedemptionService) Create(familyID, childID, rewardID string, quantity int, changedBy string, clientEventID *string) (*models.Redemption, error) {
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	var reward models.Reward
+	if err := database.DB.First(&reward, "id = ? AND family_id = ?", rewardID, familyID).Error; err != nil {
+		return nil, ErrRewardNotFound
+	}
+	if readOnly, err := IsRewardReadOnly(familyID, reward.ID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return nil, ErrRewardReadOnly
+	}
+	if readOnly, err := IsChildReadOnly(familyID, childID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return nil, ErrChildReadOnly
+	}
+
+	var child models.User
+	redemption := models.Redemption{
+		ChildID:       childID,
+		RewardID:      rewardID,
+		PointsSpent:   reward.PointsRequired * quantity,
+		Status:        "pending",
+		ClientEventID: clientEventID,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if clientEventID != nil {
+			var count int64
+			if err := tx.Model(&models.Redemption{}).Unscoped().Where("client_event_id = ?", *clientEventID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrEventApplied
+			}
+		}
+
+		// Lock the child row so two requests can't reserve the same points.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND family_id = ? AND role = 'child'", childID, familyID).First(&child).Error; err != nil {
+			return ErrChildNotFound
+		}
+		summary, err := ChildPointSummary(tx, childID)
+		if err != nil {
+			return err
+		}
+		if summary.Balance < int64(redemption.PointsSpent) {
+			return ErrInsufficientPoints
+		}
+
+		if err := tx.Create(&redemption).Error; err != nil {
+			if clientEventID != nil && isUniqueViolation(err) {
+				return ErrEventApplied
+			}
+			return err
+		}
+		return recordRedemptionStatus(tx, redemption, changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyRequest(child, reward, redemption)
+	s.events.Publish(familyID, events.RedemptionCreated, redemptionEvent(redemption))
+	return &redemption, nil
+}
+
+// UpdateStatus approves or rejects a redemption of the family.
+func (s *RedemptionService) UpdateStatus(familyID, redemptionID, status, changedBy string) (*models.Redemption, error) {
+	if status != "approved" && status != "rejected" {
+		return nil, InvalidField("status", "one_of", map[string]interface{}{"values": []string{"approved", "rejected"}})
+	}
+
+	var redemption models.Redemption
+	err := database.DB.Joins("JOIN users ON users.id = redemptions.child_id").
+		Where("redemptions.id = ? AND users.family_id = ?", redemptionID, familyID).
+		First(&redemption).Error
+	if err != nil {
+		return nil, ErrRedemptionNotFound
+	}
+
+	redemption.Status = status
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&redemption).Error; err != nil {
+			return err
+		}
+		return recordRedemptionStatus(tx, redemption, changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyDecision(redemption)
+	s.events.Publish(familyID, events.RedemptionUpdated, redemptionEvent(redemption))
+	return &redemption, nil
+}
+
+func (s *RedemptionService) notifyRequest(child models.User, reward models.Reward, redemption models.Redemption) {
+	err := s.notifications.NotifyParents(reward.FamilyID, Notification{
+		Kind:  NotifyRedemption,
+		Title: fmt.Sprintf("🎁 %s ingin menukar %s", child.Name, reward.Name),
+		Body:  fmt.Sprintf("%d poin — setujui atau tolak di dashboard.", redemption.PointsSpent),
+		URL:   "/dashboard",
+	})
+	if err != nil {
+		log.Printf("push: redemption %s: %v", redemption.ID, err)
+	}
+
+	params := []string{child.Name, strconv.Itoa(redemption.PointsSpent), reward.Name}
+	go func() {
+		err := s.whatsapp.NotifyParents(context.Background(), reward.FamilyID, whatsapp.TemplateRedemptionRequest, params, "redemption:"+redemption.ID)
+		if err != nil {
+			log.Printf("whatsapp: redemption %s: %v", redemption.ID, err)
+		}
+	}()
+}
+
+func (s *RedemptionService) notifyDecision(redemption models.Redemption) {
+	var reward models.Reward
+	if err := database.DB.Select("name").First(&reward, "id = ?", redemption.RewardID).Error; err != nil {
+		return
+	}
+	n := Notification{
+		Kind:  NotifyRedemption,
+		Title: "Hadiah disetujui! 🎉",
+		Body:  fmt.Sprintf("%s siap untukmu.", reward.Name),
+		URL:   "/",
+	}
+	if redemption.Status == "rejected" {
+		n.Title = "Penukaran belum disetujui"
+		n.Body = fmt.Sprintf("Poinmu untuk %s sudah dikembalikan.", reward.Name)
+	}
+	if err := s.notifications.Notify(redemption.ChildID, n); err != nil {
+		log.Printf("push: redemption %s: %v", redemption.ID, err)
+	}
+}
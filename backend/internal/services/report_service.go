@@ -0,0 +1,321 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/mailer"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/reports"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+)
+
+type ReportService struct {
+	mailer mailer.Mailer
+}
+
+func NewReportService(m mailer.Mailer) *ReportService {
+	return &ReportService{mailer: m}
+}
+
+var monthsID = [...]string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// BuildChildReport gathers the rapor of a child for period ("week" or "season").
+// date picks the week to report on and defaults to the current week.
+func (s *ReportService) BuildChildReport(familyID, childID, period, date string) (*reports.ChildReport, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	var child models.User
+	if err := database.DB.Where("id = ? AND family_id = ? AND role = 'child'", childID, familyID).First(&child).Error; err != nil {
+		return nil, ErrChildNotFound
+	}
+
+	today := utils.Today(utils.LoadLocation(family.Timezone))
+	var from, to time.Time
+	var label string
+
+	switch period {
+	case "", "week":
+		period = "week"
+		day := today
+		if date != "" {
+			d, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return nil, InvalidField("date", "date", nil)
+			}
+			day = d
+		}
+		from, to = utils.WeekBounds(day)
+		label = fmt.Sprintf("Pekan %d %s %d", from.Day(), monthsID[from.Month()], from.Year())
+	case "season":
+		if family.SeasonStart == nil || family.SeasonEnd == nil {
+			return nil, ErrSeasonNotConfigured
+		}
+		from, to = *family.SeasonStart, *family.SeasonEnd
+		label = fmt.Sprintf("Ramadhan %d", from.Year())
+	default:
+		return nil, InvalidField("period", "one_of", map[string]interface{}{"values": []string{"week", "season"}})
+	}
+
+	// Days that can count towards prayers: the period, cut off at today.
+	lastDay := to
+	if today.Before(lastDay) {
+		lastDay = today
+	}
+	days := int64(lastDay.Sub(from).Hours()/24) + 1
+	if days < 0 {
+		days = 0
+	}
+
+	report := &reports.ChildReport{
+		FamilyName:  family.Name,
+		ChildName:   child.Name,
+		Avatar:      child.AvatarIcon,
+		Period:      period,
+		PeriodLabel: label,
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		GeneratedAt: time.Now().In(utils.LoadLocation(family.Timezone)),
+	}
+
+	var totals struct {
+		Points      int64
+		Completions int64
+		Pages       int64
+	}
+	err := database.DB.Raw(`
+		SELECT COALESCE(SUM(dl.earned_points), 0) AS points,
+			COUNT(*) AS completions,
+			COALESCE(SUM(dl.quantity) FILTER (WHERE t.category = 'quran'), 0) AS pages
+		FROM daily_logs dl
+		JOIN tasks t ON t.id = dl.task_id
+		WHERE dl.child_id = ? AND dl.status = 'verified' AND dl.deleted_at IS NULL
+			AND dl.completed_date BETWEEN ? AND ?`,
+		childID, report.From, report.To).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	report.PointsEarned = totals.Points
+	report.Completions = totals.Completions
+	report.PagesRead = totals.Pages
+
+	// A prayer task has MaxPerDay slots a day (one when unlimited); extra
+	// completions of an unlimited task don't count twice.
+	err = database.DB.Raw(`
+		SELECT COALESCE(SUM(LEAST(kept, slots)), 0)
+		FROM (
+			SELECT COUNT(*) AS kept, COALESCE(NULLIF(t.max_per_day, 0), 1) AS slots
+			FROM daily_logs dl
+			JOIN tasks t ON t.id = dl.task_id
+			WHERE dl.child_id = ? AND dl.status = 'verified' AND dl.deleted_at IS NULL
+				AND t.category = 'prayer' AND dl.completed_date BETWEEN ? AND ?
+			GROUP BY dl.task_id, dl.completed_date, t.max_per_day
+		) per_day`,
+		childID, report.From, report.To).Scan(&report.PrayersKept).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var prayerSlots int64
+	err = database.DB.Model(&models.Task{}).
+		Where("family_id = ? AND is_active = true AND category = ?", familyID, models.TaskCategoryPrayer).
+		Select("COALESCE(SUM(COALESCE(NULLIF(max_per_day, 0), 1)), 0)").
+		Scan(&prayerSlots).Error
+	if err != nil {
+		return nil, err
+	}
+	report.PrayersPossible = prayerSlots * days
+
+	var streaks []StreakInfo
+	err = database.DB.Raw(streakQuery, map[string]interface{}{
+		"children": []string{childID},
+		"to":       report.To,
+		"today":    today.Format("2006-01-02"),
+	}).Scan(&streaks).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(streaks) > 0 {
+		report.CurrentStreak = streaks[0].Current
+		report.LongestStreak = streaks[0].Longest
+	}
+
+	var redemptions []models.Redemption
+	err = database.DB.Preload("Reward").
+		Where("child_id = ? AND status = 'approved' AND (updated_at AT TIME ZONE ?)::date BETWEEN ? AND ?",
+			childID, utils.TimezoneName(family.Timezone), report.From, report.To).
+		Order("updated_at").
+		Find(&redemptions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range redemptions {
+		report.Rewards = append(report.Rewards, reports.RedeemedReward{
+			Name:   r.Reward.Name,
+			Icon:   r.Reward.Icon,
+			Points: int64(r.PointsSpent),
+			Date:   r.UpdatedAt.In(utils.LoadLocation(family.Timezone)).Format("2006-01-02"),
+		})
+	}
+
+	report.Encouragement = encouragement(report)
+	return report, nil
+}
+
+// encouragement picks a short line for the end of the rapor.
+func encouragement(r *reports.ChildReport) string {
+	name := r.ChildName
+	switch {
+	case r.PrayersPossible > 0 && r.PrayerPercent() >= 90:
+		return fmt.Sprintf("MasyaAllah, %s hampir tidak pernah meninggalkan sholat. Pertahankan, ya!", name)
+	case r.CurrentStreak >= 7:
+		return fmt.Sprintf("%s sudah %d hari berturut-turut semangat beribadah. Luar biasa!", name, r.CurrentStreak)
+	case r.PagesRead >= 20:
+		return fmt.Sprintf("%s sudah membaca %d halaman Al-Quran. Semoga jadi cahaya di hari nanti.", name, r.PagesRead)
+	case r.Completions > 0:
+		return fmt.Sprintf("Terus semangat, %s! Setiap kebaikan kecil dicatat oleh Allah.", name)
+	default:
+		return fmt.Sprintf("Yuk %s, kita mulai lagi pekan ini. Sedikit demi sedikit, lama-lama jadi bukit!", name)
+	}
+}
+
+// RenderChildReport returns the rapor as HTML or PDF bytes along with its content type.
+func (s *ReportService) RenderChildReport(report *reports.ChildReport, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "", "html":
+		if err := reports.RenderHTML(&buf, *report); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "text/html; charset=utf-8", nil
+	case "pdf":
+		if err := reports.RenderPDF(&buf, *report); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "application/pdf", nil
+	}
+	return nil, "", InvalidField("format", "one_of", map[string]interface{}{"values": []string{"html", "pdf"}})
+}
+
+// SendChildReport mails the rapor as an HTML body with the PDF attached.
+func (s *ReportService) SendChildReport(ctx context.Context, report *reports.ChildReport, to []string) error {
+	if len(to) == 0 {
+		return ErrNoRecipient
+	}
+
+	var html, pdf bytes.Buffer
+	if err := reports.RenderHTML(&html, *report); err != nil {
+		return err
+	}
+	if err := reports.RenderPDF(&pdf, *report); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      to,
+		Subject: fmt.Sprintf("Rapor Ramadhan %s — %s", report.ChildName, report.PeriodLabel),
+		HTML:    html.String(),
+		Attachments: []mailer.Attachment{{
+			Filename:    fmt.Sprintf("rapor-%s-%s.pdf", utils.Slugify(report.ChildName), report.From),
+			ContentType: "application/pdf",
+			Data:        pdf.Bytes(),
+		}},
+	})
+}
+
+// SendFamilyDigests mails every child's rapor for period to the family's parents.
+func (s *ReportService) SendFamilyDigests(ctx context.Context, familyID, period string) error {
+	var parents []models.User
+	if err := database.DB.Where("family_id = ? AND role = 'parent' AND email IS NOT NULL", familyID).Find(&parents).Error; err != nil {
+		return err
+	}
+	to := make([]string, 0, len(parents))
+	for _, p := range parents {
+		to = append(to, *p.Email)
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	var children []models.User
+	if err := database.DB.Where("family_id = ? AND role = 'child'", familyID).Find(&children).Error; err != nil {
+		return err
+	}
+	for _, child := range children {
+		report, err := s.BuildChildReport(familyID, child.ID, period, "")
+		if err != nil {
+			return err
+		}
+		if err := s.SendChildReport(ctx, report, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendAllDigests mails the rapor of period to every family.
+func (s *ReportService) SendAllDigests(ctx context.Context, period string) error {
+	var families []models.Family
+	if err := database.DB.WithContext(ctx).Find(&families).Error; err != nil {
+		return err
+	}
+	return s.sendDigests(ctx, families, period)
+}
+
+// seasonDigestHour is the local hour the season rapor goes out on the last
+// day of a family's season.
+const seasonDigestHour = 19
+
+// SendSeasonEndDigests mails the season rapor to the families whose season
+// ends today, at seasonDigestHour in their own timezone. Run it hourly.
+func (s *ReportService) SendSeasonEndDigests(ctx context.Context) error {
+	var families []models.Family
+	if err := database.DB.WithContext(ctx).Where("season_start IS NOT NULL AND season_end IS NOT NULL").Find(&families).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var due []models.Family
+	for _, family := range families {
+		loc := utils.LoadLocation(family.Timezone)
+		if now.In(loc).Hour() != seasonDigestHour {
+			continue
+		}
+		if family.SeasonEnd.Format("2006-01-02") != utils.Today(loc).Format("2006-01-02") {
+			continue
+		}
+		due = append(due, family)
+	}
+	return s.sendDigests(ctx, due, "season")
+}
+
+// sendDigests runs SendFamilyDigests for each family whose plan includes
+// reports. Failures are logged and skipped rather than returned: a retry of
+// the job would mail every family that already got its rapor again.
+func (s *ReportService) sendDigests(ctx context.Context, families []models.Family, period string) error {
+	for i, family := range families {
+		if ctx.Err() != nil {
+			log.Printf("%s digest stopped after %d of %d families: %v", period, i, len(families), ctx.Err())
+			return nil
+		}
+		plan, err := EffectivePlan(family)
+		if err != nil {
+			log.Printf("%s digest for family %s failed: %v", period, family.ID, err)
+			continue
+		}
+		if !hasFeature(plan, FeatureReports) {
+			continue
+		}
+		if err := s.SendFamilyDigests(ctx, family.ID, period); err != nil {
+			log.Printf("%s digest for family %s failed: %v", period, family.ID, err)
+		}
+	}
+	return nil
+}
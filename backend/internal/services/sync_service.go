@@ -0,0 +1,391 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Sync event types sent by offline devices.
+const (
+	SyncTaskCompleted       = "task_completed"
+	SyncRedemptionRequested = "redemption_requested"
+)
+
+// Per-event outcomes.
+const (
+	SyncApplied   = "applied"
+	SyncDuplicate = "duplicate"
+	SyncRejected  = "rejected" // permanent: the device should drop the event
+	SyncError     = "error"    // temporary: send it again later
+)
+
+const (
+	MaxSyncBatch = 200
+	// syncMaxAge is how long a device may stay offline and still sync.
+	syncMaxAge = 7 * 24 * time.Hour
+	// syncClockSkew tolerates device clocks running ahead.
+	syncClockSkew = 5 * time.Minute
+	// syncDateSlack is how many days a device's date may differ from the
+	// family's date at occurredAt, e.g. a completion logged just after midnight.
+	syncDateSlack = 1
+	// syncCursorOverlap re-sends the last seconds of changes on the next pull,
+	// so rows committed while a pull ran aren't missed. Devices upsert by ID.
+	syncCursorOverlap = 5 * time.Second
+)
+
+// isUniqueViolation reports a Postgres unique constraint error.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+type SyncService struct {
+	tasks       *TaskService
+	redemptions *RedemptionService
+}
+
+func NewSyncService(tasks *TaskService, redemptions *RedemptionService) *SyncService {
+	return &SyncService{tasks: tasks, redemptions: redemptions}
+}
+
+// SyncEvent is something a device recorded while offline. ID is generated on
+// the device and makes the event idempotent.
+type SyncEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"` // device clock, RFC 3339
+	ChildID    string    `json:"childId"`
+	TaskID     string    `json:"taskId,omitempty"`
+	Date       string    `json:"date,omitempty"` // YYYY-MM-DD on the device; derived from occurredAt if empty
+	RewardID   string    `json:"rewardId,omitempty"`
+	Quantity   int       `json:"quantity,omitempty"`
+}
+
+type SyncEventResult struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+	LogID        string `json:"logId,omitempty"`
+	RedemptionID string `json:"redemptionId,omitempty"`
+	EarnedPoints *int   `json:"earnedPoints,omitempty"`
+	NewBalance   *int   `json:"newBalance,omitempty"`
+	Code         string `json:"code,omitempty"` // error code when rejected or failed
+
+	err *Error
+}
+
+// fail records why the event was not applied. Reason is English until
+// Localize is called.
+func (r *SyncEventResult) fail(status string, err *Error) {
+	r.Status, r.Code, r.Reason, r.err = status, err.Code, err.Error(), err
+}
+
+// Localize renders Reason in lang.
+func (r *SyncEventResult) Localize(lang string) {
+	if r.err != nil {
+		r.Reason = r.err.Message(lang)
+	}
+}
+
+// Push applies a batch in device order. Events are independent: one
+// rejection doesn't stop the rest.
+func (s *SyncService) Push(familyID, userID, role string, batch []SyncEvent) ([]SyncEventResult, error) {
+	if len(batch) > MaxSyncBatch {
+		return nil, InvalidField("events", "max_items", map[string]interface{}{"max": MaxSyncBatch})
+	}
+
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	loc := utils.LoadLocation(family.Timezone)
+
+	var children []string
+	if err := database.DB.Model(&models.User{}).Where("family_id = ? AND role = 'child'", familyID).Pluck("id", &children).Error; err != nil {
+		return nil, err
+	}
+	inFamily := map[string]bool{}
+	for _, id := range children {
+		inFamily[id] = true
+	}
+
+	order := make([]int, len(batch))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return batch[order[a]].OccurredAt.Before(batch[order[b]].OccurredAt) })
+
+	results := make([]SyncEventResult, len(batch))
+	seen := map[string]bool{}
+	for _, i := range order {
+		ev := batch[i]
+		if role == "child" && ev.ChildID == "" {
+			ev.ChildID = userID
+		}
+		result := SyncEventResult{ID: ev.ID}
+
+		switch {
+		case uuid.Validate(ev.ID) != nil:
+			result.fail(SyncRejected, InvalidField("id", "uuid", nil))
+		case seen[ev.ID]:
+			result.Status = SyncDuplicate
+		case role == "child" && ev.ChildID != userID:
+			result.fail(SyncRejected, ErrForbidden)
+		case !inFamily[ev.ChildID]:
+			result.fail(SyncRejected, ErrChildNotFound)
+		case ev.OccurredAt.IsZero():
+			result.fail(SyncRejected, InvalidField("occurredAt", "required", nil))
+		case ev.OccurredAt.After(time.Now().Add(syncClockSkew)):
+			result.fail(SyncRejected, InvalidField("occurredAt", "not_future", nil))
+		case time.Since(ev.OccurredAt) > syncMaxAge:
+			result.fail(SyncRejected, InvalidField("occurredAt", "max_age", map[string]interface{}{"days": int(syncMaxAge.Hours() / 24)}))
+		default:
+			result = s.apply(familyID, userID, loc, ev)
+		}
+		seen[ev.ID] = true
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (s *SyncService) apply(familyID, userID string, loc *time.Location, ev SyncEvent) SyncEventResult {
+	result := SyncEventResult{ID: ev.ID}
+	id := ev.ID
+
+	var err error
+	switch ev.Type {
+	case SyncTaskCompleted:
+		date, dateErr := syncDate(ev, loc)
+		if dateErr != nil {
+			result.fail(SyncRejected, dateErr)
+			return result
+		}
+		var task models.Task
+		if database.DB.Select("id").First(&task, "id = ? AND family_id = ?", ev.TaskID, familyID).Error != nil {
+			result.fail(SyncRejected, ErrTaskNotFound)
+			return result
+		}
+		var completion *CompletionResult
+		completion, err = s.tasks.CompleteTask(ev.ChildID, ev.TaskID, date, &id)
+		if err == nil {
+			result.LogID = completion.LogID
+			result.EarnedPoints = &completion.EarnedPoints
+			result.NewBalance = &completion.NewBalance
+		} else if errors.Is(err, ErrEventApplied) {
+			var existing models.DailyLog
+			database.DB.Unscoped().Select("id").First(&existing, "client_event_id = ?", id)
+			result.LogID = existing.ID
+		}
+
+	case SyncRedemptionRequested:
+		var redemption *models.Redemption
+		redemption, err = s.redemptions.Create(familyID, ev.ChildID, ev.RewardID, ev.Quantity, userID, &id)
+		if err == nil {
+			result.RedemptionID = redemption.ID
+		} else if errors.Is(err, ErrEventApplied) {
+			var existing models.Redemption
+			database.DB.Unscoped().Select("id").First(&existing, "client_event_id = ?", id)
+			result.RedemptionID = existing.ID
+		}
+
+	default:
+		result.fail(SyncRejected, ErrUnknownEventType)
+		return result
+	}
+
+	var domainErr *Error
+	switch {
+	case err == nil:
+		result.Status = SyncApplied
+	case errors.Is(err, ErrEventApplied):
+		result.Status = SyncDuplicate
+	case isSyncRejection(err, &domainErr):
+		result.fail(SyncRejected, domainErr)
+	default:
+		result.fail(SyncError, ErrInternal)
+	}
+	return result
+}
+
+// isSyncRejection separates business rule failures, which will fail again,
+// from errors worth retrying.
+func isSyncRejection(err error, domainErr **Error) bool {
+	return errors.As(err, domainErr) && (*domainErr).Kind != KindInternal && (*domainErr).Kind != KindUnavailable
+}
+
+// syncDate is the completion date: the device's own date when given, else
+// occurredAt in the family timezone. The device's date may be at most
+// syncDateSlack days from occurredAt, so it can't backdate or postdate.
+func syncDate(ev SyncEvent, loc *time.Location) (time.Time, *Error) {
+	local := ev.OccurredAt.In(loc)
+	occurred := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if ev.Date == "" {
+		return occurred, nil
+	}
+
+	date, err := time.Parse("2006-01-02", ev.Date)
+	if err != nil {
+		return time.Time{}, InvalidField("date", "date", nil)
+	}
+	if diff := date.Sub(occurred); diff > syncDateSlack*24*time.Hour || diff < -syncDateSlack*24*time.Hour {
+		return time.Time{}, InvalidField("date", "near", map[string]interface{}{"other": "occurredAt", "days": syncDateSlack})
+	}
+	return date, nil
+}
+
+// SyncChild is a child with the balances a device shows offline.
+type SyncChild struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	AvatarIcon      string `json:"avatarIcon"`
+	PointsBalance   int    `json:"pointsBalance"`
+	AvailablePoints int64  `json:"availablePoints"`
+}
+
+type SyncDeleted struct {
+	Tasks       []string `json:"tasks"`
+	Rewards     []string `json:"rewards"`
+	Logs        []string `json:"logs"`
+	Redemptions []string `json:"redemptions"`
+}
+
+// SyncChanges is what changed since the device's cursor. Without a cursor it
+// is a snapshot: all tasks and rewards, the last week of logs, and pending or
+// recent redemptions.
+type SyncChanges struct {
+	Cursor      string              `json:"cursor"`
+	Full        bool                `json:"full"`
+	Children    []SyncChild         `json:"children"`
+	Tasks       []models.Task       `json:"tasks"`
+	Rewards     []models.Reward     `json:"rewards"`
+	Logs        []models.DailyLog   `json:"logs"`
+	Redemptions []models.Redemption `json:"redemptions"`
+	Deleted     SyncDeleted         `json:"deleted"`
+}
+
+func encodeCursor(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(t.UnixNano(), 10)))
+}
+
+func decodeCursor(cursor string) (time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, InvalidField("since", "cursor", nil)
+	}
+	nanos, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, InvalidField("since", "cursor", nil)
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// Changes returns what a device needs to reconcile. Children only see their
+// own logs and redemptions.
+func (s *SyncService) Changes(familyID, userID, role, cursor string) (*SyncChanges, error) {
+	started := time.Now()
+	var since time.Time
+	if cursor != "" {
+		var err error
+		if since, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	full := since.IsZero()
+
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	changes := &SyncChanges{
+		Cursor:      encodeCursor(started.Add(-syncCursorOverlap)),
+		Full:        full,
+		Children:    []SyncChild{},
+		Tasks:       []models.Task{},
+		Rewards:     []models.Reward{},
+		Logs:        []models.DailyLog{},
+		Redemptions: []models.Redemption{},
+		Deleted:     SyncDeleted{Tasks: []string{}, Rewards: []string{}, Logs: []string{}, Redemptions: []string{}},
+	}
+
+	childQuery := database.DB.Where("family_id = ? AND role = 'child'", familyID)
+	if role == "child" {
+		childQuery = childQuery.Where("id = ?", userID)
+	}
+	var children []models.User
+	if err := childQuery.Order("name").Find(&children).Error; err != nil {
+		return nil, err
+	}
+	childIDs := []string{}
+	for _, child := range children {
+		summary, err := ChildPointSummary(database.DB, child.ID)
+		if err != nil {
+			return nil, err
+		}
+		childIDs = append(childIDs, child.ID)
+		changes.Children = append(changes.Children, SyncChild{
+			ID: child.ID, Name: child.Name, AvatarIcon: child.AvatarIcon,
+			PointsBalance: child.PointsBalance, AvailablePoints: summary.Balance,
+		})
+	}
+
+	changed := func(q *gorm.DB) *gorm.DB {
+		if full {
+			return q
+		}
+		return q.Where("updated_at > ?", since)
+	}
+	deleted := func(model interface{}, scope string, args []interface{}, out *[]string) error {
+		if full {
+			return nil
+		}
+		return database.DB.Unscoped().Model(model).
+			Where(scope, args...).Where("deleted_at > ?", since).Pluck("id", out).Error
+	}
+
+	if err := changed(database.DB.Where("family_id = ?", familyID)).Find(&changes.Tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := changed(database.DB.Where("family_id = ?", familyID)).Find(&changes.Rewards).Error; err != nil {
+		return nil, err
+	}
+	if err := deleted(&models.Task{}, "family_id = ?", []interface{}{familyID}, &changes.Deleted.Tasks); err != nil {
+		return nil, err
+	}
+	if err := deleted(&models.Reward{}, "family_id = ?", []interface{}{familyID}, &changes.Deleted.Rewards); err != nil {
+		return nil, err
+	}
+
+	if len(childIDs) > 0 {
+		logs := changed(database.DB.Where("child_id IN ?", childIDs))
+		redemptions := changed(database.DB.Where("child_id IN ?", childIDs))
+		if full {
+			today := utils.Today(utils.LoadLocation(family.Timezone))
+			logs = logs.Where("completed_date >= ?", today.AddDate(0, 0, -7))
+			redemptions = redemptions.Where("status = 'pending' OR created_at >= ?", started.AddDate(0, 0, -30))
+		}
+		if err := logs.Order("completed_date, created_at").Find(&changes.Logs).Error; err != nil {
+			return nil, err
+		}
+		if err := redemptions.Order("created_at").Find(&changes.Redemptions).Error; err != nil {
+			return nil, err
+		}
+		if err := deleted(&models.DailyLog{}, "child_id IN ?", []interface{}{childIDs}, &changes.Deleted.Logs); err != nil {
+			return nil, err
+		}
+		if err := deleted(&models.Redemption{}, "child_id IN ?", []interface{}{childIDs}, &changes.Deleted.Redemptions); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
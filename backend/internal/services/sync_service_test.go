@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyncDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip(err)
+	}
+	// 23:30 on 10 March in Jakarta.
+	occurredAt := time.Date(2026, 3, 10, 16, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		date    string
+		want    string
+		invalid string
+	}{
+		{date: "", want: "2026-03-10"},
+		{date: "2026-03-10", want: "2026-03-10"},
+		{date: "2026-03-09", want: "2026-03-09"},
+		{date: "2026-03-11", want: "2026-03-11"},
+		{date: "2026-03-08", invalid: "near"},
+		{date: "2026-03-12", invalid: "near"},
+		{date: "2026-02-01", invalid: "near"},
+		{date: "10/03/2026", invalid: "date"},
+	}
+	for _, tt := range tests {
+		got, err := syncDate(SyncEvent{Date: tt.date, OccurredAt: occurredAt}, jakarta)
+		if tt.invalid != "" {
+			if err == nil || len(err.Details) != 1 || err.Details[0].Rule != tt.invalid {
+				t.Errorf("date %q: err = %v, want rule %s", tt.date, err, tt.invalid)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("date %q: %v", tt.date, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("date %q = %s, want %s", tt.date, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
@@ -1,26 +1,50 @@
 package services
 
 import (
-	"errors"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/events"
 	"github.com/username/ramadhan-ceria-backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type TaskService struct{}
+type TaskService struct {
+	events *events.Bus
+}
 
-func NewTaskService() *TaskService {
-	return &TaskService{}
+func NewTaskService(bus *events.Bus) *TaskService {
+	return &TaskService{events: bus}
 }
 
 func (s *TaskService) DB() *gorm.DB {
 	return database.DB
 }
 
-func (s *TaskService) CompleteTask(childID, taskID string, date time.Time) (int, error) {
+// CompletionResult describes a finished task: the points earned after point
+// rules were applied and the child's new balance.
+type CompletionResult struct {
+	LogID        string
+	NewBalance   int
+	EarnedPoints int
+	AppliedRules []AppliedRule
+}
+
+// CompleteTask records a completion and credits the points. clientEventID is
+// the device's UUID for completions synced from offline devices; a second
+// completion with the same ID fails with "Event already applied".
+func (s *TaskService) CompleteTask(childID, taskID string, date time.Time, clientEventID *string) (*CompletionResult, error) {
+	if clientEventID != nil {
+		var count int64
+		if err := database.DB.Model(&models.DailyLog{}).Unscoped().Where("client_event_id = ?", *clientEventID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrEventApplied
+		}
+	}
+
 	tx := database.DB.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -28,140 +52,133 @@ func (s *TaskService) CompleteTask(childID, taskID string, date time.Time) (int,
 		}
 	}()
 
-	var task models.Task
-	if err := tx.Where("id = ?", taskID).First(&task).Error; err != nil {
+	// Locked so concurrent completions see each other's MaxPerDay count and balance.
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND role = 'child'", childID).First(&user).Error; err != nil {
 		tx.Rollback()
-		return 0, errors.New("Task not found")
-	}
-
-	// Check MaxPerDay limit (nil=1, 0=unlimited)
-	maxPerDay := 1
-	if task.MaxPerDay != nil {
-		maxPerDay = *task.MaxPerDay
-	}
-	if maxPerDay > 0 {
-		var count int64
-		tx.Model(&models.DailyLog{}).Where("child_id = ? AND task_id = ? AND completed_date = ? AND deleted_at IS NULL", childID, taskID, date).Count(&count)
-		if count >= int64(maxPerDay) {
-			tx.Rollback()
-			return 0, errors.New("Task already completed today")
-		}
+		return nil, ErrChildNotFound
 	}
 
-	newLog := models.DailyLog{
-		ChildID:       childID,
-		TaskID:        taskID,
-		CompletedDate: date,
-		Status:        "verified",
-		EarnedPoints:  task.PointReward,
-	}
-
-	if err := tx.Create(&newLog).Error; err != nil {
+	// Only the child's own family's tasks, so another family's rules never apply.
+	var task models.Task
+	if err := tx.Where("id = ? AND family_id = ?", taskID, user.FamilyID).First(&task).Error; err != nil {
 		tx.Rollback()
-		return 0, err
+		return nil, ErrTaskNotFound
 	}
 
-	var user models.User
-	if err := tx.Where("id = ?", childID).First(&user).Error; err != nil {
+	if err := checkCompletable(task, childID); err != nil {
 		tx.Rollback()
-		return 0, err
+		return nil, err
 	}
 
-	user.PointsBalance += task.PointReward
-	if err := tx.Save(&user).Error; err != nil {
+	newLog, appliedRules, err := creditCompletion(tx, task, &user, date, clientEventID)
+	if err != nil {
 		tx.Rollback()
-		return 0, err
+		if clientEventID != nil && isUniqueViolation(err) {
+			return nil, ErrEventApplied
+		}
+		return nil, err
 	}
+	earnedPoints := newLog.EarnedPoints
 
 	tx.Commit()
 
-	return user.PointsBalance, nil
+	s.events.Publish(task.FamilyID, events.TaskCompleted, map[string]interface{}{
+		"logId":        newLog.ID,
+		"childId":      childID,
+		"taskId":       taskID,
+		"date":         date.Format("2006-01-02"),
+		"earnedPoints": earnedPoints,
+		"newBalance":   user.PointsBalance,
+	})
+	s.events.Publish(task.FamilyID, events.LeaderboardChanged, map[string]interface{}{"childId": childID})
+
+	return &CompletionResult{
+		LogID:        newLog.ID,
+		NewBalance:   user.PointsBalance,
+		EarnedPoints: earnedPoints,
+		AppliedRules: appliedRules,
+	}, nil
 }
 
-func (s *TaskService) ApplyMagicTemplate(familyID string, templateType string) ([]models.Task, error) {
-	var preset []struct {
-		Name        string
-		PointReward int
-		Icon        string
-		MaxPerDay   int
-	}
-
-	if templateType == "TK" {
-		preset = []struct {
-			Name        string
-			PointReward int
-			Icon        string
-			MaxPerDay   int
-		}{
-			{"Sholat Subuh", 10, "🕋", 1},
-			{"Sholat Dzuhur", 10, "🕋", 1},
-			{"Sholat Ashar", 10, "🕋", 1},
-			{"Sholat Maghrib", 10, "🕋", 1},
-			{"Sholat Isya", 10, "🕋", 1},
-			{"Mengaji Iqro", 15, "📖", 1},
-			{"Hafalan Doa Harian", 10, "🤲", 1},
-			{"Puasa Penuh", 30, "🍽️", 1},
-			{"Membantu Orang Tua", 10, "🧹", 0}, // unlimited
-			{"Berbagi ke Teman", 15, "🎁", 0},   // unlimited
-		}
-	} else {
-		preset = []struct {
-			Name        string
-			PointReward int
-			Icon        string
-			MaxPerDay   int
-		}{
-			{"Sholat Subuh Berjamaah", 15, "🕋", 1},
-			{"Sholat Dzuhur", 10, "🕋", 1},
-			{"Sholat Ashar", 10, "🕋", 1},
-			{"Sholat Maghrib Berjamaah", 15, "🕋", 1},
-			{"Sholat Isya", 10, "🕋", 1},
-			{"Tadarus Al-Quran (1 Halaman)", 20, "📖", 0}, // unlimited
-			{"Hafalan Surat Pendek", 25, "🧠", 1},
-			{"Puasa Penuh", 30, "🍽️", 1},
-			{"Sholat Tarawih", 20, "🕌", 1},
-			{"Sedekah / Infaq", 15, "💷", 0}, // unlimited
+// checkCompletable rejects tasks and children that are read-only after a
+// downgrade: they stay visible but cannot be used.
+func checkCompletable(task models.Task, childID string) error {
+	readOnly, err := ReadOnlyItems(task.FamilyID)
+	if err != nil {
+		return err
+	}
+	for _, id := range readOnly.Tasks {
+		if id == task.ID {
+			return ErrTaskReadOnly
 		}
 	}
-
-	tx := database.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	for _, id := range readOnly.Children {
+		if id == childID {
+			return ErrChildReadOnly
 		}
-	}()
+	}
+	return nil
+}
 
-	var createdTasks []models.Task
-	for _, p := range preset {
-		mpd := p.MaxPerDay
-		task := models.Task{
-			ID:          uuid.New().String(),
-			FamilyID:    familyID,
-			Name:        p.Name,
-			Icon:        p.Icon,
-			PointReward: p.PointReward,
-			MaxPerDay:   &mpd,
-			TaskType:    "daily",
-			IsActive:    true,
-		}
+// taskCategory defaults an empty category to general.
+func taskCategory(category string) string {
+	if category == "" {
+		return models.TaskCategoryGeneral
+	}
+	return category
+}
 
-		// Simple Check for existing tasks with same name in family
-		var existing models.Task
-		if err := tx.Where("family_id = ? AND name = ?", familyID, p.Name).First(&existing).Error; err == nil {
-			continue // Skip if exists
-		}
+// taskQuantity defaults a missing quantity to one unit per completion.
+func taskQuantity(quantity int) int {
+	if quantity < 1 {
+		return 1
+	}
+	return quantity
+}
 
-		createdTasks = append(createdTasks, task)
+// taskMaxPerDay is how often a task counts per day (nil=1, 0=unlimited).
+func taskMaxPerDay(task models.Task) int {
+	if task.MaxPerDay != nil {
+		return *task.MaxPerDay
 	}
+	return 1
+}
 
-	if len(createdTasks) > 0 {
-		if err := tx.CreateInBatches(createdTasks, 10).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+// creditCompletion is the completion path shared by CompleteTask and the
+// bulk log editor: it enforces MaxPerDay, applies point rules, writes the log
+// and credits the child's balance. child is updated in place; the caller
+// commits.
+func creditCompletion(tx *gorm.DB, task models.Task, child *models.User, date time.Time, clientEventID *string) (models.DailyLog, []AppliedRule, error) {
+	if maxPerDay := taskMaxPerDay(task); maxPerDay > 0 {
+		var count int64
+		tx.Model(&models.DailyLog{}).Where("child_id = ? AND task_id = ? AND completed_date = ? AND status = 'verified'", child.ID, task.ID, date).Count(&count)
+		if count >= int64(maxPerDay) {
+			return models.DailyLog{}, nil, ErrTaskCompleted
 		}
 	}
 
-	tx.Commit()
+	earnedPoints, appliedRules, err := EvaluatePointRules(tx, child.ID, task, date)
+	if err != nil {
+		return models.DailyLog{}, nil, err
+	}
 
-	return createdTasks, nil
+	newLog := models.DailyLog{
+		ChildID:       child.ID,
+		TaskID:        task.ID,
+		CompletedDate: date,
+		Status:        "verified",
+		EarnedPoints:  earnedPoints,
+		Quantity:      taskQuantity(task.Quantity),
+		ClientEventID: clientEventID,
+	}
+	if err := tx.Create(&newLog).Error; err != nil {
+		return models.DailyLog{}, nil, err
+	}
+
+	child.PointsBalance += earnedPoints
+	if err := tx.Save(child).Error; err != nil {
+		return models.DailyLog{}, nil, err
+	}
+	return newLog, appliedRules, nil
 }
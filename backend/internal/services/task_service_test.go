@@ -0,0 +1,34 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+)
+
+func TestCompleteTaskOfAnotherFamily(t *testing.T) {
+	testDB(t)
+	familyID, _ := newTestFamily(t)
+	otherID, _ := newTestFamily(t)
+	child := models.User{FamilyID: familyID, Role: "child", Name: "Adik"}
+	if err := database.DB.Create(&child).Error; err != nil {
+		t.Fatal(err)
+	}
+	task := models.Task{FamilyID: otherID, Name: "Sholat Subuh", PointReward: 5}
+	if err := database.DB.Create(&task).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewTaskService(nil).CompleteTask(child.ID, task.ID, time.Now().UTC().Truncate(24*time.Hour), nil)
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("err = %v, want %v", err, ErrTaskNotFound)
+	}
+	var logs int64
+	database.DB.Model(&models.DailyLog{}).Where("child_id = ?", child.ID).Count(&logs)
+	if logs != 0 {
+		t.Errorf("%d logs created, want 0", logs)
+	}
+}
@@ -0,0 +1,865 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/username/ramadhan-ceria-backend/internal/catalog"
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Statuses of a template item in a preview or apply result.
+const (
+	TemplateItemNew      = "new"      // created by apply
+	TemplateItemExisting = "existing" // the family has one with the same name; apply links it
+	TemplateItemApplied  = "applied"  // applied before and still there, nothing to do
+	TemplateItemSkipped  = "skipped"  // cannot be applied, see Code and Reason
+)
+
+// legacyPresets maps the presets of the old magic-template endpoints to
+// catalog templates; anything else gets the SD grades 1-3 template.
+var legacyPresets = map[string]string{
+	"TK": "ramadhan-tk",
+	"tk": "ramadhan-tk",
+}
+
+const legacyDefaultPreset = "ramadhan-sd-1-3"
+
+// templateTargets are the tables template items end up in, by kind.
+var templateTargets = map[string]string{
+	catalog.KindTask:     "tasks",
+	catalog.KindReward:   "rewards",
+	catalog.KindSchedule: "point_rules",
+	catalog.KindBadge:    "badges",
+}
+
+type TemplateService struct {
+	entitlements *EntitlementService
+}
+
+func NewTemplateService(entitlements *EntitlementService) *TemplateService {
+	return &TemplateService{entitlements: entitlements}
+}
+
+// TemplateItemInput mirrors models.TemplateItem and catalog.Item field for
+// field, so the three convert into each other.
+type TemplateItemInput struct {
+	Key            string  `json:"key" validate:"required,max=50"`
+	Kind           string  `json:"kind" validate:"required,oneof=task reward schedule badge"`
+	Name           string  `json:"name" validate:"required,max=100"`
+	Icon           string  `json:"icon" validate:"emoji"`
+	Description    string  `json:"description" validate:"max=500"`
+	Points         int     `json:"points"`                                         // task
+	MaxPerDay      *int    `json:"maxPerDay" validate:"min=0,max=20"`              // task, nil = once a day, 0 = unlimited
+	Category       string  `json:"category" validate:"oneof=general prayer quran"` // task, default general
+	Quantity       int     `json:"quantity" validate:"min=0,max=100"`              // task, units per completion, 0 = 1
+	PointsRequired int     `json:"pointsRequired"`                                 // reward
+	RuleType       string  `json:"ruleType"`                                       // schedule: multiplier, bonus
+	Multiplier     float64 `json:"multiplier"`                                     // schedule
+	BonusPoints    int     `json:"bonusPoints"`                                    // schedule
+	Weekdays       []int   `json:"weekdays" validate:"max=7,dive,min=0,max=6"`     // schedule, 0=Sunday … 6=Saturday
+	LastDays       int     `json:"lastDays" validate:"min=0,max=30"`               // schedule, last N days of the season
+	Task           string  `json:"task"`                                           // schedule and badge: key of a task item
+	Metric         string  `json:"metric" validate:"oneof=completions points"`     // badge
+	Threshold      int     `json:"threshold"`                                      // badge
+}
+
+type TemplateInput struct {
+	Name        string              `json:"name" validate:"required,max=100"`
+	Description string              `json:"description" validate:"max=500"`
+	AgeBand     string              `json:"ageBand" validate:"required,oneof=tk sd_1_3 sd_4_6 smp"`
+	Items       []TemplateItemInput `json:"items" validate:"required,max=100,dive"`
+}
+
+func (in TemplateInput) Check(v *Violations) {
+	tasks := map[string]bool{}
+	for _, item := range in.Items {
+		if item.Kind == catalog.KindTask {
+			tasks[item.Key] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, item := range in.Items {
+		field := func(name string) string { return fmt.Sprintf("items[%d].%s", i, name) }
+		if item.Key != "" && seen[item.Key] {
+			v.Add(field("key"), "unique", map[string]interface{}{"value": item.Key})
+		}
+		seen[item.Key] = true
+
+		switch item.Kind {
+		case catalog.KindTask:
+			if item.Points < 1 || item.Points > 1000 {
+				v.Add(field("points"), "between", map[string]interface{}{"min": 1, "max": 1000})
+			}
+		case catalog.KindReward:
+			if item.PointsRequired < 1 || item.PointsRequired > 100000 {
+				v.Add(field("pointsRequired"), "between", map[string]interface{}{"min": 1, "max": 100000})
+			}
+		case catalog.KindSchedule:
+			switch item.RuleType {
+			case "multiplier":
+				if item.Multiplier <= 1 || item.Multiplier > 10 {
+					v.Add(field("multiplier"), "between", map[string]interface{}{"min": 1, "max": 10})
+				}
+			case "bonus":
+				if item.BonusPoints <= 0 {
+					v.Add(field("bonusPoints"), "positive", nil)
+				}
+			default:
+				v.Add(field("ruleType"), "one_of", map[string]interface{}{"values": []string{"multiplier", "bonus"}})
+			}
+		case catalog.KindBadge:
+			if item.Threshold <= 0 {
+				v.Add(field("threshold"), "positive", nil)
+			}
+		}
+		if item.Task != "" && (item.Kind == catalog.KindSchedule || item.Kind == catalog.KindBadge) && !tasks[item.Task] {
+			v.Add(field("task"), "task_ref", nil)
+		}
+	}
+}
+
+func (in *TemplateInput) normalize() {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+	for i := range in.Items {
+		in.Items[i].Key = strings.TrimSpace(in.Items[i].Key)
+		in.Items[i].Name = strings.TrimSpace(in.Items[i].Name)
+	}
+}
+
+func templateItems(in []TemplateItemInput) []models.TemplateItem {
+	items := make([]models.TemplateItem, len(in))
+	for i, item := range in {
+		items[i] = models.TemplateItem(item)
+	}
+	return items
+}
+
+// SeedCatalog copies the embedded catalog into the templates table,
+// replacing older versions of built-in templates. A catalog entry that fails
+// validation stops startup.
+func (s *TemplateService) SeedCatalog() error {
+	list, err := catalog.Load()
+	if err != nil {
+		return err
+	}
+	for _, t := range list {
+		in := TemplateInput{Name: t.Name, Description: t.Description, AgeBand: t.AgeBand, Items: make([]TemplateItemInput, len(t.Items))}
+		for i, item := range t.Items {
+			in.Items[i] = TemplateItemInput(item)
+		}
+		in.normalize()
+		if err := Validate(in); err != nil {
+			return fmt.Errorf("catalog template %s: %w", t.Key, err)
+		}
+
+		key := t.Key
+		tmpl := models.Template{Key: &key, Name: in.Name, Description: in.Description, AgeBand: in.AgeBand, Items: templateItems(in.Items)}
+		err := database.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "age_band", "items", "updated_at"}),
+		}).Create(&tmpl).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTemplates returns the built-in templates and the family's own, youngest
+// age band first. ageBand, when set, keeps only that band.
+func (s *TemplateService) ListTemplates(familyID, ageBand string) ([]models.Template, error) {
+	q := database.DB.Where("family_id IS NULL OR family_id = ?", familyID)
+	if ageBand != "" {
+		if !contains(catalog.AgeBands, ageBand) {
+			return nil, InvalidField("ageBand", "one_of", map[string]interface{}{"values": catalog.AgeBands})
+		}
+		q = q.Where("age_band = ?", ageBand)
+	}
+	list := []models.Template{}
+	if err := q.Order("family_id IS NOT NULL, name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	band := func(t models.Template) int {
+		for i, b := range catalog.AgeBands {
+			if b == t.AgeBand {
+				return i
+			}
+		}
+		return len(catalog.AgeBands)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if (list[i].FamilyID == nil) != (list[j].FamilyID == nil) {
+			return list[i].FamilyID == nil
+		}
+		return band(list[i]) < band(list[j])
+	})
+	return list, nil
+}
+
+// GetTemplate returns a template the family may apply: a built-in one, its
+// own or one shared by another family.
+func (s *TemplateService) GetTemplate(familyID, templateID string) (*models.Template, error) {
+	var tmpl models.Template
+	err := database.DB.Where("id = ?", templateID).
+		Where("family_id IS NULL OR family_id = ? OR share_code IS NOT NULL", familyID).
+		First(&tmpl).Error
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	return &tmpl, nil
+}
+
+// GetSharedTemplate looks a template up by the code its family shared.
+func (s *TemplateService) GetSharedTemplate(code string) (*models.Template, error) {
+	var tmpl models.Template
+	if err := database.DB.Where("share_code = ?", normalizeCode(code)).First(&tmpl).Error; err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	return &tmpl, nil
+}
+
+// ownTemplate returns a template written by the family; built-in templates
+// cannot be changed.
+func (s *TemplateService) ownTemplate(familyID, templateID string) (*models.Template, error) {
+	var tmpl models.Template
+	if err := database.DB.Where("id = ?", templateID).First(&tmpl).Error; err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	if tmpl.FamilyID == nil {
+		return nil, ErrTemplateBuiltIn
+	}
+	if *tmpl.FamilyID != familyID {
+		return nil, ErrTemplateNotFound
+	}
+	return &tmpl, nil
+}
+
+func (s *TemplateService) CreateTemplate(familyID string, in TemplateInput) (*models.Template, error) {
+	in.normalize()
+	if err := Validate(in); err != nil {
+		return nil, err
+	}
+	tmpl := models.Template{FamilyID: &familyID, Name: in.Name, Description: in.Description, AgeBand: in.AgeBand, Items: templateItems(in.Items)}
+	if err := database.DB.Create(&tmpl).Error; err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+func (s *TemplateService) UpdateTemplate(familyID, templateID string, in TemplateInput) (*models.Template, error) {
+	tmpl, err := s.ownTemplate(familyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	in.normalize()
+	if err := Validate(in); err != nil {
+		return nil, err
+	}
+	tmpl.Name, tmpl.Description, tmpl.AgeBand, tmpl.Items = in.Name, in.Description, in.AgeBand, templateItems(in.Items)
+	if err := database.DB.Save(tmpl).Error; err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// DeleteTemplate removes a family's template. What was applied from it stays.
+func (s *TemplateService) DeleteTemplate(familyID, templateID string) error {
+	tmpl, err := s.ownTemplate(familyID, templateID)
+	if err != nil {
+		return err
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Free the code before the soft delete so it can't be looked up again.
+		if err := tx.Model(tmpl).Update("share_code", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tmpl).Error
+	})
+}
+
+// ShareTemplate gives a family's template a share code, keeping the
+// existing one when it is already shared.
+func (s *TemplateService) ShareTemplate(familyID, templateID string) (*models.Template, error) {
+	tmpl, err := s.ownTemplate(familyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.ShareCode != nil {
+		return tmpl, nil
+	}
+	for attempt := 0; ; attempt++ {
+		suffix, err := utils.RandomHex(4)
+		if err != nil {
+			return nil, err
+		}
+		result := database.DB.Model(&models.Template{}).
+			Where("id = ? AND share_code IS NULL", tmpl.ID).
+			Update("share_code", strings.ToUpper(suffix))
+		if result.Error == nil {
+			break
+		}
+		if attempt == 4 {
+			return nil, result.Error
+		}
+	}
+	return s.ownTemplate(familyID, templateID)
+}
+
+// UnshareTemplate drops the share code; families that applied the template keep what they got.
+func (s *TemplateService) UnshareTemplate(familyID, templateID string) (*models.Template, error) {
+	tmpl, err := s.ownTemplate(familyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.DB.Model(tmpl).Update("share_code", nil).Error; err != nil {
+		return nil, err
+	}
+	tmpl.ShareCode = nil
+	return tmpl, nil
+}
+
+type TemplateFromFamilyRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	AgeBand     string `json:"ageBand" validate:"required,oneof=tk sd_1_3 sd_4_6 smp"`
+}
+
+// CreateFromFamily saves the family's active tasks, rewards, point rules and
+// badges as a new template, already marked as applied to the family itself.
+// Rules for a single child and rules with dates other than the end of the
+// season don't carry over to another family and are left out, as are rules
+// and badges for tasks that are not active.
+func (s *TemplateService) CreateFromFamily(familyID string, req TemplateFromFamilyRequest) (*models.Template, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+
+	var tasks []models.Task
+	var rewards []models.Reward
+	var rules []models.PointRule
+	var badges []models.Badge
+	if err := database.DB.Where("family_id = ? AND is_active = true", familyID).Order("created_at").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("family_id = ? AND is_active = true", familyID).Order("created_at").Find(&rewards).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("family_id = ? AND is_active = true AND child_id IS NULL", familyID).Order("created_at").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("family_id = ?", familyID).Order("created_at").Find(&badges).Error; err != nil {
+		return nil, err
+	}
+
+	in := TemplateInput{Name: req.Name, Description: req.Description, AgeBand: req.AgeBand, Items: []TemplateItemInput{}}
+	sources := []string{} // target ID of each item, in item order
+	keys := map[string]bool{}
+	key := func(kind, name string) string {
+		base := utils.Slugify(name)
+		if len(base) > 40 {
+			base = strings.Trim(base[:40], "-")
+		}
+		if base == "" {
+			base = kind
+		}
+		k := base
+		for n := 2; keys[k]; n++ {
+			k = fmt.Sprintf("%s-%d", base, n)
+		}
+		keys[k] = true
+		return k
+	}
+	add := func(item TemplateItemInput, sourceID string) {
+		in.Items = append(in.Items, item)
+		sources = append(sources, sourceID)
+	}
+
+	taskKeys := map[string]string{}
+	for _, t := range tasks {
+		k := key(catalog.KindTask, t.Name)
+		taskKeys[t.ID] = k
+		add(TemplateItemInput{Key: k, Kind: catalog.KindTask, Name: t.Name, Icon: t.Icon, Points: t.PointReward, MaxPerDay: t.MaxPerDay, Category: t.Category, Quantity: t.Quantity}, t.ID)
+	}
+	for _, r := range rewards {
+		add(TemplateItemInput{Key: key(catalog.KindReward, r.Name), Kind: catalog.KindReward, Name: r.Name, Icon: r.Icon, PointsRequired: r.PointsRequired}, r.ID)
+	}
+	for _, r := range rules {
+		item := TemplateItemInput{Kind: catalog.KindSchedule, Name: r.Name, RuleType: r.RuleType, BonusPoints: r.BonusPoints, Weekdays: parseWeekdays(r.Weekdays)}
+		if r.RuleType == "multiplier" {
+			item.Multiplier, item.BonusPoints = r.Multiplier, 0
+		}
+		if r.TaskID != nil {
+			if item.Task = taskKeys[*r.TaskID]; item.Task == "" {
+				continue
+			}
+		}
+		if r.StartDate != nil || r.EndDate != nil {
+			if r.StartDate == nil || r.EndDate == nil || family.SeasonEnd == nil || !r.EndDate.Equal(*family.SeasonEnd) {
+				continue
+			}
+			if item.LastDays = int(r.EndDate.Sub(*r.StartDate).Hours()/24) + 1; item.LastDays > 30 {
+				continue
+			}
+		}
+		item.Key = key(catalog.KindSchedule, r.Name)
+		add(item, r.ID)
+	}
+	for _, b := range badges {
+		item := TemplateItemInput{Kind: catalog.KindBadge, Name: b.Name, Icon: b.Icon, Description: b.Description, Metric: b.Metric, Threshold: b.Threshold}
+		if b.TaskID != nil {
+			if item.Task = taskKeys[*b.TaskID]; item.Task == "" {
+				continue
+			}
+		}
+		item.Key = key(catalog.KindBadge, b.Name)
+		add(item, b.ID)
+	}
+
+	in.normalize()
+	if err := Validate(in); err != nil {
+		return nil, err
+	}
+
+	tmpl := models.Template{FamilyID: &familyID, Name: in.Name, Description: in.Description, AgeBand: in.AgeBand, Items: templateItems(in.Items)}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&tmpl).Error; err != nil {
+			return err
+		}
+		for i, item := range tmpl.Items {
+			if err := rememberApplication(tx, familyID, tmpl.ID, item, sources[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+func parseWeekdays(weekdays string) []int {
+	days := []int{}
+	for _, d := range strings.Split(weekdays, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(d)); err == nil {
+			days = append(days, n)
+		}
+	}
+	return days
+}
+
+func rememberApplication(db *gorm.DB, familyID, templateID string, item models.TemplateItem, targetID string) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "family_id"}, {Name: "template_id"}, {Name: "item_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"kind", "target_id", "updated_at"}),
+	}).Create(&models.TemplateApplication{FamilyID: familyID, TemplateID: templateID, ItemKey: item.Key, Kind: item.Kind, TargetID: targetID}).Error
+}
+
+// TemplateSelection narrows what Preview and Apply act on; empty lists mean
+// every item.
+type TemplateSelection struct {
+	Items []string `json:"items" validate:"max=100"` // item keys
+	Kinds []string `json:"kinds" validate:"max=4,dive,oneof=task reward schedule badge"`
+}
+
+type TemplatePlanItem struct {
+	Key      string `json:"key"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	TargetID string `json:"targetId,omitempty"` // the task, reward, point rule or badge
+	Code     string `json:"code,omitempty"`     // why the item was skipped
+	Reason   string `json:"reason,omitempty"`
+
+	err *Error
+}
+
+// skip records why the item cannot be applied. Reason is English until
+// Localize is called.
+func (r *TemplatePlanItem) skip(err *Error) {
+	r.Status, r.Code, r.Reason, r.err = TemplateItemSkipped, err.Code, err.Error(), err
+}
+
+// TemplatePlan is what applying a template does (a preview) or did.
+type TemplatePlan struct {
+	TemplateID string             `json:"templateId"`
+	Name       string             `json:"name"`
+	Applied    bool               `json:"applied"` // false for a preview
+	Counts     map[string]int     `json:"counts"`  // items per status
+	Items      []TemplatePlanItem `json:"items"`
+}
+
+// Localize renders the Reason of skipped items in lang.
+func (p *TemplatePlan) Localize(lang string) {
+	for i := range p.Items {
+		if p.Items[i].err != nil {
+			p.Items[i].Reason = p.Items[i].err.Message(lang)
+		}
+	}
+}
+
+// Preview says what Apply would do with the same selection, changing nothing.
+func (s *TemplateService) Preview(familyID, templateID string, sel TemplateSelection) (*TemplatePlan, error) {
+	tmpl, err := s.GetTemplate(familyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(database.DB, familyID, tmpl, sel, false)
+}
+
+// Apply creates the selected items the family doesn't have yet. Items are
+// remembered by template and key, so applying again only adds what is new
+// or was deleted since; an item named like something the family already has
+// is linked to it instead of duplicated. Items over the plan's limits, or
+// that need a task or season the family lacks, are skipped.
+func (s *TemplateService) Apply(familyID, templateID string, sel TemplateSelection) (*TemplatePlan, error) {
+	tmpl, err := s.GetTemplate(familyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	var plan *TemplatePlan
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// One apply per family at a time, so two requests can't both create an item.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Family{}, "id = ?", familyID).Error; err != nil {
+			return ErrFamilyNotFound
+		}
+		var err error
+		plan, err = s.run(tx, familyID, tmpl, sel, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ApplyTaskPreset applies the tasks of the catalog template behind a preset
+// of the old magic-template endpoint (TK or SD) and returns the new ones.
+func (s *TemplateService) ApplyTaskPreset(familyID, preset string) ([]models.Task, error) {
+	ids, err := s.applyPreset(familyID, preset, catalog.KindTask)
+	if err != nil {
+		return nil, err
+	}
+	tasks := []models.Task{}
+	if len(ids) > 0 {
+		if err := database.DB.Where("id IN ?", ids).Order("created_at").Find(&tasks).Error; err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// ApplyRewardPreset is ApplyTaskPreset for the rewards.
+func (s *TemplateService) ApplyRewardPreset(familyID, preset string) ([]models.Reward, error) {
+	ids, err := s.applyPreset(familyID, preset, catalog.KindReward)
+	if err != nil {
+		return nil, err
+	}
+	rewards := []models.Reward{}
+	if len(ids) > 0 {
+		if err := database.DB.Where("id IN ?", ids).Order("created_at").Find(&rewards).Error; err != nil {
+			return nil, err
+		}
+	}
+	return rewards, nil
+}
+
+func (s *TemplateService) applyPreset(familyID, preset, kind string) ([]string, error) {
+	key, ok := legacyPresets[preset]
+	if !ok {
+		key = legacyDefaultPreset
+	}
+	var tmpl models.Template
+	if err := database.DB.Where("key = ?", key).First(&tmpl).Error; err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	plan, err := s.Apply(familyID, tmpl.ID, TemplateSelection{Kinds: []string{kind}})
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, item := range plan.Items {
+		if item.Status == TemplateItemNew {
+			ids = append(ids, item.TargetID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *TemplateService) run(db *gorm.DB, familyID string, tmpl *models.Template, sel TemplateSelection, apply bool) (*TemplatePlan, error) {
+	if err := Validate(sel); err != nil {
+		return nil, err
+	}
+	keys := map[string]bool{}
+	for _, item := range tmpl.Items {
+		keys[item.Key] = true
+	}
+	var v Violations
+	for i, key := range sel.Items {
+		if !keys[key] {
+			v.Add(fmt.Sprintf("items[%d]", i), "unknown_value", map[string]interface{}{"value": key})
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	r := &templateRun{db: db, apply: apply, template: tmpl, applied: map[string]string{}, tasks: map[string]string{}, left: map[string]int{}, full: map[string]*Error{}}
+	if err := db.First(&r.family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	var applications []models.TemplateApplication
+	if err := db.Where("family_id = ? AND template_id = ?", familyID, tmpl.ID).Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range applications {
+		r.applied[a.ItemKey] = a.TargetID
+	}
+	for _, limit := range []string{LimitTasks, LimitRewards} {
+		left, full, err := s.entitlements.Remaining(familyID, limit)
+		if err != nil {
+			return nil, err
+		}
+		r.left[limit], r.full[limit] = left, full
+	}
+	if tmpl.FamilyID != nil {
+		// Badges of built-in templates are for everyone; copying another
+		// family's badges counts as custom badges.
+		if err := s.entitlements.Check(familyID, FeatureCustomBadges); err != nil {
+			e, ok := err.(*Error)
+			if !ok {
+				return nil, err
+			}
+			r.badges = e
+		}
+	}
+
+	selected := make([]bool, len(tmpl.Items))
+	for i, item := range tmpl.Items {
+		selected[i] = (len(sel.Items) == 0 || contains(sel.Items, item.Key)) && (len(sel.Kinds) == 0 || contains(sel.Kinds, item.Kind))
+	}
+	// Tasks left out of the selection still count for the schedules and badges that need them.
+	for i, item := range tmpl.Items {
+		if item.Kind != catalog.KindTask || selected[i] {
+			continue
+		}
+		id, err := r.current(item)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			r.tasks[item.Key] = id
+		}
+	}
+
+	plan := &TemplatePlan{TemplateID: tmpl.ID, Name: tmpl.Name, Applied: apply, Counts: map[string]int{}, Items: []TemplatePlanItem{}}
+	results := make([]*TemplatePlanItem, len(tmpl.Items))
+	for _, kind := range catalog.Kinds {
+		for i, item := range tmpl.Items {
+			if !selected[i] || item.Kind != kind {
+				continue
+			}
+			result, err := r.item(item)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+		}
+	}
+	for _, result := range results {
+		if result != nil {
+			plan.Items = append(plan.Items, *result)
+			plan.Counts[result.Status]++
+		}
+	}
+	return plan, nil
+}
+
+// templateRun carries the state of one preview or apply.
+type templateRun struct {
+	db       *gorm.DB
+	apply    bool
+	family   models.Family
+	template *models.Template
+	applied  map[string]string // item key → target recorded by an earlier apply
+	tasks    map[string]string // task item key → task ID, "" when only planned by a preview
+	left     map[string]int    // items each limit still allows, -1 = unlimited
+	full     map[string]*Error // error for a limit with nothing left
+	badges   *Error            // why this template's badges can't be added, nil if they can
+}
+
+// current finds what an item already is in the family: the target of an
+// earlier apply if it still exists, else one with the same name.
+func (r *templateRun) current(item models.TemplateItem) (string, error) {
+	table := templateTargets[item.Kind]
+	if id, ok := r.applied[item.Key]; ok {
+		var count int64
+		if err := r.db.Raw(`SELECT COUNT(*) FROM `+table+` WHERE id = ? AND family_id = ? AND deleted_at IS NULL`, id, r.family.ID).Scan(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return id, nil
+		}
+	}
+	var id string
+	err := r.db.Raw(`SELECT id FROM `+table+` WHERE family_id = ? AND LOWER(name) = LOWER(?) AND deleted_at IS NULL ORDER BY created_at LIMIT 1`, r.family.ID, item.Name).Scan(&id).Error
+	return id, err
+}
+
+func (r *templateRun) item(item models.TemplateItem) (*TemplatePlanItem, error) {
+	result := &TemplatePlanItem{Key: item.Key, Kind: item.Kind, Name: item.Name}
+
+	id, err := r.current(item)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		result.TargetID = id
+		if r.applied[item.Key] == id {
+			result.Status = TemplateItemApplied
+		} else {
+			result.Status = TemplateItemExisting
+			if r.apply {
+				if err := rememberApplication(r.db, r.family.ID, r.template.ID, item, id); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if item.Kind == catalog.KindTask {
+			r.tasks[item.Key] = id
+		}
+		return result, nil
+	}
+
+	if reason := r.blocked(item); reason != nil {
+		result.skip(reason)
+		return result, nil
+	}
+	result.Status = TemplateItemNew
+	if item.Kind == catalog.KindTask {
+		r.tasks[item.Key] = ""
+	}
+	if !r.apply {
+		return result, nil
+	}
+
+	if result.TargetID, err = r.create(item); err != nil {
+		return nil, err
+	}
+	if item.Kind == catalog.KindTask {
+		r.tasks[item.Key] = result.TargetID
+	}
+	return result, rememberApplication(r.db, r.family.ID, r.template.ID, item, result.TargetID)
+}
+
+// blocked says why a new item can't be created, taking it from the plan's
+// limits when it can.
+func (r *templateRun) blocked(item models.TemplateItem) *Error {
+	if item.Task != "" {
+		if _, ok := r.tasks[item.Task]; !ok {
+			name := item.Task
+			for _, t := range r.template.Items {
+				if t.Key == item.Task {
+					name = t.Name
+				}
+			}
+			return ErrTemplateTaskMissing.With(map[string]interface{}{"task": name})
+		}
+	}
+
+	var limit string
+	switch item.Kind {
+	case catalog.KindTask:
+		limit = LimitTasks
+	case catalog.KindReward:
+		limit = LimitRewards
+	case catalog.KindSchedule:
+		if item.LastDays > 0 && r.family.SeasonEnd == nil {
+			return ErrSeasonNotConfigured
+		}
+	case catalog.KindBadge:
+		if r.badges != nil {
+			return r.badges
+		}
+	}
+	if limit != "" {
+		switch r.left[limit] {
+		case 0:
+			return r.full[limit]
+		case -1:
+		default:
+			r.left[limit]--
+		}
+	}
+	return nil
+}
+
+func (r *templateRun) create(item models.TemplateItem) (string, error) {
+	var taskID *string
+	if item.Task != "" {
+		id := r.tasks[item.Task]
+		taskID = &id
+	}
+
+	switch item.Kind {
+	case catalog.KindTask:
+		maxPerDay := item.MaxPerDay
+		if maxPerDay == nil {
+			one := 1
+			maxPerDay = &one
+		}
+		task := models.Task{FamilyID: r.family.ID, Name: item.Name, Icon: item.Icon, PointReward: item.Points, MaxPerDay: maxPerDay,
+			TaskType: "daily", Category: taskCategory(item.Category), Quantity: taskQuantity(item.Quantity), IsActive: true}
+		err := r.db.Create(&task).Error
+		return task.ID, err
+
+	case catalog.KindReward:
+		reward := models.Reward{FamilyID: r.family.ID, Name: item.Name, Icon: item.Icon, PointsRequired: item.PointsRequired, IsActive: true}
+		err := r.db.Create(&reward).Error
+		return reward.ID, err
+
+	case catalog.KindSchedule:
+		days := make([]string, 0, len(item.Weekdays))
+		for _, d := range item.Weekdays {
+			days = append(days, strconv.Itoa(d))
+		}
+		rule := models.PointRule{FamilyID: r.family.ID, Name: item.Name, RuleType: item.RuleType, Multiplier: 1, TaskID: taskID, Weekdays: strings.Join(days, ","), IsActive: true}
+		if item.RuleType == "multiplier" {
+			rule.Multiplier = item.Multiplier
+		} else {
+			rule.BonusPoints = item.BonusPoints
+		}
+		if item.LastDays > 0 {
+			end := *r.family.SeasonEnd
+			start := end.AddDate(0, 0, -(item.LastDays - 1))
+			if r.family.SeasonStart != nil && start.Before(*r.family.SeasonStart) {
+				start = *r.family.SeasonStart
+			}
+			rule.StartDate, rule.EndDate = &start, &end
+		}
+		err := r.db.Create(&rule).Error
+		return rule.ID, err
+
+	case catalog.KindBadge:
+		badge := models.Badge{FamilyID: r.family.ID, Name: item.Name, Icon: item.Icon, Description: item.Description, Metric: item.Metric, Threshold: item.Threshold, TaskID: taskID}
+		if badge.Metric == "" {
+			badge.Metric = "completions"
+		}
+		err := r.db.Create(&badge).Error
+		return badge.ID, err
+	}
+	return "", fmt.Errorf("unknown template item kind %q", item.Kind)
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
+)
+
+// Validate checks the `validate` struct tags of req and, when req is a
+// Checker, its cross-field rules. Every violation is reported at once as a
+// validation_failed error; nil means req is valid.
+//
+// Tags are comma-separated rules, applied in order:
+//
+//	required   strings not blank, pointers not nil, slices not empty
+//	notblank   a string that is present must not be blank (for *string updates)
+//	min=N      numbers >= N, strings >= N characters, slices >= N items
+//	max=N      numbers <= N, strings <= N characters, slices <= N items
+//	oneof=a b  the value is one of the listed words
+//	pin        exactly 4 digits
+//	whatsapp   a mobile number whatsapp.NormalizePhone accepts
+//	emoji      a single emoji
+//	date, time, email, uuid, timezone
+//	dive       the rules after it apply to each slice element; struct
+//	           fields and elements are only checked with dive
+//
+// Field names in violations are the json names, e.g. "tasks[0].count".
+// Blank strings and nil pointers skip every rule but required and notblank.
+func Validate(req interface{}) error {
+	var v Violations
+	v.Validate(req)
+	return v.Err()
+}
+
+// Checker is implemented by requests with rules that span fields. Check
+// runs after the tag rules and adds to the same violations.
+type Checker interface {
+	Check(v *Violations)
+}
+
+// Violations collects field problems so a request reports all of them.
+type Violations struct {
+	details []FieldError
+}
+
+// Validate adds the violations of req, for callers that check more than
+// its tags before reporting.
+func (v *Violations) Validate(req interface{}) {
+	validateValue(v, "", reflect.ValueOf(req))
+	if checker, ok := req.(Checker); ok {
+		checker.Check(v)
+	}
+}
+
+// Add records that field broke rule.
+func (v *Violations) Add(field, rule string, params map[string]interface{}) {
+	v.details = append(v.details, FieldError{Field: field, Rule: rule, Params: params})
+}
+
+// Has says whether field already has a violation, so cross-field rules can
+// skip values that didn't parse.
+func (v *Violations) Has(field string) bool {
+	for _, d := range v.details {
+		if d.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err is the validation error, or nil when nothing was added.
+func (v *Violations) Err() error {
+	if len(v.details) == 0 {
+		return nil
+	}
+	return ValidationError(v.details...)
+}
+
+func validateValue(v *Violations, prefix string, val reflect.Value) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := jsonName(sf)
+		if name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		validateField(v, name, val.Field(i), strings.Split(tag, ","))
+	}
+}
+
+func jsonName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+func validateField(v *Violations, field string, val reflect.Value, rules []string) {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			if hasRule(rules, "required") {
+				v.Add(field, "required", nil)
+			}
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		validateString(v, field, val.String(), rules)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		validateNumber(v, field, float64(val.Int()), rules)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		validateNumber(v, field, float64(val.Uint()), rules)
+	case reflect.Float32, reflect.Float64:
+		validateNumber(v, field, val.Float(), rules)
+	case reflect.Slice, reflect.Array:
+		validateSlice(v, field, val, rules)
+	case reflect.Struct:
+		if contains(rules, "dive") {
+			validateValue(v, field, val)
+		}
+	}
+}
+
+func validateString(v *Violations, field, s string, rules []string) {
+	if strings.TrimSpace(s) == "" {
+		if hasRule(rules, "required") || hasRule(rules, "notblank") {
+			v.Add(field, "required", nil)
+		}
+		return
+	}
+
+	length := utf8.RuneCountInString(s)
+	min, hasMin := ruleNumber(rules, "min")
+	max, hasMax := ruleNumber(rules, "max")
+	switch {
+	case hasMin && hasMax && (float64(length) < min || float64(length) > max):
+		v.Add(field, "length_between", bounds(min, max))
+	case hasMin && !hasMax && float64(length) < min:
+		v.Add(field, "min_length", map[string]interface{}{"min": number(min)})
+	case hasMax && !hasMin && float64(length) > max:
+		v.Add(field, "max_length", map[string]interface{}{"max": number(max)})
+	}
+
+	for _, rule := range rules {
+		name, arg := splitRule(rule)
+		var ok bool
+		switch name {
+		case "oneof":
+			if values := strings.Fields(arg); !contains(values, s) {
+				v.Add(field, "one_of", map[string]interface{}{"values": values})
+			}
+			continue
+		case "pin":
+			ok = isPIN(s)
+		case "emoji":
+			ok = isSingleEmoji(s)
+		case "whatsapp":
+			_, err := whatsapp.NormalizePhone(s)
+			ok = err == nil
+		case "date":
+			_, err := time.Parse("2006-01-02", s)
+			ok = err == nil
+		case "time":
+			_, err := time.Parse("15:04", s)
+			ok = err == nil && len(s) == 5
+		case "email":
+			addr, err := mail.ParseAddress(s)
+			ok = err == nil && addr.Address == s
+		case "uuid":
+			_, err := uuid.Parse(s)
+			ok = err == nil
+		case "timezone":
+			_, err := time.LoadLocation(s)
+			ok = err == nil
+		default:
+			continue
+		}
+		if !ok {
+			v.Add(field, name, nil)
+		}
+	}
+}
+
+func validateNumber(v *Violations, field string, n float64, rules []string) {
+	min, hasMin := ruleNumber(rules, "min")
+	max, hasMax := ruleNumber(rules, "max")
+	switch {
+	case hasMin && hasMax && (n < min || n > max):
+		v.Add(field, "between", bounds(min, max))
+	case hasMin && !hasMax && n < min:
+		switch min {
+		case 0:
+			v.Add(field, "not_negative", nil)
+		case 1:
+			v.Add(field, "positive", nil)
+		default:
+			v.Add(field, "min", map[string]interface{}{"min": number(min)})
+		}
+	case hasMax && !hasMin && n > max:
+		v.Add(field, "max", map[string]interface{}{"max": number(max)})
+	}
+
+	for _, rule := range rules {
+		name, arg := splitRule(rule)
+		if name == "oneof" && !contains(strings.Fields(arg), strconv.FormatFloat(n, 'f', -1, 64)) {
+			v.Add(field, "one_of", map[string]interface{}{"values": strings.Fields(arg)})
+		}
+	}
+}
+
+func validateSlice(v *Violations, field string, val reflect.Value, rules []string) {
+	own, elem := rules, []string(nil)
+	for i, rule := range rules {
+		if rule == "dive" {
+			own, elem = rules[:i], rules[i+1:]
+			break
+		}
+	}
+
+	n := val.Len()
+	min, hasMin := ruleNumber(own, "min")
+	max, hasMax := ruleNumber(own, "max")
+	switch {
+	case n == 0 && hasRule(own, "required"):
+		v.Add(field, "required", nil)
+	case hasMin && float64(n) < min:
+		v.Add(field, "min_items", map[string]interface{}{"min": number(min)})
+	case hasMax && float64(n) > max:
+		v.Add(field, "max_items", map[string]interface{}{"max": number(max)})
+	}
+
+	if elem == nil {
+		return
+	}
+	for i := 0; i < n; i++ {
+		item := val.Index(i)
+		name := fmt.Sprintf("%s[%d]", field, i)
+		for item.Kind() == reflect.Ptr && !item.IsNil() {
+			item = item.Elem()
+		}
+		if item.Kind() == reflect.Struct {
+			validateValue(v, name, item)
+			continue
+		}
+		validateField(v, name, item, elem)
+	}
+}
+
+func splitRule(rule string) (string, string) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+	return name, arg
+}
+
+// hasRule and ruleNumber only look at the rules before a dive.
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == "dive" {
+			return false
+		}
+		if n, _ := splitRule(rule); n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func ruleNumber(rules []string, name string) (float64, bool) {
+	for _, rule := range rules {
+		if rule == "dive" {
+			return 0, false
+		}
+		if n, arg := splitRule(rule); n == name {
+			f, err := strconv.ParseFloat(arg, 64)
+			return f, err == nil
+		}
+	}
+	return 0, false
+}
+
+func bounds(min, max float64) map[string]interface{} {
+	return map[string]interface{}{"min": number(min), "max": number(max)}
+}
+
+// number prints whole bounds without a decimal point.
+func number(f float64) interface{} {
+	if f == float64(int64(f)) {
+		return int64(f)
+	}
+	return f
+}
+
+func isPIN(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isSingleEmoji accepts one emoji as a user would pick it: a pictograph with
+// optional skin tone and variation selector, joined sequences like the family emoji, flags
+// and keycaps.
+func isSingleEmoji(s string) bool {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return false
+	}
+	if isRegionalIndicator(runes[0]) {
+		return len(runes) == 2 && isRegionalIndicator(runes[1])
+	}
+	if strings.ContainsRune("0123456789#*", runes[0]) {
+		rest := string(runes[1:])
+		return rest == "\u20e3" || rest == "\ufe0f\u20e3"
+	}
+
+	for i := 0; ; {
+		if i >= len(runes) || !isPictograph(runes[i]) {
+			return false
+		}
+		i++
+		for i < len(runes) && isEmojiModifier(runes[i]) {
+			i++
+		}
+		if i == len(runes) {
+			return true
+		}
+		if runes[i] != '\u200d' { // zero-width joiner
+			return false
+		}
+		i++
+	}
+}
+
+func isPictograph(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF && !isRegionalIndicator(r) && !isEmojiModifier(r)) ||
+		(r >= 0x2000 && r <= 0x2BFF && unicode.Is(unicode.So, r)) ||
+		r == 0x00A9 || r == 0x00AE || r == 0x203C || r == 0x2049 ||
+		r == 0x3030 || r == 0x303D || r == 0x3297 || r == 0x3299
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isEmojiModifier covers skin tones, variation selectors and the tag
+// characters of subdivision flags.
+func isEmojiModifier(r rune) bool {
+	return (r >= 0x1F3FB && r <= 0x1F3FF) || r == 0xFE0F || r == 0xFE0E ||
+		(r >= 0xE0020 && r <= 0xE007F)
+}
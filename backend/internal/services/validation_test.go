@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testItem struct {
+	TaskID string `json:"taskId" validate:"required,uuid"`
+	Count  int    `json:"count" validate:"min=0,max=20"`
+}
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testRequest struct {
+	Name     string       `json:"name" validate:"required,min=2,max=5"`
+	Nick     *string      `json:"nick" validate:"notblank,max=3"`
+	Role     string       `json:"role" validate:"oneof=parent child"`
+	Age      int          `json:"age" validate:"min=3,max=12"`
+	Points   int          `json:"points" validate:"min=0"`
+	Reward   int          `json:"reward" validate:"min=1"`
+	Level    int          `json:"level" validate:"min=5"`
+	Limit    int          `json:"limit" validate:"max=10"`
+	Days     int          `json:"days" validate:"oneof=1 7 30"`
+	PIN      string       `json:"pin" validate:"pin"`
+	Phone    string       `json:"phone" validate:"whatsapp"`
+	Icon     string       `json:"icon" validate:"emoji"`
+	Date     string       `json:"date" validate:"date"`
+	Time     string       `json:"time" validate:"time"`
+	Email    string       `json:"email" validate:"email"`
+	ID       *string      `json:"id" validate:"required,uuid"`
+	Timezone string       `json:"timezone" validate:"timezone"`
+	Tags     []string     `json:"tags" validate:"max=2,dive,min=2"`
+	Items    []testItem   `json:"items" validate:"required,dive"`
+	Address  *testAddress `json:"address" validate:"dive"`
+	Ignored  string       `json:"-" validate:"required"`
+	Untagged string       `json:"untagged"`
+	private  string       `validate:"required"`
+}
+
+func (r testRequest) Check(v *Violations) {
+	if r.Limit != 0 && !v.Has("age") && r.Age > r.Limit {
+		v.Add("age", "lte", map[string]interface{}{"other": "limit"})
+	}
+}
+
+func str(s string) *string { return &s }
+
+// validRequest passes every rule; each case breaks a few.
+func validRequest() testRequest {
+	return testRequest{
+		Name:   "Adik",
+		Role:   "child",
+		Age:    7,
+		Reward: 1,
+		Level:  5,
+		Days:   30,
+		ID:     str("0b6f4f7e-8d5e-4c8b-9f43-6c1f2a7e9d10"),
+		Items:  []testItem{{TaskID: "5f0c2b7a-1d3e-4f5a-8b6c-7d8e9f0a1b2c", Count: 2}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *testRequest)
+		want   []string
+	}{
+		{"valid", func(r *testRequest) {}, nil},
+		{"optional fields set", func(r *testRequest) {
+			r.Nick = str("Dik")
+			r.Days = 7
+			r.PIN = "0123"
+			r.Phone = "0812-3456-7890"
+			r.Icon = "👨‍👩‍👧"
+			r.Date = "2026-03-01"
+			r.Time = "04:30"
+			r.Email = "ayah@example.com"
+			r.Timezone = "Asia/Jakarta"
+			r.Tags = []string{"ab", "cd"}
+			r.Address = &testAddress{City: "Bandung"}
+		}, nil},
+		{"required", func(r *testRequest) {
+			r.Name = "  "
+			r.ID = nil
+			r.Items = nil
+		}, []string{"name required", "id required", "items required"}},
+		{"notblank only when present", func(r *testRequest) { r.Nick = str(" ") }, []string{"nick required"}},
+		{"string length", func(r *testRequest) {
+			r.Name = "A"
+			r.Nick = str("Dikdik")
+		}, []string{"name length_between", "nick max_length"}},
+		{"length counts characters", func(r *testRequest) { r.Name = "Ñañaú" }, nil},
+		{"number bounds", func(r *testRequest) {
+			r.Age = 13
+			r.Points = -1
+			r.Reward = 0
+			r.Level = 4
+			r.Limit = 11
+		}, []string{"age between", "points not_negative", "reward positive", "level min", "limit max"}},
+		{"oneof", func(r *testRequest) {
+			r.Role = "admin"
+			r.Days = 14
+		}, []string{"role one_of", "days one_of"}},
+		{"formats", func(r *testRequest) {
+			r.PIN = "12a4"
+			r.Phone = "021-5551234"
+			r.Icon = "ab"
+			r.Date = "01/03/2026"
+			r.Time = "4:30"
+			r.Email = "Ayah <ayah@example.com>"
+			r.ID = str("not-a-uuid")
+			r.Timezone = "Mars/Olympus"
+		}, []string{"pin pin", "phone whatsapp", "icon emoji", "date date", "time time", "email email", "id uuid", "timezone timezone"}},
+		{"short pin", func(r *testRequest) { r.PIN = "123" }, []string{"pin pin"}},
+		{"dive into slices", func(r *testRequest) {
+			r.Tags = []string{"a", "bc", "d"}
+			r.Items = []testItem{{TaskID: "", Count: 21}, {TaskID: "x", Count: 1}}
+		}, []string{"tags max_items", "tags[0] min_length", "tags[2] min_length", "items[0].taskId required", "items[0].count between", "items[1].taskId uuid"}},
+		{"dive into struct", func(r *testRequest) { r.Address = &testAddress{} }, []string{"address.city required"}},
+		{"cross-field check", func(r *testRequest) { r.Limit = 5 }, []string{"age lte"}},
+		{"check skips invalid fields", func(r *testRequest) {
+			r.Age = 20
+			r.Limit = 5
+		}, []string{"age between"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(&req)
+			got := violations(t, Validate(req))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+			if pointer := violations(t, Validate(&req)); !reflect.DeepEqual(pointer, tt.want) {
+				t.Errorf("through a pointer: violations = %q, want %q", pointer, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	req := validRequest()
+	req.Name = "A"
+	req.Level = 1
+	req.Role = "admin"
+	err := Validate(req)
+
+	var e *Error
+	if !errors.As(err, &e) || e.Code != CodeValidationFailed {
+		t.Fatalf("err = %v, want %s", err, CodeValidationFailed)
+	}
+	want := []FieldError{
+		{Field: "name", Rule: "length_between", Params: map[string]interface{}{"min": int64(2), "max": int64(5)}},
+		{Field: "role", Rule: "one_of", Params: map[string]interface{}{"values": []string{"parent", "child"}}},
+		{Field: "level", Rule: "min", Params: map[string]interface{}{"min": int64(5)}},
+	}
+	if !reflect.DeepEqual(e.Details, want) {
+		t.Errorf("details = %+v, want %+v", e.Details, want)
+	}
+}
+
+// violations lists err's field errors as "field rule".
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Code != CodeValidationFailed {
+		t.Fatalf("err = %v, want %s", err, CodeValidationFailed)
+	}
+	var got []string
+	for _, d := range e.Details {
+		got = append(got, d.Field+" "+d.Rule)
+	}
+	return got
+}
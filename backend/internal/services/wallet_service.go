@@ -0,0 +1,363 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WalletService struct {
+	notifications *NotificationService
+}
+
+func NewWalletService(notifications *NotificationService) *WalletService {
+	return &WalletService{notifications: notifications}
+}
+
+type WalletSummary struct {
+	models.Wallet
+	ChildName     string                     `json:"childName"`
+	Balance       int64                      `json:"balance"`         // rupiah not yet paid out
+	PendingAmount int64                      `json:"pendingAmount"`   // rupiah waiting for parent approval
+	AvailablePts  int64                      `json:"availablePoints"` // points that can still be cashed out
+	Transactions  []models.WalletTransaction `json:"transactions,omitempty"`
+}
+
+type WalletSettings struct {
+	IsActive       *bool `json:"isActive"`
+	RupiahPerPoint *int  `json:"rupiahPerPoint" validate:"min=1"`
+	THRPerPoint    *int  `json:"thrPerPoint" validate:"min=0"`
+}
+
+func (s *WalletService) findChild(familyID, childID string) (*models.User, error) {
+	var child models.User
+	if err := database.DB.Where("id = ? AND family_id = ? AND role = 'child'", childID, familyID).First(&child).Error; err != nil {
+		return nil, ErrChildNotFound
+	}
+	return &child, nil
+}
+
+func (s *WalletService) findWallet(familyID, childID string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	if err := database.DB.Where("child_id = ? AND family_id = ?", childID, familyID).First(&wallet).Error; err != nil {
+		return nil, ErrWalletNotFound
+	}
+	return &wallet, nil
+}
+
+func (s *WalletService) summarize(wallet models.Wallet, childName string, withHistory bool) (*WalletSummary, error) {
+	summary := &WalletSummary{Wallet: wallet, ChildName: childName}
+
+	err := database.DB.Model(&models.WalletTransaction{}).
+		Where("wallet_id = ? AND status IN ('approved', 'completed')", wallet.ID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&summary.Balance).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Model(&models.WalletTransaction{}).
+		Where("wallet_id = ? AND status = 'pending'", wallet.ID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&summary.PendingAmount).Error
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := ChildPointSummary(database.DB, wallet.ChildID)
+	if err != nil {
+		return nil, err
+	}
+	summary.AvailablePts = points.Balance
+
+	if withHistory {
+		if err := database.DB.Where("wallet_id = ?", wallet.ID).Order("created_at DESC").Find(&summary.Transactions).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *WalletService) GetWallets(familyID string) ([]WalletSummary, error) {
+	var wallets []models.Wallet
+	if err := database.DB.Preload("Child").Where("family_id = ?", familyID).Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+
+	summaries := make([]WalletSummary, 0, len(wallets))
+	for _, w := range wallets {
+		summary, err := s.summarize(w, w.Child.Name, false)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+func (s *WalletService) GetWallet(familyID, childID string) (*WalletSummary, error) {
+	child, err := s.findChild(familyID, childID)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := s.findWallet(familyID, childID)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(*wallet, child.Name, true)
+}
+
+// ConfigureWallet creates the wallet on first use and updates its rates afterwards.
+func (s *WalletService) ConfigureWallet(familyID, childID string, settings WalletSettings) (*models.Wallet, error) {
+	if err := Validate(settings); err != nil {
+		return nil, err
+	}
+	if _, err := s.findChild(familyID, childID); err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.findWallet(familyID, childID)
+	if err != nil {
+		wallet = &models.Wallet{
+			FamilyID:       familyID,
+			ChildID:        childID,
+			IsActive:       true,
+			RupiahPerPoint: 100,
+		}
+	}
+
+	if settings.IsActive != nil {
+		wallet.IsActive = *settings.IsActive
+	}
+	if settings.RupiahPerPoint != nil {
+		wallet.RupiahPerPoint = *settings.RupiahPerPoint
+	}
+	if settings.THRPerPoint != nil {
+		wallet.THRPerPoint = *settings.THRPerPoint
+	}
+
+	if err := database.DB.Save(wallet).Error; err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// RequestCashout reserves points and creates a pending cash-out that a parent approves or rejects.
+func (s *WalletService) RequestCashout(familyID, childID string, points int, note string) (*models.WalletTransaction, error) {
+	if points <= 0 {
+		return nil, InvalidField("points", "positive", nil)
+	}
+
+	wallet, err := s.findWallet(familyID, childID)
+	if err != nil {
+		return nil, err
+	}
+	if !wallet.IsActive {
+		return nil, ErrWalletInactive
+	}
+
+	tx := database.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the child row so concurrent requests can't reserve the same points.
+	var child models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", childID).First(&child).Error; err != nil {
+		tx.Rollback()
+		return nil, ErrChildNotFound
+	}
+
+	summary, err := ChildPointSummary(tx, childID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if summary.Balance < int64(points) {
+		tx.Rollback()
+		return nil, ErrInsufficientPoints
+	}
+
+	cashout := models.WalletTransaction{
+		WalletID: wallet.ID,
+		ChildID:  childID,
+		Type:     "cashout",
+		Status:   "pending",
+		Points:   points,
+		Amount:   int64(points) * int64(wallet.RupiahPerPoint),
+		Note:     note,
+	}
+	if err := tx.Create(&cashout).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	tx.Commit()
+
+	err = s.notifications.NotifyParents(familyID, Notification{
+		Kind:  NotifyApproval,
+		Title: fmt.Sprintf("💰 %s minta pencairan %d poin", child.Name, points),
+		Body:  fmt.Sprintf("Senilai Rp%d — setujui atau tolak di dompet anak.", cashout.Amount),
+		URL:   "/dashboard",
+	})
+	if err != nil {
+		log.Printf("push: cashout %s: %v", cashout.ID, err)
+	}
+	return &cashout, nil
+}
+
+// UpdateCashoutStatus approves or rejects a pending cash-out. Rejecting releases the reserved points.
+func (s *WalletService) UpdateCashoutStatus(familyID, transactionID, status string) (*models.WalletTransaction, error) {
+	if status != "approved" && status != "rejected" {
+		return nil, InvalidField("status", "one_of", map[string]interface{}{"values": []string{"approved", "rejected"}})
+	}
+
+	var cashout models.WalletTransaction
+	err := database.DB.Joins("JOIN wallets ON wallets.id = wallet_transactions.wallet_id").
+		Where("wallet_transactions.id = ? AND wallets.family_id = ? AND wallet_transactions.type = 'cashout'", transactionID, familyID).
+		First(&cashout).Error
+	if err != nil {
+		return nil, ErrCashoutNotFound
+	}
+
+	if cashout.Status != "pending" {
+		return nil, ErrCashoutProcessed
+	}
+
+	// Conditional on pending, so two parents deciding at once can't both win.
+	result := database.DB.Model(&cashout).Where("status = 'pending'").Update("status", status)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCashoutProcessed
+	}
+	return &cashout, nil
+}
+
+// RecordPayout records rupiah that a parent handed over, reducing the wallet balance.
+// The wallet row is locked while the balance is checked, so concurrent payouts
+// can't overdraw it.
+func (s *WalletService) RecordPayout(familyID, childID, parentID string, amount int64, note string) (*models.WalletTransaction, error) {
+	if amount <= 0 {
+		return nil, InvalidField("amount", "positive", nil)
+	}
+
+	var payout models.WalletTransaction
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var wallet models.Wallet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("child_id = ? AND family_id = ?", childID, familyID).
+			First(&wallet).Error
+		if err != nil {
+			return ErrWalletNotFound
+		}
+
+		var balance int64
+		err = tx.Model(&models.WalletTransaction{}).
+			Where("wallet_id = ? AND status IN ('approved', 'completed')", wallet.ID).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&balance).Error
+		if err != nil {
+			return err
+		}
+		if balance < amount {
+			return ErrInsufficientWallet
+		}
+
+		payout = models.WalletTransaction{
+			WalletID:   wallet.ID,
+			ChildID:    childID,
+			Type:       "payout",
+			Status:     "completed",
+			Amount:     -amount,
+			Note:       note,
+			RecordedBy: &parentID,
+		}
+		return tx.Create(&payout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+// GrantTHR credits a Lebaran bonus computed from the points earned during the
+// family's configured season. A season can only be granted once per child: the
+// check runs under a lock on the wallet and idx_wallet_transactions_child_reference
+// backs it up.
+func (s *WalletService) GrantTHR(familyID, childID, parentID string) (*models.WalletTransaction, error) {
+	var family models.Family
+	if err := database.DB.First(&family, "id = ?", familyID).Error; err != nil {
+		return nil, ErrFamilyNotFound
+	}
+	if family.SeasonStart == nil || family.SeasonEnd == nil {
+		return nil, ErrSeasonNotConfigured
+	}
+	seasonStart, seasonEnd := *family.SeasonStart, *family.SeasonEnd
+	reference := fmt.Sprintf("THR:%s:%s", seasonStart.Format("2006-01-02"), seasonEnd.Format("2006-01-02"))
+
+	var thr models.WalletTransaction
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var wallet models.Wallet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("child_id = ? AND family_id = ?", childID, familyID).
+			First(&wallet).Error
+		if err != nil {
+			return ErrWalletNotFound
+		}
+		if wallet.THRPerPoint <= 0 {
+			return ErrTHRDisabled
+		}
+
+		var existing int64
+		err = tx.Model(&models.WalletTransaction{}).
+			Where("child_id = ? AND reference = ?", childID, reference).
+			Count(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrTHRGranted
+		}
+
+		var seasonPoints int64
+		err = tx.Model(&models.DailyLog{}).
+			Where("child_id = ? AND status = 'verified' AND completed_date >= ? AND completed_date <= ?", childID, seasonStart, seasonEnd).
+			Select("COALESCE(SUM(earned_points), 0)").
+			Scan(&seasonPoints).Error
+		if err != nil {
+			return err
+		}
+
+		thr = models.WalletTransaction{
+			WalletID:   wallet.ID,
+			ChildID:    childID,
+			Type:       "thr",
+			Status:     "completed",
+			Points:     int(seasonPoints),
+			Amount:     seasonPoints * int64(wallet.THRPerPoint),
+			Note:       fmt.Sprintf("THR Lebaran (%d poin)", seasonPoints),
+			Reference:  reference,
+			RecordedBy: &parentID,
+		}
+		if err := tx.Create(&thr).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrTHRGranted
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &thr, nil
+}
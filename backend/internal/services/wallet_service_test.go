@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+)
+
+// newTestWallet adds a child with an active wallet to a new test family.
+func newTestWallet(t *testing.T, thrPerPoint int) (familyID, parentID, childID string) {
+	t.Helper()
+	familyID, parentID = newTestFamily(t)
+	child := models.User{FamilyID: familyID, Role: "child", Name: "Adik"}
+	if err := database.DB.Create(&child).Error; err != nil {
+		t.Fatal(err)
+	}
+	wallet := models.Wallet{FamilyID: familyID, ChildID: child.ID, IsActive: true, RupiahPerPoint: 100, THRPerPoint: thrPerPoint}
+	if err := database.DB.Create(&wallet).Error; err != nil {
+		t.Fatal(err)
+	}
+	return familyID, parentID, child.ID
+}
+
+// earn records a verified completion worth points on date.
+func earn(t *testing.T, familyID, childID string, date time.Time, points int) {
+	t.Helper()
+	task := models.Task{FamilyID: familyID, Name: "Sholat Subuh", PointReward: points}
+	if err := database.DB.Create(&task).Error; err != nil {
+		t.Fatal(err)
+	}
+	log := models.DailyLog{ChildID: childID, TaskID: task.ID, CompletedDate: date, Status: "verified", EarnedPoints: points}
+	if err := database.DB.Create(&log).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrantTHRUsesFamilySeason(t *testing.T) {
+	testDB(t)
+	familyID, parentID, childID := newTestWallet(t, 50)
+
+	if _, err := NewWalletService(nil).GrantTHR(familyID, childID, parentID); !errors.Is(err, ErrSeasonNotConfigured) {
+		t.Fatalf("without a season: err = %v, want %v", err, ErrSeasonNotConfigured)
+	}
+
+	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)
+	err := database.DB.Model(&models.Family{}).Where("id = ?", familyID).
+		Updates(map[string]interface{}{"season_start": start, "season_end": end}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	earn(t, familyID, childID, start, 10)
+	earn(t, familyID, childID, end, 5)
+	earn(t, familyID, childID, end.AddDate(0, 0, 1), 100) // after the season
+
+	thr, err := NewWalletService(nil).GrantTHR(familyID, childID, parentID)
+	if err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if thr.Points != 15 || thr.Amount != 750 || thr.Reference != "THR:2026-02-18:2026-03-19" {
+		t.Errorf("thr = %d points, Rp%d, %q; want 15, Rp750, THR:2026-02-18:2026-03-19", thr.Points, thr.Amount, thr.Reference)
+	}
+}
+
+func TestGrantTHROncePerSeason(t *testing.T) {
+	testDB(t)
+	familyID, parentID, childID := newTestWallet(t, 50)
+	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)
+	err := database.DB.Model(&models.Family{}).Where("id = ?", familyID).
+		Updates(map[string]interface{}{"season_start": start, "season_end": end}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	earn(t, familyID, childID, start, 10)
+
+	const attempts = 8
+	errs := make(chan error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := NewWalletService(nil).GrantTHR(familyID, childID, parentID)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	granted := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			granted++
+		case !errors.Is(err, ErrTHRGranted):
+			t.Errorf("concurrent grant: %v", err)
+		}
+	}
+	if granted != 1 {
+		t.Errorf("%d concurrent grants succeeded, want 1", granted)
+	}
+
+	var rows int64
+	database.DB.Model(&models.WalletTransaction{}).Where("child_id = ? AND type = 'thr'", childID).Count(&rows)
+	if rows != 1 {
+		t.Errorf("%d THR rows, want 1", rows)
+	}
+}
+
+func TestRecordPayoutCannotOverdraw(t *testing.T) {
+	testDB(t)
+	familyID, parentID, childID := newTestWallet(t, 0)
+	var wallet models.Wallet
+	if err := database.DB.First(&wallet, "child_id = ?", childID).Error; err != nil {
+		t.Fatal(err)
+	}
+	credit := models.WalletTransaction{WalletID: wallet.ID, ChildID: childID, Type: "cashout", Status: "approved", Points: 10, Amount: 1000}
+	if err := database.DB.Create(&credit).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Rp1000 covers two payouts of Rp400, not a third.
+	const attempts = 8
+	errs := make(chan error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := NewWalletService(nil).RecordPayout(familyID, childID, parentID, 400, "")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	paid := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			paid++
+		case !errors.Is(err, ErrInsufficientWallet):
+			t.Errorf("concurrent payout: %v", err)
+		}
+	}
+	if paid != 2 {
+		t.Errorf("%d concurrent payouts succeeded, want 2", paid)
+	}
+
+	summary, err := NewWalletService(nil).GetWallet(familyID, childID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Balance != 200 {
+		t.Errorf("balance = %d, want 200", summary.Balance)
+	}
+}
+
+func TestUpdateCashoutStatusDecidesOnce(t *testing.T) {
+	testDB(t)
+	familyID, _, childID := newTestWallet(t, 0)
+	var wallet models.Wallet
+	if err := database.DB.First(&wallet, "child_id = ?", childID).Error; err != nil {
+		t.Fatal(err)
+	}
+	cashout := models.WalletTransaction{WalletID: wallet.ID, ChildID: childID, Type: "cashout", Status: "pending", Points: 10, Amount: 1000}
+	if err := database.DB.Create(&cashout).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Parents approve and reject at the same time; only one decision stands.
+	statuses := []string{"approved", "rejected", "approved", "rejected", "approved", "rejected"}
+	decided := make(chan string, len(statuses))
+	var wg sync.WaitGroup
+	for _, status := range statuses {
+		wg.Add(1)
+		go func(status string) {
+			defer wg.Done()
+			_, err := NewWalletService(nil).UpdateCashoutStatus(familyID, cashout.ID, status)
+			switch {
+			case err == nil:
+				decided <- status
+			case !errors.Is(err, ErrCashoutProcessed):
+				t.Errorf("%s: %v", status, err)
+			}
+		}(status)
+	}
+	wg.Wait()
+	close(decided)
+
+	var wins []string
+	for status := range decided {
+		wins = append(wins, status)
+	}
+	if len(wins) != 1 {
+		t.Fatalf("decisions that succeeded = %v, want exactly one", wins)
+	}
+	var stored models.WalletTransaction
+	if err := database.DB.First(&stored, "id = ?", cashout.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != wins[0] {
+		t.Errorf("stored status = %s, want %s", stored.Status, wins[0])
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/username/ramadhan-ceria-backend/internal/database"
+	"github.com/username/ramadhan-ceria-backend/internal/models"
+	"github.com/username/ramadhan-ceria-backend/internal/utils"
+	"github.com/username/ramadhan-ceria-backend/internal/whatsapp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dailySummaryHour is the family-local hour of the WhatsApp daily summary, after Tarawih.
+const dailySummaryHour = 20
+
+type WhatsappService struct {
+	provider     whatsapp.Provider
+	webhookToken string
+}
+
+// NewWhatsappService takes the provider and the shared token inbound
+// webhooks must carry; an empty token disables the webhook.
+func NewWhatsappService(provider whatsapp.Provider, webhookToken string) *WhatsappService {
+	return &WhatsappService{provider: provider, webhookToken: webhookToken}
+}
+
+type WhatsappSettings struct {
+	Whatsapp   *string    `json:"whatsapp"`
+	OptedIn    bool       `json:"optedIn"`
+	OptedInAt  *time.Time `json:"optedInAt"`
+	OptedOutAt *time.Time `json:"optedOutAt"`
+	Provider   string     `json:"provider"`
+}
+
+type WhatsappSettingsInput struct {
+	Whatsapp *string `json:"whatsapp" validate:"max=30,whatsapp"` // any common format; "" removes the number
+	OptIn    *bool   `json:"optIn"`
+}
+
+func findConsent(db *gorm.DB, userID string) (*models.WhatsappConsent, error) {
+	var consent models.WhatsappConsent
+	err := db.Where("user_id = ?", userID).First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &consent, err
+}
+
+func (s *WhatsappService) GetSettings(userID string) (*WhatsappSettings, error) {
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	settings := &WhatsappSettings{Whatsapp: user.Whatsapp, Provider: s.provider.Name()}
+	consent, err := findConsent(database.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if consent != nil {
+		settings.OptedIn = consent.OptedIn && user.Whatsapp != nil && *user.Whatsapp == consent.Phone
+		settings.OptedInAt = consent.OptedInAt
+		settings.OptedOutAt = consent.OptedOutAt
+	}
+	return settings, nil
+}
+
+// RecordWhatsappConsent stores an opt-in for phone (already normalized).
+func RecordWhatsappConsent(tx *gorm.DB, userID, phone, source string) error {
+	now := time.Now()
+	consent := models.WhatsappConsent{UserID: userID, Phone: phone, OptedIn: true, Source: source, OptedInAt: &now}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"phone", "opted_in", "source", "opted_in_at", "updated_at"}),
+	}).Create(&consent).Error
+}
+
+func optOut(tx *gorm.DB, userID, source string) error {
+	return tx.Model(&models.WhatsappConsent{}).Where("user_id = ? AND opted_in", userID).
+		Updates(map[string]interface{}{"opted_in": false, "source": source, "opted_out_at": time.Now()}).Error
+}
+
+// UpdateSettings changes a parent's number and consent. A new number starts
+// opted out unless optIn is sent with it.
+func (s *WhatsappService) UpdateSettings(userID string, input WhatsappSettingsInput) (*WhatsappSettings, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error; err != nil {
+			return ErrUserNotFound
+		}
+
+		if input.Whatsapp != nil {
+			var phone *string
+			if *input.Whatsapp != "" {
+				normalized, _ := whatsapp.NormalizePhone(*input.Whatsapp)
+				phone = &normalized
+			}
+			changed := (phone == nil) != (user.Whatsapp == nil) || (phone != nil && *phone != *user.Whatsapp)
+			if changed {
+				if err := tx.Model(&user).Update("whatsapp", phone).Error; err != nil {
+					return err
+				}
+				if err := optOut(tx, userID, "settings"); err != nil {
+					return err
+				}
+				user.Whatsapp = phone
+			}
+		}
+
+		if input.OptIn != nil {
+			if !*input.OptIn {
+				return optOut(tx, userID, "settings")
+			}
+			if user.Whatsapp == nil {
+				return ErrWhatsappNumberMissing
+			}
+			return RecordWhatsappConsent(tx, userID, *user.Whatsapp, "settings")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSettings(userID)
+}
+
+// VerifyWebhook checks the shared token on an inbound webhook call.
+func (s *WhatsappService) VerifyWebhook(token string) bool {
+	return s.webhookToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.webhookToken)) == 1
+}
+
+// HandleInbound applies STOP / MULAI replies to every consent for the sender's number.
+func (s *WhatsappService) HandleInbound(messages []whatsapp.Inbound) (int64, error) {
+	var updated int64
+	for _, m := range messages {
+		stop, start := m.Command()
+		if !stop && !start {
+			continue
+		}
+		now := time.Now()
+		query := database.DB.Model(&models.WhatsappConsent{}).Where("phone = ?", m.From)
+		var result *gorm.DB
+		if stop {
+			result = query.Updates(map[string]interface{}{"opted_in": false, "source": "reply", "opted_out_at": now})
+		} else {
+			result = query.Updates(map[string]interface{}{"opted_in": true, "source": "reply", "opted_in_at": now})
+		}
+		if result.Error != nil {
+			return updated, result.Error
+		}
+		updated += result.RowsAffected
+	}
+	return updated, nil
+}
+
+// cleanParam makes a value safe for template parameters, which may not
+// contain newlines, tabs or long runs of spaces.
+func cleanParam(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// Send delivers a template to one user if they opted in with their current
+// number. With a dedupe key the message goes out at most once.
+func (s *WhatsappService) Send(ctx context.Context, userID string, tmpl whatsapp.Template, params []string, dedupeKey string) error {
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return ErrUserNotFound
+	}
+	consent, err := findConsent(database.DB, userID)
+	if err != nil {
+		return err
+	}
+	if consent == nil || !consent.OptedIn || user.Whatsapp == nil || *user.Whatsapp != consent.Phone {
+		return nil
+	}
+
+	cleaned := make([]string, len(params))
+	for i, p := range params {
+		cleaned[i] = cleanParam(p)
+	}
+	entry := models.WhatsappMessage{
+		UserID:   userID,
+		Phone:    consent.Phone,
+		Template: tmpl.Name,
+		Params:   cleaned,
+		Provider: s.provider.Name(),
+		Status:   "pending",
+	}
+	if dedupeKey != "" {
+		entry.DedupeKey = &dedupeKey
+	}
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil // already sent
+	}
+
+	messageID, sendErr := s.provider.Send(ctx, whatsapp.Message{To: consent.Phone, Template: tmpl, Params: cleaned})
+	update := map[string]interface{}{"status": "sent", "provider_message_id": messageID}
+	if sendErr != nil {
+		update = map[string]interface{}{"status": "failed", "error": sendErr.Error()}
+	}
+	if err := database.DB.Model(&entry).Updates(update).Error; err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// NotifyParents sends the template to every opted-in parent of the family.
+func (s *WhatsappService) NotifyParents(ctx context.Context, familyID string, tmpl whatsapp.Template, params []string, dedupeKey string) error {
+	var parentIDs []string
+	err := database.DB.Model(&models.User{}).
+		Where("family_id = ? AND role = 'parent' AND id IN (SELECT user_id FROM whatsapp_consents WHERE opted_in)", familyID).
+		Pluck("id", &parentIDs).Error
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range parentIDs {
+		if err := s.Send(ctx, id, tmpl, params, dedupeKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type childDaySummary struct {
+	Name   string
+	Tasks  int
+	Points int
+}
+
+// SendDailySummaries runs hourly and, at 20:00 family time, sends opted-in
+// parents each child's completions and points for the day.
+func (s *WhatsappService) SendDailySummaries(ctx context.Context) error {
+	var families []models.Family
+	err := database.DB.WithContext(ctx).Where(`id IN (
+		SELECT users.family_id FROM whatsapp_consents JOIN users ON users.id = whatsapp_consents.user_id
+		WHERE whatsapp_consents.opted_in AND users.role = 'parent')`).Find(&families).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, family := range families {
+		loc := utils.LoadLocation(family.Timezone)
+		if now.In(loc).Hour() != dailySummaryHour {
+			continue
+		}
+		today := utils.Today(loc)
+		if !inSeason(family, today) {
+			continue
+		}
+
+		var children []childDaySummary
+		err := database.DB.WithContext(ctx).Raw(`
+			SELECT users.name,
+				COUNT(daily_logs.id) AS tasks,
+				COALESCE(SUM(daily_logs.earned_points), 0) AS points
+			FROM users
+			LEFT JOIN daily_logs ON daily_logs.child_id = users.id AND daily_logs.completed_date = ?
+				AND daily_logs.status = 'verified' AND daily_logs.deleted_at IS NULL
+			WHERE users.family_id = ? AND users.role = 'child' AND users.deleted_at IS NULL
+			GROUP BY users.id, users.name
+			ORDER BY users.name`, today, family.ID).Scan(&children).Error
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			continue
+		}
+
+		lines := make([]string, 0, len(children))
+		for _, child := range children {
+			if child.Tasks == 0 {
+				lines = append(lines, child.Name+" belum menyelesaikan misi")
+			} else {
+				lines = append(lines, fmt.Sprintf("%s %d misi (+%d poin)", child.Name, child.Tasks, child.Points))
+			}
+		}
+		date := today.Format("2006-01-02")
+		params := []string{family.Name, today.Format("02-01-2006"), strings.Join(lines, ", ")}
+		if err := s.NotifyParents(ctx, family.ID, whatsapp.TemplateDailySummary, params, "summary:"+date); err != nil {
+			log.Printf("whatsapp summary for family %s failed: %v", family.ID, err)
+		}
+	}
+	return nil
+}
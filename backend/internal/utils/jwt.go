@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,7 +13,16 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID, familyID, role string) (string, error) {
+// JWT signs and checks login tokens with the configured secret.
+type JWT struct {
+	secret []byte
+}
+
+func NewJWT(secret string) *JWT {
+	return &JWT{secret: []byte(secret)}
+}
+
+func (j *JWT) GenerateToken(userID, familyID, role string) (string, error) {
 	claims := Claims{
 		userID,
 		familyID,
@@ -25,13 +33,13 @@ func GenerateToken(userID, familyID, role string) (string, error) {
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return token.SignedString(j.secret)
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+func (j *JWT) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+		return j.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
@@ -1,17 +1,20 @@
 package utils
 
 import (
-	"os"
-
+	"github.com/username/ramadhan-ceria-backend/internal/config"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
-func GetGoogleOAuthConfig() *oauth2.Config {
+// GoogleOAuthConfig is the "Login with Google" client, nil when it isn't configured.
+func GoogleOAuthConfig(cfg config.Google) *oauth2.Config {
+	if cfg.ClientID == "" {
+		return nil
+	}
 	return &oauth2.Config{
-		RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"), // e.g., http://localhost:3005/api/auth/google/callback
-		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
-		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+		RedirectURL:  cfg.RedirectURL,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
 		Scopes: []string{
 			"https://www.googleapis.com/auth/userinfo.email",
 			"https://www.googleapis.com/auth/userinfo.profile",
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+// timezoneCoordinates is the reference city for each Indonesian timezone.
+// Families only choose a timezone, so Maghrib is computed for that city.
+var timezoneCoordinates = map[string][2]float64{
+	"Asia/Jakarta":  {-6.2088, 106.8456}, // Jakarta
+	"Asia/Makassar": {-5.1477, 119.4327}, // Makassar
+	"Asia/Jayapura": {-2.5337, 140.7181}, // Jayapura
+}
+
+// ihtiyat is the safety margin the Kemenag schedule adds after sunset.
+const ihtiyat = 2 * time.Minute
+
+// Sunset returns the sunset of date's calendar day at lat/lon, in UTC
+// (NOAA solar position approximation, accurate to about a minute).
+func Sunset(date time.Time, lat, lon float64) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	gamma := 2 * math.Pi / 365 * float64(day.YearDay()-1)
+
+	eqTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	decl := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+
+	latRad := lat * math.Pi / 180
+	zenith := 90.833 * math.Pi / 180
+	hourAngle := math.Acos(math.Cos(zenith)/(math.Cos(latRad)*math.Cos(decl)) - math.Tan(latRad)*math.Tan(decl))
+
+	minutes := 720 - 4*(lon-hourAngle*180/math.Pi) - eqTime
+	return day.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// MaghribTime returns Maghrib on date's calendar day for a family timezone,
+// in that timezone. Unknown zones use Jakarta.
+func MaghribTime(date time.Time, timezone string) time.Time {
+	coords, ok := timezoneCoordinates[TimezoneName(timezone)]
+	if !ok {
+		coords = timezoneCoordinates["Asia/Jakarta"]
+	}
+	return Sunset(date, coords[0], coords[1]).Add(ihtiyat).In(LoadLocation(timezone))
+}
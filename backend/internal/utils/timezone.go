@@ -0,0 +1,47 @@
+package utils
+
+import "time"
+
+// jakarta is used when a family's timezone is empty or unknown to the host.
+var jakarta = time.FixedZone("WIB", 7*60*60)
+
+// LoadLocation resolves a family timezone such as "Asia/Jakarta", falling back to WIB.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return jakarta
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return jakarta
+	}
+	return loc
+}
+
+// TimezoneName returns name when the host knows it, otherwise "Asia/Jakarta".
+// Use it when the zone is handed to Postgres (AT TIME ZONE).
+func TimezoneName(name string) string {
+	if name == "" {
+		return "Asia/Jakarta"
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "Asia/Jakarta"
+	}
+	return name
+}
+
+// Today returns the current calendar date in loc as a UTC midnight, matching
+// how completed_date values are parsed from YYYY-MM-DD strings.
+func Today(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WeekBounds returns the Monday and Sunday of the week containing date.
+func WeekBounds(date time.Time) (time.Time, time.Time) {
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		weekday = 7 // Sunday = 7
+	}
+	monday := date.AddDate(0, 0, -(weekday - 1))
+	return monday, monday.AddDate(0, 0, 6)
+}
@@ -0,0 +1,223 @@
+// Package fakepush is an in-memory Web Push service. It hands out
+// subscriptions with real device keys, checks the VAPID signature of every
+// push, decrypts the payload and keeps it, so notifications can be tested end
+// to end without a browser. cmd/fakepush serves it standalone.
+package fakepush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/username/ramadhan-ceria-backend/internal/webpush"
+)
+
+// Message is one push as the device would have received it.
+type Message struct {
+	Payload    json.RawMessage `json:"payload"`
+	TTL        string          `json:"ttl"`
+	Urgency    string          `json:"urgency,omitempty"`
+	Topic      string          `json:"topic,omitempty"`
+	ReceivedAt time.Time       `json:"receivedAt"`
+}
+
+type device struct {
+	key      *ecdh.PrivateKey
+	auth     []byte
+	status   int // answer to the next pushes; 0 = 201 Created
+	messages []Message
+}
+
+type Server struct {
+	// BaseURL is this server's own URL, used in subscription endpoints and as the VAPID audience.
+	BaseURL string
+
+	mu      sync.Mutex
+	devices map[string]*device
+}
+
+func New(baseURL string) *Server {
+	return &Server{BaseURL: strings.TrimRight(baseURL, "/"), devices: map[string]*device{}}
+}
+
+// NewSubscription registers a device and returns what the browser would send to the API.
+func (s *Server) NewSubscription() (webpush.Subscription, error) {
+	var sub webpush.Subscription
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return sub, err
+	}
+	auth := make([]byte, 16)
+	id := make([]byte, 12)
+	if _, err := rand.Read(auth); err != nil {
+		return sub, err
+	}
+	if _, err := rand.Read(id); err != nil {
+		return sub, err
+	}
+
+	deviceID := hex.EncodeToString(id)
+	s.mu.Lock()
+	s.devices[deviceID] = &device{key: key, auth: auth}
+	s.mu.Unlock()
+
+	sub.Endpoint = s.BaseURL + "/push/" + deviceID
+	sub.Keys.P256dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	sub.Keys.Auth = base64.RawURLEncoding.EncodeToString(auth)
+	return sub, nil
+}
+
+func deviceID(endpoint string) string {
+	return endpoint[strings.LastIndex(endpoint, "/")+1:]
+}
+
+// SetStatus makes the device answer pushes with code, e.g. 410 to simulate an
+// unsubscribed browser or 503 to exercise retries. 0 restores 201.
+func (s *Server) SetStatus(endpoint string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.devices[deviceID(endpoint)]; ok {
+		d.status = code
+	}
+}
+
+// Messages returns what the device received so far.
+func (s *Server) Messages(endpoint string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.devices[deviceID(endpoint)]
+	if !ok {
+		return nil
+	}
+	return append([]Message{}, d.messages...)
+}
+
+// Handler serves:
+//
+//	POST /subscriptions        create a device, returns its subscription JSON
+//	POST /push/{id}            the push endpoint
+//	GET  /push/{id}/messages   decrypted messages received by the device
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		sub, err := s.NewSubscription()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, sub)
+	})
+	mux.HandleFunc("POST /push/{id}", s.handlePush)
+	mux.HandleFunc("GET /push/{id}/messages", func(w http.ResponseWriter, r *http.Request) {
+		msgs := s.Messages(r.PathValue("id"))
+		if msgs == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, msgs)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// verifyVAPID checks "vapid t=<jwt>, k=<key>": signature, audience and expiry.
+func (s *Server) verifyVAPID(header string) error {
+	if !strings.HasPrefix(header, "vapid ") {
+		return errors.New("missing vapid authorization")
+	}
+	var token, key string
+	for _, part := range strings.Split(strings.TrimPrefix(header, "vapid "), ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "t="):
+			token = strings.TrimPrefix(part, "t=")
+		case strings.HasPrefix(part, "k="):
+			key = strings.TrimPrefix(part, "k=")
+		}
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil {
+		return errors.New("invalid k")
+	}
+	pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), raw)
+	if err != nil {
+		return errors.New("invalid k")
+	}
+
+	u, _ := url.Parse(s.BaseURL)
+	audience := u.Scheme + "://" + u.Host
+	_, err = jwt.Parse(token, func(t *jwt.Token) (interface{}, error) { return pub, nil },
+		jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	return err
+}
+
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	d, ok := s.devices[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "subscription not found", http.StatusGone)
+		return
+	}
+	if err := s.verifyVAPID(r.Header.Get("Authorization")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("TTL") == "" {
+		http.Error(w, "TTL header required", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "aes128gcm" {
+		http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 8192))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	payload, err := webpush.Decrypt(body, d.key, d.auth)
+	if err != nil {
+		http.Error(w, "cannot decrypt payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(string(payload))
+	}
+
+	s.mu.Lock()
+	status := d.status
+	if status == 0 || status < 300 {
+		d.messages = append(d.messages, Message{
+			Payload:    payload,
+			TTL:        r.Header.Get("TTL"),
+			Urgency:    r.Header.Get("Urgency"),
+			Topic:      r.Header.Get("Topic"),
+			ReceivedAt: time.Now(),
+		})
+	}
+	s.mu.Unlock()
+
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.WriteHeader(status)
+}
@@ -0,0 +1,327 @@
+// Package webpush sends Web Push messages (RFC 8030) with VAPID authentication
+// (RFC 8292) and aes128gcm payload encryption (RFC 8291), using only the
+// standard library crypto packages.
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+)
+
+// recordSize is the aes128gcm record size; payloads are sent as a single record.
+const recordSize = 4096
+
+// MaxPayload is the largest plaintext that fits one record alongside the
+// padding delimiter and the GCM tag.
+const MaxPayload = recordSize - 17 - 1
+
+var (
+	// ErrGone means the push service no longer knows the subscription (404/410).
+	ErrGone = errors.New("webpush: subscription expired or unsubscribed")
+	// ErrPayloadTooLarge is returned for payloads over MaxPayload.
+	ErrPayloadTooLarge = errors.New("webpush: payload too large")
+)
+
+// Subscription is what PushManager.subscribe() gives the browser, keys base64url encoded.
+type Subscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// Options are the per-message headers.
+type Options struct {
+	TTL     time.Duration // how long the push service keeps the message for an offline device
+	Urgency string        // very-low, low, normal, high
+	Topic   string        // replaces an undelivered message with the same topic
+}
+
+// StatusError is a non-2xx answer from the push service.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webpush: push service answered HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether sending again later may succeed.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+type Sender struct {
+	// PublicKey is the uncompressed P-256 application server key, base64url,
+	// which the browser needs as applicationServerKey.
+	PublicKey string
+	// Subject is a mailto: or https: contact for the push service operator.
+	Subject string
+	HTTP    *http.Client
+
+	key *ecdsa.PrivateKey
+}
+
+// NewSender builds a Sender from a base64url encoded VAPID private key (the 32
+// byte scalar) and its public key.
+func NewSender(publicKey, privateKey, subject string) (*Sender, error) {
+	d, err := decodeKey(privateKey)
+	if err != nil || len(d) != 32 {
+		return nil, errors.New("webpush: VAPID private key must be 32 bytes, base64url")
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), d)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid VAPID private key: %w", err)
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if publicKey != "" {
+		given, err := decodeKey(publicKey)
+		if err != nil || !bytes.Equal(given, pub) {
+			return nil, errors.New("webpush: VAPID public key does not match the private key")
+		}
+	}
+
+	return &Sender{
+		PublicKey: base64.RawURLEncoding.EncodeToString(pub),
+		Subject:   subject,
+		HTTP:      &http.Client{Timeout: 15 * time.Second},
+		key:       key,
+	}, nil
+}
+
+// SenderFromConfig returns nil, nil when no private key is configured.
+func SenderFromConfig(cfg config.VAPID) (*Sender, error) {
+	if cfg.PrivateKey == "" {
+		return nil, nil
+	}
+	return NewSender(cfg.PublicKey, cfg.PrivateKey, cfg.Subject)
+}
+
+// GenerateVAPIDKeys returns a new key pair, base64url encoded.
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	priv, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(priv.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(priv.Bytes()), nil
+}
+
+// decodeKey accepts base64url with or without padding, and standard base64.
+func decodeKey(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{base64.RawURLEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.StdEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, errors.New("webpush: invalid base64")
+}
+
+// vapidHeader signs a VAPID JWT for the endpoint's origin.
+func (s *Sender) vapidHeader(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("webpush: invalid endpoint %q", endpoint)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": s.Subject,
+	})
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+	return "vapid t=" + signed + ", k=" + s.PublicKey, nil
+}
+
+// Send encrypts payload for the subscription and posts it to the push service.
+func (s *Sender) Send(ctx context.Context, sub Subscription, payload []byte, opts Options) error {
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	auth, err := s.vapidHeader(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Authorization", auth)
+	if opts.Urgency != "" {
+		req.Header.Set("Urgency", opts.Urgency)
+	}
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+
+	resp, err := s.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return ErrGone
+	}
+	if resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(text)}
+	}
+	return nil
+}
+
+// keys derives the content encryption key and nonce (RFC 8291 section 3.4).
+func keys(sharedSecret, authSecret, uaPublic, asPublic, salt []byte) (cek, nonce []byte, err error) {
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm, err := hkdf.Key(sha256.New, sharedSecret, authSecret, string(keyInfo), 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cek, err = hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16); err != nil {
+		return nil, nil, err
+	}
+	if nonce, err = hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12); err != nil {
+		return nil, nil, err
+	}
+	return cek, nonce, nil
+}
+
+// Encrypt produces the aes128gcm body for a subscription: salt, record size,
+// the ephemeral public key, then one encrypted record.
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+	uaBytes, err := decodeKey(sub.Keys.P256dh)
+	if err != nil {
+		return nil, errors.New("webpush: invalid p256dh key")
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaBytes)
+	if err != nil {
+		return nil, errors.New("webpush: invalid p256dh key")
+	}
+	authSecret, err := decodeKey(sub.Keys.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("webpush: auth secret must be 16 bytes")
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	cek, nonce, err := keys(shared, authSecret, uaBytes, asPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 marks the last (and only) record; no further padding.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// Decrypt reverses Encrypt given the subscriber's private key and auth
+// secret. Browsers do this; the fake push service uses it to check payloads.
+func Decrypt(body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) ([]byte, error) {
+	if len(body) < 21 {
+		return nil, errors.New("webpush: body too short")
+	}
+	salt := body[:16]
+	idLen := int(body[20])
+	if len(body) < 21+idLen {
+		return nil, errors.New("webpush: body too short")
+	}
+	asBytes := body[21 : 21+idLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asBytes)
+	if err != nil {
+		return nil, errors.New("webpush: invalid key id")
+	}
+	shared, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		return nil, err
+	}
+	cek, nonce, err := keys(shared, authSecret, uaPrivate.PublicKey().Bytes(), asBytes, salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		return nil, err
+	}
+	// Strip zero padding back to the delimiter.
+	i := len(plaintext) - 1
+	for i >= 0 && plaintext[i] == 0 {
+		i--
+	}
+	if i < 0 || (plaintext[i] != 1 && plaintext[i] != 2) {
+		return nil, errors.New("webpush: missing padding delimiter")
+	}
+	return plaintext[:i], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,112 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CloudAPIURL is the Graph API base of the WhatsApp Cloud API.
+const CloudAPIURL = "https://graph.facebook.com/v20.0"
+
+// CloudProvider sends approved templates through the WhatsApp Cloud API.
+type CloudProvider struct {
+	BaseURL       string
+	PhoneNumberID string
+	Token         string
+	HTTP          *http.Client
+}
+
+func NewCloudProvider(baseURL, phoneNumberID, token string) *CloudProvider {
+	if baseURL == "" {
+		baseURL = CloudAPIURL
+	}
+	return &CloudProvider{
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		PhoneNumberID: phoneNumberID,
+		Token:         token,
+		HTTP:          &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *CloudProvider) Name() string { return "cloud" }
+
+type cloudParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type cloudComponent struct {
+	Type       string           `json:"type"`
+	Parameters []cloudParameter `json:"parameters"`
+}
+
+type cloudRequest struct {
+	MessagingProduct string `json:"messaging_product"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Template         struct {
+		Name     string `json:"name"`
+		Language struct {
+			Code string `json:"code"`
+		} `json:"language"`
+		Components []cloudComponent `json:"components,omitempty"`
+	} `json:"template"`
+}
+
+type cloudResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+func (p *CloudProvider) Send(ctx context.Context, msg Message) (string, error) {
+	req := cloudRequest{MessagingProduct: "whatsapp", To: digitsOnly(msg.To), Type: "template"}
+	req.Template.Name = msg.Template.Name
+	req.Template.Language.Code = msg.Template.Language
+	if len(msg.Params) > 0 {
+		body := cloudComponent{Type: "body"}
+		for _, param := range msg.Params {
+			body.Parameters = append(body.Parameters, cloudParameter{Type: "text", Text: param})
+		}
+		req.Template.Components = []cloudComponent{body}
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/"+p.PhoneNumberID+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.Token)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.HTTP.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out cloudResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&out)
+	if resp.StatusCode >= 300 || out.Error != nil {
+		message := resp.Status
+		if out.Error != nil {
+			message = out.Error.Message
+		}
+		return "", &ProviderError{Provider: p.Name(), StatusCode: resp.StatusCode, Message: message}
+	}
+	if decodeErr != nil || len(out.Messages) == 0 {
+		return "", &ProviderError{Provider: p.Name(), StatusCode: resp.StatusCode, Message: "unexpected response"}
+	}
+	return out.Messages[0].ID, nil
+}
@@ -0,0 +1,110 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FonnteAPIURL is Fonnte's API base.
+const FonnteAPIURL = "https://api.fonnte.com"
+
+// postForm sends a form to a local gateway with the device token in the
+// Authorization header, as both Fonnte and Wablas expect.
+func postForm(ctx context.Context, client *http.Client, endpoint, token string, form url.Values, out interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, fmt.Errorf("whatsapp: unexpected response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// FonnteProvider sends plain text through a Fonnte device. Fonnte has no
+// template approval, so templates are rendered locally.
+type FonnteProvider struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+func NewFonnteProvider(baseURL, token string) *FonnteProvider {
+	if baseURL == "" {
+		baseURL = FonnteAPIURL
+	}
+	return &FonnteProvider{BaseURL: strings.TrimRight(baseURL, "/"), Token: token, HTTP: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (p *FonnteProvider) Name() string { return "fonnte" }
+
+func (p *FonnteProvider) Send(ctx context.Context, msg Message) (string, error) {
+	var out struct {
+		Status bool          `json:"status"`
+		Reason string        `json:"reason"`
+		ID     []json.Number `json:"id"`
+	}
+	form := url.Values{"target": {digitsOnly(msg.To)}, "message": {msg.Text()}}
+	status, err := postForm(ctx, p.HTTP, p.BaseURL+"/send", p.Token, form, &out)
+	if err != nil {
+		return "", err
+	}
+	if !out.Status || status >= 300 {
+		return "", &ProviderError{Provider: p.Name(), StatusCode: status, Message: out.Reason}
+	}
+	if len(out.ID) == 0 {
+		return "", nil
+	}
+	return out.ID[0].String(), nil
+}
+
+// WablasProvider sends plain text through a Wablas device. BaseURL is the
+// account's server, e.g. https://jkt.wablas.com.
+type WablasProvider struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+func NewWablasProvider(baseURL, token string) *WablasProvider {
+	return &WablasProvider{BaseURL: strings.TrimRight(baseURL, "/"), Token: token, HTTP: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (p *WablasProvider) Name() string { return "wablas" }
+
+func (p *WablasProvider) Send(ctx context.Context, msg Message) (string, error) {
+	var out struct {
+		Status  bool   `json:"status"`
+		Message string `json:"message"`
+		Data    struct {
+			Messages []struct {
+				ID string `json:"id"`
+			} `json:"messages"`
+		} `json:"data"`
+	}
+	form := url.Values{"phone": {digitsOnly(msg.To)}, "message": {msg.Text()}}
+	status, err := postForm(ctx, p.HTTP, p.BaseURL+"/api/send-message", p.Token, form, &out)
+	if err != nil {
+		return "", err
+	}
+	if !out.Status || status >= 300 {
+		return "", &ProviderError{Provider: p.Name(), StatusCode: status, Message: out.Message}
+	}
+	if len(out.Data.Messages) == 0 {
+		return "", nil
+	}
+	return out.Data.Messages[0].ID, nil
+}
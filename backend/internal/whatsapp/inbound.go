@@ -0,0 +1,111 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Inbound is a message a user sent to our number.
+type Inbound struct {
+	From string // E.164
+	Text string
+}
+
+// Keywords users reply with to stop or resume messages.
+var (
+	optOutKeywords = []string{"STOP", "BERHENTI", "UNSUBSCRIBE"}
+	optInKeywords  = []string{"START", "MULAI", "SUBSCRIBE"}
+)
+
+// Command reports whether the message asks to opt out or back in.
+func (m Inbound) Command() (optOut, optIn bool) {
+	word := strings.ToUpper(strings.TrimSpace(m.Text))
+	for _, k := range optOutKeywords {
+		if word == k {
+			return true, false
+		}
+	}
+	for _, k := range optInKeywords {
+		if word == k {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+type cloudWebhook struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Messages []struct {
+					From string `json:"from"`
+					Type string `json:"type"`
+					Text struct {
+						Body string `json:"body"`
+					} `json:"text"`
+					Button struct {
+						Text string `json:"text"`
+					} `json:"button"`
+				} `json:"messages"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// ParseInbound reads an incoming-message webhook from any supported provider:
+// Cloud API JSON, or the flat sender/phone + message fields Fonnte and Wablas
+// post as JSON or form data. Status callbacks and unparsable numbers are skipped.
+func ParseInbound(body []byte, contentType string) []Inbound {
+	var fields map[string]string
+	var messages []Inbound
+	add := func(from, text string) {
+		if phone, err := NormalizePhone(from); err == nil && text != "" {
+			messages = append(messages, Inbound{From: phone, Text: text})
+		}
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil
+		}
+		fields = map[string]string{}
+		for k := range values {
+			fields[k] = values.Get(k)
+		}
+	} else {
+		var cloud cloudWebhook
+		if err := json.Unmarshal(body, &cloud); err == nil && len(cloud.Entry) > 0 {
+			for _, entry := range cloud.Entry {
+				for _, change := range entry.Changes {
+					for _, m := range change.Value.Messages {
+						text := m.Text.Body
+						if m.Type == "button" {
+							text = m.Button.Text
+						}
+						add(m.From, text)
+					}
+				}
+			}
+			return messages
+		}
+		var raw map[string]interface{}
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil
+		}
+		fields = map[string]string{}
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				fields[k] = s
+			}
+		}
+	}
+
+	from := fields["sender"] // Fonnte
+	if from == "" {
+		from = fields["phone"] // Wablas
+	}
+	add(from, fields["message"])
+	return messages
+}
@@ -0,0 +1,61 @@
+package whatsapp
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone is returned for numbers that can't be turned into E.164.
+var ErrInvalidPhone = errors.New("Invalid WhatsApp number")
+
+// NormalizePhone converts the ways Indonesians write a mobile number
+// (0812-3456-7890, 62812..., +62 812..., 812...) to E.164: +6281234567890.
+// Numbers already in international form with another country code are kept.
+func NormalizePhone(raw string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	n := b.String()
+
+	switch {
+	case strings.HasPrefix(n, "+"):
+	case strings.HasPrefix(n, "00"):
+		n = "+" + n[2:]
+	case strings.HasPrefix(n, "62"):
+		n = "+" + n
+	case strings.HasPrefix(n, "0"):
+		n = "+62" + n[1:]
+	case strings.HasPrefix(n, "8"):
+		n = "+62" + n
+	default:
+		return "", ErrInvalidPhone
+	}
+
+	digits := n[1:]
+	if strings.HasPrefix(digits, "62") {
+		// Indonesian mobile numbers: 8xx followed by 7-10 digits.
+		local := digits[2:]
+		if !strings.HasPrefix(local, "8") || len(local) < 9 || len(local) > 12 {
+			return "", ErrInvalidPhone
+		}
+		return n, nil
+	}
+	if digits == "" || digits[0] == '0' || len(digits) < 8 || len(digits) > 15 {
+		return "", ErrInvalidPhone
+	}
+	return n, nil
+}
+
+// digitsOnly is the number without "+", the form provider APIs expect.
+func digitsOnly(e164 string) string {
+	return strings.TrimPrefix(e164, "+")
+}
@@ -0,0 +1,62 @@
+package whatsapp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"081234567890", "+6281234567890"},
+		{"0812-3456-7890", "+6281234567890"},
+		{"0812 3456 7890", "+6281234567890"},
+		{"(0812) 3456.7890", "+6281234567890"},
+		{"6281234567890", "+6281234567890"},
+		{"+62 812 3456 7890", "+6281234567890"},
+		{"+62-812-3456-7890", "+6281234567890"},
+		{"006281234567890", "+6281234567890"},
+		{"81234567890", "+6281234567890"},
+		{"  081234567890  ", "+6281234567890"},
+		{"0812345678", "+62812345678"},       // shortest: 8 plus 8 digits
+		{"0812345678901", "+62812345678901"}, // longest: 8 plus 11 digits
+		{"+60123456789", "+60123456789"},     // Malaysia kept as is
+		{"0060123456789", "+60123456789"},
+		{"+1 (415) 555-0100", "+14155550100"},
+		{"+12345678", "+12345678"},               // shortest international
+		{"+123456789012345", "+123456789012345"}, // longest E.164
+	}
+	for _, tt := range tests {
+		got, err := NormalizePhone(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizePhoneRejects(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"+",
+		"   ",
+		"12345678",          // no country code and not a local mobile number
+		"021-5551234",       // Jakarta landline
+		"+62215551234",      // landline in international form
+		"+62081234567890",   // trunk 0 kept after the country code
+		"081234567",         // too short
+		"08123456789012",    // too long
+		"+1234567",          // too short for E.164
+		"+1234567890123456", // too long for E.164
+		"+0123456789",       // country codes don't start with 0
+		"0812+34567890",     // + in the middle
+		"0812/3456/7890",    // unsupported separator
+		"0812345678x",
+		"wa.me/6281234567890",
+	} {
+		if got, err := NormalizePhone(raw); !errors.Is(err, ErrInvalidPhone) {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %v", raw, got, err, ErrInvalidPhone)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package whatsapp sends templated WhatsApp messages through a pluggable
+// provider: the WhatsApp Cloud API, or local gateways such as Fonnte and Wablas.
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/username/ramadhan-ceria-backend/internal/config"
+)
+
+// Template is a message with positional {{1}}, {{2}}... parameters. The
+// Cloud API sends the pre-approved template by Name; gateways without
+// template support send Body with the parameters filled in.
+type Template struct {
+	Name     string
+	Language string
+	Body     string
+}
+
+// Templates registered with Meta. Parameters must not contain newlines.
+var (
+	TemplateDailySummary = Template{
+		Name:     "rc_daily_summary",
+		Language: "id",
+		Body:     "Assalamu'alaikum, berikut rekap Ramadhan Ceria keluarga {{1}} hari ini ({{2}}): {{3}}. Balas STOP untuk berhenti menerima pesan.",
+	}
+	TemplateRedemptionRequest = Template{
+		Name:     "rc_redemption_request",
+		Language: "id",
+		Body:     "{{1}} ingin menukar {{2}} poin untuk hadiah \"{{3}}\". Setujui atau tolak di dashboard Ramadhan Ceria.",
+	}
+	TemplatePlanExpiry = Template{
+		Name:     "rc_plan_expiry",
+		Language: "id",
+		Body:     "Paket {{1}} keluarga {{2}} berakhir pada {{3}}. {{4}}",
+	}
+)
+
+// Render fills the template body with params.
+func (t Template) Render(params []string) string {
+	body := t.Body
+	for i, p := range params {
+		body = strings.ReplaceAll(body, "{{"+strconv.Itoa(i+1)+"}}", p)
+	}
+	return body
+}
+
+// Message is one outgoing template message. To is E.164, e.g. +6281234567890.
+type Message struct {
+	To       string
+	Template Template
+	Params   []string
+}
+
+// Text is the rendered body, used by providers without template support.
+func (m Message) Text() string {
+	return m.Template.Render(m.Params)
+}
+
+type Provider interface {
+	// Name identifies the provider in the message log.
+	Name() string
+	// Send delivers msg and returns the provider's message ID.
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+// ProviderError is a rejection reported by the provider's API.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("whatsapp: %s answered HTTP %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// FromConfig picks the provider named by cfg.Provider (cloud, fonnte or
+// wablas). It returns a LogProvider when no provider is configured.
+func FromConfig(cfg config.WhatsApp) (Provider, error) {
+	token := cfg.Token
+	baseURL := cfg.APIURL
+
+	switch name := cfg.Provider; name {
+	case "":
+		return &LogProvider{}, nil
+	case "cloud":
+		phoneID := cfg.PhoneNumberID
+		if token == "" || phoneID == "" {
+			return nil, errors.New("whatsapp: cloud provider needs WHATSAPP_TOKEN and WHATSAPP_PHONE_NUMBER_ID")
+		}
+		return NewCloudProvider(baseURL, phoneID, token), nil
+	case "fonnte":
+		if token == "" {
+			return nil, errors.New("whatsapp: fonnte provider needs WHATSAPP_TOKEN")
+		}
+		return NewFonnteProvider(baseURL, token), nil
+	case "wablas":
+		if token == "" || baseURL == "" {
+			return nil, errors.New("whatsapp: wablas provider needs WHATSAPP_TOKEN and WHATSAPP_API_URL (your server, e.g. https://jkt.wablas.com)")
+		}
+		return NewWablasProvider(baseURL, token), nil
+	default:
+		return nil, fmt.Errorf("whatsapp: unknown WHATSAPP_PROVIDER %q", name)
+	}
+}
+
+// LogProvider only logs what would have been sent.
+type LogProvider struct{}
+
+func (p *LogProvider) Name() string { return "log" }
+
+func (p *LogProvider) Send(ctx context.Context, msg Message) (string, error) {
+	log.Printf("whatsapp: to=%s template=%s (WHATSAPP_PROVIDER not set, not sent): %s", msg.To, msg.Template.Name, msg.Text())
+	return "", nil
+}
+
+// FakeProvider keeps sent messages in memory for tests and local tooling.
+// Set Err to make every send fail.
+type FakeProvider struct {
+	mu   sync.Mutex
+	Sent []Message
+	Err  error
+}
+
+func (p *FakeProvider) Name() string { return "fake" }
+
+func (p *FakeProvider) Send(ctx context.Context, msg Message) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return "", p.Err
+	}
+	p.Sent = append(p.Sent, msg)
+	return "fake-" + strconv.Itoa(len(p.Sent)), nil
+}
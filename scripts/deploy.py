@@ -116,6 +116,7 @@ def main():
             f"-e DB_PORT=5432 "
             f"-e PORT=3005 "
             f"-e JWT_SECRET={JWT_SECRET} "
+            f"-e FRONTEND_URL=https://{DOMAIN} "
             f"-p 3005:3005 "
             f"ramadhan-backend"
         )